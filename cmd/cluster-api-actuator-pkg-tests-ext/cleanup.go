@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+// newCleanupCommand returns the command deleting e2e resources left behind by
+// interrupted test runs, e.g. when a CI job times out before cleanup.
+func newCleanupCommand() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Delete leftover e2e MachineSets and workload Jobs",
+		Long: "Delete objects labeled with " + framework.ReasonKey + "=" + framework.ReasonE2E +
+			": MAPI MachineSets, CAPI MachineSets and their infrastructure machine templates, autoscaler workload Jobs" +
+			" and user data Secrets.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCleanup(cmd.Context(), cmd, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only list the objects which would be deleted")
+
+	return cmd
+}
+
+func runCleanup(ctx context.Context, cmd *cobra.Command, dryRun bool) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cl, err := framework.LoadClient()
+	if err != nil {
+		return fmt.Errorf("failed to create a new controller-runtime client: %w", err)
+	}
+
+	objs, err := framework.CleanupOrphanedE2EResources(ctx, cl, dryRun)
+	if err != nil {
+		return err
+	}
+
+	action := "deleted"
+	if dryRun {
+		action = "would delete"
+	}
+
+	for _, obj := range objs {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %T %s/%s\n", action, obj, obj.GetNamespace(), obj.GetName())
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+// extensionInfo describes the test extension to the schedulers running its tests.
+type extensionInfo struct {
+	Name   string      `json:"name"`
+	Suites []suiteInfo `json:"suites"`
+	Labels []labelInfo `json:"labels"`
+}
+
+// suiteInfo is a set of tests, selected by a Ginkgo label filter.
+type suiteInfo struct {
+	Name        string `json:"name"`
+	LabelFilter string `json:"labelFilter"`
+	Parallel    bool   `json:"parallel"`
+	// Qualifiers are CEL expressions which must all be true for a test of the suite to be selected.
+	// They are evaluated against the labels of the test and the facts of the environment:
+	// platform (e.g. AWS), topology (the control plane topology, e.g. SingleReplica) and featureSet.
+	Qualifiers []string `json:"qualifiers,omitempty"`
+}
+
+// environmentQualifiers keep the tests whose platform, topology or feature set requirements
+// the environment does not meet from being selected, rather than having them skip at runtime.
+var environmentQualifiers = []string{
+	// Hosted control planes have no Machine API, their machines are managed by the management cluster.
+	`topology != "External"`,
+	labelSetQualifier(framework.PlatformLabelKey, "platform"),
+	labelSetQualifier(framework.TopologyLabelKey, "topology"),
+	`!("techpreview" in labels) || featureSet == "TechPreviewNoUpgrade"`,
+	fmt.Sprintf(`!("%s" in labels) || topology != "SingleReplica"`, framework.LabelRequiresMachineManagement[0]),
+}
+
+// labelSetQualifier returns a CEL expression selecting the tests without the label set,
+// or whose label set contains the value of the environment fact.
+func labelSetQualifier(key, fact string) string {
+	return fmt.Sprintf(`!labels.exists(l, l.startsWith("%[1]s:")) || ("%[1]s:" + %[2]s) in labels`, key, fact)
+}
+
+// labelInfo documents a label the tests are annotated with.
+// Label sets are written key:value on the tests, e.g. platform:AWS.
+type labelInfo struct {
+	Name        string `json:"name"`
+	Set         bool   `json:"set,omitempty"`
+	Description string `json:"description"`
+	// MaxAttempts is the number of times the tests with the label are run before they fail, when they are retried.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+}
+
+// info is the metadata advertised by the info command. The suites match the Makefile test-e2e targets.
+var info = extensionInfo{
+	Name: "cluster-api-actuator-pkg",
+	Suites: []suiteInfo{
+		{Name: "e2e", LabelFilter: "!periodic&&!qe-only", Parallel: true, Qualifiers: environmentQualifiers},
+		{Name: "e2e-periodic", LabelFilter: "periodic&&!qe-only&&!large-scale&&!soak", Parallel: true, Qualifiers: environmentQualifiers},
+		{Name: "e2e-chaos", LabelFilter: "chaos&&!qe-only", Qualifiers: environmentQualifiers},
+		{Name: "e2e-large-scale", LabelFilter: "large-scale&&!qe-only", Qualifiers: environmentQualifiers},
+		{Name: "e2e-soak", LabelFilter: "soak&&!qe-only", Qualifiers: environmentQualifiers},
+		{Name: "e2e-readonly", LabelFilter: "read-only&&!disruptive&&!qe-only", Parallel: true, Qualifiers: environmentQualifiers},
+	},
+	Labels: []labelInfo{
+		{Name: "disruptive", Description: "the test disrupts the cluster, e.g. by creating machines or restarting controllers"},
+		{Name: "read-only", Description: "the test only observes the cluster, it can run against production clusters"},
+		{Name: "techpreview", Description: "the test only runs on TechPreviewNoUpgrade clusters"},
+		{Name: framework.LabelRequiresMachineManagement[0], Description: "the test creates machines from the worker MachineSets, which single node clusters do not have"},
+		{Name: "qe-only", Description: "the test can run in the QE cloud accounts only"},
+		{Name: "dev-only", Description: "the test can run in the dev cloud accounts only"},
+		{Name: "flake-retry", MaxAttempts: framework.FlakeRetryAttempts, Description: "the test is retried once on failure, after its cleanup has run; " +
+			"a test passing on retry is reported with a Flaky report entry"},
+		{Name: framework.PlatformLabelKey, Set: true, Description: "the platforms the test runs on; tests without it run on every platform"},
+		{Name: framework.TopologyLabelKey, Set: true, Description: "the control plane topologies the test runs on; tests without it run on every topology"},
+		{Name: framework.MachinesLabelKey, Set: true, Description: "the approximate number of machines the test creates"},
+	},
+}
+
+// newInfoCommand returns the command printing the extension metadata, so that the tests
+// can be scheduled on suitable clusters rather than skipping at runtime.
+func newInfoCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info",
+		Short: "Print the extension metadata as JSON",
+		Long: "Print the suites of the extension and the labels their tests are annotated with, as JSON. " +
+			"The labels of each test are listed by running the suite with ginkgo --dry-run --json-report.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+
+			if err := encoder.Encode(info); err != nil {
+				return fmt.Errorf("failed to encode the extension info: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
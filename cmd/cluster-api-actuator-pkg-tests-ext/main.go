@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+func init() {
+	if err := framework.RegisterAllSchemes(scheme.Scheme); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+func main() {
+	// Expose the --kubeconfig go flag registered by controller-runtime, which cobra does not parse.
+	// The other go flags are the ones of ginkgo, imported by the framework, which do not apply to the commands.
+	pflag.CommandLine.AddGoFlag(flag.CommandLine.Lookup(config.KubeconfigFlagName))
+
+	root := &cobra.Command{
+		Use:          "cluster-api-actuator-pkg-tests-ext",
+		Short:        "Cluster API actuator e2e tests extension",
+		SilenceUsage: true,
+	}
+
+	root.AddCommand(newCleanupCommand())
+	root.AddCommand(newInfoCommand())
+
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -12,4 +12,4 @@ go run ./vendor/github.com/onsi/ginkgo/v2/ginkgo \
     --junit-report="junit_cluster_api_actuator_pkg_e2e.xml" \
     --output-dir="${OUTPUT_DIR}" \
     "$@" \
-    ./pkg/ -- --alsologtostderr -v 4 -kubeconfig ${KUBECONFIG:-~/.kube/config}
+    ./pkg/ -- --alsologtostderr -v 4 -kubeconfig ${KUBECONFIG:-~/.kube/config} ${CHAOS:+--chaos} ${NODE_TRIAGE:+--node-triage} ${WINDOWS_IMAGE:+--windows-image=${WINDOWS_IMAGE}} ${SOAK_DURATION:+--soak-duration=${SOAK_DURATION}} ${MANAGEMENT_KUBECONFIG:+--management-kubeconfig=${MANAGEMENT_KUBECONFIG}}
@@ -9,7 +9,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,10 +44,29 @@ const (
 	toBeDeletedTaintKey           = "ToBeDeletedByClusterAutoscaler"
 	caMinSizeAnnotation           = "machine.openshift.io/cluster-api-autoscaler-node-group-min-size"
 	caMaxSizeAnnotation           = "machine.openshift.io/cluster-api-autoscaler-node-group-max-size"
+	jobNameLabel                  = "job-name"
+	safeToEvictAnnotationKey      = "cluster-autoscaler.kubernetes.io/safe-to-evict"
 )
 
+// clusterAutoscalerOption customizes the CA resource built by clusterAutoscalerResource.
+type clusterAutoscalerOption func(*caov1.ClusterAutoscaler)
+
+// withUtilizationThreshold sets the node utilization level below which a node can be considered for scale down.
+func withUtilizationThreshold(threshold string) clusterAutoscalerOption {
+	return func(ca *caov1.ClusterAutoscaler) {
+		ca.Spec.ScaleDown.UtilizationThreshold = &threshold
+	}
+}
+
+// withIgnoreDaemonsetsUtilization sets whether DaemonSet pods are ignored when computing the node utilization for scale down.
+func withIgnoreDaemonsetsUtilization(ignore bool) clusterAutoscalerOption {
+	return func(ca *caov1.ClusterAutoscaler) {
+		ca.Spec.IgnoreDaemonsetsUtilization = &ignore
+	}
+}
+
 // Build default CA resource to allow fast scaling up and down.
-func clusterAutoscalerResource(maxNodesTotal int) *caov1.ClusterAutoscaler {
+func clusterAutoscalerResource(maxNodesTotal int, opts ...clusterAutoscalerOption) *caov1.ClusterAutoscaler {
 	tenSecondString := "10s"
 
 	// Choose a time that is at least twice as the sync period
@@ -56,7 +78,7 @@ func clusterAutoscalerResource(maxNodesTotal int) *caov1.ClusterAutoscaler {
 	// set the logging verbosity high enough that we can get more debugging information
 	var logverbosity int32 = 4
 
-	return &caov1.ClusterAutoscaler{
+	ca := &caov1.ClusterAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "default",
 			Namespace: framework.MachineAPINamespace,
@@ -82,6 +104,12 @@ func clusterAutoscalerResource(maxNodesTotal int) *caov1.ClusterAutoscaler {
 			LogVerbosity: ptr.To[int32](logverbosity),
 		},
 	}
+
+	for _, opt := range opts {
+		opt(ca)
+	}
+
+	return ca
 }
 
 // Build MA resource from targeted machineset.
@@ -110,15 +138,93 @@ func machineAutoscalerResource(targetMachineSet *machinev1.MachineSet, minReplic
 	}
 }
 
-var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.LabelDisruptive, Serial, func() {
+// Build a DaemonSet resource running on the nodes with the given label.
+func daemonSetResource(name string, memoryRequest resource.Quantity, nodeLabel string) *appsv1.DaemonSet {
+	podLabels := map[string]string{
+		"app": name,
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: framework.MachineAPINamespace,
+			Labels: map[string]string{
+				autoscalingTestLabel: "",
+				framework.ReasonKey:  framework.ReasonE2E,
+			},
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    name,
+							Image:   "registry.access.redhat.com/ubi8/ubi-minimal:latest",
+							Command: []string{"sleep", "86400"}, // 1 day
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: memoryRequest,
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+								},
+							},
+						},
+					},
+					NodeSelector: map[string]string{
+						nodeLabel: "",
+					},
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "kubemark",
+							Operator: corev1.TolerationOpExists,
+						},
+						{
+							Key:    framework.ClusterAPIActuatorPkgTaint,
+							Effect: corev1.TaintEffectPreferNoSchedule,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Build a PriorityClass resource with the given value.
+func priorityClassResource(name string, value int32) *schedulingv1.PriorityClass {
+	return &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				autoscalingTestLabel: "",
+			},
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PriorityClass",
+			APIVersion: "scheduling.k8s.io/v1",
+		},
+		Value: value,
+	}
+}
+
+var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, Serial, func() {
 
 	var workloadMemRequest resource.Quantity
+	var workloadCPURequest resource.Quantity
 	var client runtimeclient.Client
 	var gatherer *gatherer.StateGatherer
 	var err error
 	var cleanupObjects map[string]runtimeclient.Object
 
-	ctx := context.Background()
+	var ctx context.Context
 	cascadeDelete := metav1.DeletePropagationForeground
 	deleteObject := func(name string, obj runtimeclient.Object) error {
 		klog.Infof("[cleanup] %q (%T)", name, obj)
@@ -128,12 +234,12 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 	}
 
 	BeforeEach(func() {
+		ctx = framework.GetContext()
+
 		client, err = framework.LoadClient()
 		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes client for test")
 
-		komega.SetClient(client)
-
-		workerNodes, err := framework.GetWorkerNodes(client)
+		workerNodes, err := framework.GetWorkerNodes(ctx, client)
 		Expect(err).NotTo(HaveOccurred(), "Failed to get worker Node objects")
 		Expect(len(workerNodes)).To(BeNumerically(">=", 1), "Expected >= 1 worker node, observed %d", len(workerNodes))
 
@@ -150,6 +256,10 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 		// node.
 		workloadMemRequest = resource.MustParse(fmt.Sprintf("%v", 0.7*float32(bytes)))
 
+		// 70% of the CPU, for the same reason.
+		cpuCapacity := workerNodes[0].Status.Allocatable[corev1.ResourceCPU]
+		workloadCPURequest = *resource.NewMilliQuantity(cpuCapacity.MilliValue()*7/10, resource.DecimalSI)
+
 		// Anything we create we must cleanup
 		cleanupObjects = make(map[string]runtimeclient.Object)
 
@@ -231,7 +341,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 
 		// Machines required for test: 2
 		// Reason: This tests checks that autoscaler is able to scale from zero. It requires 2 machines to ensure it scales to the correct number of nodes based on the workload size.
-		It("It scales from/to zero", func() {
+		It("It scales from/to zero", framework.LabelMachines(2), func() {
 			// Only run in platforms which support autoscaling from/to zero.
 			clusterInfra, err := framework.GetInfrastructure(ctx, client)
 			Expect(err).NotTo(HaveOccurred(), "Failed to get cluster infrastructure object")
@@ -258,7 +368,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			Eventually(func() (map[string]string, error) {
 				// Checking for the keys of the old ScaleFromZero annotations before creating a MachineAutoscaler.
 				// Only checking for the CPU and Mem annotations, as some platforms do not include the GPU annotations.
-				ms, err := framework.GetMachineSet(context.TODO(), client, machineSet.GetName())
+				ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
 				if err != nil {
 					return nil, err
 				}
@@ -285,20 +395,13 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			cleanupObjects[workload.GetName()] = workload
 			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", workloadJobName)
 
-			Eventually(func() bool {
-				ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
-				Expect(err).ToNot(HaveOccurred(), "Failed to get MachineSet %s", machineSet.GetName())
-
-				By(fmt.Sprintf("Waiting for machineSet replicas to scale out. Current replicas are %v, expected %v.",
-					*ms.Spec.Replicas, expectedReplicas))
-
-				return *ms.Spec.Replicas == expectedReplicas
-			}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+			By(fmt.Sprintf("Waiting for machineSet replicas to scale out to %v", expectedReplicas))
+			framework.WaitForMachineSetReplicas(ctx, client, machineSet, expectedReplicas, framework.WaitMedium)
 
 			Eventually(func() (map[string]string, error) {
 				// Checking for the keys of the newly added upstream annotations from the CAO.
 				// Only checking for the CPU and Mem annotations, as some platforms do not include the GPU annotations.
-				ms, err := framework.GetMachineSet(context.TODO(), client, machineSet.GetName())
+				ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
 				if err != nil {
 					return nil, err
 				}
@@ -316,15 +419,8 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			By("Deleting the workload")
 			Expect(deleteObject(workload.Name, cleanupObjects[workload.Name])).Should(Succeed(), "Failed to delete scale-out workload %s", workload.Name)
 			delete(cleanupObjects, workload.Name)
-			Eventually(func() bool {
-				ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
-				Expect(err).ToNot(HaveOccurred(), "Failed to get MachineSet %s", machineSet.GetName())
-
-				By(fmt.Sprintf("Waiting for machineSet replicas to scale in. Current replicas are %v, expected %v.",
-					*ms.Spec.Replicas, expectedReplicas))
-
-				return *ms.Spec.Replicas == expectedReplicas
-			}, framework.WaitLong, pollingInterval).Should(BeTrue(), "MachineSet %s failed to scale in to 0 replicas", machineSet.GetName())
+			By(fmt.Sprintf("Waiting for machineSet replicas to scale in to %v", expectedReplicas))
+			framework.WaitForMachineSetReplicas(ctx, client, machineSet, expectedReplicas, framework.WaitLong)
 		})
 
 		// Machines required for test: 1
@@ -332,7 +428,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 		// Reason: This test checks that the autoscaler is able to scale from zero when a workload requires specific architecture in the node affinity fields.
 		// Moreover, this test gives a better signal when multiple architectures are available in the cluster or the cluster is not amd64,
 		// as the workload is set to be scheduled on an architecture different from amd64.
-		It("It scales from/to zero a machine set with the architecture requested by the workload", func() {
+		It("It scales from/to zero a machine set with the architecture requested by the workload", framework.LabelMachines(1), func() {
 			// Only run in platforms which support arch-aware autoscaling from/to zero.
 			clusterInfra, err := framework.GetInfrastructure(ctx, client)
 			Expect(err).NotTo(HaveOccurred(), "Failed to get cluster infrastructure object")
@@ -398,7 +494,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", workloadJobName)
 			runCtx, cancel := context.WithTimeout(ctx, framework.WaitLong)
 			defer cancel()
-			framework.RunCheckUntil(runCtx,
+			framework.RunInvariant(runCtx,
 				func(ctx context.Context, g framework.GomegaAssertions) bool { // Continuous check condition
 					updatedMachineSets := []*machinev1.MachineSet{}
 					for _, machineSet := range machineSets {
@@ -426,9 +522,92 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 				})
 		})
 
+		// Machines required for test: 1
+		// Reason: This test checks that the autoscaler scales from zero the MachineSet providing the GPU requested by the workload.
+		// The GPU machine is not required to become a node, so the test does not depend on the GPU capacity of the cloud.
+		It("It scales from zero a machine set with the GPU requested by the workload", framework.LabelMachines(1), func() {
+			clusterInfra, err := framework.GetInfrastructure(ctx, client)
+			Expect(err).NotTo(HaveOccurred(), "Failed to get cluster infrastructure object")
+
+			platform := clusterInfra.Status.PlatformStatus.Type
+			switch platform {
+			case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+				klog.Infof("Platform is %v", platform)
+			default:
+				Skip(fmt.Sprintf("Platform %v does not have GPU instance types set, skipping.", platform))
+			}
+
+			targetedNodeLabel := fmt.Sprintf("%v-scale-from-zero-gpu", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 0)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+
+			By("Creating a new MachineSet with 0 replicas and without GPU")
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 0 replicas")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Creating a new MachineSet with 0 replicas and a GPU")
+			gpuMachineSetParams, err := framework.BuildGPUMachineSetParams(framework.BuildMachineSetParams(ctx, client, 0), platform)
+			Expect(err).ToNot(HaveOccurred(), "Failed to build GPU MachineSet parameters")
+			gpuMachineSetParams.Labels[targetedNodeLabel] = ""
+			gpuMachineSet, err := framework.CreateMachineSet(client, gpuMachineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create GPU MachineSet with 0 replicas")
+			cleanupObjects[gpuMachineSet.GetName()] = gpuMachineSet
+
+			Eventually(func() (map[string]string, error) {
+				ms, err := framework.GetMachineSet(ctx, client, gpuMachineSet.GetName())
+				if err != nil {
+					return nil, err
+				}
+
+				return ms.Annotations, nil
+			}, framework.WaitMedium, pollingInterval).Should(HaveKeyWithValue(annotationsutil.GpuCountKeyDeprecated, "1"),
+				"No GPU scale from zero annotation found")
+
+			for _, ms := range []*machinev1.MachineSet{machineSet, gpuMachineSet} {
+				By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s/%s - min:%v, max:%v",
+					ms.GetNamespace(), ms.GetName(), 0, 1))
+				asr := machineAutoscalerResource(ms, 0, 1)
+				Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 0/max 1 replicas")
+				cleanupObjects[asr.GetName()] = asr
+			}
+
+			uniqueJobName := fmt.Sprintf("%s-scale-from-zero-gpu", workloadJobName)
+			By(fmt.Sprintf("Creating scale-out workload %s requesting a GPU", uniqueJobName))
+			workload := framework.NewWorkLoad(1, resource.MustParse("1Gi"), uniqueJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			workload.Spec.Template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+				annotationsutil.GpuNvidiaType: resource.MustParse("1"),
+			}
+			workload.Spec.Template.Spec.Tolerations = append(workload.Spec.Template.Spec.Tolerations, corev1.Toleration{
+				Key:      annotationsutil.GpuNvidiaType,
+				Operator: corev1.TolerationOpExists,
+			})
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
+
+			By(fmt.Sprintf("Waiting for GPU MachineSet %s replicas to scale out", gpuMachineSet.GetName()))
+			Eventually(komega.Object(gpuMachineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(1))),
+				"GPU MachineSet %s failed to scale out to 1 replica", gpuMachineSet.GetName())
+
+			Eventually(komega.Object(gpuMachineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("ObjectMeta.Annotations", SatisfyAll(
+					HaveKeyWithValue(annotationsutil.GpuCountKey, "1"),
+					HaveKeyWithValue(annotationsutil.GpuTypeKey, annotationsutil.GpuNvidiaType),
+				)), "New GPU scale from zero annotations not found")
+
+			By(fmt.Sprintf("Verifying MachineSet %s without GPU is not scaled out", machineSet.GetName()))
+			Consistently(komega.Object(machineSet), framework.WaitShort, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(0))),
+				"MachineSet %s without GPU should not be scaled out", machineSet.GetName())
+		})
+
 		// Machines required for test: 2
 		// Reason: Needs to scale down to minReplicas = 1. Scales 1 -> 2 -> 1.
-		It("cleanup deletion information after scale down [Slow]", func() {
+		It("cleanup deletion information after scale down [Slow]", framework.LabelMachines(2), func() {
 			By("Creating MachineSet with 1 replica")
 			targetedNodeLabel := fmt.Sprintf("%v-delete-cleanup", autoscalerWorkerNodeRoleLabel)
 			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
@@ -459,14 +638,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
 
 			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out", machineSet.GetName()))
-			Eventually(func() (int32, error) {
-				current, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
-				if err != nil {
-					return 0, err
-				}
-
-				return *current.Spec.Replicas, nil
-			}, framework.WaitMedium, pollingInterval).Should(BeEquivalentTo(expectedReplicas), "MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+			framework.WaitForMachineSetReplicas(ctx, client, machineSet, expectedReplicas, framework.WaitMedium)
 
 			By("Waiting for all Machines in the MachineSet to enter Running phase")
 			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
@@ -541,6 +713,224 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 				}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "Node %s has a deletion taint and it should not", machine.Status.NodeRef.Name)
 			}
 		})
+
+		// Machines required for test: 2
+		// Reason: The placeholder pod fills the single machine of the MachineSet. Once it is preempted by the workload,
+		// the cluster autoscaler scales the MachineSet out to 2 replicas for the placeholder pod.
+		It("scale out for placeholder pods preempted by a higher priority workload [Slow]", framework.LabelMachines(2), func() {
+			By("Creating PriorityClasses for the placeholder pods and the workload")
+			// The placeholder priority is above the default pod priority threshold of the cluster autoscaler,
+			// so that pending placeholder pods trigger a scale out.
+			placeholderPriorityClass := priorityClassResource("e2e-autoscaler-overprovisioning", -1)
+			Expect(client.Create(ctx, placeholderPriorityClass)).Should(Succeed(), "Failed to create placeholder PriorityClass")
+			cleanupObjects[placeholderPriorityClass.GetName()] = placeholderPriorityClass
+
+			workloadPriorityClass := priorityClassResource("e2e-autoscaler-high-priority", 1000)
+			Expect(client.Create(ctx, workloadPriorityClass)).Should(Succeed(), "Failed to create workload PriorityClass")
+			cleanupObjects[workloadPriorityClass.GetName()] = workloadPriorityClass
+
+			By("Creating MachineSet with 1 replica")
+			targetedNodeLabel := fmt.Sprintf("%v-overprovisioning", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 1 replica")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Waiting for the machineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get Nodes of MachineSet %s", machineSet.GetName())
+			Expect(nodes).To(HaveLen(1), "Expected a single Node for MachineSet %s", machineSet.GetName())
+			originalNodeName := nodes[0].Name
+
+			expectedReplicas := int32(2)
+			By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: 1, max: %d",
+				machineSet.GetName(), expectedReplicas))
+			asr := machineAutoscalerResource(machineSet, 1, expectedReplicas)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 1/max %d replicas", expectedReplicas)
+			cleanupObjects[asr.GetName()] = asr
+
+			nodeSelector := corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			}
+
+			// runningPodNodes returns the nodes of the running pods of the given job.
+			runningPodNodes := func(jobName string) ([]string, error) {
+				pods, err := framework.GetPods(ctx, client, map[string]string{jobNameLabel: jobName})
+				if err != nil {
+					return nil, err
+				}
+
+				var nodeNames []string
+				for _, pod := range pods.Items {
+					if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp.IsZero() {
+						nodeNames = append(nodeNames, pod.Spec.NodeName)
+					}
+				}
+
+				return nodeNames, nil
+			}
+
+			placeholderJobName := fmt.Sprintf("%s-overprovisioning-placeholder", workloadJobName)
+			By(fmt.Sprintf("Creating placeholder workload %s: jobs: 1, memory: %s", placeholderJobName, workloadMemRequest.String()))
+			placeholder := framework.NewWorkLoad(1, workloadMemRequest, placeholderJobName, autoscalingTestLabel, "", nodeSelector)
+			placeholder.Spec.Template.Spec.PriorityClassName = placeholderPriorityClass.GetName()
+			cleanupObjects[placeholder.GetName()] = placeholder
+			Expect(client.Create(ctx, placeholder)).Should(Succeed(), "Failed to create placeholder workload %s", placeholderJobName)
+
+			By("Waiting for the placeholder pod to run on the MachineSet node")
+			Eventually(func() ([]string, error) {
+				return runningPodNodes(placeholderJobName)
+			}, framework.WaitMedium, pollingInterval).Should(ConsistOf(originalNodeName), "Placeholder pod should run on Node %s", originalNodeName)
+
+			workloadName := fmt.Sprintf("%s-overprovisioning", workloadJobName)
+			By(fmt.Sprintf("Creating high priority workload %s: jobs: 1, memory: %s", workloadName, workloadMemRequest.String()))
+			workload := framework.NewWorkLoad(1, workloadMemRequest, workloadName, autoscalingTestLabel, "", nodeSelector)
+			workload.Spec.Template.Spec.PriorityClassName = workloadPriorityClass.GetName()
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create high priority workload %s", workloadName)
+
+			By("Waiting for the workload to preempt the placeholder pod")
+			// The workload runs on the original node without waiting for a new machine.
+			Eventually(func() ([]string, error) {
+				return runningPodNodes(workloadName)
+			}, framework.WaitMedium, pollingInterval).Should(ConsistOf(originalNodeName), "Workload pod should preempt the placeholder pod on Node %s", originalNodeName)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out for the preempted placeholder pod", machineSet.GetName()))
+			framework.WaitForMachineSetReplicas(ctx, client, machineSet, expectedReplicas, framework.WaitMedium)
+
+			By("Waiting for all Machines in the MachineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			By("Waiting for the placeholder pod to run on the new node")
+			Eventually(func() ([]string, error) {
+				return runningPodNodes(placeholderJobName)
+			}, framework.WaitMedium, pollingInterval).Should(SatisfyAll(
+				HaveLen(1),
+				Not(ContainElement(originalNodeName)),
+			), "Placeholder pod should be rescheduled onto the new Node")
+		})
+
+		// Machines required for test: 3
+		// Reason: Scales 1 -> 3, then in to the 2 nodes running a pod which is not safe to evict, then to 1 once the pods are deleted.
+		It("not scale in nodes running pods which are not safe to evict [Slow]", framework.LabelMachines(3), func() {
+			By("Creating MachineSet with 1 replica")
+			targetedNodeLabel := fmt.Sprintf("%v-safe-to-evict", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 1 replica")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Waiting for the machineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the machines of MachineSet %s", machineSet.GetName())
+			Expect(machines).To(HaveLen(1), "MachineSet %s should have 1 machine", machineSet.GetName())
+			initialMachineName := machines[0].GetName()
+
+			expectedReplicas := int32(3)
+			By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: 1, max: %d",
+				machineSet.GetName(), expectedReplicas))
+			asr := machineAutoscalerResource(machineSet, 1, expectedReplicas)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 1/max %d replicas", expectedReplicas)
+			cleanupObjects[asr.GetName()] = asr
+
+			jobReplicas := expectedReplicas
+			uniqueJobName := fmt.Sprintf("%s-safe-to-evict", workloadJobName)
+			By(fmt.Sprintf("Creating scale-out workload %s: jobs: %v, memory: %s",
+				uniqueJobName, jobReplicas, workloadMemRequest.String()))
+			workload := framework.NewWorkLoad(jobReplicas, workloadMemRequest, uniqueJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out", machineSet.GetName()))
+			Eventually(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(expectedReplicas))),
+				"MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+
+			By("Waiting for all Machines in the MachineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			machines, err = framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the machines of MachineSet %s", machineSet.GetName())
+
+			By("Running a pod which is not safe to evict on each node added by the scale out")
+			var protectedMachines []*machinev1.Machine
+			var protectedWorkloads []*batchv1.Job
+
+			for _, machine := range machines {
+				if machine.GetName() == initialMachineName {
+					continue
+				}
+
+				node, err := framework.GetNodeForMachine(ctx, client, machine)
+				Expect(err).ToNot(HaveOccurred(), "Failed to get the node of machine %s", machine.GetName())
+
+				protectedJobName := fmt.Sprintf("%s-not-safe-to-evict-%d", workloadJobName, len(protectedWorkloads))
+				protectedWorkload := framework.NewPodCountWorkLoad(1, protectedJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+					Key:      corev1.LabelHostname,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{node.GetName()},
+				})
+				framework.AddWorkLoadPodAnnotations(protectedWorkload, map[string]string{safeToEvictAnnotationKey: "false"})
+				cleanupObjects[protectedWorkload.GetName()] = protectedWorkload
+				Expect(client.Create(ctx, protectedWorkload)).Should(Succeed(), "Failed to create workload %s", protectedJobName)
+
+				Eventually(komega.Object(protectedWorkload), framework.WaitMedium, pollingInterval).Should(
+					HaveField("Status.Ready", HaveValue(BeEquivalentTo(1))),
+					"Workload %s failed to run its pod on node %s", protectedJobName, node.GetName())
+
+				protectedMachines = append(protectedMachines, machine)
+				protectedWorkloads = append(protectedWorkloads, protectedWorkload)
+			}
+
+			Expect(protectedMachines).To(HaveLen(int(expectedReplicas-1)), "MachineSet %s should have %d machines added by the scale out", machineSet.GetName(), expectedReplicas-1)
+
+			By("Deleting the workload")
+			Expect(deleteObject(workload.Name, cleanupObjects[workload.Name])).Should(Succeed(), "Failed to delete workload object %s", workload.Name)
+			delete(cleanupObjects, workload.Name)
+
+			// Only the node of the initial machine is safe to scale in.
+			protectedReplicas := int32(len(protectedMachines))
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale in to the %d protected nodes", machineSet.GetName(), protectedReplicas))
+			Eventually(komega.Object(machineSet), framework.WaitLong, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(protectedReplicas))),
+				"MachineSet %s failed to scale in to %d replicas", machineSet.GetName(), protectedReplicas)
+
+			By(fmt.Sprintf("Verifying the MachineSet %s does not scale in the protected nodes", machineSet.GetName()))
+			Consistently(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(protectedReplicas))),
+				"MachineSet %s should not scale in nodes running pods which are not safe to evict", machineSet.GetName())
+
+			machines, err = framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the machines of MachineSet %s", machineSet.GetName())
+			Expect(framework.MachinesPresent(machines, protectedMachines...)).To(BeTrue(), "The machines of the protected nodes should not have been scaled in")
+
+			By("Deleting the pods which are not safe to evict")
+			for _, protectedWorkload := range protectedWorkloads {
+				Expect(deleteObject(protectedWorkload.Name, cleanupObjects[protectedWorkload.Name])).Should(Succeed(), "Failed to delete workload object %s", protectedWorkload.Name)
+				delete(cleanupObjects, protectedWorkload.Name)
+			}
+
+			expectedLength := 1
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale in to %d", machineSet.GetName(), expectedLength))
+			Eventually(func() (int, error) {
+				machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+				if err != nil {
+					return 0, err
+				}
+
+				return len(machines), nil
+			}, framework.WaitLong, pollingInterval).Should(Equal(expectedLength), "MachineSet %s failed to scale in to %d replicas", machineSet.GetName(), expectedLength)
+		})
 	})
 
 	Context("use a ClusterAutoscaler that has balance similar nodes enabled and 100 maximum total nodes", func() {
@@ -588,7 +978,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 		// Reason: This test starts with 2 machinesets, each with 1 replica to avoid scaling from zero.
 		// Then it autoscales both machinesets to 2 replicas.
 		// Does not start with replicas=0 machineset to avoid scaling from 0.
-		It("places nodes evenly across node groups [Slow]", func() {
+		It("places nodes evenly across node groups [Slow]", framework.LabelMachines(4), func() {
 			By("Creating 2 MachineSets each with 1 replica")
 			var transientMachineSets [2]*machinev1.MachineSet
 			targetedNodeLabel := fmt.Sprintf("%v-balance-nodes", autoscalerWorkerNodeRoleLabel)
@@ -712,29 +1102,39 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			cleanupObjects[workload.GetName()] = workload
 			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
 
-			expectedReplicas := int(2)
+			expectedReplicas := int32(2)
 			By("Waiting for transient MachineSets replicas to scale out")
-			Eventually(func() (map[string]int, error) {
-				allreplicas := map[string]int{}
+			// The cluster autoscaler must balance the nodes while scaling out,
+			// no MachineSet may scale out past the expected replicas.
+			framework.EventuallyWithInvariant(ctx, framework.WaitOverMedium, pollingInterval,
+				func(ctx context.Context, g framework.GomegaAssertions) bool {
+					for _, machineSet := range transientMachineSets {
+						ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
+						if !g.Expect(err).ToNot(HaveOccurred(), "Failed to get MachineSet %s", machineSet.GetName()) {
+							return false
+						}
 
-				for _, machineSet := range transientMachineSets {
-					ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
-					if err != nil {
-						return allreplicas, err
+						if !g.Expect(ms.Spec.Replicas).To(HaveValue(BeNumerically("<=", expectedReplicas)),
+							"Observed replicas in MachineSet %s exceeding the expected replicas of %d", ms.GetName(), expectedReplicas) {
+							return false
+						}
 					}
 
-					replicas := int(ptr.Deref(ms.Spec.Replicas, 0))
-					allreplicas[ms.GetName()] = replicas
+					return true
+				}, func(ctx context.Context, g framework.GomegaAssertions) bool {
+					for _, machineSet := range transientMachineSets {
+						ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
+						if !g.Expect(err).ToNot(HaveOccurred(), "Failed to get MachineSet %s", machineSet.GetName()) {
+							return false
+						}
 
-					if replicas > expectedReplicas {
-						return allreplicas, StopTrying(fmt.Sprintf(
-							"observed %d replicas in MachineSet %s, which exceeds the expected replicas of %d",
-							replicas, ms.GetName(), expectedReplicas))
+						if !g.Expect(ms.Spec.Replicas).To(HaveValue(Equal(expectedReplicas)), "Failed to balance properly") {
+							return false
+						}
 					}
-				}
 
-				return allreplicas, nil
-			}, framework.WaitOverMedium, pollingInterval).Should(HaveEach(expectedReplicas), "Failed to balance properly")
+					return true
+				})
 		})
 	})
 
@@ -796,7 +1196,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 		// Reason: This test starts with 1 replica machineSet. Then it creates a workload that would require 3 replicas,
 		// but it only scales up to 2 replicas because the cluster is at maximum size of 8 machines. (3 masters and 3 other worker machines; 2 workers from this test)
 		// Does not start with replicas=0 machineset to avoid scaling from 0.
-		It("scales up and down while respecting MaxNodesTotal [Slow][Serial]", func() {
+		It("scales up and down while respecting MaxNodesTotal [Slow][Serial]", framework.LabelMachines(2), func() {
 			// This test requires to have exactly 6 machines in the cluster at the beginning and to run serially.
 			By(fmt.Sprintf("Ensuring there are %d machines in the cluster", machinesNumBaseleline))
 			Eventually(func() (int, error) {
@@ -852,11 +1252,20 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			// Because the autoscaler will ignore nodes that are not ready or unschedulable,
 			// we need to check against the number of ready nodes in the cluster since
 			// previous tests might have left nodes that are not ready or unschedulable.
+			// The cluster must never grow past MaxNodesTotal on its way there.
 			By(fmt.Sprintf("Waiting for cluster to scale up to %d nodes", caMaxNodesTotal))
-			Eventually(func() (bool, error) {
-				nodes, err := framework.GetReadyAndSchedulableNodes(client)
-				return len(nodes) == caMaxNodesTotal, err
-			}, framework.WaitOverMedium, pollingInterval).Should(BeTrue(), "Cluster failed to reach %d nodes", caMaxNodesTotal)
+			framework.EventuallyWithInvariant(ctx, framework.WaitOverMedium, pollingInterval,
+				func(ctx context.Context, g framework.GomegaAssertions) bool {
+					nodes, err := framework.GetReadyAndSchedulableNodes(ctx, client)
+
+					return g.Expect(err).ToNot(HaveOccurred(), "Failed to get the ready and schedulable nodes") &&
+						g.Expect(len(nodes)).To(BeNumerically("<=", caMaxNodesTotal), "Cluster grew past %d nodes", caMaxNodesTotal)
+				}, func(ctx context.Context, g framework.GomegaAssertions) bool {
+					nodes, err := framework.GetReadyAndSchedulableNodes(ctx, client)
+
+					return g.Expect(err).ToNot(HaveOccurred(), "Failed to get the ready and schedulable nodes") &&
+						g.Expect(nodes).To(HaveLen(caMaxNodesTotal), "Cluster failed to reach %d nodes", caMaxNodesTotal)
+				})
 
 			// Wait for all nodes to become ready, we wait here to help ensure
 			// that the cluster has reached a steady state and no more machines
@@ -865,15 +1274,23 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			framework.WaitForMachineSet(ctx, client, transientMachineSet.GetName())
 
 			// Now that the cluster has reached maximum size, we want to ensure
-			// that it doesn't try to grow larger.
+			// that it doesn't try to grow larger while the workload still has pending pods.
 			// Because the autoscaler will ignore nodes that are not ready or unschedulable,
 			// we need to check against the number of ready nodes in the cluster since
 			// previous tests might have left nodes that are not ready or unschedulable.
 			By("Watching Cluster node count to ensure it remains consistent")
-			Consistently(func() (int, error) {
-				nodes, err := framework.GetReadyAndSchedulableNodes(client)
-				return len(nodes), err
-			}, framework.WaitShort, pollingInterval).Should(Equal(caMaxNodesTotal), "Cluster failed to stay consistent at %d nodes", caMaxNodesTotal)
+			framework.ConsistentlyWhile(ctx, framework.WaitShort, pollingInterval,
+				func(ctx context.Context, g framework.GomegaAssertions) bool {
+					nodes, err := framework.GetReadyAndSchedulableNodes(ctx, client)
+
+					return g.Expect(err).ToNot(HaveOccurred(), "Failed to get the ready and schedulable nodes") &&
+						g.Expect(nodes).To(HaveLen(caMaxNodesTotal), "Cluster failed to stay consistent at %d nodes", caMaxNodesTotal)
+				}, func(ctx context.Context, g framework.GomegaAssertions) bool {
+					job := &batchv1.Job{}
+
+					return g.Expect(client.Get(ctx, runtimeclient.ObjectKeyFromObject(workload), job)).To(Succeed(), "Failed to get workload %s", workload.GetName()) &&
+						g.Expect(job.Status.Active).To(BeNumerically(">", ptr.Deref(job.Status.Ready, 0)), "Workload %s has no pending pods", workload.GetName())
+				})
 
 			By("Deleting the workload")
 			Expect(deleteObject(workload.Name, cleanupObjects[workload.Name])).Should(Succeed(), "Failed to delete scale-out workload %s", workload.Name)
@@ -882,14 +1299,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			// With the workload gone, the MachineSet should scale back down to
 			// its minimum size of 1.
 			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale down", transientMachineSet.GetName()))
-			Eventually(func() (bool, error) {
-				machineSet, err := framework.GetMachineSet(ctx, client, transientMachineSet.Name)
-				if err != nil {
-					return false, err
-				}
-
-				return ptr.Deref(machineSet.Spec.Replicas, -1) == 1, nil
-			}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "MachineSet %s failed to scale down to 1 replica", transientMachineSet.GetName())
+			framework.WaitForMachineSetReplicas(ctx, client, transientMachineSet, 1, framework.WaitMedium)
 			By(fmt.Sprintf("Waiting for Deleted MachineSet %s nodes to go away", transientMachineSet.GetName()))
 			Eventually(func() (bool, error) {
 				nodes, err := framework.GetNodesFromMachineSet(ctx, client, transientMachineSet)
@@ -948,7 +1358,7 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 		// Does not start with replicas=0 machineset to avoid scaling from 0.
 		// OCP-73446 - Cluster autoscaler support priority expander option
 		// author: zhsun@redhat.com
-		It("high priority machineset should be scaled up first [Slow]", func() {
+		It("high priority machineset should be scaled up first [Slow]", framework.LabelMachines(4), func() {
 			By("Creating 2 MachineSets each with 1 replica")
 			var transientMachineSets [2]*machinev1.MachineSet
 			targetedNodeLabel := fmt.Sprintf("%v-priority-expander", autoscalerWorkerNodeRoleLabel)
@@ -1014,4 +1424,454 @@ var _ = Describe("Autoscaler should", framework.LabelAutoscaler, framework.Label
 			Expect(machineP20.CreationTimestamp.Time).To(BeTemporally("<", machineP10.CreationTimestamp.Time))
 		})
 	})
+
+	Context("use a ClusterAutoscaler that ignores DaemonSets utilization", func() {
+		var clusterAutoscaler *caov1.ClusterAutoscaler
+
+		BeforeEach(func() {
+			gatherer, err = framework.NewGatherer()
+			Expect(err).ToNot(HaveOccurred(), "Failed to create gatherer")
+
+			// The DaemonSet pods alone are above the utilization threshold,
+			// so nodes can only be scaled down if their utilization is ignored.
+			By("Creating ClusterAutoscaler")
+			clusterAutoscaler = clusterAutoscalerResource(100,
+				withUtilizationThreshold("0.1"),
+				withIgnoreDaemonsetsUtilization(true),
+			)
+			Expect(client.Create(ctx, clusterAutoscaler)).Should(Succeed(), "Failed to create ClusterAutoscaler")
+			cleanupObjects[clusterAutoscaler.GetName()] = clusterAutoscaler
+		})
+
+		AfterEach(func() {
+			specReport := CurrentSpecReport()
+			if specReport.Failed() {
+				Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "Failed to gather spec report")
+			}
+
+			// explicitly delete the ClusterAutoscaler
+			// this is needed due to the autoscaler tests requiring singleton
+			// deployments of the ClusterAutoscaler.
+			By("Waiting for ClusterAutoscaler to delete.")
+			caName := clusterAutoscaler.GetName()
+			Expect(deleteObject(caName, cleanupObjects[caName])).Should(Succeed(), "Failed to delete ClusterAutoscaler")
+			delete(cleanupObjects, caName)
+			Eventually(func() (bool, error) {
+				_, err := framework.GetClusterAutoscaler(client, caName)
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				// Return the error so that failures print additional errors
+				return false, err
+			}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "Failed to cleanup Cluster Autoscaler before timeout")
+		})
+
+		// Machines required for test: 2
+		// Reason: Scales 1 -> 2 -> 1 while a DaemonSet runs on every node of the MachineSet.
+		It("scales down nodes running only DaemonSet pods [Slow]", framework.LabelMachines(2), func() {
+			By("Creating MachineSet with 1 replica")
+			targetedNodeLabel := fmt.Sprintf("%v-daemonset-utilization", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 1 replica")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Waiting for the machineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			// The workload requests 70% of the node memory, so the DaemonSet requests 20%.
+			daemonSetMemRequest := resource.NewQuantity(workloadMemRequest.Value()*2/7, resource.BinarySI)
+			daemonSetName := fmt.Sprintf("%s-daemonset", workloadJobName)
+			By(fmt.Sprintf("Creating DaemonSet %s: memory: %s", daemonSetName, daemonSetMemRequest.String()))
+			daemonSet := daemonSetResource(daemonSetName, *daemonSetMemRequest, targetedNodeLabel)
+			Expect(client.Create(ctx, daemonSet)).Should(Succeed(), "Failed to create DaemonSet %s", daemonSetName)
+			cleanupObjects[daemonSet.GetName()] = daemonSet
+
+			expectedReplicas := int32(2)
+			By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: 1, max: %d",
+				machineSet.GetName(), expectedReplicas))
+			asr := machineAutoscalerResource(machineSet, 1, expectedReplicas)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 1/max %d replicas", expectedReplicas)
+			cleanupObjects[asr.GetName()] = asr
+
+			jobReplicas := expectedReplicas
+			uniqueJobName := fmt.Sprintf("%s-daemonset-utilization", workloadJobName)
+			By(fmt.Sprintf("Creating scale-out workload %s: jobs: %v, memory: %s",
+				uniqueJobName, jobReplicas, workloadMemRequest.String()))
+			workload := framework.NewWorkLoad(jobReplicas, workloadMemRequest, uniqueJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out", machineSet.GetName()))
+			Eventually(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(expectedReplicas))),
+				"MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+
+			By("Waiting for all Machines in the MachineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			By("Waiting for the DaemonSet pods to run on every node of the MachineSet")
+			Eventually(komega.Object(daemonSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Status.NumberReady", BeEquivalentTo(expectedReplicas)),
+				"DaemonSet %s should have a ready pod on each of the %d nodes", daemonSetName, expectedReplicas)
+
+			By("Deleting the workload")
+			Expect(deleteObject(workload.Name, cleanupObjects[workload.Name])).Should(Succeed(), "Failed to delete workload object %s", workload.Name)
+			delete(cleanupObjects, workload.Name)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale in despite the DaemonSet pods", machineSet.GetName()))
+			Eventually(func() (int, error) {
+				machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+				if err != nil {
+					return 0, err
+				}
+
+				return len(machines), nil
+			}, framework.WaitLong, pollingInterval).Should(Equal(1), "MachineSet %s failed to scale in to 1 replica", machineSet.GetName())
+		})
+	})
+
+	Context("use a ClusterAutoscaler with workloads bound by CPU or pod count", func() {
+		var clusterAutoscaler *caov1.ClusterAutoscaler
+
+		BeforeEach(func() {
+			gatherer, err = framework.NewGatherer()
+			Expect(err).ToNot(HaveOccurred(), "Failed to create gatherer")
+
+			By("Creating ClusterAutoscaler")
+			clusterAutoscaler = clusterAutoscalerResource(100)
+			Expect(client.Create(ctx, clusterAutoscaler)).Should(Succeed(), "Failed to create ClusterAutoscaler")
+			cleanupObjects[clusterAutoscaler.GetName()] = clusterAutoscaler
+		})
+
+		AfterEach(func() {
+			specReport := CurrentSpecReport()
+			if specReport.Failed() {
+				Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "Failed to gather spec report")
+			}
+
+			// explicitly delete the ClusterAutoscaler
+			// this is needed due to the autoscaler tests requiring singleton
+			// deployments of the ClusterAutoscaler.
+			By("Waiting for ClusterAutoscaler to delete.")
+			caName := clusterAutoscaler.GetName()
+			Expect(deleteObject(caName, cleanupObjects[caName])).Should(Succeed(), "Failed to delete ClusterAutoscaler")
+			delete(cleanupObjects, caName)
+			Eventually(func() (bool, error) {
+				_, err := framework.GetClusterAutoscaler(client, caName)
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				// Return the error so that failures print additional errors
+				return false, err
+			}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "Failed to cleanup Cluster Autoscaler before timeout")
+		})
+
+		// Machines required for test: 2
+		// Reason: Scales 1 -> 2 for a workload whose pods each request 70% of the node CPU.
+		It("scales out for a workload bound by CPU requests [Slow]", framework.LabelMachines(2), func() {
+			By("Creating MachineSet with 1 replica")
+			targetedNodeLabel := fmt.Sprintf("%v-cpu-bound", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 1 replica")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Waiting for the machineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			expectedReplicas := int32(2)
+			By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: 1, max: %d",
+				machineSet.GetName(), expectedReplicas))
+			asr := machineAutoscalerResource(machineSet, 1, expectedReplicas)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 1/max %d replicas", expectedReplicas)
+			cleanupObjects[asr.GetName()] = asr
+
+			jobReplicas := expectedReplicas
+			uniqueJobName := fmt.Sprintf("%s-cpu-bound", workloadJobName)
+			By(fmt.Sprintf("Creating scale-out workload %s: jobs: %v, cpu: %s",
+				uniqueJobName, jobReplicas, workloadCPURequest.String()))
+			workload := framework.NewCPUWorkLoad(jobReplicas, workloadCPURequest, uniqueJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out", machineSet.GetName()))
+			Eventually(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(expectedReplicas))),
+				"MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+
+			By("Waiting for all Machines in the MachineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+
+		// Machines required for test: 2
+		// Reason: Scales 1 -> 2 for a workload with more pods than a node can run.
+		It("scales out for a workload bound by the pod count of the nodes [Slow]", framework.LabelMachines(2), func() {
+			By("Creating MachineSet with 1 replica")
+			targetedNodeLabel := fmt.Sprintf("%v-pod-count-bound", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 1 replica")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Waiting for the machineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the machines of MachineSet %s", machineSet.GetName())
+			Expect(machines).To(HaveLen(1), "MachineSet %s should have 1 machine", machineSet.GetName())
+			node, err := framework.GetNodeForMachine(ctx, client, machines[0])
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the node of machine %s", machines[0].GetName())
+			podCapacity := node.Status.Allocatable.Pods().Value()
+			Expect(podCapacity).To(BeNumerically(">", 0), "Node %s does not advertise an allocatable pod capacity", node.GetName())
+
+			expectedReplicas := int32(2)
+			By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: 1, max: %d",
+				machineSet.GetName(), expectedReplicas))
+			asr := machineAutoscalerResource(machineSet, 1, expectedReplicas)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 1/max %d replicas", expectedReplicas)
+			cleanupObjects[asr.GetName()] = asr
+
+			// As many pods as the node can run, on top of the DaemonSet pods already running
+			// on it, fit on 2 nodes but not on 1.
+			jobReplicas := int32(podCapacity)
+			uniqueJobName := fmt.Sprintf("%s-pod-count-bound", workloadJobName)
+			By(fmt.Sprintf("Creating scale-out workload %s: jobs: %v", uniqueJobName, jobReplicas))
+			workload := framework.NewPodCountWorkLoad(jobReplicas, uniqueJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out", machineSet.GetName()))
+			Eventually(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(expectedReplicas))),
+				"MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+
+			By("Waiting for all Machines in the MachineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+	})
+
+	Context("use a ClusterAutoscaler with a MachineHealthCheck on the autoscaled MachineSet", func() {
+		var clusterAutoscaler *caov1.ClusterAutoscaler
+
+		BeforeEach(func() {
+			gatherer, err = framework.NewGatherer()
+			Expect(err).ToNot(HaveOccurred(), "Failed to create gatherer")
+
+			By("Creating ClusterAutoscaler")
+			clusterAutoscaler = clusterAutoscalerResource(100)
+			Expect(client.Create(ctx, clusterAutoscaler)).Should(Succeed(), "Failed to create ClusterAutoscaler")
+			cleanupObjects[clusterAutoscaler.GetName()] = clusterAutoscaler
+		})
+
+		AfterEach(func() {
+			specReport := CurrentSpecReport()
+			if specReport.Failed() {
+				Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "Failed to gather spec report")
+			}
+
+			// explicitly delete the ClusterAutoscaler
+			// this is needed due to the autoscaler tests requiring singleton
+			// deployments of the ClusterAutoscaler.
+			By("Waiting for ClusterAutoscaler to delete.")
+			caName := clusterAutoscaler.GetName()
+			Expect(deleteObject(caName, cleanupObjects[caName])).Should(Succeed(), "Failed to delete ClusterAutoscaler")
+			delete(cleanupObjects, caName)
+			Eventually(func() (bool, error) {
+				_, err := framework.GetClusterAutoscaler(client, caName)
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+				// Return the error so that failures print additional errors
+				return false, err
+			}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "Failed to cleanup Cluster Autoscaler before timeout")
+		})
+
+		// Machines required for test: 4
+		// Reason: Scales 1 -> 3, then 1 replacement for the machine remediated during the scale out.
+		It("remediates an unhealthy node during a scale out without the autoscaler changing the replicas [Slow]", framework.LabelMachineHealthCheck, framework.LabelMachines(4), func() {
+			By("Creating MachineSet with 1 replica")
+			targetedNodeLabel := fmt.Sprintf("%v-mhc", autoscalerWorkerNodeRoleLabel)
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSetParams.Labels[targetedNodeLabel] = ""
+			machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 1 replica")
+			cleanupObjects[machineSet.GetName()] = machineSet
+
+			By("Waiting for the machineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the machines of MachineSet %s", machineSet.GetName())
+			Expect(machines).To(HaveLen(1), "MachineSet %s should have 1 machine", machineSet.GetName())
+			unhealthyMachine := machines[0]
+
+			// MaxUnhealthy is left unset: the machines of the scale out are counted as unhealthy
+			// until their node joins, which would otherwise restrict the remediation.
+			By("Creating a MachineHealthCheck for the MachineSet")
+			mhc, err := framework.CreateMHC(client, framework.MachineHealthCheckParams{
+				Name:       fmt.Sprintf("%s-mhc", machineSet.GetName()),
+				Labels:     machineSet.Labels,
+				Conditions: framework.E2EUnhealthyConditions(time.Second),
+			})
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineHealthCheck for MachineSet %s", machineSet.GetName())
+			cleanupObjects[mhc.GetName()] = mhc
+
+			expectedReplicas := int32(3)
+			By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: 1, max: %d",
+				machineSet.GetName(), expectedReplicas))
+			asr := machineAutoscalerResource(machineSet, 1, expectedReplicas)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler with min 1/max %d replicas", expectedReplicas)
+			cleanupObjects[asr.GetName()] = asr
+
+			jobReplicas := expectedReplicas
+			uniqueJobName := fmt.Sprintf("%s-mhc", workloadJobName)
+			By(fmt.Sprintf("Creating scale-out workload %s: jobs: %v, memory: %s",
+				uniqueJobName, jobReplicas, workloadMemRequest.String()))
+			workload := framework.NewWorkLoad(jobReplicas, workloadMemRequest, uniqueJobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			cleanupObjects[workload.GetName()] = workload
+			Expect(client.Create(ctx, workload)).Should(Succeed(), "Failed to create scale-out workload %s", uniqueJobName)
+
+			By(fmt.Sprintf("Waiting for MachineSet %s replicas to scale out", machineSet.GetName()))
+			Eventually(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(expectedReplicas))),
+				"MachineSet %s failed to scale out to %d replicas", machineSet.GetName(), expectedReplicas)
+
+			// The node is marked unhealthy while the machines of the scale out are still provisioning.
+			By(fmt.Sprintf("Setting an unhealthy condition on the node of machine %s during the scale out", unhealthyMachine.GetName()))
+			node, err := framework.GetNodeForMachine(ctx, client, unhealthyMachine)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the node of machine %s", unhealthyMachine.GetName())
+			Expect(framework.AddNodeCondition(client, node, framework.NewE2EUnhealthyNodeCondition())).To(Succeed(), "Failed to add the unhealthy condition to node %s", node.GetName())
+
+			// The MachineSet replaces the remediated machine, the autoscaler must neither scale
+			// out for the evicted workload pod nor scale in the node of the remediated machine.
+			By(fmt.Sprintf("Waiting for machine %s to be remediated while the MachineSet replicas stay at %d", unhealthyMachine.GetName(), expectedReplicas))
+			var observedReplicas []int32
+			Eventually(func() (bool, error) {
+				current := &machinev1.MachineSet{}
+				if err := client.Get(ctx, runtimeclient.ObjectKeyFromObject(machineSet), current); err != nil {
+					return false, err
+				}
+				observedReplicas = append(observedReplicas, ptr.Deref(current.Spec.Replicas, 0))
+
+				err := client.Get(ctx, runtimeclient.ObjectKeyFromObject(unhealthyMachine), &machinev1.Machine{})
+				if apierrors.IsNotFound(err) {
+					return true, nil
+				}
+
+				return false, err
+			}, framework.WaitLong, pollingInterval).Should(BeTrue(), "Machine %s failed to be remediated", unhealthyMachine.GetName())
+			Expect(observedReplicas).To(HaveEach(BeEquivalentTo(expectedReplicas)),
+				"MachineSet %s replicas should not change during the remediation", machineSet.GetName())
+
+			By("Waiting for all Machines in the MachineSet to enter Running phase")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			By(fmt.Sprintf("Verifying the MachineSet %s replicas stay at %d", machineSet.GetName(), expectedReplicas))
+			Consistently(komega.Object(machineSet), framework.WaitShort, pollingInterval).Should(
+				HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(expectedReplicas))),
+				"MachineSet %s replicas should stay at %d once the remediation is over", machineSet.GetName(), expectedReplicas)
+
+			machines, err = framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get the machines of MachineSet %s", machineSet.GetName())
+			Expect(machines).To(HaveLen(int(expectedReplicas)), "MachineSet %s should have %d machines", machineSet.GetName(), expectedReplicas)
+		})
+	})
+
+	Context("validate MachineAutoscalers", func() {
+		var machineSet *machinev1.MachineSet
+
+		BeforeEach(func() {
+			By("Creating a new MachineSet with 0 replicas")
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 0)
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with 0 replicas")
+			cleanupObjects[machineSet.GetName()] = machineSet
+		})
+
+		// Machines required for test: 0
+		// Reason: The MachineAutoscaler is rejected before the MachineSet is scaled.
+		It("reject a MachineAutoscaler with min replicas greater than max replicas", framework.LabelMachines(0), func() {
+			asr := machineAutoscalerResource(machineSet, 2, 1)
+			err := client.Create(ctx, asr)
+			if err == nil {
+				cleanupObjects[asr.GetName()] = asr
+			}
+
+			Expect(err).To(HaveOccurred(), "MachineAutoscaler with min 2/max 1 replicas should be rejected")
+		})
+
+		// Machines required for test: 0
+		// Reason: The MachineSet is never scaled.
+		It("surface an error for a MachineAutoscaler targeting a non-existent MachineSet", framework.LabelMachines(0), func() {
+			missingMachineSet := machineSet.DeepCopy()
+			missingMachineSet.Name = framework.Names.GenerateName("missing-machineset-")
+
+			asr := machineAutoscalerResource(missingMachineSet, 0, 1)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler")
+			cleanupObjects[asr.GetName()] = asr
+
+			By("Waiting for a warning event for the MachineAutoscaler")
+			Expect(framework.WaitForWarningEvent(ctx, client, "MachineAutoscaler", asr.GetName())).To(Succeed(),
+				"MachineAutoscaler %s should have a warning event", asr.GetName())
+
+			Consistently(komega.Object(asr), framework.WaitShort, pollingInterval).Should(
+				HaveField("Status.LastTargetRef", BeNil()),
+				"MachineAutoscaler %s should not record a last target", asr.GetName())
+		})
+
+		// Machines required for test: 0
+		// Reason: The MachineSet is never scaled.
+		It("not let a second MachineAutoscaler take over a MachineSet already targeted", framework.LabelMachines(0), func() {
+			By("Creating a MachineAutoscaler - min: 0, max: 1")
+			asr := machineAutoscalerResource(machineSet, 0, 1)
+			Expect(client.Create(ctx, asr)).Should(Succeed(), "Failed to create MachineAutoscaler")
+			cleanupObjects[asr.GetName()] = asr
+
+			Eventually(komega.Object(machineSet), framework.WaitMedium, pollingInterval).Should(
+				HaveField("ObjectMeta.Annotations", SatisfyAll(
+					HaveKeyWithValue(caMinSizeAnnotation, "0"),
+					HaveKeyWithValue(caMaxSizeAnnotation, "1"),
+				)), "MachineSet %s should have the size of the first MachineAutoscaler", machineSet.GetName())
+
+			Eventually(komega.Object(asr), framework.WaitMedium, pollingInterval).Should(
+				HaveField("Status.LastTargetRef", HaveValue(HaveField("Name", machineSet.GetName()))),
+				"MachineAutoscaler %s should record its target", asr.GetName())
+
+			By("Creating a second MachineAutoscaler for the same MachineSet - min: 0, max: 3")
+			duplicateAsr := machineAutoscalerResource(machineSet, 0, 3)
+			err := client.Create(ctx, duplicateAsr)
+			if err != nil {
+				// The duplicate MachineAutoscaler was rejected up front.
+				return
+			}
+
+			cleanupObjects[duplicateAsr.GetName()] = duplicateAsr
+
+			By("Waiting for a warning event for the second MachineAutoscaler")
+			Expect(framework.WaitForWarningEvent(ctx, client, "MachineAutoscaler", duplicateAsr.GetName())).To(Succeed(),
+				"MachineAutoscaler %s should have a warning event", duplicateAsr.GetName())
+
+			Consistently(komega.Object(machineSet), framework.WaitShort, pollingInterval).Should(
+				HaveField("ObjectMeta.Annotations", SatisfyAll(
+					HaveKeyWithValue(caMinSizeAnnotation, "0"),
+					HaveKeyWithValue(caMaxSizeAnnotation, "1"),
+				)), "MachineSet %s should keep the size of the first MachineAutoscaler", machineSet.GetName())
+		})
+	})
 })
@@ -0,0 +1,211 @@
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// soakMinReplicas and soakMaxReplicas are the bounds the soak MachineSet is scaled between on each cycle.
+	soakMinReplicas = 1
+	soakMaxReplicas = 3
+
+	// soakMaxAPIErrorRate is the fraction of the API calls of the soak spec allowed to fail.
+	soakMaxAPIErrorRate = 0.01
+)
+
+// clusterAutoscalerPodLabels select the pods of the cluster autoscaler deployed for the default ClusterAutoscaler.
+var clusterAutoscalerPodLabels = map[string]string{"cluster-autoscaler": "default"}
+
+var _ = Describe("Autoscaler soak should", framework.LabelAutoscaler, framework.LabelDisruptive, framework.LabelPeriodic, framework.LabelSoak, framework.LabelRequiresMachineManagement, Serial, func() {
+	var client runtimeclient.Client
+	var gatherer *gatherer.StateGatherer
+	var machineSet *machinev1.MachineSet
+	var workloadMemRequest resource.Quantity
+
+	var ctx context.Context
+	cascadeDelete := metav1.DeletePropagationForeground
+	targetedNodeLabel := fmt.Sprintf("%v-soak", autoscalerWorkerNodeRoleLabel)
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		client, err = framework.LoadClient()
+		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes client for test")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to create gatherer")
+
+		workerNodes, err := framework.GetWorkerNodes(ctx, client)
+		Expect(err).NotTo(HaveOccurred(), "Failed to get worker Node objects")
+		Expect(workerNodes).ToNot(BeEmpty(), "Expected >= 1 worker node")
+
+		// 70% of the memory, so that each node runs a single pod of the workload.
+		memCapacity := workerNodes[0].Status.Allocatable[corev1.ResourceMemory]
+		bytes, ok := memCapacity.AsInt64()
+		Expect(ok).Should(BeTrue(), "Failed to convert allocatable memory capacity into byte count as Int64, capacity is %v", memCapacity)
+		workloadMemRequest = resource.MustParse(fmt.Sprintf("%v", 0.7*float32(bytes)))
+
+		By("Creating ClusterAutoscaler")
+		clusterAutoscaler := clusterAutoscalerResource(100)
+		Expect(client.Create(ctx, clusterAutoscaler)).Should(Succeed(), "Failed to create ClusterAutoscaler")
+
+		DeferCleanup(func() {
+			By("Deleting ClusterAutoscaler")
+			Expect(client.Delete(ctx, clusterAutoscaler)).To(Succeed(), "Failed to delete ClusterAutoscaler")
+		})
+
+		By(fmt.Sprintf("Creating a MachineSet with %d replica", soakMinReplicas))
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, soakMinReplicas)
+		machineSetParams.Labels[targetedNodeLabel] = ""
+		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet")
+
+		DeferCleanup(func() {
+			By("Deleting the MachineSet")
+			Expect(client.Delete(ctx, machineSet, &runtimeclient.DeleteOptions{PropagationPolicy: &cascadeDelete})).To(Succeed(), "Failed to delete MachineSet")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By(fmt.Sprintf("Creating a MachineAutoscaler backed by MachineSet %s - min: %d, max: %d", machineSet.GetName(), soakMinReplicas, soakMaxReplicas))
+		machineAutoscaler := machineAutoscalerResource(machineSet, soakMinReplicas, soakMaxReplicas)
+		Expect(client.Create(ctx, machineAutoscaler)).Should(Succeed(), "Failed to create MachineAutoscaler")
+
+		DeferCleanup(func() {
+			By("Deleting the MachineAutoscaler")
+			Expect(client.Delete(ctx, machineAutoscaler)).To(Succeed(), "Failed to delete MachineAutoscaler")
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "Failed to gather spec report")
+		}
+	})
+
+	// Machines required for test: 2
+	// Reason: The MachineSet is scaled from 1 to 3 replicas and back on each cycle, for framework.SoakDuration (2h by default).
+	It("scale up and down repeatedly without leaking machines [Slow]", framework.LabelMachines(2), func() {
+		report := &framework.SoakReport{}
+		restarts := framework.NewPodRestartTracker(clusterAutoscalerPodLabels)
+
+		// poll waits for the condition, recording the API calls it makes rather than failing on the first error.
+		poll := func(timeout time.Duration, condition func(ctx context.Context) (bool, error)) error {
+			return wait.PollUntilContextTimeout(ctx, pollingInterval, timeout, true, func(ctx context.Context) (bool, error) {
+				done, err := condition(ctx)
+				report.RecordAPICall(err)
+
+				if err != nil {
+					klog.Warningf("[soak] API call failed: %v", err)
+				}
+
+				return done && err == nil, nil
+			})
+		}
+
+		// The ClusterAutoscaler deployment may take a while to start after the ClusterAutoscaler is created.
+		Expect(poll(framework.WaitMedium, func(ctx context.Context) (bool, error) {
+			pods, err := framework.GetPods(ctx, client, clusterAutoscalerPodLabels)
+			return err == nil && len(pods.Items) > 0, err
+		})).To(Succeed(), "Cluster autoscaler should be running")
+		Expect(restarts.Sample(ctx, client)).To(Succeed(), "Should be able to list the cluster autoscaler pods")
+
+		replicasAre := func(replicas int32) func(ctx context.Context) (bool, error) {
+			return func(ctx context.Context) (bool, error) {
+				current, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
+				if err != nil {
+					return false, err
+				}
+
+				return ptr.Deref(current.Spec.Replicas, -1) == replicas, nil
+			}
+		}
+
+		start := time.Now()
+
+		for cycle := 1; time.Since(start) < framework.SoakDuration; cycle++ {
+			jobName := fmt.Sprintf("%s-soak-%d", workloadJobName, cycle)
+
+			By(fmt.Sprintf("Cycle %d: creating scale-out workload %s", cycle, jobName))
+			workload := framework.NewWorkLoad(soakMaxReplicas, workloadMemRequest, jobName, autoscalingTestLabel, "", corev1.NodeSelectorRequirement{
+				Key:      targetedNodeLabel,
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			Expect(client.Create(ctx, workload)).To(Succeed(), "Failed to create workload %s", jobName)
+
+			DeferCleanup(func() {
+				Expect(runtimeclient.IgnoreNotFound(client.Delete(ctx, workload, &runtimeclient.DeleteOptions{PropagationPolicy: &cascadeDelete}))).
+					To(Succeed(), "Failed to delete workload %s", jobName)
+			})
+
+			By(fmt.Sprintf("Cycle %d: waiting for MachineSet %s to scale up to %d replicas", cycle, machineSet.GetName(), soakMaxReplicas))
+			Expect(poll(framework.WaitMedium, replicasAre(soakMaxReplicas))).To(Succeed(),
+				"MachineSet %s should scale up to %d replicas on cycle %d", machineSet.GetName(), soakMaxReplicas, cycle)
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			By(fmt.Sprintf("Cycle %d: deleting workload %s", cycle, jobName))
+			Expect(client.Delete(ctx, workload, &runtimeclient.DeleteOptions{PropagationPolicy: &cascadeDelete})).To(Succeed(), "Failed to delete workload %s", jobName)
+
+			By(fmt.Sprintf("Cycle %d: waiting for MachineSet %s to scale down to %d replica", cycle, machineSet.GetName(), soakMinReplicas))
+			Expect(poll(framework.WaitLong, replicasAre(soakMinReplicas))).To(Succeed(),
+				"MachineSet %s should scale down to %d replica on cycle %d", machineSet.GetName(), soakMinReplicas, cycle)
+
+			// The machines and nodes left over once the scale down has settled are leaks, recorded rather
+			// than failing the cycle, so that a growing leak can be told from a transient one.
+			leaked := 0
+			_ = poll(framework.WaitLong, func(ctx context.Context) (bool, error) {
+				machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+				if err != nil {
+					return false, err
+				}
+
+				nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+				if err != nil {
+					return false, err
+				}
+
+				leaked = max(len(machines), len(nodes)) - soakMinReplicas
+
+				return leaked <= 0, nil
+			})
+
+			if err := restarts.Sample(ctx, client); err != nil {
+				report.RecordAPICall(err)
+			}
+
+			report.RecordRestarts(restarts.Restarts())
+			report.RecordCycle(max(leaked, 0))
+
+			klog.Infof("[soak] cycle %d done after %s:\n%s", cycle, time.Since(start).Round(time.Second), report.Format())
+		}
+
+		AddReportEntry("Autoscaler soak", report.Format())
+
+		Expect(report.Cycles).To(BeNumerically(">", 0), "At least one cycle should have completed")
+		Expect(report.LeakedMachines).To(BeZero(), "No machine should be left over after the last cycle")
+		Expect(report.Restarts).To(BeZero(), "Cluster autoscaler should not restart")
+		Expect(report.APIErrorRate()).To(BeNumerically("<=", soakMaxAPIErrorRate), "API calls should not fail more than %.0f%% of the time", 100*soakMaxAPIErrorRate)
+	})
+})
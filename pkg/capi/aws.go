@@ -2,15 +2,24 @@ package capi
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/ec2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	configv1 "github.com/openshift/api/config/v1"
 	mapiv1 "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/chaos"
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
 	capiinfrastructurev1beta2resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/infrastructure/v1beta2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	awsv1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -27,10 +36,10 @@ const (
 	infraAPIVersion        = "infrastructure.cluster.x-k8s.io/v1beta1"
 )
 
-var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.LabelDisruptive, Ordered, func() {
+var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.LabelDisruptive, framework.LabelTechPreview, framework.LabelPlatforms(configv1.AWSPlatformType), framework.LabelRequiresMachineManagement, Ordered, func() {
 	var (
 		cl                      client.Client
-		ctx                     = context.Background()
+		ctx                     context.Context
 		platform                configv1.PlatformType
 		clusterName             string
 		oc                      *gatherer.CLI
@@ -38,10 +47,13 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 		machineSetParams        framework.CAPIMachineSetParams
 		machineSet              *clusterv1.MachineSet
 		mapiDefaultProviderSpec *mapiv1.AWSMachineProviderConfig
+		zoneNetwork             framework.ZoneNetwork
 		err                     error
 	)
 
 	BeforeAll(func() {
+		ctx = framework.GetContext()
+
 		cfg, err := config.GetConfig()
 		Expect(err).ToNot(HaveOccurred(), "Failed to GetConfig")
 
@@ -63,6 +75,10 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 		Expect(err).ToNot(HaveOccurred(), "Failed to new CLI")
 		framework.SkipIfNotTechPreviewNoUpgrade(oc, cl)
 		_, mapiDefaultProviderSpec = getDefaultAWSMAPIProviderSpec(cl)
+		network, err := framework.DiscoverNetwork(ctx, cl, platform)
+		Expect(err).ToNot(HaveOccurred(), "Failed to discover the cluster network")
+		zoneNetwork, err = network.ForZone(mapiDefaultProviderSpec.Placement.AvailabilityZone)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the network of the default zone")
 		machineSetParams = framework.NewCAPIMachineSetParams(
 			"aws-machineset",
 			clusterName,
@@ -78,24 +94,207 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 	})
 
 	AfterEach(func() {
-		framework.DeleteCAPIMachineSets(ctx, cl, machineSet)
+		Expect(framework.DeleteCAPIMachineSets(ctx, cl, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
 		framework.WaitForCAPIMachineSetsDeleted(ctx, cl, machineSet)
 		framework.DeleteObjects(ctx, cl, awsMachineTemplate)
 	})
 
 	//huliu-OCP-51071 - [CAPI] Create machineset with CAPI on aws
-	It("should be able to run a machine with a default provider spec", func() {
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
+	It("should be able to run a machine with a default provider spec", framework.LabelChaos, func() {
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
 		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+
+		providerController, _ := chaos.CAPIProviderController(platform)
+		stopChaos := chaos.During(ctx, cl, chaos.CAPIController, providerController)
+
 		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-51071", machineSetParams)
 		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
 		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
 		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		stopChaos()
+	})
+
+	// [CAPI] A paused MachineSet should not be reconciled until it is unpaused.
+	It("should not create machines while the machineset is paused", func() {
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		pausedMachineSetParams := framework.NewCAPIMachineSetParams(
+			"aws-machineset-paused",
+			clusterName,
+			mapiDefaultProviderSpec.Placement.AvailabilityZone,
+			0,
+			corev1.ObjectReference{
+				Kind:       "AWSMachineTemplate",
+				APIVersion: infraAPIVersion,
+				Name:       awsMachineTemplateName,
+			},
+		)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, pausedMachineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+
+		framework.PauseCAPIMachineSet(ctx, cl, machineSet)
+		framework.ScaleCAPIMachineSet(ctx, cl, machineSet.Name, 1)
+		framework.ExpectNoNewCAPIMachines(ctx, cl, machineSet, 0)
+
+		framework.UnpauseCAPIMachineSet(ctx, cl, machineSet)
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+	})
+
+	// [CAPI] A machineset can be scaled up and down through its scale subresource.
+	It("should scale a machineset through its scale subresource", func() {
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, framework.UpdateCAPIMachineSetName("aws-machineset-scale", machineSetParams))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		framework.ScaleCAPIMachineSetWithSubresource(ctx, machineSet.Name, 3)
+		framework.WaitForCAPIMachineSetScale(ctx, cl, machineSet.Name, 3)
+
+		framework.ScaleCAPIMachineSetWithSubresource(ctx, machineSet.Name, 0)
+		framework.WaitForCAPIMachineSetScale(ctx, cl, machineSet.Name, 0)
+	})
+
+	// [CAPI] Machine deletion proceeds once the node drain timeout is exceeded.
+	It("should delete a machine whose node cannot be drained once the drain timeout is exceeded", func() {
+		const nodeDrainTimeout = 2 * time.Minute
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-drain-timeout", machineSetParams)
+		machineSetParams = framework.UpdateCAPIMachineSetNodeDrainTimeout(nodeDrainTimeout, machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines")
+		Expect(machines).To(HaveLen(1), "Expected a single CAPI machine")
+		machine := machines[0]
+
+		node, err := framework.GetCAPINodeForMachine(ctx, cl, machine)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+
+		By("Creating a pod which cannot be evicted from the node")
+		// The openshift-machine-api namespace is excluded from Pod security admission checks.
+		podLabels := map[string]string{"app": "drain-timeout-" + machine.Name}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "drain-timeout-" + machine.Name,
+				Namespace: framework.MachineAPINamespace,
+				Labels:    podLabels,
+			},
+			Spec: corev1.PodSpec{
+				NodeName: node.Name,
+				Containers: []corev1.Container{{
+					Name:    "work",
+					Image:   "registry.ci.openshift.org/openshift/origin-v4.0:base",
+					Command: []string{"sleep", "10h"},
+				}},
+			},
+		}
+		maxUnavailable := intstr.FromInt(0)
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "drain-timeout-" + machine.Name,
+				Namespace: framework.MachineAPINamespace,
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector:       &metav1.LabelSelector{MatchLabels: podLabels},
+				MaxUnavailable: &maxUnavailable,
+			},
+		}
+		Expect(cl.Create(ctx, pdb)).To(Succeed(), "Failed to create PodDisruptionBudget")
+		DeferCleanup(framework.DeleteObjects, ctx, cl, pdb)
+		Expect(cl.Create(ctx, pod)).To(Succeed(), "Failed to create pod")
+		DeferCleanup(func() {
+			// The pod is usually gone with its node.
+			Expect(client.IgnoreNotFound(cl.Delete(ctx, pod))).To(Succeed(), "Failed to delete pod")
+		})
+
+		Eventually(ctx, func() (corev1.PodPhase, error) {
+			if err := cl.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+				return "", err
+			}
+
+			return pod.Status.Phase, nil
+		}, framework.WaitMedium, framework.RetryMedium).Should(Equal(corev1.PodRunning), "The pod should be running")
+
+		By("Deleting the machine")
+		deletionStart := time.Now()
+		Expect(cl.Delete(ctx, machine)).To(Succeed(), "Failed to delete CAPI machine")
+
+		By("Verifying the drain is blocked by the PodDisruptionBudget")
+		framework.WaitForCAPIMachineDrainBlocked(ctx, cl, machine)
+
+		By("Verifying the machine is deleted once the drain timeout is exceeded")
+		Eventually(ctx, func() bool {
+			return apierrors.IsNotFound(cl.Get(ctx, client.ObjectKeyFromObject(machine), &clusterv1.Machine{}))
+		}, nodeDrainTimeout+framework.WaitLong, framework.RetryMedium).Should(BeTrue(), "The CAPI machine should have been deleted")
+		Expect(time.Since(deletionStart)).To(BeNumerically(">=", nodeDrainTimeout), "The CAPI machine should not be deleted before the drain timeout")
+	})
+
+	// [CAPI] The node labels of the machineset are synced to the node, without dropping the taints added to the node.
+	// Taints cannot be set from the machineset: this version of the Cluster API has no taints in the machine spec,
+	// and the bootstrap data of OpenShift does not come from a bootstrap provider which could register the node with them.
+	It("should sync the node labels of the machineset to the node and keep the taints added to the node", func() {
+		const nodeLabelKey = "node.cluster.x-k8s.io/e2e"
+
+		userTaint := corev1.Taint{
+			Key:    "e2e.openshift.io/user-taint",
+			Value:  framework.Rand.String(8),
+			Effect: corev1.TaintEffectPreferNoSchedule,
+		}
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		labelsMachineSetParams := framework.UpdateCAPIMachineSetName("aws-machineset-node-sync", machineSetParams)
+		labelsMachineSetParams = framework.UpdateCAPIMachineSetLabels(map[string]string{nodeLabelKey: "initial"}, labelsMachineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, labelsMachineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines")
+		Expect(machines).To(HaveLen(1), "Expected a single CAPI machine")
+		machine := machines[0]
+
+		getNode := func() (*corev1.Node, error) {
+			return framework.GetCAPINodeForMachine(ctx, cl, machine)
+		}
+
+		By("Verifying the node label of the machineset is synced to the node")
+		Eventually(ctx, getNode, framework.WaitMedium, framework.RetryMedium).Should(
+			HaveField("Labels", HaveKeyWithValue(nodeLabelKey, "initial")), "The node label should be synced to the node")
+
+		By("Adding a taint to the node")
+		node, err := getNode()
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Spec.Taints = append(node.Spec.Taints, userTaint)
+		Expect(cl.Patch(ctx, node, patch)).To(Succeed(), "Failed to add the taint to the node")
+
+		By("Updating the node label of the machineset in place")
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(machineSet), machineSet)).To(Succeed(), "Failed to get CAPI machineset")
+		patch = client.MergeFrom(machineSet.DeepCopy())
+		machineSet.Spec.Template.Labels[nodeLabelKey] = "updated"
+		Expect(cl.Patch(ctx, machineSet, patch)).To(Succeed(), "Failed to update the node label of the CAPI machineset")
+
+		By("Verifying the node is synced again and keeps its taint")
+		Eventually(ctx, getNode, framework.WaitMedium, framework.RetryMedium).Should(
+			HaveField("Labels", HaveKeyWithValue(nodeLabelKey, "updated")), "The updated node label should be synced to the node")
+		Consistently(ctx, getNode, framework.WaitShort, framework.RetryShort).Should(
+			HaveField("Spec.Taints", ContainElement(userTaint)), "The taint added to the node should survive the node sync")
+
+		machines, err = framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines")
+		Expect(machines).To(ConsistOf(HaveField("Name", machine.Name)), "The machine should be updated in place rather than replaced")
 	})
 
 	//huliu-OCP-75395 - [CAPI] AWS Placement group support.
 	It("should be able to run a machine with cluster placement group", func() {
-		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc))
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
 		placementGroupName := clusterName + "pgcluster"
 		placementGroupID, err := awsClient.CreatePlacementGroup(placementGroupName, "cluster")
 		Expect(err).ToNot(HaveOccurred(), "Failed to create placementgroup")
@@ -105,7 +304,7 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 			Expect(err).ToNot(HaveOccurred(), "Failed to delete placementgroup")
 		})
 
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
 		awsMachineTemplate.Spec.Template.Spec.PlacementGroupName = placementGroupName
 		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
 		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-75395", machineSetParams)
@@ -114,10 +313,60 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
 	})
 
+	// [CAPI] AWS partition placement group support.
+	It("should be able to run a machine in a given partition of a partition placement group", func() {
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		placementGroupName := clusterName + "pgpartition"
+		placementGroupID, err := awsClient.CreatePlacementGroup(placementGroupName, ec2.PlacementStrategyPartition, 3)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create placementgroup")
+		Expect(placementGroupID).ToNot(Equal(""), "expected the placementGroupID to not be empty string")
+		DeferCleanup(func() {
+			_, err = awsClient.DeletePlacementGroup(placementGroupName)
+			Expect(err).ToNot(HaveOccurred(), "Failed to delete placementgroup")
+		})
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.PlacementGroupName = placementGroupName
+		awsMachineTemplate.Spec.Template.Spec.PlacementGroupPartition = 2
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-pgpartition", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		Expect(ptr.Deref(instance.Placement.GroupName, "")).To(Equal(placementGroupName), "expected the instance to be in the placement group")
+		Expect(ptr.Deref(instance.Placement.PartitionNumber, 0)).To(BeEquivalentTo(2), "expected the instance to be in partition 2")
+	})
+
+	// [CAPI] AWS spread placement group support.
+	It("should be able to run a machine with spread placement group", func() {
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		placementGroupName := clusterName + "pgspread"
+		placementGroupID, err := awsClient.CreatePlacementGroup(placementGroupName, ec2.PlacementStrategySpread)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create placementgroup")
+		Expect(placementGroupID).ToNot(Equal(""), "expected the placementGroupID to not be empty string")
+		DeferCleanup(func() {
+			_, err = awsClient.DeletePlacementGroup(placementGroupName)
+			Expect(err).ToNot(HaveOccurred(), "Failed to delete placementgroup")
+		})
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.PlacementGroupName = placementGroupName
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-pgspread", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		Expect(ptr.Deref(instance.Placement.GroupName, "")).To(Equal(placementGroupName), "expected the instance to be in the placement group")
+	})
+
 	//huliu-OCP-75396 - [CAPI] Creating machines using KMS keys from AWS.
 	It("should be able to run a machine using KMS keys", framework.LabelQEOnly, func() {
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
-		awskmsClient := framework.NewAwsKmsClient(framework.GetCredentialsFromCluster(oc))
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awskmsClient := framework.NewAwsKmsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
 		key, err := awskmsClient.CreateKey(infrastructureName + " key 75396")
 		if err != nil {
 			Skip("Create key failed, skip the cases!!")
@@ -143,11 +392,41 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
 		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
 		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		expectVolumeEncryptedWithKey(awsClient, instance, "/dev/xvda", key)
+	})
+
+	// [CAPI] Encrypted volumes without a key should use the default EBS KMS key of the account.
+	It("should be able to run a machine with a volume encrypted with the default KMS key", framework.LabelQEOnly, func() {
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		awskmsClient := framework.NewAwsKmsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+
+		defaultKeyID, err := awsClient.GetEBSDefaultKMSKeyID()
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the default EBS KMS key")
+		defaultKey, err := awskmsClient.GetKeyARN(defaultKeyID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the ARN of the default EBS KMS key")
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.RootVolume = &awsv1.Volume{
+			Size:      120,
+			Type:      awsv1.VolumeTypeGP3,
+			Encrypted: ptr.To(true),
+		}
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-default-kms", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		expectVolumeEncryptedWithKey(awsClient, instance, ptr.Deref(instance.RootDeviceName, ""), defaultKey)
 	})
 
 	//OCP-78677 - [CAPI] Dedicated tenancy should be exposed on aws providerspec.
 	It("should be able to run a machine with dedicated instance", func() {
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
 		awsMachineTemplate.Spec.Template.Spec.Tenancy = "dedicated"
 		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
 		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-78677", machineSetParams)
@@ -156,9 +435,134 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
 	})
 
+	// [CAPI] A custom security group referenced by ID should be attached to the network interface of the instance.
+	It("should be able to run a machine with a custom security group", func() {
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		vpcID, err := awsClient.GetSubnetVPCID(zoneNetwork.SubnetID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the VPC of the subnet")
+		groupID, err := awsClient.CreateSecurityGroup("cluster-api-e2e-"+framework.Rand.String(8), vpcID, "cluster-api e2e custom security group")
+		Expect(err).ToNot(HaveOccurred(), "Failed to create the custom security group")
+		// Cleanup runs after the AfterEach deleting the machineset, but the
+		// network interfaces may take a while to be released from the group.
+		DeferCleanup(func() {
+			Eventually(func() error {
+				return awsClient.DeleteSecurityGroup(groupID)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to delete the custom security group")
+		})
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.AdditionalSecurityGroups = append(awsMachineTemplate.Spec.Template.Spec.AdditionalSecurityGroups,
+			awsv1.AWSResourceReference{ID: ptr.To(groupID)})
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-custom-sg", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		Expect(framework.AWSInstanceENISecurityGroupIDs(instance)).To(ContainElement(groupID), "expected the network interface of the instance to carry the custom security group")
+	})
+
+	// [CAPI] Additional security groups may mix references by ID and by filters.
+	It("should be able to run a machine with additional security groups referenced both by ID and by filters", func() {
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		vpcID, err := awsClient.GetSubnetVPCID(zoneNetwork.SubnetID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the VPC of the subnet")
+		groupName := "cluster-api-e2e-" + framework.Rand.String(8)
+		groupID, err := awsClient.CreateSecurityGroup(groupName, vpcID, "cluster-api e2e custom security group")
+		Expect(err).ToNot(HaveOccurred(), "Failed to create the custom security group")
+		// Cleanup runs after the AfterEach deleting the machineset, but the
+		// network interfaces may take a while to be released from the group.
+		DeferCleanup(func() {
+			Eventually(func() error {
+				return awsClient.DeleteSecurityGroup(groupID)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to delete the custom security group")
+		})
+
+		// The security groups of the zone are referenced by ID, the custom one by filters.
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.AdditionalSecurityGroups = append(awsMachineTemplate.Spec.Template.Spec.AdditionalSecurityGroups,
+			awsv1.AWSResourceReference{
+				Filters: []awsv1.Filter{
+					{Name: "vpc-id", Values: []string{vpcID}},
+					{Name: "group-name", Values: []string{groupName}},
+				},
+			})
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-mixed-sg", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		Expect(framework.AWSInstanceENISecurityGroupIDs(instance)).To(ContainElements(append(slices.Clone(zoneNetwork.SecurityGroupIDs), groupID)),
+			"expected the network interface of the instance to carry the security groups referenced both by ID and by filters")
+	})
+
+	// [CAPI] A pre-created network interface with secondary private IPs should be attached as the primary interface of the instance.
+	It("should be able to run a machine with a pre-created network interface with secondary private IPs", func() {
+		const secondaryPrivateIPCount = 2
+
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		// The awsmachine has no field for the secondary private IPs, so they are set on the network interface.
+		networkInterfaceID, err := awsClient.CreateNetworkInterface(zoneNetwork.SubnetID, zoneNetwork.SecurityGroupIDs, secondaryPrivateIPCount,
+			"cluster-api e2e pre-created network interface")
+		Expect(err).ToNot(HaveOccurred(), "Failed to create the network interface")
+		// Cleanup runs after the AfterEach deleting the machineset, but the
+		// network interface may take a while to be detached from the terminated instance.
+		DeferCleanup(func() {
+			Eventually(func() error {
+				return awsClient.DeleteNetworkInterface(networkInterfaceID)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to delete the network interface")
+		})
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.NetworkInterfaces = []string{networkInterfaceID}
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-eni", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		networkInterface := framework.AWSInstanceNetworkInterface(instance, networkInterfaceID)
+		Expect(networkInterface).ToNot(BeNil(), "expected the pre-created network interface to be attached to the instance")
+		Expect(networkInterface.Attachment).ToNot(BeNil(), "expected the pre-created network interface to have an attachment")
+		Expect(ptr.Deref(networkInterface.Attachment.DeviceIndex, -1)).To(BeEquivalentTo(0), "expected the pre-created network interface to be the primary interface of the instance")
+		Expect(networkInterface.PrivateIpAddresses).To(HaveLen(1+secondaryPrivateIPCount), "expected the network interface to carry the primary and the secondary private IPs")
+	})
+
+	// [CAPI] Host tenancy should place the instance on an allocated dedicated host.
+	It("should be able to run a machine on a dedicated host", framework.LabelQEOnly, func() {
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		hostIDs, err := awsClient.AllocateHosts(mapiDefaultProviderSpec.InstanceType, mapiDefaultProviderSpec.Placement.AvailabilityZone, 1)
+		if err != nil {
+			Skip("Allocate dedicated host failed, skip the cases!!")
+		}
+		Expect(hostIDs).To(HaveLen(1), "expected a single dedicated host to be allocated")
+		// Cleanup runs after the AfterEach deleting the machineset, but the
+		// instance may still be terminating, so retry until the host is released.
+		DeferCleanup(func() {
+			Eventually(func() error {
+				return awsClient.ReleaseHosts(hostIDs...)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to release dedicated host")
+		})
+
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.Tenancy = "host"
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-dedicated-host", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		instance := getCAPIMachineSetInstance(ctx, cl, awsClient, machineSet)
+		Expect(ptr.Deref(instance.Placement.HostId, "")).To(Equal(hostIDs[0]), "expected the instance to run on the dedicated host")
+	})
+
 	//huliu-OCP-75662 - [CAPI] AWS Machine API Support of more than one block device.
 	It("should be able to run a machine with more than one block device", func() {
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
 		awsMachineTemplate.Spec.Template.Spec.NonRootVolumes = []awsv1.Volume{
 			{
 				DeviceName: "/dev/xvda",
@@ -183,7 +587,7 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 
 	//huliu-OCP-75663 - [CAPI] User defined tags can be applied to AWS EC2 Instances.
 	It("should be able to run a machine with user defined tags", func() {
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
 		awsMachineTemplate.Spec.Template.Spec.AdditionalTags = map[string]string{
 			"adminContact": "qe",
 			"costCenter":   "1981",
@@ -197,11 +601,41 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
 	})
 
+	// [CAPI] Instance metadata options should enforce IMDSv2 on the EC2 instance.
+	It("should be able to run a machine with IMDSv2 required", func() {
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
+		awsMachineTemplate.Spec.Template.Spec.InstanceMetadataOptions = &awsv1.InstanceMetadataOptions{
+			HTTPEndpoint:            awsv1.InstanceMetadataEndpointStateEnabled,
+			HTTPPutResponseHopLimit: 1,
+			HTTPTokens:              awsv1.HTTPTokensStateRequired,
+			InstanceMetadataTags:    awsv1.InstanceMetadataEndpointStateDisabled,
+		}
+		Expect(cl.Create(ctx, awsMachineTemplate)).To(Succeed(), "Failed to create awsmachinetemplate")
+		machineSetParams = framework.UpdateCAPIMachineSetName("aws-machineset-imdsv2", machineSetParams)
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		By("Checking the instance metadata options of the EC2 instance")
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines from machineset")
+		Expect(machines).ToNot(BeEmpty(), "expected the CAPI machineset to have machines")
+
+		for _, machine := range machines {
+			Expect(machine.Spec.ProviderID).ToNot(BeNil(), "expected machine %s to have a providerID", machine.Name)
+			instance, err := awsClient.DescribeInstanceByProviderID(*machine.Spec.ProviderID)
+			Expect(err).ToNot(HaveOccurred(), "Failed to describe the instance of machine %s", machine.Name)
+			Expect(instance.MetadataOptions).ToNot(BeNil(), "expected the instance of machine %s to have metadata options", machine.Name)
+			Expect(ptr.Deref(instance.MetadataOptions.HttpTokens, "")).To(Equal(string(awsv1.HTTPTokensStateRequired)), "expected the instance of machine %s to require IMDSv2", machine.Name)
+		}
+	})
+
 	//OCP-76794 - [CAPI] Support AWS capacity-reservations in CAPA.
 	It("should be able to run a machine with capacity-reservations", func() {
-		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec)
+		awsMachineTemplate = newAWSMachineTemplate(mapiDefaultProviderSpec, zoneNetwork)
 		By("Access AWS to create CapacityReservation")
-		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc))
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
 		capacityReservationID, err := awsClient.CreateCapacityReservation(mapiDefaultProviderSpec.InstanceType, "Linux/UNIX", mapiDefaultProviderSpec.Placement.AvailabilityZone, 1)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(capacityReservationID).ToNot(Equal(""))
@@ -219,6 +653,36 @@ var _ = Describe("Cluster API AWS MachineSet", framework.LabelCAPI, framework.La
 	})
 })
 
+// getCAPIMachineSetInstance returns the EC2 instance backing the single machine of the given CAPI machineset.
+func getCAPIMachineSetInstance(ctx context.Context, cl client.Client, awsClient *framework.AwsClient, machineSet *clusterv1.MachineSet) *ec2.Instance {
+	By("Getting the EC2 instance of the CAPI machine")
+
+	machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines from machineset")
+	Expect(machines).To(HaveLen(1), "expected the CAPI machineset to have a single machine")
+
+	infraMachine, err := framework.GetCAPIInfraMachine(ctx, cl, machines[0])
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the infrastructure machine of the CAPI machine")
+	Expect(infraMachine.InstanceID()).ToNot(BeEmpty(), "expected the infrastructure machine to have an instance ID")
+
+	instance, err := awsClient.DescribeInstance(infraMachine.InstanceID())
+	Expect(err).ToNot(HaveOccurred(), "Failed to describe the instance of the machine")
+	Expect(instance.Placement).ToNot(BeNil(), "expected the instance to have a placement")
+
+	return instance
+}
+
+// expectVolumeEncryptedWithKey checks the EBS volume attached to the instance under the given device name
+// is encrypted with the KMS key of the given ARN.
+func expectVolumeEncryptedWithKey(awsClient *framework.AwsClient, instance *ec2.Instance, deviceName string, keyARN string) {
+	By(fmt.Sprintf("Checking the volume %s of the instance is encrypted with the KMS key %s", deviceName, keyARN))
+
+	volume, err := awsClient.DescribeInstanceVolume(instance, deviceName)
+	Expect(err).ToNot(HaveOccurred(), "Failed to describe the volume of the instance")
+	Expect(ptr.Deref(volume.Encrypted, false)).To(BeTrue(), "expected the volume to be encrypted")
+	Expect(ptr.Deref(volume.KmsKeyId, "")).To(Equal(keyARN), "expected the volume to be encrypted with the KMS key")
+}
+
 func getDefaultAWSMAPIProviderSpec(cl client.Client) (*mapiv1.MachineSet, *mapiv1.AWSMachineProviderConfig) {
 	machineSetList := &mapiv1.MachineSetList{}
 
@@ -236,7 +700,7 @@ func getDefaultAWSMAPIProviderSpec(cl client.Client) (*mapiv1.MachineSet, *mapiv
 	return machineSet, providerSpec
 }
 
-func newAWSMachineTemplate(mapiProviderSpec *mapiv1.AWSMachineProviderConfig) *awsv1.AWSMachineTemplate {
+func newAWSMachineTemplate(mapiProviderSpec *mapiv1.AWSMachineProviderConfig, zoneNetwork framework.ZoneNetwork) *awsv1.AWSMachineTemplate {
 	By("Creating AWS machine template")
 
 	Expect(mapiProviderSpec).ToNot(BeNil(), "expected the mapi ProviderSpec to not be nil")
@@ -245,25 +709,11 @@ func newAWSMachineTemplate(mapiProviderSpec *mapiv1.AWSMachineProviderConfig) *a
 	Expect(mapiProviderSpec.InstanceType).ToNot(BeEmpty(), "expected the mapi InstanceType to not be empty")
 	Expect(mapiProviderSpec.Placement.AvailabilityZone).ToNot(BeEmpty(), "expected the mapi Placement.AvailabilityZone to not be empty")
 	Expect(mapiProviderSpec.AMI.ID).ToNot(BeNil(), "expected the mapi AMI.ID to not be nil")
-	Expect(mapiProviderSpec.SecurityGroups).ToNot(HaveLen(0), "expected the mapi SecurityGroups to be present")
-	Expect(mapiProviderSpec.SecurityGroups[0].Filters).ToNot(HaveLen(0), "expected the mapi SecurityGroups[0].Filters to be present")
-	Expect(mapiProviderSpec.SecurityGroups[0].Filters[0].Values).ToNot(HaveLen(0), "expected the mapi SecurityGroups[0].Filters[0].Values to be present")
-
-	var subnet awsv1.AWSResourceReference
+	Expect(zoneNetwork.SubnetID).ToNot(BeEmpty(), "expected the zone SubnetID to not be empty")
+	Expect(zoneNetwork.SecurityGroupIDs).ToNot(BeEmpty(), "expected the zone SecurityGroupIDs to be present")
 
-	if len(mapiProviderSpec.Subnet.Filters) == 0 {
-		subnet = awsv1.AWSResourceReference{
-			ID: mapiProviderSpec.Subnet.ID,
-		}
-	} else {
-		subnet = awsv1.AWSResourceReference{
-			Filters: []awsv1.Filter{
-				{
-					Name:   "tag:Name",
-					Values: mapiProviderSpec.Subnet.Filters[0].Values,
-				},
-			},
-		}
+	subnet := awsv1.AWSResourceReference{
+		ID: ptr.To(zoneNetwork.SubnetID),
 	}
 
 	uncompressedUserData := true
@@ -274,24 +724,11 @@ func newAWSMachineTemplate(mapiProviderSpec *mapiv1.AWSMachineProviderConfig) *a
 		Version:     "3.4",
 		StorageType: awsv1.IgnitionStorageTypeOptionUnencryptedUserData,
 	}
-	additionalSecurityGroups := []awsv1.AWSResourceReference{
-		{
-			Filters: []awsv1.Filter{
-				{
-					Name:   "tag:Name",
-					Values: mapiProviderSpec.SecurityGroups[0].Filters[0].Values,
-				},
-			},
-		},
-		{
-			Filters: []awsv1.Filter{
-				{
-					Name:   "tag:Name",
-					Values: mapiProviderSpec.SecurityGroups[1].Filters[0].Values,
-				},
-			},
-		},
+	additionalSecurityGroups := make([]awsv1.AWSResourceReference, 0, len(zoneNetwork.SecurityGroupIDs))
+	for _, groupID := range zoneNetwork.SecurityGroupIDs {
+		additionalSecurityGroups = append(additionalSecurityGroups, awsv1.AWSResourceReference{ID: ptr.To(groupID)})
 	}
+
 	awsmt := capiinfrastructurev1beta2resourcebuilder.
 		AWSMachineTemplate().
 		WithUncompressedUserData(uncompressedUserData).
@@ -303,6 +740,7 @@ func newAWSMachineTemplate(mapiProviderSpec *mapiv1.AWSMachineProviderConfig) *a
 		WithAdditionalSecurityGroups(additionalSecurityGroups).
 		WithName(awsMachineTemplateName).
 		WithNamespace(framework.ClusterAPINamespace).
+		WithLabels(map[string]string{framework.ReasonKey: framework.ReasonE2E}).
 		Build()
 
 	return awsmt
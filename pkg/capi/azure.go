@@ -3,6 +3,7 @@ package capi
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	gotypes "github.com/onsi/ginkgo/v2/types"
@@ -13,11 +14,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	ptr "k8s.io/utils/ptr"
 	azurev1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 	yaml "sigs.k8s.io/yaml"
 )
 
@@ -27,11 +28,12 @@ const (
 	capzManagerBootstrapCredentials = "capz-manager-bootstrap-credentials"
 )
 
-var _ = Describe("Cluster API Azure MachineSet", framework.LabelCAPI, framework.LabelDisruptive, Ordered, func() {
+var _ = Describe("Cluster API Azure MachineSet", framework.LabelCAPI, framework.LabelDisruptive, framework.LabelTechPreview, framework.LabelPlatforms(configv1.AzurePlatformType), framework.LabelRequiresMachineManagement, Ordered, func() {
 	var azureMachineTemplate *azurev1.AzureMachineTemplate
 	var machineSet *clusterv1.MachineSet
 	var mapiMachineSpec *mapiv1.AzureMachineProviderSpec
 	var client runtimeclient.Client
+	var clientset *kubernetes.Clientset
 	var ctx context.Context
 	var platform configv1.PlatformType
 	var clusterName string
@@ -40,13 +42,14 @@ var _ = Describe("Cluster API Azure MachineSet", framework.LabelCAPI, framework.
 	BeforeAll(func() {
 		client, err = framework.LoadClient()
 		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes client for test")
-		komega.SetClient(client)
 		ctx = framework.GetContext()
 		platform, err = framework.GetPlatform(ctx, client)
 		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
 		if platform != configv1.AzurePlatformType {
 			Skip("Skipping Azure E2E tests")
 		}
+		clientset, err = framework.LoadClientset()
+		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes clientset for test")
 		oc, _ := framework.NewCLI()
 		framework.SkipIfNotTechPreviewNoUpgrade(oc, client)
 
@@ -64,7 +67,7 @@ var _ = Describe("Cluster API Azure MachineSet", framework.LabelCAPI, framework.
 			return
 		}
 
-		framework.DeleteCAPIMachineSets(ctx, client, machineSet)
+		Expect(framework.DeleteCAPIMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
 		framework.WaitForCAPIMachineSetsDeleted(ctx, client, machineSet)
 		framework.DeleteObjects(ctx, client, azureMachineTemplate)
 	})
@@ -143,6 +146,129 @@ var _ = Describe("Cluster API Azure MachineSet", framework.LabelCAPI, framework.
 
 		By("Verifying the accelerated network configuration on the created Azure MachineTemplate")
 		Expect(azureMachineTemplate.Spec.Template.Spec.NetworkInterfaces[0].AcceleratedNetworking).To(Equal(ptr.To(true)))
+
+		By("Verifying accelerated networking is active on the node")
+		node := getAzureCAPIMachineSetNode(ctx, client, machineSet)
+		active, err := framework.IsAzureAcceleratedNetworkingActive(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to inspect the network interfaces of the node")
+		Expect(active).To(BeTrue(), "expected accelerated networking to be active on the node")
+	})
+
+	// [CAPI] Multiple network interfaces can be attached to a machine on Azure.
+	It("should be able to run a machine with multiple network interfaces", func() {
+		azureMachineTemplate = newAzureMachineTemplate(client, mapiMachineSpec)
+		azureMachineTemplate.Spec.Template.Spec.NetworkInterfaces = []azurev1.NetworkInterface{
+			{
+				AcceleratedNetworking: ptr.To(true),
+				SubnetName:            mapiMachineSpec.Subnet,
+			},
+			{
+				AcceleratedNetworking: ptr.To(false),
+				SubnetName:            mapiMachineSpec.Subnet,
+			},
+		}
+		Expect(client.Create(ctx, azureMachineTemplate)).To(Succeed(), "Failed to create azuremachinetemplate")
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, client, framework.NewCAPIMachineSetParams(
+			"azure-machineset-multi-nic",
+			clusterName,
+			mapiMachineSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "AzureMachineTemplate",
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Name:       azureMachineTemplateName,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI multiple network interfaces machineset")
+		framework.WaitForCAPIMachinesRunning(framework.GetContext(), client, machineSet.Name)
+
+		By("Verifying the network interfaces of the virtual machine")
+		node := getAzureCAPIMachineSetNode(ctx, client, machineSet)
+		metadata, err := framework.GetAzureInstanceMetadata(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the instance metadata of the node")
+		Expect(metadata.Network.Interface).To(HaveLen(2), "expected the virtual machine to have two network interfaces")
+	})
+
+	// [CAPI] A machine can be created from an image version of an Azure compute gallery referenced by its gallery, name and version.
+	It("should be able to run a machine from a compute gallery image", func() {
+		azureMachineTemplate = newAzureMachineTemplate(client, mapiMachineSpec)
+		galleryImage, err := framework.ParseAzureGalleryImageID(ptr.Deref(azureMachineTemplate.Spec.Template.Spec.Image.ID, ""))
+		if err != nil {
+			Skip(fmt.Sprintf("Skipping compute gallery image test, the default image is not a gallery image: %v", err))
+		}
+
+		azureMachineTemplate.Spec.Template.Spec.Image = &azurev1.Image{
+			ComputeGallery: &azurev1.AzureComputeGalleryImage{
+				Gallery:        galleryImage.Gallery,
+				Name:           galleryImage.Name,
+				Version:        galleryImage.Version,
+				SubscriptionID: ptr.To(galleryImage.SubscriptionID),
+				ResourceGroup:  ptr.To(galleryImage.ResourceGroup),
+			},
+		}
+		Expect(client.Create(ctx, azureMachineTemplate)).To(Succeed(), "Failed to create azuremachinetemplate")
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, client, framework.NewCAPIMachineSetParams(
+			"azure-machineset-gallery",
+			clusterName,
+			mapiMachineSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "AzureMachineTemplate",
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Name:       azureMachineTemplateName,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI compute gallery image machineset")
+		framework.WaitForCAPIMachinesRunning(framework.GetContext(), client, machineSet.Name)
+
+		By("Verifying the virtual machine was created from the gallery image")
+		node := getAzureCAPIMachineSetNode(ctx, client, machineSet)
+		metadata, err := framework.GetAzureInstanceMetadata(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the instance metadata of the node")
+		Expect(strings.ToLower(metadata.Compute.StorageProfile.ImageReference.ID)).To(
+			HaveSuffix(strings.ToLower(fmt.Sprintf("/galleries/%s/images/%s/versions/%s", galleryImage.Gallery, galleryImage.Name, galleryImage.Version))),
+			"expected the virtual machine to be created from the gallery image")
+	})
+
+	// [CAPI] A machine can be created from a marketplace image sold with a purchase plan.
+	// The terms of the image must have been accepted in the subscription, added framework.LabelQEOnly
+	It("should be able to run a machine from a marketplace image with a purchase plan", framework.LabelQEOnly, func() {
+		azureMachineTemplate = newAzureMachineTemplate(client, mapiMachineSpec)
+		azureMachineTemplate.Spec.Template.Spec.Image = &azurev1.Image{
+			Marketplace: &azurev1.AzureMarketplaceImage{
+				ImagePlan: azurev1.ImagePlan{
+					Publisher: framework.AzureMarketplaceImagePublisher,
+					Offer:     framework.AzureMarketplaceImageOffer,
+					SKU:       framework.AzureMarketplaceImageSKU,
+				},
+				Version:         "latest",
+				ThirdPartyImage: true,
+			},
+		}
+		Expect(client.Create(ctx, azureMachineTemplate)).To(Succeed(), "Failed to create azuremachinetemplate")
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, client, framework.NewCAPIMachineSetParams(
+			"azure-machineset-marketplace",
+			clusterName,
+			mapiMachineSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "AzureMachineTemplate",
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Name:       azureMachineTemplateName,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI marketplace image machineset")
+		framework.WaitForCAPIMachinesRunning(framework.GetContext(), client, machineSet.Name)
+
+		By("Verifying the virtual machine was created with the purchase plan of the image")
+		node := getAzureCAPIMachineSetNode(ctx, client, machineSet)
+		metadata, err := framework.GetAzureInstanceMetadata(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the instance metadata of the node")
+		Expect(metadata.Compute.Plan).To(Equal(framework.AzurePlanMetadata{
+			Name:      framework.AzureMarketplaceImageSKU,
+			Product:   framework.AzureMarketplaceImageOffer,
+			Publisher: framework.AzureMarketplaceImagePublisher,
+		}), "expected the virtual machine to have the purchase plan of the image")
 	})
 
 	// OCP-75972 - [CAPI] Spot instance can be created successfully with capi on azure.
@@ -171,6 +297,18 @@ var _ = Describe("Cluster API Azure MachineSet", framework.LabelCAPI, framework.
 	})
 })
 
+// getAzureCAPIMachineSetNode returns the node of the single machine of the given CAPI machineset.
+func getAzureCAPIMachineSetNode(ctx context.Context, client runtimeclient.Client, machineSet *clusterv1.MachineSet) *corev1.Node {
+	machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, client, machineSet)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines from machineset")
+	Expect(machines).To(HaveLen(1), "expected the CAPI machineset to have a single machine")
+
+	node, err := framework.GetCAPINodeForMachine(ctx, client, machines[0])
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+
+	return node
+}
+
 func getAzureMAPIProviderSpec(client runtimeclient.Client) *mapiv1.AzureMachineProviderSpec {
 	machineSetList := &mapiv1.MachineSetList{}
 
@@ -240,6 +378,7 @@ func newAzureMachineTemplate(client runtimeclient.Client, mapiProviderSpec *mapi
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      azureMachineTemplateName,
 			Namespace: framework.ClusterAPINamespace,
+			Labels:    map[string]string{framework.ReasonKey: framework.ReasonE2E},
 		},
 		Spec: azurev1.AzureMachineTemplateSpec{
 			Template: azurev1.AzureMachineTemplateResource{
@@ -16,13 +16,15 @@ import (
 	gcpv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 	yaml "sigs.k8s.io/yaml"
 )
 
 const (
 	OnHostMaintenanceTerminate = "Terminate"
 	OnHostMaintenanceMigrate   = "Migrate"
+
+	// gcpCustomMachineType is an N1 custom machine type with 4 vCPUs and 16GiB of memory.
+	gcpCustomMachineType = "custom-4-16384"
 )
 
 var (
@@ -30,19 +32,19 @@ var (
 	cl          client.Client
 )
 
-var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.LabelDisruptive, Ordered, func() {
+var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.LabelDisruptive, framework.LabelTechPreview, framework.LabelPlatforms(configv1.GCPPlatformType), framework.LabelRequiresMachineManagement, Ordered, func() {
 	var gcpMachineTemplate *gcpv1.GCPMachineTemplate
 	var machineSet *clusterv1.MachineSet
 	var mapiMachineSpec *mapiv1.GCPMachineProviderSpec
 	var ctx context.Context
 	var platform configv1.PlatformType
 	var clusterName string
+	var zoneNetwork framework.ZoneNetwork
 	var err error
 
 	BeforeAll(func() {
 		cl, err = framework.LoadClient()
 		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes client for test")
-		komega.SetClient(cl)
 		ctx = framework.GetContext()
 		platform, err = framework.GetPlatform(ctx, cl)
 		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
@@ -59,6 +61,11 @@ var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.La
 
 		framework.CreateCoreCluster(ctx, cl, clusterName, "GCPCluster")
 		mapiMachineSpec = getGCPMAPIProviderSpec(cl)
+
+		network, err := framework.DiscoverNetwork(ctx, cl, platform)
+		Expect(err).ToNot(HaveOccurred(), "Failed to discover the cluster network")
+		zoneNetwork, err = network.ForZone(mapiMachineSpec.Zone)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the network of the default zone")
 	})
 
 	AfterEach(func() {
@@ -66,15 +73,39 @@ var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.La
 		if CurrentSpecReport().State == gotypes.SpecStateSkipped {
 			return
 		}
-		framework.DeleteCAPIMachineSets(ctx, cl, machineSet)
+		Expect(framework.DeleteCAPIMachineSets(ctx, cl, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
 		framework.WaitForCAPIMachineSetsDeleted(ctx, cl, machineSet)
 		framework.DeleteObjects(ctx, cl, gcpMachineTemplate)
 	})
+	// [CAPI] A machineset can be scaled up and down through its scale subresource.
+	It("should scale a machineset through its scale subresource", func() {
+		gcpMachineTemplate = createGCPMachineTemplate(mapiMachineSpec, zoneNetwork)
+		Expect(cl.Create(ctx, gcpMachineTemplate)).To(Succeed())
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, framework.NewCAPIMachineSetParams(
+			"gcp-machineset-scale",
+			clusterName,
+			mapiMachineSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "GCPMachineTemplate",
+				APIVersion: infraAPIVersion,
+				Name:       gcpMachineTemplate.Name,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		framework.ScaleCAPIMachineSetWithSubresource(ctx, machineSet.Name, 3)
+		framework.WaitForCAPIMachineSetScale(ctx, cl, machineSet.Name, 3)
+
+		framework.ScaleCAPIMachineSetWithSubresource(ctx, machineSet.Name, 0)
+		framework.WaitForCAPIMachineSetScale(ctx, cl, machineSet.Name, 0)
+	})
 	DescribeTable("should be able to run a machine with disk types", framework.LabelCAPI, framework.LabelDisruptive,
 		func(expectedDiskType gcpv1.DiskType) {
 			mapiProviderSpec := getGCPMAPIProviderSpec(cl)
 			Expect(mapiProviderSpec).ToNot(BeNil())
-			gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec)
+			gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
 			gcpMachineTemplate.Spec.Template.Spec.RootDeviceType = &expectedDiskType
 			Expect(cl.Create(ctx, gcpMachineTemplate)).To(Succeed())
 			machineSet, _ = framework.CreateCAPIMachineSet(ctx, cl, framework.NewCAPIMachineSetParams(
@@ -98,7 +129,7 @@ var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.La
 		func(enableSecureBoot gcpv1.SecureBootPolicy, enableVtpm gcpv1.VirtualizedTrustedPlatformModulePolicy, enableIntegrityMonitoring gcpv1.IntegrityMonitoringPolicy) {
 			mapiProviderSpec := getGCPMAPIProviderSpec(cl)
 			Expect(mapiProviderSpec).ToNot(BeNil())
-			gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec)
+			gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
 			mapiProviderSpec.OnHostMaintenance = OnHostMaintenanceMigrate
 			gcpMachineTemplate.Spec.Template.Spec.OnHostMaintenance = (*gcpv1.HostMaintenancePolicy)(&mapiProviderSpec.OnHostMaintenance)
 			gcpMachineTemplate.Spec.Template.Spec.ShieldedInstanceConfig = &gcpv1.GCPShieldedInstanceConfig{
@@ -157,7 +188,7 @@ var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.La
 			}
 
 			// Create GCP MachineTemplate after relevant fields are updated
-			gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec)
+			gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
 			gcpMachineTemplate.Spec.Template.Spec.ConfidentialCompute = ptr.To(confidentialCompute)
 			gcpMachineTemplate.Spec.Template.Spec.InstanceType = "n2d-standard-4"
 			gcpMachineTemplate.Spec.Template.Spec.OnHostMaintenance = ptr.To(gcpv1.HostMaintenancePolicy(mapiProviderSpec.OnHostMaintenance))
@@ -189,10 +220,126 @@ var _ = Describe("Cluster API GCP MachineSet", framework.LabelCAPI, framework.La
 		Entry("Confidential Compute enabled", gcpv1.ConfidentialComputePolicyEnabled),
 		Entry("Confidential Compute disabled", gcpv1.ConfidentialComputePolicyDisabled),
 	)
+	It("should be able to run a machine with local SSD and additional persistent disks", func() {
+		mapiProviderSpec := getGCPMAPIProviderSpec(cl)
+		Expect(mapiProviderSpec).ToNot(BeNil())
+		gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
+		gcpMachineTemplate.Spec.Template.Spec.AdditionalDisks = []gcpv1.AttachedDiskSpec{
+			{
+				DeviceType: ptr.To(gcpv1.LocalSsdDiskType),
+			},
+			{
+				DeviceType: ptr.To(gcpv1.PdSsdDiskType),
+				Size:       ptr.To[int64](64),
+			},
+		}
+		Expect(cl.Create(ctx, gcpMachineTemplate)).To(Succeed())
+
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, framework.NewCAPIMachineSetParams(
+			"gcp-machineset-additional-disks",
+			clusterName,
+			mapiProviderSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "GCPMachineTemplate",
+				APIVersion: infraAPIVersion,
+				Name:       gcpMachineTemplate.Name,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI MachineSet with additional disks")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		By("Verifying the disks attached to the node")
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines from machineset")
+		Expect(machines).To(HaveLen(1))
+
+		node, err := framework.GetCAPINodeForMachine(ctx, cl, machines[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+
+		clientset, err := framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load clientset")
+
+		disks, err := framework.GetNodeDisks(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to list the disks attached to the node")
+		Expect(disks).To(HaveLen(3), "expected the boot disk and the additional disks to be attached to the node")
+		Expect(disks).To(ContainElement(HaveField("SizeBytes", BeEquivalentTo(int64(375)<<30))), "expected the local SSD to be attached to the node")
+		Expect(disks).To(ContainElement(HaveField("SizeBytes", BeEquivalentTo(int64(64)<<30))), "expected the additional persistent disk to be attached to the node")
+	})
+	It("should be able to run a machine with a custom machine type", func() {
+		mapiProviderSpec := getGCPMAPIProviderSpec(cl)
+		Expect(mapiProviderSpec).ToNot(BeNil())
+		mapiProviderSpec.MachineType = gcpCustomMachineType
+		gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
+		Expect(gcpMachineTemplate.Spec.Template.Spec.InstanceType).To(Equal(gcpCustomMachineType))
+		Expect(cl.Create(ctx, gcpMachineTemplate)).To(Succeed())
+
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, framework.NewCAPIMachineSetParams(
+			"gcp-machineset-custom-type",
+			clusterName,
+			mapiProviderSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "GCPMachineTemplate",
+				APIVersion: infraAPIVersion,
+				Name:       gcpMachineTemplate.Name,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI MachineSet with a custom machine type")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		By("Verifying the node matches the custom machine type")
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines from machineset")
+		Expect(machines).To(HaveLen(1))
+
+		node, err := framework.GetCAPINodeForMachine(ctx, cl, machines[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+		Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, gcpCustomMachineType))
+		Expect(node.Status.Capacity.Cpu().Value()).To(BeEquivalentTo(4), "expected the node to have 4 vCPUs")
+	})
+	// The additional labels are not verified: the metadata server does not expose them and the framework has no GCP client.
+	It("should be able to run a machine with additional network tags", func() {
+		networkTag := "cluster-api-e2e-" + framework.Rand.String(8)
+		mapiProviderSpec := getGCPMAPIProviderSpec(cl)
+		Expect(mapiProviderSpec).ToNot(BeNil())
+		gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
+		gcpMachineTemplate.Spec.Template.Spec.AdditionalNetworkTags = append(gcpMachineTemplate.Spec.Template.Spec.AdditionalNetworkTags, networkTag)
+		Expect(cl.Create(ctx, gcpMachineTemplate)).To(Succeed())
+
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, cl, framework.NewCAPIMachineSetParams(
+			"gcp-machineset-network-tags",
+			clusterName,
+			mapiProviderSpec.Zone,
+			1,
+			corev1.ObjectReference{
+				Kind:       "GCPMachineTemplate",
+				APIVersion: infraAPIVersion,
+				Name:       gcpMachineTemplate.Name,
+			},
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI MachineSet with additional network tags")
+		framework.WaitForCAPIMachinesRunning(ctx, cl, machineSet.Name)
+
+		By("Verifying the network tags of the instance")
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines from machineset")
+		Expect(machines).To(HaveLen(1))
+
+		node, err := framework.GetCAPINodeForMachine(ctx, cl, machines[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+
+		clientset, err := framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load clientset")
+
+		tags, err := framework.GetGCPInstanceNetworkTags(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the network tags of the instance")
+		Expect(tags).To(ContainElements(gcpMachineTemplate.Spec.Template.Spec.AdditionalNetworkTags), "expected the instance to have the additional network tags")
+	})
 	It("should provision Preemptible machine successfully", func() {
 		mapiProviderSpec := getGCPMAPIProviderSpec(cl)
 		Expect(mapiProviderSpec).ToNot(BeNil())
-		gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec)
+		gcpMachineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
 		gcpMachineTemplate.Spec.Template.Spec.Preemptible = true
 		mapiProviderSpec.OnHostMaintenance = OnHostMaintenanceTerminate
 		gcpMachineTemplate.Spec.Template.Spec.OnHostMaintenance = (*gcpv1.HostMaintenancePolicy)(&mapiProviderSpec.OnHostMaintenance)
@@ -244,7 +391,7 @@ func getGCPMAPIProviderSpec(cl client.Client) *mapiv1.GCPMachineProviderSpec {
 	return providerSpec
 }
 
-func createGCPMachineTemplate(mapiProviderSpec *mapiv1.GCPMachineProviderSpec) *gcpv1.GCPMachineTemplate {
+func createGCPMachineTemplate(mapiProviderSpec *mapiv1.GCPMachineProviderSpec, zoneNetwork framework.ZoneNetwork) *gcpv1.GCPMachineTemplate {
 	By("Creating GCP machine template")
 
 	Expect(mapiProviderSpec).ToNot(BeNil())
@@ -254,7 +401,7 @@ func createGCPMachineTemplate(mapiProviderSpec *mapiv1.GCPMachineProviderSpec) *
 	Expect(mapiProviderSpec.MachineType).ToNot(BeEmpty())
 	Expect(mapiProviderSpec.NetworkInterfaces).ToNot(BeNil())
 	Expect(len(mapiProviderSpec.NetworkInterfaces)).To(BeNumerically(">", 0))
-	Expect(mapiProviderSpec.NetworkInterfaces[0].Subnetwork).ToNot(BeEmpty())
+	Expect(zoneNetwork.Subnetwork).ToNot(BeEmpty())
 	Expect(mapiProviderSpec.ServiceAccounts).ToNot(BeNil())
 	Expect(mapiProviderSpec.ServiceAccounts[0].Email).ToNot(BeEmpty())
 	Expect(mapiProviderSpec.ServiceAccounts[0].Scopes).ToNot(BeNil())
@@ -268,7 +415,7 @@ func createGCPMachineTemplate(mapiProviderSpec *mapiv1.GCPMachineProviderSpec) *
 		RootDeviceSize: mapiProviderSpec.Disks[0].SizeGB,
 		InstanceType:   mapiProviderSpec.MachineType,
 		Image:          &mapiProviderSpec.Disks[0].Image,
-		Subnet:         &mapiProviderSpec.NetworkInterfaces[0].Subnetwork,
+		Subnet:         &zoneNetwork.Subnetwork,
 		ServiceAccount: &gcpv1.ServiceAccount{
 			Email:  mapiProviderSpec.ServiceAccounts[0].Email,
 			Scopes: mapiProviderSpec.ServiceAccounts[0].Scopes,
@@ -283,6 +430,7 @@ func createGCPMachineTemplate(mapiProviderSpec *mapiv1.GCPMachineProviderSpec) *
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "gcpmachinetemplate-",
 			Namespace:    framework.ClusterAPINamespace,
+			Labels:       map[string]string{framework.ReasonKey: framework.ReasonE2E},
 		},
 		Spec: gcpv1.GCPMachineTemplateSpec{
 			Template: gcpv1.GCPMachineTemplateResource{
@@ -0,0 +1,191 @@
+package capi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	gotypes "github.com/onsi/ginkgo/v2/types"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// capiMHCMachineTemplateName is the name of the infrastructure machine template of the MachineHealthCheck suite,
+	// distinct from the templates of the platform suites.
+	capiMHCMachineTemplateName = "mhc-machine-template"
+
+	// capiMHCConditionType is the node condition the MachineHealthCheck suite marks nodes unhealthy with.
+	capiMHCConditionType = "CAPIMachineHealthCheckE2E"
+)
+
+var _ = Describe("Cluster API MachineHealthCheck", framework.LabelCAPI, framework.LabelMachineHealthCheck, framework.LabelDisruptive, framework.LabelTechPreview, framework.LabelPlatforms(configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType), framework.LabelRequiresMachineManagement, Ordered, func() {
+	const replicas = 2
+
+	var (
+		mhcClient          client.Client
+		ctx                context.Context
+		mhcClusterName     string
+		machineTemplate    client.Object
+		infrastructureRef  corev1.ObjectReference
+		failureDomain      string
+		machineSet         *clusterv1.MachineSet
+		machineHealthCheck *clusterv1.MachineHealthCheck
+		err                error
+	)
+
+	BeforeAll(func() {
+		mhcClient, err = framework.LoadClient()
+		Expect(err).NotTo(HaveOccurred(), "Failed to create Kubernetes client for test")
+		ctx = framework.GetContext()
+
+		platform, err := framework.GetPlatform(ctx, mhcClient)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+
+		var infraClusterKind, machineTemplateKind string
+
+		// The machines are created from the infrastructure machine template of the platform suites.
+		switch platform {
+		case configv1.AWSPlatformType:
+			_, mapiProviderSpec := getDefaultAWSMAPIProviderSpec(mhcClient)
+			zoneNetwork := getZoneNetwork(ctx, mhcClient, platform, mapiProviderSpec.Placement.AvailabilityZone)
+			machineTemplate = newAWSMachineTemplate(mapiProviderSpec, zoneNetwork)
+			failureDomain = mapiProviderSpec.Placement.AvailabilityZone
+			infraClusterKind = "AWSCluster"
+			machineTemplateKind = "AWSMachineTemplate"
+		case configv1.AzurePlatformType:
+			mapiProviderSpec := getAzureMAPIProviderSpec(mhcClient)
+			machineTemplate = newAzureMachineTemplate(mhcClient, mapiProviderSpec)
+			failureDomain = mapiProviderSpec.Zone
+			infraClusterKind = "AzureCluster"
+			machineTemplateKind = "AzureMachineTemplate"
+		case configv1.GCPPlatformType:
+			mapiProviderSpec := getGCPMAPIProviderSpec(mhcClient)
+			zoneNetwork := getZoneNetwork(ctx, mhcClient, platform, mapiProviderSpec.Zone)
+			machineTemplate = createGCPMachineTemplate(mapiProviderSpec, zoneNetwork)
+			failureDomain = mapiProviderSpec.Zone
+			infraClusterKind = "GCPCluster"
+			machineTemplateKind = "GCPMachineTemplate"
+		default:
+			Skip(fmt.Sprintf("Skipping CAPI MachineHealthCheck tests on %s", platform))
+		}
+
+		oc, _ := framework.NewCLI()
+		framework.SkipIfNotTechPreviewNoUpgrade(oc, mhcClient)
+
+		infra, err := framework.GetInfrastructure(ctx, mhcClient)
+		Expect(err).NotTo(HaveOccurred(), "Failed to get cluster infrastructure object")
+		Expect(infra.Status.InfrastructureName).ShouldNot(BeEmpty(), "infrastructure name was empty on Infrastructure.Status.")
+		mhcClusterName = infra.Status.InfrastructureName
+		framework.CreateCoreCluster(ctx, mhcClient, mhcClusterName, infraClusterKind)
+
+		machineTemplate.SetName(capiMHCMachineTemplateName)
+		infrastructureRef = corev1.ObjectReference{
+			Kind:       machineTemplateKind,
+			APIVersion: infraAPIVersion,
+			Name:       capiMHCMachineTemplateName,
+		}
+	})
+
+	AfterEach(func() {
+		// if the current testing are skipped, we skip clean resources
+		if CurrentSpecReport().State == gotypes.SpecStateSkipped {
+			return
+		}
+
+		if machineHealthCheck != nil {
+			framework.DeleteObjects(ctx, mhcClient, machineHealthCheck)
+		}
+
+		Expect(framework.DeleteCAPIMachineSets(ctx, mhcClient, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+		framework.WaitForCAPIMachineSetsDeleted(ctx, mhcClient, machineSet)
+		framework.DeleteObjects(ctx, mhcClient, machineTemplate)
+	})
+
+	// [CAPI] A MachineHealthCheck remediates an unhealthy CAPI machine, which its machineset replaces.
+	It("should remediate a machine whose node is unhealthy", func() {
+		Expect(mhcClient.Create(ctx, machineTemplate)).To(Succeed(), "Failed to create the infrastructure machine template")
+		machineSet, err = framework.CreateCAPIMachineSet(ctx, mhcClient, framework.NewCAPIMachineSetParams(
+			"capi-machineset-mhc",
+			mhcClusterName,
+			failureDomain,
+			replicas,
+			infrastructureRef,
+		))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CAPI machineset")
+		framework.WaitForCAPIMachinesRunning(ctx, mhcClient, machineSet.Name)
+
+		machines, err := framework.GetCAPIMachinesFromMachineSet(ctx, mhcClient, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines")
+		Expect(machines).To(HaveLen(replicas), "Expected the CAPI machineset to have all its machines")
+		unhealthyMachine, healthyMachine := machines[0], machines[1]
+
+		By("Setting an unhealthy condition on the node of a machine")
+		node, err := framework.GetCAPINodeForMachine(ctx, mhcClient, unhealthyMachine)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the CAPI machine")
+		Expect(framework.AddNodeCondition(mhcClient, node, corev1.NodeCondition{
+			Type:               capiMHCConditionType,
+			Status:             corev1.ConditionTrue,
+			LastHeartbeatTime:  metav1.Now(),
+			LastTransitionTime: metav1.Now(),
+			Reason:             "E2E",
+			Message:            "CAPI MachineHealthCheck E2E tests",
+		})).To(Succeed(), "Failed to add the unhealthy condition to the node")
+
+		By("Creating a MachineHealthCheck for the machineset")
+		machineHealthCheck = &clusterv1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      machineSet.Name,
+				Namespace: framework.ClusterAPINamespace,
+			},
+			Spec: clusterv1.MachineHealthCheckSpec{
+				ClusterName: mhcClusterName,
+				Selector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"machine.openshift.io/cluster-api-machineset": machineSet.Name},
+				},
+				UnhealthyConditions: []clusterv1.UnhealthyCondition{
+					{
+						Type:    capiMHCConditionType,
+						Status:  corev1.ConditionTrue,
+						Timeout: metav1.Duration{Duration: time.Second},
+					},
+				},
+				MaxUnhealthy: ptr.To(intstr.FromInt(1)),
+			},
+		}
+		Expect(mhcClient.Create(ctx, machineHealthCheck)).To(Succeed(), "Failed to create CAPI MachineHealthCheck")
+
+		By("Waiting for the unhealthy machine to be deleted")
+		Eventually(ctx, func() bool {
+			return apierrors.IsNotFound(mhcClient.Get(ctx, client.ObjectKeyFromObject(unhealthyMachine), &clusterv1.Machine{}))
+		}, framework.WaitLong, framework.RetryMedium).Should(BeTrue(), "The unhealthy CAPI machine should have been remediated")
+
+		By("Verifying the machineset replaces the unhealthy machine")
+		framework.WaitForCAPIMachineSetScale(ctx, mhcClient, machineSet.Name, replicas)
+
+		machines, err = framework.GetCAPIMachinesFromMachineSet(ctx, mhcClient, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get CAPI machines")
+		Expect(machines).To(ContainElement(HaveField("Name", healthyMachine.Name)), "The healthy CAPI machine should not have been remediated")
+		Expect(machines).ToNot(ContainElement(HaveField("Name", unhealthyMachine.Name)), "The unhealthy CAPI machine should have been replaced")
+	})
+})
+
+// getZoneNetwork returns the network of the given zone of the cluster.
+func getZoneNetwork(ctx context.Context, cl client.Client, platform configv1.PlatformType, zone string) framework.ZoneNetwork {
+	network, err := framework.DiscoverNetwork(ctx, cl, platform)
+	Expect(err).ToNot(HaveOccurred(), "Failed to discover the cluster network")
+
+	zoneNetwork, err := network.ForZone(zone)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the network of the zone")
+
+	return zoneNetwork
+}
@@ -1,8 +1,10 @@
 package e2e
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"testing"
-	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -10,75 +12,251 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog"
 
-	osconfigv1 "github.com/openshift/api/config/v1"
-	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/extension"
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
-	caov1alpha1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis"
-	awsv1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
-	azurev1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
-	gcpv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
-	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/chaos"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
 
 	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/autoscaler"
 	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/capi"
 	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/infra"
 	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/machinehealthcheck"
+	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/mapi"
 	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/operators"
 	_ "github.com/openshift/cluster-api-actuator-pkg/pkg/providers"
 )
 
 func init() {
 	klog.InitFlags(nil)
+	flag.StringVar(&framework.SharedMachineSetName, "reuse-machineset", "",
+		"name of a MachineSet to reuse across the specs which only need a running machine; created if it does not exist")
+	flag.BoolVar(&chaos.Enabled, "chaos", false,
+		"kill controller pods at random points during the specs labelled chaos, and verify they reconcile idempotently")
+	flag.IntVar(&framework.LargeScaleReplicas, "large-scale-replicas", framework.LargeScaleReplicas,
+		"number of replicas the specs labelled large-scale scale a MachineSet to")
+	flag.DurationVar(&framework.LargeScaleRunningBudget, "large-scale-running-budget", framework.LargeScaleRunningBudget,
+		"maximum time for all the machines of a large-scale MachineSet to be running")
+	flag.DurationVar(&framework.LargeScaleNodesReadyBudget, "large-scale-nodes-ready-budget", framework.LargeScaleNodesReadyBudget,
+		"maximum time for all the nodes of a large-scale MachineSet to be ready")
+	flag.DurationVar(&framework.SoakDuration, "soak-duration", framework.SoakDuration,
+		"time the specs labelled soak repeat their cycles for")
+	flag.BoolVar(&framework.UseSuiteCache, "suite-cache", framework.UseSuiteCache,
+		"wait on an informer cache of the Machines, MachineSets and Nodes instead of polling the API server")
+	flag.StringVar(&framework.WindowsImage, "windows-image", "",
+		"image of the Windows machines: an AMI ID on AWS (required), a Windows Server SKU on Azure or an image path on GCP")
+	flag.StringVar(&framework.GCPNonUEFIImage, "gcp-non-uefi-image", "",
+		"boot image of the GCP machines which does not support UEFI, the specs booting a non UEFI image are skipped without it")
+	flag.BoolVar(&gatherer.NodeTriageEnabled, "node-triage", false,
+		"on spec failure, collect the journal and network state of the NotReady nodes from debug pods, which requires cluster-admin")
+	flag.StringVar(&framework.ManagementKubeconfig, "management-kubeconfig", "",
+		"kubeconfig of the management cluster holding the machines, when it is not the cluster of the nodes")
+	flag.StringVar(&framework.ManagementKubeconfigContext, "management-kubeconfig-context", "",
+		"context of the management cluster in its kubeconfig, or in the default kubeconfig without --management-kubeconfig")
 	klog.SetOutput(GinkgoWriter)
 
-	if err := machinev1.AddToScheme(scheme.Scheme); err != nil {
+	if err := framework.RegisterAllSchemes(scheme.Scheme); err != nil {
 		klog.Fatal(err)
 	}
+}
 
-	if err := caov1alpha1.AddToScheme(scheme.Scheme); err != nil {
-		klog.Fatal(err)
+// timeline records Machine and Node transitions for the whole suite,
+// so they can be attached to the report of failed specs.
+var timeline = framework.NewTimeline()
+
+// provisioning records the provisioning durations of the Machines created by the suite,
+// reported once the suite has run.
+var provisioning = framework.NewProvisioningRecorder()
+
+// baseline is the steady state of the cluster before the suite runs, verified once all the specs have run.
+// It is only captured and verified on the first process.
+var baseline *framework.Baseline
+
+// flakyReportEntry is the name of the report entry marking the specs which passed on a retry.
+const flakyReportEntry = "Flaky"
+
+// watchdog monitors the cluster health while the suite runs.
+var watchdog *framework.ClusterHealthWatchdog
+
+func TestE2E(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Machine Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	// Runs on the first process before any process runs a spec, so the baseline has no machine of the specs.
+	// A skip here skips the suite on every process.
+	client, err := framework.LoadClient()
+	Expect(err).ToNot(HaveOccurred())
+
+	ctx := framework.GetContext()
+
+	hosted, err := framework.IsHostedControlPlane(ctx, client)
+	Expect(err).ToNot(HaveOccurred(), "Should be able to get the cluster topology")
+
+	if hosted {
+		Skip("Skipping the suite on a cluster with a hosted control plane: it has no Machine API, " +
+			"its machines are managed by the NodePools of the management cluster")
 	}
 
-	if err := osconfigv1.AddToScheme(scheme.Scheme); err != nil {
-		klog.Fatal(err)
+	baseline, err = framework.CaptureBaseline(ctx, client)
+	Expect(err).ToNot(HaveOccurred(), "Should be able to capture the cluster baseline")
+
+	return nil
+}, func(_ []byte) {
+	client, err := framework.LoadClient()
+	Expect(err).ToNot(HaveOccurred())
+
+	ctx := framework.GetContext()
+
+	stopSuiteCache, err := framework.StartSuiteCache(ctx)
+	Expect(err).ToNot(HaveOccurred(), "Suite cache should be able to start")
+	DeferCleanup(stopSuiteCache)
+
+	// Both are fed by the informers of the suite cache.
+	Expect(timeline.Start(ctx)).To(Succeed(), "Machine and Node timeline should be able to start")
+	Expect(provisioning.Start(ctx)).To(Succeed(), "Machine provisioning recorder should be able to start")
+
+	// Delete the MachineSets created by the running specs if the suite is interrupted.
+	DeferCleanup(framework.HandleInterrupts(client))
+
+	Expect(extension.SetPlatformTimeouts(ctx, client)).To(Succeed(), "Should be able to set the platform timeouts")
+
+	// Started once the platform timeouts are set, as its grace period is WaitLong.
+	watchdog = framework.NewClusterHealthWatchdog(client)
+	watchdog.Start(ctx)
+	DeferCleanup(watchdog.Stop)
+})
+
+var _ = SynchronizedAfterSuite(func() {
+	framework.WaitForInterruptCleanup()
+
+	client, err := framework.LoadClient()
+	Expect(err).ToNot(HaveOccurred())
+
+	// The cleanup of the specs stopped by the watchdog failed with the suite context, as on interrupt.
+	if watchdog != nil && watchdog.Err() != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), framework.InterruptGracePeriod)
+		defer cancel()
+
+		if err := framework.Tracked.DeleteAll(ctx, client); err != nil {
+			klog.Errorf("[cleanup] error deleting tracked objects: %v", err)
+		}
 	}
 
-	if err := clusterv1.AddToScheme(scheme.Scheme); err != nil {
-		klog.Fatal(err)
+	// The suite context is cancelled when the run is interrupted or stopped by the watchdog.
+	if framework.GetContext().Err() != nil {
+		return
 	}
 
-	if err := azurev1.AddToScheme(scheme.Scheme); err != nil {
-		klog.Fatal(err)
+	platform, err := framework.GetPlatform(framework.GetContext(), client)
+	Expect(err).ToNot(HaveOccurred())
+
+	AddReportEntry("Machine provisioning", provisioning.Format(platform))
+}, func() {
+	// Runs on the first process once all the processes are done, so the specs of the others do not count as drift.
+	// When the suite context is cancelled, the tracked cleanup already deleted the shared MachineSet.
+	if framework.GetContext().Err() != nil {
+		return
 	}
 
-	if err := awsv1.AddToScheme(scheme.Scheme); err != nil {
-		klog.Fatal(err)
+	client, err := framework.LoadClient()
+	Expect(err).ToNot(HaveOccurred())
+
+	// The shared MachineSet may be used by the specs of any process, so it is only deleted once they are all done.
+	if shared := framework.SharedMachineSets(); shared != nil {
+		Expect(shared.Cleanup(framework.GetContext(), client)).To(Succeed(), "Shared MachineSet should be able to be deleted")
 	}
 
-	if err := gcpv1.AddToScheme(scheme.Scheme); err != nil {
-		klog.Fatal(err)
+	// The baseline was captured on the first process too, before any spec ran.
+	if baseline == nil {
+		return
 	}
-}
 
-func TestE2E(t *testing.T) {
-	RegisterFailHandler(Fail)
-	RunSpecs(t, "Machine Suite")
-}
+	Expect(baseline.Verify(framework.GetContext(), client)).To(Succeed(), "Suite should leave the cluster in its baseline state")
+})
+
+// Make object names and random choices reproducible per spec for a given -seed.
+// The retries of the specs marked LabelFlakeRetry are seeded apart, so they do not reuse the names
+// of the objects of the failed attempt, which may still be deleting.
+var _ = BeforeEach(func() {
+	specReport := CurrentSpecReport()
+	if specReport.NumAttempts > 1 {
+		framework.Rand.Reseed(fmt.Sprintf("%s#%d", specReport.FullText(), specReport.NumAttempts))
+		return
+	}
+
+	framework.Rand.Reseed(specReport.FullText())
+})
+
+// Single node clusters have no worker MachineSet to create machines from.
+var _ = BeforeEach(func() {
+	if !framework.HasLabels(CurrentSpecReport().Labels(), framework.LabelRequiresMachineManagement) {
+		return
+	}
 
-var _ = BeforeSuite(func() {
 	client, err := framework.LoadClient()
 	Expect(err).ToNot(HaveOccurred())
 
-	ctx := framework.GetContext()
+	sno, err := framework.IsSNO(framework.GetContext(), client)
+	Expect(err).ToNot(HaveOccurred(), "Should be able to get the cluster topology")
 
-	platform, err := framework.GetPlatform(ctx, client)
-	Expect(err).ToNot(HaveOccurred())
+	if sno {
+		Skip("Skipping spec creating machines on a single node cluster")
+	}
+})
+
+// Do not start a disruptive spec on a cluster which is already unhealthy.
+// The watchdog has already stopped the running specs by cancelling the suite context.
+var _ = BeforeEach(func() {
+	if framework.HasLabels(CurrentSpecReport().Labels(), framework.LabelDisruptive) {
+		watchdog.AbortSuiteIfUnhealthy()
+
+		client, err := framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred())
+
+		framework.PreSpecCheck(framework.GetContext(), client)
+	}
+})
 
-	// Extend timeouts for slower providers
-	switch platform {
-	case osconfigv1.AzurePlatformType, osconfigv1.GCPPlatformType, osconfigv1.VSpherePlatformType, osconfigv1.OpenStackPlatformType, osconfigv1.PowerVSPlatformType, osconfigv1.NutanixPlatformType:
-		framework.WaitShort = 2 * time.Minute  // Normally 1m
-		framework.WaitMedium = 6 * time.Minute // Normally 3m
-		framework.WaitLong = 30 * time.Minute  // Normally 15m
+var _ = AfterEach(func() {
+	specReport := CurrentSpecReport()
+	if specReport.Failed() {
+		AddReportEntry("Machine and Node timeline", timeline.Format(specReport.StartTime))
+		stampFailureFingerprint(specReport)
+	} else if specReport.NumAttempts > 1 {
+		AddReportEntry(flakyReportEntry, fmt.Sprintf("passed on attempt %d of %d", specReport.NumAttempts, specReport.MaxFlakeAttempts))
 	}
 })
+
+// Reported once the DeferCleanup of the spec has run, so its calls are attributed to the spec too.
+var _ = ReportAfterEach(func(SpecReport) {
+	if calls := framework.CloudAPICalls.Format(); calls != "" {
+		AddReportEntry("Cloud API calls", calls)
+	}
+
+	framework.CloudAPICalls.Reset()
+})
+
+// stampFailureFingerprint classifies the failure of the spec, and stamps the fingerprint into the spec report
+// and into the artifacts of the spec, so that the CI aggregation can tell the infra flakes from the regressions.
+func stampFailureFingerprint(specReport SpecReport) {
+	client, err := framework.LoadClient()
+	Expect(err).ToNot(HaveOccurred())
+
+	// The evidence is best effort: the failure is still classified from its message without it.
+	evidence, err := framework.CollectFailureEvidence(framework.GetContext(), client, framework.Tracked.MachineSetsCreatedSince(specReport.StartTime))
+	if err != nil {
+		klog.Warningf("Unable to collect the failure evidence: %v", err)
+	}
+
+	fingerprint := framework.FingerprintFailure(specReport.Failure.Message, evidence)
+	fingerprint.Attempt = specReport.NumAttempts
+	AddReportEntry(framework.FailureFingerprintReportEntry, fingerprint)
+
+	g, err := framework.NewGatherer()
+	Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+	if _, err := g.CLI.WithSubPath(specReport.FullText()).WriteToFile("fingerprint.json", fingerprint.String()); err != nil {
+		klog.Warningf("Unable to write the failure fingerprint: %v", err)
+	}
+}
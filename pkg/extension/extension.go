@@ -0,0 +1,32 @@
+// Package extension holds the setup shared by the e2e suite and the test extension binary,
+// so that the platform timeouts cannot drift between them. Both register their schemes with
+// framework.RegisterAllSchemes.
+package extension
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	osconfigv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+// SetPlatformTimeouts extends the framework timeouts on the platforms slower to provision machines.
+func SetPlatformTimeouts(ctx context.Context, cl runtimeclient.Client) error {
+	platform, err := framework.GetPlatform(ctx, cl)
+	if err != nil {
+		return fmt.Errorf("error getting platform: %w", err)
+	}
+
+	switch platform {
+	case osconfigv1.AzurePlatformType, osconfigv1.GCPPlatformType, osconfigv1.VSpherePlatformType, osconfigv1.OpenStackPlatformType, osconfigv1.PowerVSPlatformType, osconfigv1.NutanixPlatformType:
+		framework.WaitShort = 2 * time.Minute  // Normally 1m
+		framework.WaitMedium = 6 * time.Minute // Normally 3m
+		framework.WaitLong = 30 * time.Minute  // Normally 15m
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldManager is the field manager of the mutations the specs apply with server-side apply.
+const FieldManager = "cluster-api-actuator-pkg-e2e"
+
+// ErrFieldManagerConflict is returned when fields applied by the specs are owned by another field manager
+// with a different value, e.g. a controller reverting the mutation of a spec.
+var ErrFieldManagerConflict = errors.New("field manager conflict")
+
+// PatchMachine applies the fields set in the given Machine with server-side apply, taking their ownership
+// from the other field managers. Unlike an update it needs no resource version, so it never conflicts with
+// the controllers updating the Machine concurrently. The Machine should only set the fields to mutate
+// along with its name and namespace: the atomic lists, e.g. the taints, are replaced as a whole.
+func PatchMachine(ctx context.Context, cl runtimeclient.Client, machine *machinev1.Machine) error {
+	machine.SetGroupVersionKind(machinev1.GroupVersion.WithKind("Machine"))
+
+	return applyObject(ctx, cl, machine, runtimeclient.ForceOwnership)
+}
+
+// PatchMachineSet applies the fields set in the given MachineSet with server-side apply, see PatchMachine.
+func PatchMachineSet(ctx context.Context, cl runtimeclient.Client, machineSet *machinev1.MachineSet) error {
+	machineSet.SetGroupVersionKind(machinev1.GroupVersion.WithKind("MachineSet"))
+
+	return applyObject(ctx, cl, machineSet, runtimeclient.ForceOwnership)
+}
+
+// ExpectNoFieldManagerConflicts returns an error wrapping ErrFieldManagerConflict when the fields set in the
+// given Machine or MachineSet, previously applied with PatchMachine or PatchMachineSet, have since been taken
+// over by another field manager with a different value. The check is a dry run of the apply, without force.
+func ExpectNoFieldManagerConflicts(ctx context.Context, cl runtimeclient.Client, obj runtimeclient.Object) error {
+	switch obj.(type) {
+	case *machinev1.Machine:
+		obj.GetObjectKind().SetGroupVersionKind(machinev1.GroupVersion.WithKind("Machine"))
+	case *machinev1.MachineSet:
+		obj.GetObjectKind().SetGroupVersionKind(machinev1.GroupVersion.WithKind("MachineSet"))
+	}
+
+	return applyObject(ctx, cl, obj, runtimeclient.DryRunAll)
+}
+
+// applyObject applies the object with server-side apply as FieldManager.
+func applyObject(ctx context.Context, cl runtimeclient.Client, obj runtimeclient.Object, opts ...runtimeclient.PatchOption) error {
+	// Server-side apply rejects the managed fields, and the resource version would make it conflict
+	// with every concurrent update.
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	err := cl.Patch(ctx, obj, runtimeclient.Apply, append(opts, runtimeclient.FieldOwner(FieldManager))...)
+	if conflicts := fieldManagerConflicts(err); len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s %s: %s", ErrFieldManagerConflict, obj.GetObjectKind().GroupVersionKind().Kind,
+			obj.GetName(), strings.Join(conflicts, "; "))
+	}
+
+	if err != nil {
+		return fmt.Errorf("error applying %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// fieldManagerConflicts returns the conflicts of the error of an apply, one per conflicting field.
+func fieldManagerConflicts(err error) []string {
+	var statusErr *apierrors.StatusError
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return nil
+	}
+
+	var conflicts []string
+
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s", cause.Field, cause.Message))
+		}
+	}
+
+	return conflicts
+}
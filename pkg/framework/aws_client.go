@@ -1,60 +1,113 @@
 package framework
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"k8s.io/klog"
 	"k8s.io/utils/ptr"
 )
 
+var (
+	errInstanceNotFound     = errors.New("instance not found")
+	errInvalidAWSProviderID = errors.New("invalid AWS provider ID")
+	errReleaseHostsFailed   = errors.New("failed to release dedicated hosts")
+	errInstanceTypeNotFound = errors.New("instance type not found")
+	errVolumeNotFound       = errors.New("volume not found")
+	errSubnetNotFound       = errors.New("subnet not found")
+)
+
 // AwsClient struct.
 type AwsClient struct {
-	svc *ec2.EC2
+	ctx      context.Context
+	svc      *ec2.EC2
+	quotasvc *servicequotas.ServiceQuotas
 }
 
 // Init the aws client.
-func NewAwsClient(accessKeyID []byte, secureKey []byte, clusterRegion string) *AwsClient {
-	awsSession := newAwsSession(accessKeyID, secureKey, clusterRegion)
+// The optional session token is required when the credentials are temporary, e.g. on STS clusters.
+func NewAwsClient(accessKeyID []byte, secureKey []byte, clusterRegion string, sessionToken ...[]byte) *AwsClient {
+	awsSession := newAwsSession(accessKeyID, secureKey, clusterRegion, sessionToken...)
 	aClient := &AwsClient{
-		svc: ec2.New(awsSession),
+		ctx:      context.Background(),
+		svc:      ec2.New(awsSession),
+		quotasvc: servicequotas.New(awsSession),
 	}
 
 	return aClient
 }
 
+// WithContext returns a copy of the client whose API calls, including their retries,
+// are bound to the given context.
+func (a *AwsClient) WithContext(ctx context.Context) *AwsClient {
+	aClient := *a
+	aClient.ctx = ctx
+
+	return &aClient
+}
+
 // AwsKmsClient struct.
 type AwsKmsClient struct {
+	ctx    context.Context
 	kmssvc *kms.KMS
 }
 
 // Init the aws kms client.
-func NewAwsKmsClient(accessKeyID []byte, secureKey []byte, clusterRegion string) *AwsKmsClient {
-	awsSession := newAwsSession(accessKeyID, secureKey, clusterRegion)
+// The optional session token is required when the credentials are temporary, e.g. on STS clusters.
+func NewAwsKmsClient(accessKeyID []byte, secureKey []byte, clusterRegion string, sessionToken ...[]byte) *AwsKmsClient {
+	awsSession := newAwsSession(accessKeyID, secureKey, clusterRegion, sessionToken...)
 	kmsClient := &AwsKmsClient{
+		ctx:    context.Background(),
 		kmssvc: kms.New(awsSession),
 	}
 
 	return kmsClient
 }
 
+// WithContext returns a copy of the client whose API calls, including their retries,
+// are bound to the given context.
+func (akms *AwsKmsClient) WithContext(ctx context.Context) *AwsKmsClient {
+	kmsClient := *akms
+	kmsClient.ctx = ctx
+
+	return &kmsClient
+}
+
 // Create aws backend session connection.
-func newAwsSession(accessKeyID []byte, secureKey []byte, clusterRegion string) *session.Session {
+// Throttled and transient failures are retried according to CloudAPIRetry,
+// and every call is recorded in CloudAPICalls.
+func newAwsSession(accessKeyID []byte, secureKey []byte, clusterRegion string, sessionToken ...[]byte) *session.Session {
+	var token string
+	if len(sessionToken) > 0 {
+		token = string(sessionToken[0])
+	}
+
+	return newAwsSessionWithCredentials(credentials.NewStaticCredentials(string(accessKeyID), string(secureKey), token), clusterRegion)
+}
+
+// newAwsSessionWithCredentials creates an aws session using the given credentials.
+func newAwsSessionWithCredentials(creds *credentials.Credentials, clusterRegion string) *session.Session {
 	awsConfig := &aws.Config{
-		Region: aws.String(clusterRegion),
-		Credentials: credentials.NewStaticCredentials(
-			string(accessKeyID),
-			string(secureKey),
-			"",
-		),
+		Region:      aws.String(clusterRegion),
+		Credentials: creds,
 	}
+	request.WithRetryer(awsConfig, awsRetryer(CloudAPIRetry))
 
-	return session.Must(session.NewSession(awsConfig))
+	awsSession := session.Must(session.NewSession(awsConfig))
+	instrumentAWSHandlers(&awsSession.Handlers, CloudAPICalls)
+
+	return awsSession
 }
 
 // CreateCapacityReservation Create CapacityReservation.
@@ -68,7 +121,7 @@ func (a *AwsClient) CreateCapacityReservation(instanceType string, instancePlatf
 		EndDateType:           aws.String("limited"),
 		EndDate:               timePtr(time.Now().Add(35 * time.Minute)),
 	}
-	result, err := a.svc.CreateCapacityReservation(input)
+	result, err := a.svc.CreateCapacityReservationWithContext(a.ctx, input)
 
 	if err != nil {
 		return "", fmt.Errorf("error creating capacity reservation: %w", err)
@@ -85,7 +138,7 @@ func (a *AwsClient) CancelCapacityReservation(capacityReservationID string) (boo
 	input := &ec2.CancelCapacityReservationInput{
 		CapacityReservationId: aws.String(capacityReservationID),
 	}
-	result, err := a.svc.CancelCapacityReservation(input)
+	result, err := a.svc.CancelCapacityReservationWithContext(a.ctx, input)
 
 	return ptr.Deref(result.Return, false), err
 }
@@ -106,7 +159,7 @@ func (a *AwsClient) CreatePlacementGroup(groupName string, strategy string, part
 		}
 	}
 
-	result, err := a.svc.CreatePlacementGroup(input)
+	result, err := a.svc.CreatePlacementGroupWithContext(a.ctx, input)
 
 	if err != nil {
 		return "", fmt.Errorf("error creating placement group: %w", err)
@@ -123,7 +176,7 @@ func (a *AwsClient) DeletePlacementGroup(groupName string) (string, error) {
 	input := &ec2.DeletePlacementGroupInput{
 		GroupName: aws.String(groupName),
 	}
-	result, err := a.svc.DeletePlacementGroup(input)
+	result, err := a.svc.DeletePlacementGroupWithContext(a.ctx, input)
 
 	if err != nil {
 		return "", fmt.Errorf("could not delete placement group: %w", err)
@@ -132,12 +185,401 @@ func (a *AwsClient) DeletePlacementGroup(groupName string) (string, error) {
 	return result.String(), nil
 }
 
+// AllocateHosts allocates dedicated hosts for the given instance type in the given availability zone.
+// Auto placement is enabled, so that instances launched with host tenancy in the
+// availability zone are placed on the allocated hosts.
+func (a *AwsClient) AllocateHosts(instanceType string, availabilityZone string, quantity int64) ([]string, error) {
+	input := &ec2.AllocateHostsInput{
+		InstanceType:     aws.String(instanceType),
+		AvailabilityZone: aws.String(availabilityZone),
+		Quantity:         aws.Int64(quantity),
+		AutoPlacement:    aws.String(ec2.AutoPlacementOn),
+	}
+	result, err := a.svc.AllocateHostsWithContext(a.ctx, input)
+
+	if err != nil {
+		return nil, fmt.Errorf("error allocating dedicated hosts: %w", err)
+	}
+
+	hostIDs := aws.StringValueSlice(result.HostIds)
+	klog.Infof("The allocated dedicated hostIDs are %v", hostIDs)
+
+	return hostIDs, nil
+}
+
+// ReleaseHosts releases the given dedicated hosts.
+// Hosts can only be released once all the instances running on them are terminated.
+func (a *AwsClient) ReleaseHosts(hostIDs ...string) error {
+	input := &ec2.ReleaseHostsInput{
+		HostIds: aws.StringSlice(hostIDs),
+	}
+	result, err := a.svc.ReleaseHostsWithContext(a.ctx, input)
+
+	if err != nil {
+		return fmt.Errorf("error releasing dedicated hosts: %w", err)
+	}
+
+	if len(result.Unsuccessful) > 0 {
+		return fmt.Errorf("%w: %s", errReleaseHostsFailed, result.Unsuccessful)
+	}
+
+	return nil
+}
+
+// DescribeInstance returns the EC2 instance with the given ID.
+func (a *AwsClient) DescribeInstance(instanceID string) (*ec2.Instance, error) {
+	input := &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	}
+	result, err := a.svc.DescribeInstancesWithContext(a.ctx, input)
+
+	if err != nil {
+		return nil, fmt.Errorf("error describing instance %s: %w", instanceID, err)
+	}
+
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			if ptr.Deref(instance.InstanceId, "") == instanceID {
+				return instance, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errInstanceNotFound, instanceID)
+}
+
+// DescribeInstanceByProviderID returns the EC2 instance backing the machine with the given provider ID.
+func (a *AwsClient) DescribeInstanceByProviderID(providerID string) (*ec2.Instance, error) {
+	instanceID, err := AWSInstanceIDFromProviderID(providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.DescribeInstance(instanceID)
+}
+
+// DescribeInstanceVolume returns the EBS volume attached to the instance under the given device name.
+func (a *AwsClient) DescribeInstanceVolume(instance *ec2.Instance, deviceName string) (*ec2.Volume, error) {
+	var volumeID string
+
+	for _, mapping := range instance.BlockDeviceMappings {
+		if ptr.Deref(mapping.DeviceName, "") == deviceName && mapping.Ebs != nil {
+			volumeID = ptr.Deref(mapping.Ebs.VolumeId, "")
+		}
+	}
+
+	if volumeID == "" {
+		return nil, fmt.Errorf("%w: no volume attached to instance %s as %s", errVolumeNotFound, ptr.Deref(instance.InstanceId, ""), deviceName)
+	}
+
+	result, err := a.svc.DescribeVolumesWithContext(a.ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volumeID)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing volume %s: %w", volumeID, err)
+	}
+
+	if len(result.Volumes) == 0 {
+		return nil, fmt.Errorf("%w: %s", errVolumeNotFound, volumeID)
+	}
+
+	return result.Volumes[0], nil
+}
+
+// GetEBSDefaultKMSKeyID returns the KMS key the account encrypts the EBS volumes with by default,
+// which is the AWS managed key alias/aws/ebs unless the account set a customer managed key.
+func (a *AwsClient) GetEBSDefaultKMSKeyID() (string, error) {
+	result, err := a.svc.GetEbsDefaultKmsKeyIdWithContext(a.ctx, &ec2.GetEbsDefaultKmsKeyIdInput{})
+	if err != nil {
+		return "", fmt.Errorf("error getting the default EBS KMS key: %w", err)
+	}
+
+	return ptr.Deref(result.KmsKeyId, ""), nil
+}
+
+// GetServiceQuota returns the applied value of the given service quota.
+func (a *AwsClient) GetServiceQuota(serviceCode, quotaCode string) (float64, error) {
+	input := &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	}
+	result, err := a.quotasvc.GetServiceQuotaWithContext(a.ctx, input)
+
+	if err != nil {
+		return 0, fmt.Errorf("error getting service quota %s/%s: %w", serviceCode, quotaCode, err)
+	}
+
+	return ptr.Deref(result.Quota.Value, 0), nil
+}
+
+// GetInstanceTypeVCPUs returns the default number of vCPUs of the given instance type.
+func (a *AwsClient) GetInstanceTypeVCPUs(instanceType string) (int64, error) {
+	input := &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	}
+	result, err := a.svc.DescribeInstanceTypesWithContext(a.ctx, input)
+
+	if err != nil {
+		return 0, fmt.Errorf("error describing instance type %s: %w", instanceType, err)
+	}
+
+	if len(result.InstanceTypes) == 0 || result.InstanceTypes[0].VCpuInfo == nil {
+		return 0, fmt.Errorf("%w: %s", errInstanceTypeNotFound, instanceType)
+	}
+
+	return ptr.Deref(result.InstanceTypes[0].VCpuInfo.DefaultVCpus, 0), nil
+}
+
+// GetRunningVCPUs returns the number of vCPUs used by the pending and running instances
+// whose instance type matches the given filter.
+func (a *AwsClient) GetRunningVCPUs(instanceTypeFilter func(instanceType string) bool) (int64, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}),
+			},
+		},
+	}
+
+	var vCPUs int64
+
+	if err := a.svc.DescribeInstancesPagesWithContext(a.ctx, input, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if !instanceTypeFilter(ptr.Deref(instance.InstanceType, "")) || instance.CpuOptions == nil {
+					continue
+				}
+
+				vCPUs += ptr.Deref(instance.CpuOptions.CoreCount, 0) * ptr.Deref(instance.CpuOptions.ThreadsPerCore, 1)
+			}
+		}
+
+		return true
+	}); err != nil {
+		return 0, fmt.Errorf("error describing instances: %w", err)
+	}
+
+	return vCPUs, nil
+}
+
+// DescribeSubnetIDs returns the IDs of the subnets matching the given filters.
+func (a *AwsClient) DescribeSubnetIDs(filters []*ec2.Filter) ([]string, error) {
+	input := &ec2.DescribeSubnetsInput{
+		Filters: filters,
+	}
+	result, err := a.svc.DescribeSubnetsWithContext(a.ctx, input)
+
+	if err != nil {
+		return nil, fmt.Errorf("error describing subnets: %w", err)
+	}
+
+	subnetIDs := make([]string, 0, len(result.Subnets))
+	for _, subnet := range result.Subnets {
+		subnetIDs = append(subnetIDs, ptr.Deref(subnet.SubnetId, ""))
+	}
+
+	return subnetIDs, nil
+}
+
+// DescribeSecurityGroupIDs returns the IDs of the security groups matching the given filters.
+func (a *AwsClient) DescribeSecurityGroupIDs(filters []*ec2.Filter) ([]string, error) {
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: filters,
+	}
+	result, err := a.svc.DescribeSecurityGroupsWithContext(a.ctx, input)
+
+	if err != nil {
+		return nil, fmt.Errorf("error describing security groups: %w", err)
+	}
+
+	groupIDs := make([]string, 0, len(result.SecurityGroups))
+	for _, group := range result.SecurityGroups {
+		groupIDs = append(groupIDs, ptr.Deref(group.GroupId, ""))
+	}
+
+	return groupIDs, nil
+}
+
+// GetSubnetVPCID returns the ID of the VPC of the subnet with the given ID.
+func (a *AwsClient) GetSubnetVPCID(subnetID string) (string, error) {
+	result, err := a.svc.DescribeSubnetsWithContext(a.ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: []*string{aws.String(subnetID)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error describing subnet %s: %w", subnetID, err)
+	}
+
+	if len(result.Subnets) == 0 {
+		return "", fmt.Errorf("%w: %s", errSubnetNotFound, subnetID)
+	}
+
+	return ptr.Deref(result.Subnets[0].VpcId, ""), nil
+}
+
+// GetPublicSubnetID returns the ID of a public subnet of the VPC in the given availability zone:
+// a subnet whose route table routes to an internet gateway.
+func (a *AwsClient) GetPublicSubnetID(vpcID string, availabilityZone string) (string, error) {
+	subnets, err := a.svc.DescribeSubnetsWithContext(a.ctx, &ec2.DescribeSubnetsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{vpcID})},
+			{Name: aws.String("availability-zone"), Values: aws.StringSlice([]string{availabilityZone})},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error describing subnets of VPC %s: %w", vpcID, err)
+	}
+
+	for _, subnet := range subnets.Subnets {
+		routeTables, err := a.svc.DescribeRouteTablesWithContext(a.ctx, &ec2.DescribeRouteTablesInput{
+			Filters: []*ec2.Filter{
+				{Name: aws.String("association.subnet-id"), Values: []*string{subnet.SubnetId}},
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("error describing route tables of subnet %s: %w", ptr.Deref(subnet.SubnetId, ""), err)
+		}
+
+		for _, routeTable := range routeTables.RouteTables {
+			for _, route := range routeTable.Routes {
+				if strings.HasPrefix(ptr.Deref(route.GatewayId, ""), "igw-") {
+					return ptr.Deref(subnet.SubnetId, ""), nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: no public subnet in VPC %s and zone %s", errSubnetNotFound, vpcID, availabilityZone)
+}
+
+// CreateSecurityGroup creates a security group without rules in the given VPC and returns its ID.
+func (a *AwsClient) CreateSecurityGroup(groupName string, vpcID string, description string) (string, error) {
+	result, err := a.svc.CreateSecurityGroupWithContext(a.ctx, &ec2.CreateSecurityGroupInput{
+		GroupName:   aws.String(groupName),
+		VpcId:       aws.String(vpcID),
+		Description: aws.String(description),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating security group %s: %w", groupName, err)
+	}
+
+	klog.Infof("security group created: %s", ptr.Deref(result.GroupId, ""))
+
+	return ptr.Deref(result.GroupId, ""), nil
+}
+
+// DeleteSecurityGroup deletes the security group with the given ID.
+// It fails as long as network interfaces are attached to the group.
+func (a *AwsClient) DeleteSecurityGroup(groupID string) error {
+	if _, err := a.svc.DeleteSecurityGroupWithContext(a.ctx, &ec2.DeleteSecurityGroupInput{
+		GroupId: aws.String(groupID),
+	}); err != nil {
+		return fmt.Errorf("error deleting security group %s: %w", groupID, err)
+	}
+
+	return nil
+}
+
+// CreateNetworkInterface creates a network interface in the given subnet, with the given security groups
+// and number of secondary private IP addresses, and returns its ID.
+func (a *AwsClient) CreateNetworkInterface(subnetID string, securityGroupIDs []string, secondaryPrivateIPCount int64, description string) (string, error) {
+	input := &ec2.CreateNetworkInterfaceInput{
+		SubnetId:    aws.String(subnetID),
+		Groups:      aws.StringSlice(securityGroupIDs),
+		Description: aws.String(description),
+	}
+
+	if secondaryPrivateIPCount > 0 {
+		input.SecondaryPrivateIpAddressCount = aws.Int64(secondaryPrivateIPCount)
+	}
+
+	result, err := a.svc.CreateNetworkInterfaceWithContext(a.ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("error creating network interface in subnet %s: %w", subnetID, err)
+	}
+
+	networkInterfaceID := ptr.Deref(result.NetworkInterface.NetworkInterfaceId, "")
+	klog.Infof("network interface created: %s", networkInterfaceID)
+
+	return networkInterfaceID, nil
+}
+
+// DeleteNetworkInterface deletes the network interface with the given ID.
+// It fails as long as the network interface is attached to an instance.
+func (a *AwsClient) DeleteNetworkInterface(networkInterfaceID string) error {
+	if _, err := a.svc.DeleteNetworkInterfaceWithContext(a.ctx, &ec2.DeleteNetworkInterfaceInput{
+		NetworkInterfaceId: aws.String(networkInterfaceID),
+	}); err != nil {
+		return fmt.Errorf("error deleting network interface %s: %w", networkInterfaceID, err)
+	}
+
+	return nil
+}
+
+// AWSInstanceNetworkInterface returns the network interface of the instance with the given ID, nil if it is not attached.
+func AWSInstanceNetworkInterface(instance *ec2.Instance, networkInterfaceID string) *ec2.InstanceNetworkInterface {
+	for _, networkInterface := range instance.NetworkInterfaces {
+		if ptr.Deref(networkInterface.NetworkInterfaceId, "") == networkInterfaceID {
+			return networkInterface
+		}
+	}
+
+	return nil
+}
+
+// AWSInstanceENISecurityGroupIDs returns the IDs of the security groups attached to the network interfaces of the instance.
+func AWSInstanceENISecurityGroupIDs(instance *ec2.Instance) []string {
+	var groupIDs []string
+
+	for _, networkInterface := range instance.NetworkInterfaces {
+		for _, group := range networkInterface.Groups {
+			groupIDs = append(groupIDs, ptr.Deref(group.GroupId, ""))
+		}
+	}
+
+	return groupIDs
+}
+
+// AssumeRoleWithWebIdentity exchanges the given web identity token for temporary credentials of the given role.
+// The request is not signed, so no prior credentials are required.
+func AssumeRoleWithWebIdentity(clusterRegion string, roleARN string, token []byte) ([]byte, []byte, []byte, error) {
+	stssvc := sts.New(newAwsSessionWithCredentials(credentials.AnonymousCredentials, clusterRegion))
+
+	result, err := stssvc.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(roleARN),
+		RoleSessionName:  aws.String(fmt.Sprintf("machine-api-e2e-%d", time.Now().Unix())),
+		WebIdentityToken: aws.String(string(token)),
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error assuming role %s with web identity: %w", roleARN, err)
+	}
+
+	creds := result.Credentials
+
+	return []byte(ptr.Deref(creds.AccessKeyId, "")), []byte(ptr.Deref(creds.SecretAccessKey, "")), []byte(ptr.Deref(creds.SessionToken, "")), nil
+}
+
+// AWSInstanceIDFromProviderID returns the EC2 instance ID from a provider ID
+// in the form aws:///<availability-zone>/<instance-id>.
+func AWSInstanceIDFromProviderID(providerID string) (string, error) {
+	if !strings.HasPrefix(providerID, "aws://") {
+		return "", fmt.Errorf("%w: %q", errInvalidAWSProviderID, providerID)
+	}
+
+	instanceID := providerID[strings.LastIndex(providerID, "/")+1:]
+	if !strings.HasPrefix(instanceID, "i-") {
+		return "", fmt.Errorf("%w: %q", errInvalidAWSProviderID, providerID)
+	}
+
+	return instanceID, nil
+}
+
 // Describes aws customer managed kms key info.
 func (akms *AwsKmsClient) DescribeKeyByID(kmsKeyID string) (string, error) {
 	input := &kms.DescribeKeyInput{
 		KeyId: aws.String(kmsKeyID),
 	}
-	result, err := akms.kmssvc.DescribeKey(input)
+	result, err := akms.kmssvc.DescribeKeyWithContext(akms.ctx, input)
 
 	if err != nil {
 		return "", fmt.Errorf("could not get the key: %w", err)
@@ -146,9 +588,21 @@ func (akms *AwsKmsClient) DescribeKeyByID(kmsKeyID string) (string, error) {
 	return result.String(), nil
 }
 
+// GetKeyARN returns the ARN of the KMS key with the given ID, ARN or alias.
+func (akms *AwsKmsClient) GetKeyARN(keyID string) (string, error) {
+	result, err := akms.kmssvc.DescribeKeyWithContext(akms.ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(keyID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not get the key %s: %w", keyID, err)
+	}
+
+	return ptr.Deref(result.KeyMetadata.Arn, ""), nil
+}
+
 // CreateKey create a key.
 func (akms *AwsKmsClient) CreateKey(description string) (string, error) {
-	createRes, err := akms.kmssvc.CreateKey(&kms.CreateKeyInput{
+	createRes, err := akms.kmssvc.CreateKeyWithContext(akms.ctx, &kms.CreateKeyInput{
 		Description: aws.String(description),
 	})
 	if err != nil {
@@ -163,7 +617,7 @@ func (akms *AwsKmsClient) CreateKey(description string) (string, error) {
 
 // DeleteKey delete a key.
 func (akms *AwsKmsClient) DeleteKey(key string) error {
-	_, err := akms.kmssvc.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+	_, err := akms.kmssvc.ScheduleKeyDeletionWithContext(akms.ctx, &kms.ScheduleKeyDeletionInput{
 		KeyId:               aws.String(key),
 		PendingWindowInDays: aws.Int64(7),
 	})
@@ -0,0 +1,197 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	azureInstanceMetadataEndpoint   = "http://169.254.169.254/metadata/instance"
+	azureInstanceMetadataAPIVersion = "2021-02-01"
+
+	// AzureMarketplaceImagePublisher, AzureMarketplaceImageOffer and AzureMarketplaceImageSKU identify the
+	// OpenShift worker image of the Azure Marketplace, which is sold with a purchase plan of the same name.
+	AzureMarketplaceImagePublisher = "redhat"
+	AzureMarketplaceImageOffer     = "rh-ocp-worker"
+	AzureMarketplaceImageSKU       = "rh-ocp-worker"
+)
+
+var errNotAzureGalleryImage = errors.New("not an Azure compute gallery image")
+
+// azureGalleryImageIDRegexp matches the resource ID of an image version of an Azure compute gallery,
+// with or without its subscription.
+var azureGalleryImageIDRegexp = regexp.MustCompile(`(?i)^(?:/subscriptions/([^/]+))?/resourceGroups/([^/]+)/providers/Microsoft\.Compute/galleries/([^/]+)/images/([^/]+)/versions/([^/]+)$`)
+
+// AzureGalleryImage identifies an image version of an Azure compute gallery.
+type AzureGalleryImage struct {
+	// SubscriptionID is empty when the resource ID did not include it.
+	SubscriptionID string
+	ResourceGroup  string
+	Gallery        string
+	Name           string
+	Version        string
+}
+
+// ParseAzureGalleryImageID parses the resource ID of an image version of an Azure compute gallery.
+func ParseAzureGalleryImageID(resourceID string) (*AzureGalleryImage, error) {
+	matches := azureGalleryImageIDRegexp.FindStringSubmatch(resourceID)
+	if matches == nil {
+		return nil, fmt.Errorf("%w: %q", errNotAzureGalleryImage, resourceID)
+	}
+
+	return &AzureGalleryImage{
+		SubscriptionID: matches[1],
+		ResourceGroup:  matches[2],
+		Gallery:        matches[3],
+		Name:           matches[4],
+		Version:        matches[5],
+	}, nil
+}
+
+// AzureInstanceMetadata is the subset of the Azure Instance Metadata Service
+// compute and network documents used by the tests. More details can be found here:
+// https://learn.microsoft.com/en-us/azure/virtual-machines/instance-metadata-service
+type AzureInstanceMetadata struct {
+	Compute AzureComputeMetadata `json:"compute"`
+	Network AzureNetworkMetadata `json:"network"`
+}
+
+// AzureComputeMetadata describes the virtual machine.
+type AzureComputeMetadata struct {
+	VMSize         string                      `json:"vmSize"`
+	Zone           string                      `json:"zone"`
+	StorageProfile AzureStorageProfileMetadata `json:"storageProfile"`
+	TagsList       []AzureTagMetadata          `json:"tagsList"`
+	Plan           AzurePlanMetadata           `json:"plan"`
+}
+
+// AzurePlanMetadata is the purchase plan of the marketplace image of the virtual machine.
+// It is empty when the image has no plan.
+type AzurePlanMetadata struct {
+	Name      string `json:"name"`
+	Product   string `json:"product"`
+	Publisher string `json:"publisher"`
+}
+
+// AzureTagMetadata is a tag of the virtual machine.
+type AzureTagMetadata struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Tags returns the tags of the virtual machine, keyed by name.
+func (m AzureComputeMetadata) Tags() map[string]string {
+	tags := make(map[string]string, len(m.TagsList))
+
+	for _, tag := range m.TagsList {
+		tags[tag.Name] = tag.Value
+	}
+
+	return tags
+}
+
+// AzureStorageProfileMetadata describes the disks of the virtual machine.
+type AzureStorageProfileMetadata struct {
+	ImageReference AzureImageReferenceMetadata `json:"imageReference"`
+	OSDisk         AzureDiskMetadata           `json:"osDisk"`
+	DataDisks      []AzureDiskMetadata         `json:"dataDisks"`
+}
+
+// AzureImageReferenceMetadata describes the image the virtual machine was created from:
+// its resource ID for a gallery image, or its publisher, offer, SKU and version for a marketplace image.
+type AzureImageReferenceMetadata struct {
+	ID        string `json:"id"`
+	Publisher string `json:"publisher"`
+	Offer     string `json:"offer"`
+	SKU       string `json:"sku"`
+	Version   string `json:"version"`
+}
+
+// AzureDiskMetadata describes a disk of the virtual machine.
+type AzureDiskMetadata struct {
+	Name             string `json:"name"`
+	Caching          string `json:"caching"`
+	DiskSizeGB       string `json:"diskSizeGB"`
+	Lun              string `json:"lun"`
+	DiffDiskSettings struct {
+		Option string `json:"option"`
+	} `json:"diffDiskSettings"`
+	ManagedDisk struct {
+		StorageAccountType string `json:"storageAccountType"`
+	} `json:"managedDisk"`
+}
+
+// AzureNetworkMetadata describes the network interfaces of the virtual machine.
+type AzureNetworkMetadata struct {
+	Interface []AzureInterfaceMetadata `json:"interface"`
+}
+
+// AzureInterfaceMetadata describes a network interface of the virtual machine.
+type AzureInterfaceMetadata struct {
+	MacAddress string `json:"macAddress"`
+	IPv4       struct {
+		IPAddress []struct {
+			PrivateIPAddress string `json:"privateIpAddress"`
+		} `json:"ipAddress"`
+		Subnet []struct {
+			Address string `json:"address"`
+			Prefix  string `json:"prefix"`
+		} `json:"subnet"`
+	} `json:"ipv4"`
+}
+
+// GetAzureInstanceMetadata queries the Azure Instance Metadata Service from the given node
+// and returns the metadata of the virtual machine backing it.
+func GetAzureInstanceMetadata(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node) (*AzureInstanceMetadata, error) {
+	podSpec := corev1.PodSpec{
+		HostNetwork: true,
+		Containers: []corev1.Container{
+			{
+				Name:    "azure-instance-metadata",
+				Image:   "registry.access.redhat.com/ubi8/ubi-minimal:latest",
+				Command: []string{"curl"},
+				Args: []string{
+					"--silent", "--fail",
+					"--header", "Metadata: true",
+					fmt.Sprintf("%s?api-version=%s", azureInstanceMetadataEndpoint, azureInstanceMetadataAPIVersion),
+				},
+			},
+		},
+		Tolerations: []corev1.Toleration{
+			{
+				Operator: corev1.TolerationOpExists,
+			},
+		},
+	}
+
+	logs, err := RunPodOnNodeToCompletion(ctx, clientset, node, podSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &AzureInstanceMetadata{}
+	if err := json.Unmarshal([]byte(logs), metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling Azure instance metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// IsAzureAcceleratedNetworkingActive returns true when the given node has a virtual function
+// bonded to one of its network interfaces, which is how Azure exposes accelerated networking
+// to the guest. See https://learn.microsoft.com/en-us/azure/virtual-network/accelerated-networking-how-it-works
+func IsAzureAcceleratedNetworkingActive(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node) (bool, error) {
+	output, err := RunHostCommandOnNode(ctx, clientset, node, "find", "/sys/class/net/", "-mindepth", "2", "-maxdepth", "2", "-name", "master")
+	if err != nil {
+		return false, fmt.Errorf("error listing bonded network interfaces: %w", err)
+	}
+
+	return strings.TrimSpace(output) != "", nil
+}
@@ -0,0 +1,306 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// machineAPIAzureCredentialsSecret is the credentials secret of the Machine API on Azure.
+	machineAPIAzureCredentialsSecret = "azure-cloud-credentials"
+
+	// azureTokenEndpoint is the Microsoft Entra ID endpoint issuing tokens for client credentials.
+	azureTokenEndpoint = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+
+	// azureWorkloadIdentityTokenAudience is the audience of the service account tokens exchanged for
+	// Microsoft Entra ID tokens by workload identity.
+	azureWorkloadIdentityTokenAudience = "api://AzureADTokenExchange"
+
+	// azureClientAssertionType is the type of the client assertions of workload identity, the service account tokens.
+	azureClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// azureAvailabilitySetFaultDomains and azureAvailabilitySetUpdateDomains are supported by every region
+	// with availability sets of managed disks.
+	azureAvailabilitySetFaultDomains  = 2
+	azureAvailabilitySetUpdateDomains = 5
+)
+
+// ErrAzureClientSecretMissing is returned by NewAzureClient when the credentials secret of the Machine API
+// has neither a client secret nor a federated token file, e.g. on clusters using a managed identity.
+var ErrAzureClientSecretMissing = errors.New("secret has neither a client secret nor a federated token file")
+
+var (
+	errAzureTokenRequestFailed = errors.New("token request failed")
+	errAzureVMSizeNotFound     = errors.New("virtual machine size not found")
+)
+
+// AzureClient verifies and prepares the Azure resources of the tests with the Azure Resource Manager API.
+type AzureClient struct {
+	ctx              context.Context
+	virtualMachines  *armcompute.VirtualMachinesClient
+	availabilitySets *armcompute.AvailabilitySetsClient
+	usages           *armcompute.UsageClient
+	resourceSKUs     *armcompute.ResourceSKUsClient
+}
+
+// NewAzureClient creates an Azure client with the Machine API credentials: its client secret or, on clusters
+// using workload identity, bound tokens of its service account exchanged for Microsoft Entra ID tokens.
+func NewAzureClient(ctx context.Context, client runtimeclient.Client) (*AzureClient, error) {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, runtimeclient.ObjectKey{Namespace: MachineAPINamespace, Name: machineAPIAzureCredentialsSecret}, secret); err != nil {
+		return nil, fmt.Errorf("error getting secret %s/%s: %w", MachineAPINamespace, machineAPIAzureCredentialsSecret, err)
+	}
+
+	credential := &azureClientCredential{
+		tenantID:     string(secret.Data["azure_tenant_id"]),
+		clientID:     string(secret.Data["azure_client_id"]),
+		clientSecret: string(secret.Data["azure_client_secret"]),
+	}
+
+	switch {
+	case credential.clientSecret != "":
+	case len(secret.Data["azure_federated_token_file"]) > 0:
+		credential.clientAssertion = func(ctx context.Context) (string, error) {
+			return createServiceAccountToken(ctx, client, MachineAPINamespace, machineAPIControllersServiceAccount, azureWorkloadIdentityTokenAudience)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s/%s", ErrAzureClientSecretMissing, MachineAPINamespace, machineAPIAzureCredentialsSecret)
+	}
+
+	subscriptionID := string(secret.Data["azure_subscription_id"])
+
+	virtualMachines, err := armcompute.NewVirtualMachinesClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Azure virtual machines client: %w", err)
+	}
+
+	availabilitySets, err := armcompute.NewAvailabilitySetsClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Azure availability sets client: %w", err)
+	}
+
+	usages, err := armcompute.NewUsageClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Azure usage client: %w", err)
+	}
+
+	resourceSKUs, err := armcompute.NewResourceSKUsClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Azure resource SKUs client: %w", err)
+	}
+
+	return &AzureClient{
+		ctx:              ctx,
+		virtualMachines:  virtualMachines,
+		availabilitySets: availabilitySets,
+		usages:           usages,
+		resourceSKUs:     resourceSKUs,
+	}, nil
+}
+
+// WithContext returns a copy of the client whose API calls are bound to the given context.
+func (a *AzureClient) WithContext(ctx context.Context) *AzureClient {
+	aClient := *a
+	aClient.ctx = ctx
+
+	return &aClient
+}
+
+// GetVirtualMachine returns the virtual machine of the given resource group.
+// The virtual machines of the Machine API are named after their machines.
+func (a *AzureClient) GetVirtualMachine(resourceGroup string, name string) (*armcompute.VirtualMachine, error) {
+	result, err := a.virtualMachines.Get(a.ctx, resourceGroup, name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting virtual machine %s: %w", name, err)
+	}
+
+	return &result.VirtualMachine, nil
+}
+
+// VirtualMachineExists returns whether the virtual machine of the given resource group exists.
+func (a *AzureClient) VirtualMachineExists(resourceGroup string, name string) (bool, error) {
+	_, err := a.GetVirtualMachine(resourceGroup, name)
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// GetBootDiagnosticsSerialConsoleLog returns the serial console log of the boot diagnostics of the virtual machine,
+// which must have boot diagnostics enabled.
+func (a *AzureClient) GetBootDiagnosticsSerialConsoleLog(resourceGroup string, name string) (string, error) {
+	result, err := a.virtualMachines.RetrieveBootDiagnosticsData(a.ctx, resourceGroup, name, nil)
+	if err != nil {
+		return "", fmt.Errorf("error retrieving boot diagnostics of virtual machine %s: %w", name, err)
+	}
+
+	if ptr.Deref(result.SerialConsoleLogBlobURI, "") == "" {
+		return "", fmt.Errorf("%w: virtual machine %s", errConsoleOutputEmpty, name)
+	}
+
+	return httpGet(a.ctx, *result.SerialConsoleLogBlobURI)
+}
+
+// CreateAvailabilitySet creates an availability set for virtual machines with managed disks and returns its ID.
+func (a *AzureClient) CreateAvailabilitySet(resourceGroup string, location string, name string) (string, error) {
+	result, err := a.availabilitySets.CreateOrUpdate(a.ctx, resourceGroup, name, armcompute.AvailabilitySet{
+		Location: ptr.To(location),
+		SKU: &armcompute.SKU{
+			// Virtual machines with managed disks require an aligned availability set.
+			Name: ptr.To("Aligned"),
+		},
+		Properties: &armcompute.AvailabilitySetProperties{
+			PlatformFaultDomainCount:  ptr.To[int32](azureAvailabilitySetFaultDomains),
+			PlatformUpdateDomainCount: ptr.To[int32](azureAvailabilitySetUpdateDomains),
+		},
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating availability set %s: %w", name, err)
+	}
+
+	return ptr.Deref(result.ID, ""), nil
+}
+
+// DeleteAvailabilitySet deletes the availability set, which fails while it has virtual machines.
+func (a *AzureClient) DeleteAvailabilitySet(resourceGroup string, name string) error {
+	if _, err := a.availabilitySets.Delete(a.ctx, resourceGroup, name, nil); err != nil {
+		return fmt.Errorf("error deleting availability set %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// GetComputeUsages returns the compute usages of the subscription in the location by name,
+// e.g. "cores" for the total regional vCPUs and "standardDSv3Family" for those of a VM size family.
+func (a *AzureClient) GetComputeUsages(location string) (map[string]*armcompute.Usage, error) {
+	usages := map[string]*armcompute.Usage{}
+
+	pager := a.usages.NewListPager(location, nil)
+	for pager.More() {
+		page, err := pager.NextPage(a.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing compute usages in %s: %w", location, err)
+		}
+
+		for _, usage := range page.Value {
+			if usage.Name != nil {
+				usages[ptr.Deref(usage.Name.Value, "")] = usage
+			}
+		}
+	}
+
+	return usages, nil
+}
+
+// GetVirtualMachineSizeVCPUs returns the family, named after its usage, and the number of vCPUs of the VM size in the location.
+func (a *AzureClient) GetVirtualMachineSizeVCPUs(location string, vmSize string) (string, int64, error) {
+	pager := a.resourceSKUs.NewListPager(&armcompute.ResourceSKUsClientListOptions{
+		Filter: ptr.To(fmt.Sprintf("location eq '%s'", location)),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(a.ctx)
+		if err != nil {
+			return "", 0, fmt.Errorf("error listing resource SKUs in %s: %w", location, err)
+		}
+
+		for _, sku := range page.Value {
+			if ptr.Deref(sku.ResourceType, "") != "virtualMachines" || !strings.EqualFold(ptr.Deref(sku.Name, ""), vmSize) {
+				continue
+			}
+
+			for _, capability := range sku.Capabilities {
+				if ptr.Deref(capability.Name, "") != "vCPUs" {
+					continue
+				}
+
+				vCPUs, err := strconv.ParseInt(ptr.Deref(capability.Value, ""), 10, 64)
+				if err != nil {
+					return "", 0, fmt.Errorf("error parsing vCPUs of virtual machine size %s: %w", vmSize, err)
+				}
+
+				return ptr.Deref(sku.Family, ""), vCPUs, nil
+			}
+		}
+	}
+
+	return "", 0, fmt.Errorf("%w: %s in %s", errAzureVMSizeNotFound, vmSize, location)
+}
+
+// azureClientCredential is an azcore.TokenCredential authenticating with a client secret or, when it is empty,
+// a client assertion, as the azidentity module is not vendored. Only the public Azure cloud is supported.
+type azureClientCredential struct {
+	tenantID     string
+	clientID     string
+	clientSecret string
+	// clientAssertion returns a new client assertion, as they are short-lived.
+	clientAssertion func(ctx context.Context) (string, error)
+}
+
+// GetToken implements azcore.TokenCredential.
+func (c *azureClientCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"client_id":  {c.clientID},
+		"scope":      {strings.Join(options.Scopes, " ")},
+	}
+
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	} else {
+		assertion, err := c.clientAssertion(ctx)
+		if err != nil {
+			return azcore.AccessToken{}, err
+		}
+
+		form.Set("client_assertion_type", azureClientAssertionType)
+		form.Set("client_assertion", assertion)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(azureTokenEndpoint, c.tenantID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return azcore.AccessToken{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("error requesting Azure token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return azcore.AccessToken{}, fmt.Errorf("%w: %s", errAzureTokenRequestFailed, resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("error decoding Azure token: %w", err)
+	}
+
+	return azcore.AccessToken{
+		Token:     token.AccessToken,
+		ExpiresOn: time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,144 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// baselineUnownedMachines is the Baseline key of the machines owned by no MachineSet, e.g. the control plane.
+const baselineUnownedMachines = "<none>"
+
+// Baseline is the steady state of the cluster: the number of nodes, the number of machines of each
+// MachineSet and the Available and Degraded status of each ClusterOperator. It is captured before
+// the suite runs and verified after it, to detect the replicas and machines the per-spec cleanup missed.
+// The Progressing status is not part of the baseline, as operators report it on their own schedule.
+type Baseline struct {
+	Nodes            int
+	Machines         map[string]int
+	ClusterOperators map[string]string
+}
+
+// CaptureBaseline captures the current Baseline of the cluster.
+func CaptureBaseline(ctx context.Context, cl runtimeclient.Client) (*Baseline, error) {
+	nodes := &corev1.NodeList{}
+	if err := cl.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("unable to list nodes: %w", err)
+	}
+
+	machines := &machinev1.MachineList{}
+	if err := cl.List(ctx, machines, runtimeclient.InNamespace(MachineAPINamespace)); err != nil {
+		return nil, fmt.Errorf("unable to list machines: %w", err)
+	}
+
+	clusterOperators := &configv1.ClusterOperatorList{}
+	if err := cl.List(ctx, clusterOperators); err != nil {
+		return nil, fmt.Errorf("unable to list ClusterOperators: %w", err)
+	}
+
+	baseline := &Baseline{
+		Nodes:            len(nodes.Items),
+		Machines:         map[string]int{},
+		ClusterOperators: map[string]string{},
+	}
+
+	for i := range machines.Items {
+		owner := baselineUnownedMachines
+		if ref := metav1.GetControllerOf(&machines.Items[i]); ref != nil && ref.Kind == "MachineSet" {
+			owner = ref.Name
+		}
+
+		baseline.Machines[owner]++
+	}
+
+	for _, co := range clusterOperators.Items {
+		baseline.ClusterOperators[co.Name] = fmt.Sprintf("Available=%s, Degraded=%s",
+			clusterOperatorConditionStatus(co, configv1.OperatorAvailable),
+			clusterOperatorConditionStatus(co, configv1.OperatorDegraded))
+	}
+
+	return baseline, nil
+}
+
+// Diff returns the differences of the given Baseline from this one, sorted.
+func (b *Baseline) Diff(other *Baseline) []string {
+	var diff []string
+
+	if b.Nodes != other.Nodes {
+		diff = append(diff, fmt.Sprintf("nodes: %d -> %d", b.Nodes, other.Nodes))
+	}
+
+	for _, owner := range sortedUnion(b.Machines, other.Machines) {
+		if b.Machines[owner] != other.Machines[owner] {
+			diff = append(diff, fmt.Sprintf("machines of MachineSet %s: %d -> %d", owner, b.Machines[owner], other.Machines[owner]))
+		}
+	}
+
+	for _, name := range sortedUnion(b.ClusterOperators, other.ClusterOperators) {
+		before, found := b.ClusterOperators[name]
+		if !found {
+			before = "absent"
+		}
+
+		after, found := other.ClusterOperators[name]
+		if !found {
+			after = "absent"
+		}
+
+		if before != after {
+			diff = append(diff, fmt.Sprintf("ClusterOperator %s: %s -> %s", name, before, after))
+		}
+	}
+
+	return diff
+}
+
+// Verify waits up to WaitLong for the cluster to be back to this Baseline, e.g. while the autoscaler
+// scales down after the last spec, and returns an error listing the differences if it is not.
+func (b *Baseline) Verify(ctx context.Context, cl runtimeclient.Client) error {
+	var diff []string
+
+	err := wait.PollUntilContextTimeout(ctx, RetryMedium, WaitLong, true, func(ctx context.Context) (bool, error) {
+		current, err := CaptureBaseline(ctx, cl)
+		if err != nil {
+			klog.Warningf("[baseline] unable to capture the cluster state: %v", err)
+			return false, nil
+		}
+
+		diff = b.Diff(current)
+
+		return len(diff) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cluster did not return to its baseline state: %w\n%s", err, strings.Join(diff, "\n"))
+	}
+
+	return nil
+}
+
+// clusterOperatorConditionStatus returns the status of the given condition of the ClusterOperator,
+// Unknown when it is not set.
+func clusterOperatorConditionStatus(co configv1.ClusterOperator, conditionType configv1.ClusterStatusConditionType) configv1.ConditionStatus {
+	if condition := cov1helpers.FindStatusCondition(co.Status.Conditions, conditionType); condition != nil {
+		return condition.Status
+	}
+
+	return configv1.ConditionUnknown
+}
+
+// sortedUnion returns the sorted keys of both maps.
+func sortedUnion[V any](a, b map[string]V) []string {
+	keys := sets.KeySet(a).Union(sets.KeySet(b))
+
+	return sets.List(keys)
+}
@@ -0,0 +1,215 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/api/features"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MachineConfigOperatorNamespace is the namespace of the Machine Config Operator.
+	MachineConfigOperatorNamespace = "openshift-machine-config-operator"
+
+	// coreOSBootImagesConfigMap holds the CoreOS stream metadata of the boot images of the release.
+	coreOSBootImagesConfigMap = "coreos-bootimages"
+)
+
+// machineConfigurationGVK is the kind of the MachineConfiguration configuring the Machine Config Operator.
+// Its types are not vendored, so it is handled as an unstructured object.
+var machineConfigurationGVK = schema.GroupVersionKind{Group: "operator.openshift.io", Version: "v1", Kind: "MachineConfiguration"}
+
+var (
+	errBootImagesUnsupportedPlatform = errors.New("boot image management is not supported on the platform")
+	errBootImageNotInStream          = errors.New("no boot image in the CoreOS stream")
+)
+
+// BootImagesFeatureGate returns the feature gate of the boot image management of the given platform.
+func BootImagesFeatureGate(platform configv1.PlatformType) (configv1.FeatureGateName, error) {
+	switch platform {
+	case configv1.GCPPlatformType:
+		return features.FeatureGateManagedBootImages, nil
+	case configv1.AWSPlatformType:
+		return features.FeatureGateManagedBootImagesAWS, nil
+	default:
+		return "", fmt.Errorf("%w: %s", errBootImagesUnsupportedPlatform, platform)
+	}
+}
+
+// SetManagedBootImagesSelector opts the MachineSets matching the given selector in the boot image management
+// of the Machine Config Operator, and the others out. The returned function restores the previous configuration.
+func SetManagedBootImagesSelector(ctx context.Context, cl runtimeclient.Client, selector *metav1.LabelSelector) (func() error, error) {
+	machineConfiguration := &unstructured.Unstructured{}
+	machineConfiguration.SetGroupVersionKind(machineConfigurationGVK)
+
+	if err := cl.Get(ctx, runtimeclient.ObjectKey{Name: "cluster"}, machineConfiguration); err != nil {
+		return nil, fmt.Errorf("error getting MachineConfiguration: %w", err)
+	}
+
+	original, found, err := unstructured.NestedFieldCopy(machineConfiguration.Object, "spec", "managedBootImages")
+	if err != nil {
+		return nil, fmt.Errorf("error reading managedBootImages: %w", err)
+	}
+
+	matchLabels := map[string]interface{}{}
+	for key, value := range selector.MatchLabels {
+		matchLabels[key] = value
+	}
+
+	managedBootImages := map[string]interface{}{
+		"machineManagers": []interface{}{
+			map[string]interface{}{
+				"resource": "machinesets",
+				"apiGroup": machinev1.GroupName,
+				"selection": map[string]interface{}{
+					"mode": "Partial",
+					"partial": map[string]interface{}{
+						"machineResourceSelector": map[string]interface{}{
+							"matchLabels": matchLabels,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := patchManagedBootImages(ctx, cl, managedBootImages); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		if !found {
+			return patchManagedBootImages(ctx, cl, nil)
+		}
+
+		return patchManagedBootImages(ctx, cl, original)
+	}, nil
+}
+
+// patchManagedBootImages sets the managedBootImages of the MachineConfiguration, removing them when nil.
+func patchManagedBootImages(ctx context.Context, cl runtimeclient.Client, managedBootImages interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"managedBootImages": managedBootImages,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling managedBootImages patch: %w", err)
+	}
+
+	machineConfiguration := &unstructured.Unstructured{}
+	machineConfiguration.SetGroupVersionKind(machineConfigurationGVK)
+	machineConfiguration.SetName("cluster")
+
+	if err := cl.Patch(ctx, machineConfiguration, runtimeclient.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("error patching MachineConfiguration: %w", err)
+	}
+
+	return nil
+}
+
+// coreOSStream is the subset of the CoreOS stream metadata holding the boot images of the x86_64 architecture.
+type coreOSStream struct {
+	Architectures struct {
+		X86_64 struct {
+			Images struct {
+				AWS *struct {
+					Regions map[string]struct {
+						Image string `json:"image"`
+					} `json:"regions"`
+				} `json:"aws"`
+				GCP *struct {
+					Project string `json:"project"`
+					Name    string `json:"name"`
+				} `json:"gcp"`
+			} `json:"images"`
+		} `json:"x86_64"`
+	} `json:"architectures"`
+}
+
+// GetStreamBootImage returns the boot image of the release on the given platform, from the CoreOS stream metadata
+// the Machine Config Operator updates the managed MachineSets with: an AMI ID of the region on AWS, an image path on GCP.
+func GetStreamBootImage(ctx context.Context, cl runtimeclient.Client, platform configv1.PlatformType, region string) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := cl.Get(ctx, runtimeclient.ObjectKey{Namespace: MachineConfigOperatorNamespace, Name: coreOSBootImagesConfigMap}, configMap); err != nil {
+		return "", fmt.Errorf("error getting ConfigMap %s: %w", coreOSBootImagesConfigMap, err)
+	}
+
+	var stream coreOSStream
+	if err := json.Unmarshal([]byte(configMap.Data["stream"]), &stream); err != nil {
+		return "", fmt.Errorf("error unmarshalling the CoreOS stream: %w", err)
+	}
+
+	images := stream.Architectures.X86_64.Images
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		if images.AWS != nil && images.AWS.Regions[region].Image != "" {
+			return images.AWS.Regions[region].Image, nil
+		}
+	case configv1.GCPPlatformType:
+		if images.GCP != nil && images.GCP.Name != "" {
+			return fmt.Sprintf("projects/%s/global/images/%s", images.GCP.Project, images.GCP.Name), nil
+		}
+	default:
+		return "", fmt.Errorf("%w: %s", errBootImagesUnsupportedPlatform, platform)
+	}
+
+	return "", fmt.Errorf("%w: %s %s", errBootImageNotInStream, platform, region)
+}
+
+// GetBootImage returns the boot image of the given ProviderSpec: the AMI ID on AWS, the image path on GCP.
+func GetBootImage(providerSpec *machinev1.ProviderSpec, platform configv1.PlatformType) (string, error) {
+	switch platform {
+	case configv1.AWSPlatformType:
+		var awsProviderConfig machinev1.AWSMachineProviderConfig
+		if err := json.Unmarshal(providerSpec.Value.Raw, &awsProviderConfig); err != nil {
+			return "", fmt.Errorf("error unmarshalling providerSpec: %w", err)
+		}
+
+		return ptr.Deref(awsProviderConfig.AMI.ID, ""), nil
+	case configv1.GCPPlatformType:
+		return GetGCPBootImage(providerSpec)
+	default:
+		return "", fmt.Errorf("%w: %s", errBootImagesUnsupportedPlatform, platform)
+	}
+}
+
+// UpdateProviderSpecBootImage creates a new ProviderSpec booting from the given image: the AMI ID on AWS,
+// the image path on GCP.
+func UpdateProviderSpecBootImage(providerSpec *machinev1.ProviderSpec, platform configv1.PlatformType, image string) (*machinev1.ProviderSpec, error) {
+	switch platform {
+	case configv1.AWSPlatformType:
+		var awsProviderConfig machinev1.AWSMachineProviderConfig
+		if err := json.Unmarshal(providerSpec.Value.Raw, &awsProviderConfig); err != nil {
+			return nil, fmt.Errorf("error unmarshalling providerSpec: %w", err)
+		}
+
+		awsProviderConfig.AMI = machinev1.AWSResourceReference{ID: &image}
+
+		updatedProviderSpec, err := json.Marshal(awsProviderConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling providerSpec: %w", err)
+		}
+
+		return &machinev1.ProviderSpec{
+			Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+		}, nil
+	case configv1.GCPPlatformType:
+		return UpdateProviderSpecGCPBootImage(providerSpec, image)
+	default:
+		return nil, fmt.Errorf("%w: %s", errBootImagesUnsupportedPlatform, platform)
+	}
+}
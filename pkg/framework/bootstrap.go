@@ -0,0 +1,186 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BootstrapFailureKind is the stage a machine failed to bootstrap its node at.
+type BootstrapFailureKind string
+
+const (
+	// BootstrapInstanceNotProvisioned is an instance not created by the cloud provider yet.
+	BootstrapInstanceNotProvisioned BootstrapFailureKind = "instance-not-provisioned"
+	// BootstrapInstanceNeverBooted is an instance which never requested a certificate for its kubelet,
+	// e.g. as it could not fetch its ignition config.
+	BootstrapInstanceNeverBooted BootstrapFailureKind = "instance-never-booted"
+	// BootstrapCSRNotApproved is a kubelet whose certificate signing request is still pending.
+	BootstrapCSRNotApproved BootstrapFailureKind = "csr-not-approved"
+	// BootstrapKubeletNotRegistering is a kubelet which got its certificate but never registered its node.
+	BootstrapKubeletNotRegistering BootstrapFailureKind = "kubelet-not-registering"
+	// BootstrapNodeNotReady is a node registered by the kubelet which never became ready.
+	BootstrapNodeNotReady BootstrapFailureKind = "node-not-ready"
+)
+
+// BootstrapFailure is the classified reason a machine has no ready node.
+type BootstrapFailure struct {
+	Machine string
+	Kind    BootstrapFailureKind
+	// Evidence is the state of the cluster the failure was classified by.
+	Evidence string
+}
+
+// String returns the failure on a single line.
+func (f BootstrapFailure) String() string {
+	return fmt.Sprintf("machine %s: %s: %s", f.Machine, f.Kind, f.Evidence)
+}
+
+// ignitionEventPattern matches the events of the machines reporting an issue with their ignition config.
+var ignitionEventPattern = regexp.MustCompile(`(?i)ignition|user\s*data`)
+
+// ClassifyBootstrapFailures classifies the reason each of the machines has no ready node, from the certificate
+// signing requests of the kubelets, the events of the machines and the nodes. The machines with a ready node are skipped.
+func ClassifyBootstrapFailures(ctx context.Context, c runtimeclient.Client, machines []*machinev1.Machine) ([]BootstrapFailure, error) {
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := c.List(ctx, csrs); err != nil {
+		return nil, fmt.Errorf("unable to list certificate signing requests: %w", err)
+	}
+
+	events := &corev1.EventList{}
+	if err := c.List(ctx, events, runtimeclient.InNamespace(MachineAPINamespace)); err != nil {
+		return nil, fmt.Errorf("unable to list events: %w", err)
+	}
+
+	var failures []BootstrapFailure
+
+	for _, machine := range machines {
+		var node *corev1.Node
+
+		if machine.Status.NodeRef != nil {
+			var err error
+
+			node, err = GetNodeForMachine(ctx, c, machine)
+			if runtimeclient.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("unable to get the node of machine %s: %w", machine.Name, err)
+			}
+		}
+
+		if node != nil && IsNodeReady(node) {
+			continue
+		}
+
+		failures = append(failures, classifyBootstrapFailure(machine, node, csrs.Items, events.Items))
+	}
+
+	return failures, nil
+}
+
+// classifyBootstrapFailure classifies the reason the machine has no ready node, the pending certificate signing
+// requests first as they also block the nodes registered by the kubelet from becoming ready.
+func classifyBootstrapFailure(machine *machinev1.Machine, node *corev1.Node, csrs []certificatesv1.CertificateSigningRequest, events []corev1.Event) BootstrapFailure {
+	failure := BootstrapFailure{Machine: machine.Name}
+
+	nodeNames := machineNodeNames(machine)
+
+	var pending, issued []string
+
+	for _, csr := range csrs {
+		if !nodeNames[CSRNodeName(csr)] {
+			continue
+		}
+
+		if IsCSRPending(csr) {
+			pending = append(pending, csr.Name)
+		} else {
+			issued = append(issued, csr.Name)
+		}
+	}
+
+	switch {
+	case len(pending) > 0:
+		failure.Kind = BootstrapCSRNotApproved
+		failure.Evidence = fmt.Sprintf("pending certificate signing requests: %s", strings.Join(pending, ", "))
+	case node != nil:
+		failure.Kind = BootstrapNodeNotReady
+		failure.Evidence = fmt.Sprintf("node %s is not ready", node.Name)
+	case ptr.Deref(machine.Spec.ProviderID, "") == "" || len(machine.Status.Addresses) == 0:
+		failure.Kind = BootstrapInstanceNotProvisioned
+		failure.Evidence = fmt.Sprintf("machine is in phase %q without a provider ID or addresses", ptr.Deref(machine.Status.Phase, ""))
+	case len(issued) > 0:
+		failure.Kind = BootstrapKubeletNotRegistering
+		failure.Evidence = fmt.Sprintf("certificate signing requests issued without a node: %s", strings.Join(issued, ", "))
+	default:
+		failure.Kind = BootstrapInstanceNeverBooted
+		failure.Evidence = "no certificate signing request for the kubelet"
+
+		if messages := ignitionEvents(machine, events); len(messages) > 0 {
+			failure.Evidence += fmt.Sprintf(", ignition events: %s", strings.Join(messages, "; "))
+		}
+	}
+
+	return failure
+}
+
+// machineNodeNames returns the names the node of the machine may be registered with: the name of the machine
+// or one of its host names, depending on the platform.
+func machineNodeNames(machine *machinev1.Machine) map[string]bool {
+	names := map[string]bool{strings.ToLower(machine.Name): true}
+
+	for _, address := range machine.Status.Addresses {
+		if address.Type == corev1.NodeInternalDNS || address.Type == corev1.NodeHostName {
+			names[strings.ToLower(address.Address)] = true
+		}
+	}
+
+	return names
+}
+
+// ignitionEvents returns the messages of the events of the machine about its ignition config.
+func ignitionEvents(machine *machinev1.Machine, events []corev1.Event) []string {
+	var messages []string
+
+	for _, event := range events {
+		if event.InvolvedObject.Name == machine.Name && ignitionEventPattern.MatchString(event.Message) {
+			messages = append(messages, firstLine(event.Message))
+		}
+	}
+
+	return messages
+}
+
+// describeBootstrapFailures returns the classified bootstrap failures of the machines of the MachineSet,
+// to describe the timeouts waiting for their nodes.
+func describeBootstrapFailures(ctx context.Context, c runtimeclient.Client, machineSet *machinev1.MachineSet) string {
+	machines, err := GetMachinesFromMachineSet(ctx, c, machineSet)
+	if err != nil {
+		return fmt.Sprintf("unable to get the machines of MachineSet %q: %v", machineSet.Name, err)
+	}
+
+	failures, err := ClassifyBootstrapFailures(ctx, c, machines)
+	if err != nil {
+		return fmt.Sprintf("unable to classify the bootstrap failures of MachineSet %q: %v", machineSet.Name, err)
+	}
+
+	if len(failures) == 0 {
+		return fmt.Sprintf("MachineSet %q has no machine without a ready node", machineSet.Name)
+	}
+
+	lines := []string{fmt.Sprintf("node bootstrap failed for MachineSet %q:", machineSet.Name)}
+	for _, failure := range failures {
+		lines = append(lines, failure.String())
+	}
+
+	description := strings.Join(lines, "\n")
+	klog.Errorf("%s", description)
+
+	return description
+}
@@ -0,0 +1,159 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+var errSuiteCacheNotStarted = errors.New("suite cache is not started")
+
+// UseSuiteCache makes the waits read from the suite cache, set with the --suite-cache flag.
+// Disabling it makes the waits poll the API server, e.g. to compare the API load of both.
+var UseSuiteCache = true
+
+var (
+	suiteCacheLock      sync.Mutex
+	suiteCacheClient    runtimeclient.Client
+	suiteCacheInformers cache.Informers
+)
+
+// StartSuiteCache starts an informer cache of the Machines, MachineSets and Nodes for the whole suite.
+// While it runs, the long waits such as WaitForMachineSet poll the cache, which is kept up to date
+// by watches, instead of listing the objects from the API server every few seconds.
+// Its informers also feed the Timeline and the ProvisioningRecorder, so the cache is started even when
+// UseSuiteCache is false, the waits then poll the API server. The returned function stops the cache.
+func StartSuiteCache(ctx context.Context) (func(), error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error getting config: %w", err)
+	}
+
+	informerCache, err := cache.New(cfg, cache.Options{
+		ByObject: map[runtimeclient.Object]cache.ByObject{
+			&machinev1.Machine{}:    {Namespaces: map[string]cache.Config{MachineAPINamespace: {}}},
+			&machinev1.MachineSet{}: {Namespaces: map[string]cache.Config{MachineAPINamespace: {}}},
+			// CAPI Machines are only cached once first read, as their CRD is not installed on every cluster.
+			&clusterv1.Machine{}: {Namespaces: map[string]cache.Config{ClusterAPINamespace: {}}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating suite cache: %w", err)
+	}
+
+	// Start the informers of the objects every suite waits on, so that the first wait does not have to.
+	for _, obj := range []runtimeclient.Object{&machinev1.Machine{}, &machinev1.MachineSet{}, &corev1.Node{}} {
+		if _, err := informerCache.GetInformer(ctx, obj); err != nil {
+			return nil, fmt.Errorf("error starting suite cache informer for %T: %w", obj, err)
+		}
+	}
+
+	cl, err := runtimeclient.New(cfg, runtimeclient.Options{
+		Cache: &runtimeclient.CacheOptions{Reader: informerCache},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating suite cache client: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if err := informerCache.Start(ctx); err != nil {
+			klog.Errorf("[cache] suite cache stopped: %v", err)
+		}
+	}()
+
+	if !informerCache.WaitForCacheSync(ctx) {
+		cancel()
+		<-done
+
+		return nil, fmt.Errorf("error syncing suite cache: %w", ctx.Err())
+	}
+
+	suiteCacheLock.Lock()
+	if UseSuiteCache {
+		suiteCacheClient = cl
+	}
+	suiteCacheInformers = informerCache
+	suiteCacheLock.Unlock()
+
+	return func() {
+		suiteCacheLock.Lock()
+		suiteCacheClient = nil
+		suiteCacheInformers = nil
+		suiteCacheLock.Unlock()
+
+		cancel()
+		<-done
+	}, nil
+}
+
+// suiteCache returns a client reading from the suite cache, or nil when the suite cache is not running.
+func suiteCache() runtimeclient.Client {
+	suiteCacheLock.Lock()
+	defer suiteCacheLock.Unlock()
+
+	return suiteCacheClient
+}
+
+// addSuiteCacheHandlers adds the given handlers to the Machine and Node informers of the suite cache,
+// which must be running. The handlers are called until the suite cache is stopped.
+func addSuiteCacheHandlers(ctx context.Context, machineHandler, nodeHandler toolscache.ResourceEventHandler) error {
+	suiteCacheLock.Lock()
+	informers := suiteCacheInformers
+	suiteCacheLock.Unlock()
+
+	if informers == nil {
+		return errSuiteCacheNotStarted
+	}
+
+	machineInformer, err := informers.GetInformer(ctx, &machinev1.Machine{})
+	if err != nil {
+		return fmt.Errorf("error getting Machine informer: %w", err)
+	}
+
+	if _, err := machineInformer.AddEventHandler(machineHandler); err != nil {
+		return fmt.Errorf("could not add Machine event handler: %w", err)
+	}
+
+	nodeInformer, err := informers.GetInformer(ctx, &corev1.Node{})
+	if err != nil {
+		return fmt.Errorf("error getting Node informer: %w", err)
+	}
+
+	if _, err := nodeInformer.AddEventHandler(nodeHandler); err != nil {
+		return fmt.Errorf("could not add Node event handler: %w", err)
+	}
+
+	return nil
+}
+
+// waitCached waits for the check to succeed against the suite cache, when running, and then confirms
+// the result against the API server with the given client, as the cache may lag slightly behind it.
+// The confirmation only allows WaitShort for the API server to catch up, so the API server is usually
+// hit once instead of at every interval. Without the suite cache, the check polls the API server.
+func waitCached(ctx context.Context, c runtimeclient.Client, check func(runtimeclient.Client) error, timeout, interval time.Duration, description ...interface{}) {
+	if cached := suiteCache(); cached != nil {
+		Eventually(ctx, func() error { return check(cached) }, timeout, interval).Should(Succeed(), description...)
+
+		timeout = min(timeout, WaitShort)
+	}
+
+	Eventually(ctx, func() error { return check(c) }, timeout, interval).Should(Succeed(), description...)
+}
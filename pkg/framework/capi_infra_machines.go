@@ -0,0 +1,122 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/utils/ptr"
+	awsv1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	azurev1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	gcpv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errUnsupportedInfraMachineKind = errors.New("unsupported infrastructure machine kind")
+
+// InfraMachine is a typed view over the provider specific infrastructure machine
+// referenced by a CAPI Machine, e.g. an AWSMachine or a GCPMachine.
+type InfraMachine interface {
+	client.Object
+
+	// ProviderID returns the provider ID of the infrastructure machine, or an empty string if it is not set yet.
+	ProviderID() string
+	// InstanceID returns the cloud instance identifier of the infrastructure machine,
+	// or an empty string if it is not known yet.
+	InstanceID() string
+}
+
+// AWSInfraMachine is the InfraMachine backed by an AWSMachine.
+type AWSInfraMachine struct {
+	*awsv1.AWSMachine
+}
+
+// ProviderID returns the provider ID of the AWSMachine.
+func (m AWSInfraMachine) ProviderID() string {
+	return ptr.Deref(m.Spec.ProviderID, "")
+}
+
+// InstanceID returns the EC2 instance ID of the AWSMachine.
+func (m AWSInfraMachine) InstanceID() string {
+	return ptr.Deref(m.Spec.InstanceID, "")
+}
+
+// AzureInfraMachine is the InfraMachine backed by an AzureMachine.
+type AzureInfraMachine struct {
+	*azurev1.AzureMachine
+}
+
+// ProviderID returns the provider ID of the AzureMachine.
+func (m AzureInfraMachine) ProviderID() string {
+	return ptr.Deref(m.Spec.ProviderID, "")
+}
+
+// InstanceID returns the virtual machine name of the AzureMachine, taken from its provider ID.
+func (m AzureInfraMachine) InstanceID() string {
+	return lastProviderIDSegment(m.ProviderID())
+}
+
+// GCPInfraMachine is the InfraMachine backed by a GCPMachine.
+type GCPInfraMachine struct {
+	*gcpv1.GCPMachine
+}
+
+// ProviderID returns the provider ID of the GCPMachine.
+func (m GCPInfraMachine) ProviderID() string {
+	return ptr.Deref(m.Spec.ProviderID, "")
+}
+
+// InstanceID returns the instance name of the GCPMachine, taken from its provider ID.
+func (m GCPInfraMachine) InstanceID() string {
+	return lastProviderIDSegment(m.ProviderID())
+}
+
+// GetCAPIInfraMachine gets the infrastructure machine referenced by the given CAPI Machine.
+// The concrete type of the result can be asserted to access the provider specific spec fields,
+// e.g. infraMachine.(framework.AWSInfraMachine).Spec.InstanceType.
+func GetCAPIInfraMachine(ctx context.Context, cl client.Client, machine *clusterv1.Machine) (InfraMachine, error) {
+	ref := machine.Spec.InfrastructureRef
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+
+	if key.Namespace == "" {
+		key.Namespace = machine.Namespace
+	}
+
+	switch ref.Kind {
+	case "AWSMachine":
+		awsMachine := &awsv1.AWSMachine{}
+		if err := cl.Get(ctx, key, awsMachine); err != nil {
+			return nil, fmt.Errorf("error getting AWSMachine %q: %w", key.Name, err)
+		}
+
+		return AWSInfraMachine{awsMachine}, nil
+	case "AzureMachine":
+		azureMachine := &azurev1.AzureMachine{}
+		if err := cl.Get(ctx, key, azureMachine); err != nil {
+			return nil, fmt.Errorf("error getting AzureMachine %q: %w", key.Name, err)
+		}
+
+		return AzureInfraMachine{azureMachine}, nil
+	case "GCPMachine":
+		gcpMachine := &gcpv1.GCPMachine{}
+		if err := cl.Get(ctx, key, gcpMachine); err != nil {
+			return nil, fmt.Errorf("error getting GCPMachine %q: %w", key.Name, err)
+		}
+
+		return GCPInfraMachine{gcpMachine}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnsupportedInfraMachineKind, ref.Kind)
+	}
+}
+
+// lastProviderIDSegment returns the last path segment of a provider ID,
+// which is the instance name for the providers that do not record an instance ID.
+func lastProviderIDSegment(providerID string) string {
+	if providerID == "" {
+		return ""
+	}
+
+	return providerID[strings.LastIndex(providerID, "/")+1:]
+}
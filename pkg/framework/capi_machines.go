@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,3 +56,26 @@ func FilterCAPIMachinesInPhase(machines []*clusterv1.Machine, machinePhase strin
 
 	return result
 }
+
+// WaitForCAPIMachineDrainBlocked waits until the given Machine is in the "Deleting" phase and
+// its DrainingSucceeded condition is false, e.g. because a PodDisruptionBudget prevents the eviction of its pods.
+func WaitForCAPIMachineDrainBlocked(ctx context.Context, cl client.Client, machine *clusterv1.Machine) {
+	Eventually(ctx, func() (bool, error) {
+		m := &clusterv1.Machine{}
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(machine), m); err != nil {
+			return false, err
+		}
+
+		if m.Status.Phase != string(clusterv1.MachinePhaseDeleting) {
+			return false, nil
+		}
+
+		for _, condition := range m.Status.Conditions {
+			if condition.Type == clusterv1.DrainingSucceededCondition {
+				return condition.Status == corev1.ConditionFalse, nil
+			}
+		}
+
+		return false, nil
+	}, WaitLong, RetryMedium).Should(BeTrue(), "CAPI Machine %q should be deleting with a blocked drain", machine.GetName())
+}
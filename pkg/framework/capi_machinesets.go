@@ -2,7 +2,11 @@ package framework
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -10,6 +14,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
 	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +27,8 @@ type CAPIMachineSetParams struct {
 	failureDomain     string
 	replicas          int32
 	infrastructureRef corev1.ObjectReference
+	nodeDrainTimeout  *metav1.Duration
+	labels            map[string]string
 }
 
 // NewCAPIMachineSetParams returns a new CAPIMachineSetParams object.
@@ -44,13 +52,30 @@ func NewCAPIMachineSetParams(msName, clusterName, failureDomain string, replicas
 func UpdateCAPIMachineSetName(msName string, params CAPIMachineSetParams) CAPIMachineSetParams {
 	Expect(msName).ToNot(BeEmpty(), "expected the capi msName to not be empty")
 
-	return CAPIMachineSetParams{
-		msName:            msName,
-		clusterName:       params.clusterName,
-		replicas:          params.replicas,
-		infrastructureRef: params.infrastructureRef,
-		failureDomain:     params.failureDomain,
+	params.msName = msName
+
+	return params
+}
+
+// UpdateCAPIMachineSetNodeDrainTimeout returns CAPIMachineSetParams object with the updated node drain timeout
+// of the machines: once it is exceeded, the machines are deleted even if their node could not be drained.
+func UpdateCAPIMachineSetNodeDrainTimeout(timeout time.Duration, params CAPIMachineSetParams) CAPIMachineSetParams {
+	params.nodeDrainTimeout = &metav1.Duration{Duration: timeout}
+
+	return params
+}
+
+// UpdateCAPIMachineSetLabels returns CAPIMachineSetParams object with the given labels added to the machine template.
+// The labels of the node.cluster.x-k8s.io domain are synced from the machines to their nodes.
+func UpdateCAPIMachineSetLabels(labels map[string]string, params CAPIMachineSetParams) CAPIMachineSetParams {
+	params.labels = maps.Clone(params.labels)
+	if params.labels == nil {
+		params.labels = map[string]string{}
 	}
+
+	maps.Copy(params.labels, labels)
+
+	return params
 }
 
 // CreateCAPIMachineSet creates a new MachineSet resource.
@@ -74,48 +99,101 @@ func CreateCAPIMachineSet(ctx context.Context, cl client.Client, params CAPIMach
 			ClusterName:       params.clusterName,
 			InfrastructureRef: params.infrastructureRef,
 			FailureDomain:     &params.failureDomain,
+			NodeDrainTimeout:  params.nodeDrainTimeout,
 		},
 	}
-	ms := capiv1resourcebuilder.MachineSet().WithName(params.msName).WithNamespace(ClusterAPINamespace).WithReplicas(params.replicas).WithClusterName(params.clusterName).WithSelector(selector).WithTemplate(template).WithLabels(map[string]string{"cluster.x-k8s.io/cluster-name": params.clusterName}).Build()
+	maps.Copy(template.ObjectMeta.Labels, params.labels)
+
+	ms := capiv1resourcebuilder.MachineSet().WithName(params.msName).WithNamespace(ClusterAPINamespace).WithReplicas(params.replicas).WithClusterName(params.clusterName).WithSelector(selector).WithTemplate(template).WithLabels(map[string]string{"cluster.x-k8s.io/cluster-name": params.clusterName, ReasonKey: ReasonE2E}).Build()
 
-	Eventually(func() error {
+	Eventually(ctx, func() error {
 		return cl.Create(ctx, ms)
 	}, WaitLong, RetryShort).Should(Succeed(), "it should have been able to create a new CAPI MachineSet")
 
+	Tracked.Track(ms)
+
 	return ms, nil
 }
 
 // WaitForCAPIMachineSetsDeleted polls until the given MachineSets are not found, and
 // there are zero Machines found matching the MachineSet's label selector.
+// The MachineSets are waited for together, within a single WaitLong deadline.
 func WaitForCAPIMachineSetsDeleted(ctx context.Context, cl client.Client, machineSets ...*clusterv1.MachineSet) {
-	for _, ms := range machineSets {
-		By(fmt.Sprintf("Waiting for MachineSet %q to be deleted", ms.GetName()))
-		Eventually(func() bool {
-			selector := ms.Spec.Selector
-
-			machines, err := GetCAPIMachines(ctx, cl, &selector)
-			if err != nil || len(machines) != 0 {
-				return false // Still have Machines, or other error.
+	if len(machineSets) == 0 {
+		return
+	}
+
+	By(fmt.Sprintf("Waiting for %d MachineSets to be deleted", len(machineSets)))
+	Eventually(ctx, func() []string {
+		var remaining []string
+
+		for _, ms := range machineSets {
+			if !capiMachineSetDeleted(ctx, cl, ms) {
+				remaining = append(remaining, ms.GetName())
 			}
+		}
+
+		return remaining
+	}, WaitLong, RetryMedium).Should(BeEmpty(), "it should have been able to delete all the CAPI MachineSets")
+}
 
-			err = cl.Get(ctx, client.ObjectKey{
-				Name:      ms.GetName(),
-				Namespace: ms.GetNamespace(),
-			}, &clusterv1.MachineSet{})
+// capiMachineSetDeleted returns true when the MachineSet and its Machines are deleted.
+func capiMachineSetDeleted(ctx context.Context, cl client.Client, ms *clusterv1.MachineSet) bool {
+	selector := ms.Spec.Selector
 
-			return apierrors.IsNotFound(err) // MachineSet and Machines were deleted.
-		}, WaitLong, RetryMedium).Should(BeTrue(), "it should have been able to delete all the CAPI MachineSets")
+	machines, err := GetCAPIMachines(ctx, cl, &selector)
+	if err != nil || len(machines) != 0 {
+		return false // Still have Machines, or other error.
 	}
+
+	err = cl.Get(ctx, client.ObjectKey{
+		Name:      ms.GetName(),
+		Namespace: ms.GetNamespace(),
+	}, &clusterv1.MachineSet{})
+
+	return apierrors.IsNotFound(err) // MachineSet and Machines were deleted.
 }
 
-// DeleteCAPIMachineSets deletes the specified machinesets and returns an error on failure.
-func DeleteCAPIMachineSets(ctx context.Context, cl client.Client, machineSets ...*clusterv1.MachineSet) {
-	for _, ms := range machineSets {
+// DeleteCAPIMachineSets deletes the specified machinesets in parallel and returns an error on failure.
+// The deletions are retried until WaitLong, shared by all of them, and the errors of those which failed are joined.
+func DeleteCAPIMachineSets(ctx context.Context, cl client.Client, machineSets ...*clusterv1.MachineSet) error {
+	ctx, cancel := context.WithTimeout(ctx, WaitLong)
+	defer cancel()
+
+	errs := make([]error, len(machineSets))
+
+	var wg sync.WaitGroup
+
+	for i, ms := range machineSets {
 		By(fmt.Sprintf("Deleting MachineSet %q", ms.GetName()))
-		Eventually(func() error {
-			return cl.Delete(ctx, ms)
-		}, WaitLong, RetryShort).Should(Succeed(), "the CAPI MachineSets should have been deleted")
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var lastErr error
+
+			if err := wait.PollUntilContextCancel(ctx, RetryShort, true, func(ctx context.Context) (bool, error) {
+				if lastErr = client.IgnoreNotFound(cl.Delete(ctx, ms)); lastErr != nil {
+					klog.Errorf("Error deleting CAPI MachineSet %q: %v, retrying...", ms.Name, lastErr)
+					return false, nil
+				}
+
+				return true, nil
+			}); err != nil {
+				errs[i] = fmt.Errorf("error deleting CAPI MachineSet %q: %w", ms.Name, errors.Join(err, lastErr))
+
+				return
+			}
+
+			Tracked.Untrack(ms)
+		}()
 	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
 
 // WaitForCAPIMachinesRunning waits for the all Machines belonging to the named
@@ -127,7 +205,7 @@ func WaitForCAPIMachinesRunning(ctx context.Context, cl client.Client, name stri
 	machineSet, err := GetCAPIMachineSet(ctx, cl, name)
 	Expect(err).ToNot(HaveOccurred(), "Failed to get capi machineset")
 
-	Eventually(func() error {
+	waitCached(ctx, cl, func(cl client.Client) error {
 		machines, err := GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
 		if err != nil {
 			return err
@@ -160,7 +238,7 @@ func WaitForCAPIMachinesRunning(ctx context.Context, cl client.Client, name stri
 		}
 
 		return nil
-	}, WaitOverLong, RetryMedium).Should(Succeed(), "all machines belonging to the MachineSet should be in Running phase")
+	}, WaitOverLong, RetryMedium, "all machines belonging to the MachineSet should be in Running phase")
 }
 
 // GetCAPIMachineSet gets a machineset by its name from the default machine API namespace.
@@ -168,7 +246,7 @@ func GetCAPIMachineSet(ctx context.Context, cl client.Client, name string) (*clu
 	machineSet := &clusterv1.MachineSet{}
 	key := client.ObjectKey{Namespace: ClusterAPINamespace, Name: name}
 
-	Eventually(func() error {
+	Eventually(ctx, func() error {
 		return cl.Get(ctx, key, machineSet)
 	}, WaitShort, RetryShort).Should(Succeed(), "it should be able to get a machineset by its name")
 
@@ -192,3 +270,151 @@ func GetCAPIMachinesFromMachineSet(ctx context.Context, cl client.Client, machin
 
 	return machinesForSet, nil
 }
+
+// ScaleCAPIMachineSet scales a CAPI MachineSet with a given name to the given number of replicas.
+func ScaleCAPIMachineSet(ctx context.Context, cl client.Client, name string, replicas int32) {
+	By(fmt.Sprintf("Scaling MachineSet %q to %d replicas", name, replicas))
+
+	Eventually(ctx, func() error {
+		machineSet, err := GetCAPIMachineSet(ctx, cl, name)
+		if err != nil {
+			return err
+		}
+
+		patchBase := client.MergeFrom(machineSet.DeepCopy())
+		machineSet.Spec.Replicas = ptr.To(replicas)
+
+		return cl.Patch(ctx, machineSet, patchBase)
+	}, WaitShort, RetryShort).Should(Succeed(), "it should be able to scale the CAPI MachineSet")
+}
+
+// ScaleCAPIMachineSetWithSubresource scales a CAPI MachineSet with a given name to the given number of replicas
+// through its scale subresource, as the autoscaler and 'oc scale' do.
+func ScaleCAPIMachineSetWithSubresource(ctx context.Context, name string, replicas int32) {
+	By(fmt.Sprintf("Scaling MachineSet %q to %d replicas through the scale subresource", name, replicas))
+
+	scaleClient, err := getScaleClient()
+	Expect(err).ToNot(HaveOccurred(), "it should be able to create a scale client")
+
+	machineSetResource := clusterv1.GroupVersion.WithResource("machinesets").GroupResource()
+
+	Eventually(ctx, func() error {
+		scale, err := scaleClient.Scales(ClusterAPINamespace).Get(ctx, machineSetResource, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting scale of MachineSet %q: %w", name, err)
+		}
+
+		scale.Spec.Replicas = replicas
+
+		if _, err := scaleClient.Scales(ClusterAPINamespace).Update(ctx, machineSetResource, scale, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error updating scale of MachineSet %q: %w", name, err)
+		}
+
+		return nil
+	}, WaitShort, RetryShort).Should(Succeed(), "it should be able to scale the CAPI MachineSet through its scale subresource")
+}
+
+// WaitForCAPIMachineSetScale waits for the CAPI MachineSet with a given name to converge to the given number of
+// replicas: its status reports them as ready, and it owns exactly as many Machines, all running with a ready node.
+// Scaling to zero waits for all the Machines to be deleted.
+func WaitForCAPIMachineSetScale(ctx context.Context, cl client.Client, name string, replicas int32) {
+	By(fmt.Sprintf("Waiting for MachineSet %q to converge to %d replicas", name, replicas))
+
+	waitCached(ctx, cl, func(cl client.Client) error {
+		machineSet := &clusterv1.MachineSet{}
+		if err := cl.Get(ctx, client.ObjectKey{Namespace: ClusterAPINamespace, Name: name}, machineSet); err != nil {
+			return err
+		}
+
+		if ptr.Deref(machineSet.Spec.Replicas, 0) != replicas {
+			return fmt.Errorf("%q: MachineSet has %d desired replicas, expected %d", name, ptr.Deref(machineSet.Spec.Replicas, 0), replicas)
+		}
+
+		if machineSet.Status.Replicas != replicas || machineSet.Status.ReadyReplicas != replicas {
+			return fmt.Errorf("%q: MachineSet status has %d replicas, %d ready, expected %d",
+				name, machineSet.Status.Replicas, machineSet.Status.ReadyReplicas, replicas)
+		}
+
+		machines, err := GetCAPIMachinesFromMachineSet(ctx, cl, machineSet)
+		if err != nil {
+			return err
+		}
+
+		// Machines being deleted are still owned by the MachineSet.
+		if len(machines) != int(replicas) {
+			return fmt.Errorf("%q: found %d Machines, expected %d", name, len(machines), replicas)
+		}
+
+		for _, m := range machines {
+			if m.Status.Phase != string(clusterv1.MachinePhaseRunning) {
+				return fmt.Errorf("%q: Machine %s is in phase %q", name, m.Name, m.Status.Phase)
+			}
+
+			node, err := GetCAPINodeForMachine(ctx, cl, m)
+			if err != nil {
+				return err
+			}
+
+			if !IsNodeReady(node) {
+				return fmt.Errorf("%s: node is not ready", node.Name)
+			}
+		}
+
+		return nil
+	}, WaitOverLong, RetryMedium, "the MachineSet should converge to its desired replicas")
+}
+
+// PauseCAPIMachineSet sets the Cluster API paused annotation on the given MachineSet,
+// which stops the MachineSet controller from reconciling it.
+func PauseCAPIMachineSet(ctx context.Context, cl client.Client, ms *clusterv1.MachineSet) {
+	By(fmt.Sprintf("Pausing MachineSet %q", ms.GetName()))
+	setCAPIMachineSetPaused(ctx, cl, ms, true)
+}
+
+// UnpauseCAPIMachineSet removes the Cluster API paused annotation from the given MachineSet.
+func UnpauseCAPIMachineSet(ctx context.Context, cl client.Client, ms *clusterv1.MachineSet) {
+	By(fmt.Sprintf("Unpausing MachineSet %q", ms.GetName()))
+	setCAPIMachineSetPaused(ctx, cl, ms, false)
+}
+
+// setCAPIMachineSetPaused adds or removes the paused annotation on the given MachineSet.
+func setCAPIMachineSetPaused(ctx context.Context, cl client.Client, ms *clusterv1.MachineSet, paused bool) {
+	Eventually(ctx, func() error {
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(ms), ms); err != nil {
+			return err
+		}
+
+		patchBase := client.MergeFrom(ms.DeepCopy())
+		annotations := ms.GetAnnotations()
+
+		if paused {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+
+			annotations[clusterv1.PausedAnnotation] = ""
+		} else {
+			delete(annotations, clusterv1.PausedAnnotation)
+		}
+
+		ms.SetAnnotations(annotations)
+
+		return cl.Patch(ctx, ms, patchBase)
+	}, WaitShort, RetryShort).Should(Succeed(), "it should be able to update the paused annotation on the CAPI MachineSet")
+}
+
+// ExpectNoNewCAPIMachines verifies that the number of Machines owned by the given
+// MachineSet stays at the expected count for a short period of time.
+// It is used to check that a paused MachineSet is not being reconciled.
+func ExpectNoNewCAPIMachines(ctx context.Context, cl client.Client, ms *clusterv1.MachineSet, expected int) {
+	By(fmt.Sprintf("Verifying MachineSet %q keeps %d Machines", ms.GetName(), expected))
+
+	Consistently(func() (int, error) {
+		machines, err := GetCAPIMachinesFromMachineSet(ctx, cl, ms)
+		if err != nil {
+			return 0, err
+		}
+
+		return len(machines), nil
+	}, WaitShort, RetryMedium).Should(Equal(expected), "no new Machines should be created for the CAPI MachineSet")
+}
@@ -0,0 +1,267 @@
+// Package chaos implements an opt-in chaos mode, in which controller pods are killed
+// at random points while specs scale and delete machines. Once the chaos stops, the specs
+// verify the controllers reconciled idempotently, e.g. that no duplicate machines were created.
+//
+// Chaos is disabled unless the suite is run with the --chaos flag, in which case
+// the specs labelled with framework.LabelChaos kill their target controllers.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+const (
+	// minKillInterval and maxKillInterval bound the randomized delay between two kills.
+	minKillInterval = 10 * time.Second
+	maxKillInterval = 60 * time.Second
+)
+
+// Enabled turns the chaos mode on, set with the --chaos flag.
+// When false, During is a no-op and the specs run unaffected.
+var Enabled bool
+
+// Target is a controller whose pods can be killed, identified by its Deployment.
+type Target struct {
+	Namespace  string
+	Deployment string
+}
+
+// String returns the namespaced name of the target Deployment.
+func (t Target) String() string {
+	return t.Namespace + "/" + t.Deployment
+}
+
+var (
+	// MachineAPIControllers runs the machine-controller and machineset-controller containers,
+	// which are killed together with their pod.
+	MachineAPIControllers = Target{Namespace: framework.MachineAPINamespace, Deployment: "machine-api-controllers"}
+
+	// CAPIController runs the core Cluster API controllers.
+	CAPIController = Target{Namespace: framework.ClusterAPINamespace, Deployment: "capi-controller-manager"}
+)
+
+// capiProviderDeployments are the Deployments of the CAPI infrastructure providers, by platform.
+var capiProviderDeployments = map[configv1.PlatformType]string{
+	configv1.AWSPlatformType:   "capa-controller-manager",
+	configv1.AzurePlatformType: "capz-controller-manager",
+	configv1.GCPPlatformType:   "capg-controller-manager",
+}
+
+// CAPIProviderController returns the target of the CAPI infrastructure provider of the platform,
+// and false when the platform has no known provider.
+func CAPIProviderController(platform configv1.PlatformType) (Target, bool) {
+	deployment, ok := capiProviderDeployments[platform]
+	if !ok {
+		return Target{}, false
+	}
+
+	return Target{Namespace: framework.ClusterAPINamespace, Deployment: deployment}, true
+}
+
+// Kill records a pod killed by a Monkey.
+type Kill struct {
+	Target Target
+	Pod    string
+	Time   time.Time
+}
+
+// Monkey kills a random pod of a random target at randomized intervals, until stopped.
+// Randomness comes from framework.Rand, so a run can be reproduced with the same -seed.
+type Monkey struct {
+	client  runtimeclient.Client
+	targets []Target
+
+	lock  sync.Mutex
+	kills []Kill
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMonkey returns a Monkey killing the pods of the given targets.
+func NewMonkey(client runtimeclient.Client, targets ...Target) *Monkey {
+	return &Monkey{
+		client:  client,
+		targets: targets,
+	}
+}
+
+// Start starts killing pods in the background until Stop is called or the given context is cancelled.
+func (m *Monkey) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+
+	go func() {
+		defer GinkgoRecover()
+		defer close(m.done)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(randomKillInterval()):
+				m.killOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops killing pods and returns the pods killed so far.
+func (m *Monkey) Stop() []Kill {
+	if m.cancel != nil {
+		m.cancel()
+		<-m.done
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]Kill(nil), m.kills...)
+}
+
+// killOnce deletes a random pod of a random target without grace period.
+// Errors are logged rather than failing the spec, as the next kill will be attempted anyway.
+func (m *Monkey) killOnce(ctx context.Context) {
+	if len(m.targets) == 0 {
+		return
+	}
+
+	target := m.targets[framework.Rand.Intn(len(m.targets))]
+
+	pod, err := m.randomPod(ctx, target)
+	if err != nil {
+		klog.Warningf("[chaos] unable to find a pod of %s: %v", target, err)
+		return
+	}
+
+	if pod == nil {
+		return
+	}
+
+	klog.Infof("[chaos] killing pod %s/%s of %s", pod.Namespace, pod.Name, target)
+
+	if err := m.client.Delete(ctx, pod, runtimeclient.GracePeriodSeconds(0)); runtimeclient.IgnoreNotFound(err) != nil {
+		klog.Warningf("[chaos] unable to kill pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.kills = append(m.kills, Kill{Target: target, Pod: pod.Name, Time: time.Now()})
+}
+
+// randomPod returns a random running pod of the target, or nil when it has none.
+func (m *Monkey) randomPod(ctx context.Context, target Target) (*corev1.Pod, error) {
+	deployment, err := framework.GetDeployment(ctx, m.client, target.Deployment, target.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of deployment %s: %w", target, err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := m.client.List(ctx, pods, runtimeclient.InNamespace(target.Namespace), runtimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	var running []*corev1.Pod
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning && pods.Items[i].DeletionTimestamp == nil {
+			running = append(running, &pods.Items[i])
+		}
+	}
+
+	if len(running) == 0 {
+		return nil, nil
+	}
+
+	return running[framework.Rand.Intn(len(running))], nil
+}
+
+// During starts killing the pods of the given targets when chaos is enabled, and returns a function
+// which stops the kills and waits for the targets to be available again. The returned function
+// must be called before the spec verifies the outcome of the disrupted operation.
+// When chaos is disabled, nothing is killed and the returned function does nothing.
+func During(ctx context.Context, client runtimeclient.Client, targets ...Target) func() {
+	if !Enabled {
+		return func() {}
+	}
+
+	By(fmt.Sprintf("Killing the pods of %v at random points", targets))
+
+	monkey := NewMonkey(client, targets...)
+	monkey.Start(ctx)
+
+	return func() {
+		kills := monkey.Stop()
+		klog.Infof("[chaos] killed %d pods", len(kills))
+
+		for _, kill := range kills {
+			AddReportEntry(fmt.Sprintf("chaos: killed pod %s of %s", kill.Pod, kill.Target), kill.Time)
+		}
+
+		By("Waiting for the killed controllers to be available again")
+
+		for _, target := range targets {
+			Expect(framework.IsDeploymentAvailable(ctx, client, target.Deployment, target.Namespace)).To(BeTrue(),
+				"deployment %s should be available after the chaos", target)
+		}
+	}
+}
+
+// ExpectIdempotentMachineSet verifies that the MachineSet owns exactly as many machines as its replicas,
+// and that no two of them share a provider ID, i.e. that no cloud instance was created twice
+// by controllers restarted in the middle of a reconciliation.
+func ExpectIdempotentMachineSet(ctx context.Context, client runtimeclient.Client, machineSet *machinev1.MachineSet) {
+	By(fmt.Sprintf("Checking MachineSet %s was reconciled idempotently", machineSet.GetName()))
+
+	current, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
+	Expect(err).ToNot(HaveOccurred(), "MachineSet should be retrievable")
+
+	machines, err := framework.GetMachinesFromMachineSet(ctx, client, current)
+	Expect(err).ToNot(HaveOccurred(), "Machines of the MachineSet should be listable")
+	Expect(machines).To(HaveLen(int(ptr.Deref(current.Spec.Replicas, framework.DefaultMachineSetReplicas))),
+		"MachineSet %s should own exactly as many machines as its replicas", current.GetName())
+
+	providerIDs := map[string]string{}
+
+	for _, machine := range machines {
+		providerID := ptr.Deref(machine.Spec.ProviderID, "")
+		if providerID == "" {
+			continue
+		}
+
+		Expect(providerIDs).ToNot(HaveKey(providerID),
+			"machine %s shares its provider ID %q with machine %s", machine.GetName(), providerID, providerIDs[providerID])
+
+		providerIDs[providerID] = machine.GetName()
+	}
+}
+
+// randomKillInterval returns a random delay between minKillInterval and maxKillInterval.
+func randomKillInterval() time.Duration {
+	spread := int((maxKillInterval - minKillInterval) / time.Second)
+
+	return minKillInterval + time.Duration(framework.Rand.Intn(spread+1))*time.Second
+}
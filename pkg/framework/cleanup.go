@@ -0,0 +1,126 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	awsv1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	azurev1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	gcpv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// e2eResourceLabels selects the objects created by the e2e framework.
+var e2eResourceLabels = runtimeclient.MatchingLabels{ReasonKey: ReasonE2E}
+
+// ListOrphanedE2EResources lists the objects created by the e2e framework which are
+// still present in the cluster: MAPI MachineSets, CAPI MachineSets and their infrastructure
+// machine templates, workload Jobs and user data Secrets labeled with the ReasonE2E reason.
+func ListOrphanedE2EResources(ctx context.Context, cl runtimeclient.Client) ([]runtimeclient.Object, error) {
+	var objs []runtimeclient.Object
+
+	machineSets := &machinev1.MachineSetList{}
+	if err := cl.List(ctx, machineSets, runtimeclient.InNamespace(MachineAPINamespace), e2eResourceLabels); err != nil {
+		return nil, fmt.Errorf("error querying api for machineSetList object: %w", err)
+	}
+
+	for i := range machineSets.Items {
+		objs = append(objs, &machineSets.Items[i])
+	}
+
+	capiMachineSets := &clusterv1.MachineSetList{}
+	if err := cl.List(ctx, capiMachineSets, runtimeclient.InNamespace(ClusterAPINamespace), e2eResourceLabels); err != nil && !isNoMatchError(err) {
+		return nil, fmt.Errorf("error querying api for CAPI machineSetList object: %w", err)
+	}
+
+	for i := range capiMachineSets.Items {
+		objs = append(objs, &capiMachineSets.Items[i])
+	}
+
+	// Only the CRDs of the infrastructure provider of the platform are installed.
+	machineTemplateLists := []runtimeclient.ObjectList{
+		&awsv1.AWSMachineTemplateList{},
+		&azurev1.AzureMachineTemplateList{},
+		&gcpv1.GCPMachineTemplateList{},
+	}
+
+	for _, list := range machineTemplateLists {
+		if err := cl.List(ctx, list, runtimeclient.InNamespace(ClusterAPINamespace), e2eResourceLabels); isNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error querying api for %T object: %w", list, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, fmt.Errorf("error extracting %T items: %w", list, err)
+		}
+
+		for _, item := range items {
+			objs = append(objs, item.(runtimeclient.Object))
+		}
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := cl.List(ctx, jobs, runtimeclient.InNamespace(MachineAPINamespace), e2eResourceLabels); err != nil {
+		return nil, fmt.Errorf("error querying api for jobList object: %w", err)
+	}
+
+	for i := range jobs.Items {
+		objs = append(objs, &jobs.Items[i])
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := cl.List(ctx, secrets, runtimeclient.InNamespace(MachineAPINamespace), e2eResourceLabels); err != nil {
+		return nil, fmt.Errorf("error querying api for secretList object: %w", err)
+	}
+
+	for i := range secrets.Items {
+		objs = append(objs, &secrets.Items[i])
+	}
+
+	return objs, nil
+}
+
+// CleanupOrphanedE2EResources deletes the objects left behind by previous e2e runs,
+// e.g. when a CI job timed out before the test cleanup could run.
+// When dryRun is true, the objects are only listed.
+// It returns the objects that were, or would have been, deleted.
+func CleanupOrphanedE2EResources(ctx context.Context, cl runtimeclient.Client, dryRun bool) ([]runtimeclient.Object, error) {
+	objs, err := ListOrphanedE2EResources(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return objs, nil
+	}
+
+	propagationPolicy := metav1.DeletePropagationBackground
+
+	for _, obj := range objs {
+		klog.Infof("[cleanup] deleting %T %s/%s", obj, obj.GetNamespace(), obj.GetName())
+
+		if err := cl.Delete(ctx, obj, &runtimeclient.DeleteOptions{
+			PropagationPolicy: &propagationPolicy,
+		}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error deleting %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	return objs, nil
+}
+
+// isNoMatchError returns true when the resource kind is not served by the cluster,
+// e.g. when the Cluster API CRDs are not installed.
+func isNoMatchError(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
@@ -0,0 +1,164 @@
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// CloudAPIRetryConfig configures how cloud API calls failing with throttling or
+// transient errors are retried. Delays grow exponentially between MinDelay and MaxDelay.
+type CloudAPIRetryConfig struct {
+	MaxRetries int
+	MinDelay   time.Duration
+	MaxDelay   time.Duration
+}
+
+// CloudAPIRetry is the retry configuration used by the cloud clients created by the framework.
+// It can be overridden, e.g. in BeforeSuite, before the clients are created.
+var CloudAPIRetry = CloudAPIRetryConfig{
+	MaxRetries: 8,
+	MinDelay:   500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// CloudAPICalls aggregates the cloud API calls made by the clients created by the framework.
+var CloudAPICalls = NewCloudAPIStats()
+
+// CloudAPICallStats are the aggregated statistics of a single cloud API operation.
+type CloudAPICallStats struct {
+	Calls     int
+	Retries   int
+	Throttled int
+	Failures  int
+}
+
+// CloudAPIStats aggregates cloud API call statistics per operation.
+type CloudAPIStats struct {
+	lock       sync.Mutex
+	operations map[string]*CloudAPICallStats
+}
+
+// NewCloudAPIStats returns a new, empty CloudAPIStats.
+func NewCloudAPIStats() *CloudAPIStats {
+	return &CloudAPIStats{operations: map[string]*CloudAPICallStats{}}
+}
+
+// Snapshot returns a copy of the statistics recorded so far, keyed by operation.
+func (s *CloudAPIStats) Snapshot() map[string]CloudAPICallStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot := make(map[string]CloudAPICallStats, len(s.operations))
+	for operation, stats := range s.operations {
+		snapshot[operation] = *stats
+	}
+
+	return snapshot
+}
+
+// Reset drops the statistics recorded so far.
+func (s *CloudAPIStats) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.operations = map[string]*CloudAPICallStats{}
+}
+
+// Format returns the statistics recorded so far, one operation per line.
+// It returns an empty string when no call has been recorded.
+func (s *CloudAPIStats) Format() string {
+	snapshot := s.Snapshot()
+
+	operations := make([]string, 0, len(snapshot))
+	for operation := range snapshot {
+		operations = append(operations, operation)
+	}
+
+	sort.Strings(operations)
+
+	lines := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		stats := snapshot[operation]
+		lines = append(lines, fmt.Sprintf("%s: %d calls, %d retries, %d throttled, %d failures",
+			operation, stats.Calls, stats.Retries, stats.Throttled, stats.Failures))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// get returns the statistics of the given operation, creating them if needed.
+// The lock must be held by the caller.
+func (s *CloudAPIStats) get(operation string) *CloudAPICallStats {
+	stats, ok := s.operations[operation]
+	if !ok {
+		stats = &CloudAPICallStats{}
+		s.operations[operation] = stats
+	}
+
+	return stats
+}
+
+// recordAttempt records a failed attempt of the given operation which may be retried.
+func (s *CloudAPIStats) recordAttempt(operation string, throttled bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if throttled {
+		s.get(operation).Throttled++
+	}
+}
+
+// recordCall records a completed call of the given operation.
+func (s *CloudAPIStats) recordCall(operation string, retries int, failed bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats := s.get(operation)
+	stats.Calls++
+	stats.Retries += retries
+
+	if failed {
+		stats.Failures++
+	}
+}
+
+// awsRetryer returns the AWS SDK retryer matching the given configuration.
+func awsRetryer(cfg CloudAPIRetryConfig) request.Retryer {
+	return client.DefaultRetryer{
+		NumMaxRetries:    cfg.MaxRetries,
+		MinRetryDelay:    cfg.MinDelay,
+		MaxRetryDelay:    cfg.MaxDelay,
+		MinThrottleDelay: cfg.MinDelay,
+		MaxThrottleDelay: cfg.MaxDelay,
+	}
+}
+
+// instrumentAWSHandlers records the calls made through the given handlers in the given statistics.
+func instrumentAWSHandlers(handlers *request.Handlers, stats *CloudAPIStats) {
+	operation := func(r *request.Request) string {
+		return fmt.Sprintf("aws/%s/%s", r.ClientInfo.ServiceName, r.Operation.Name)
+	}
+
+	// The default AfterRetry handler clears the error of retried attempts, so this must run first.
+	handlers.AfterRetry.PushFrontNamed(request.NamedHandler{
+		Name: "framework.CloudAPIStats.AfterRetry",
+		Fn: func(r *request.Request) {
+			if r.Error != nil {
+				stats.recordAttempt(operation(r), request.IsErrorThrottle(r.Error))
+			}
+		},
+	})
+
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "framework.CloudAPIStats.Complete",
+		Fn: func(r *request.Request) {
+			stats.recordCall(operation(r), r.RetryCount, r.Error != nil)
+		},
+	})
+}
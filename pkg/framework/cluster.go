@@ -41,7 +41,7 @@ func CreateCoreCluster(ctx context.Context, cl client.Client, clusterName, infra
 		Expect(err).ToNot(HaveOccurred(), "Failed to create cluster")
 	}
 
-	Eventually(func() (bool, error) {
+	Eventually(ctx, func() (bool, error) {
 		patchedCluster := &clusterv1.Cluster{}
 		err := cl.Get(ctx, client.ObjectKeyFromObject(cluster), patchedCluster)
 		if err != nil {
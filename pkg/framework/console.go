@@ -0,0 +1,145 @@
+package framework
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+var errConsoleOutputEmpty = errors.New("console output is empty")
+
+func init() {
+	gatherer.RegisterConsoleCollector(configv1.AWSPlatformType, newAWSConsoleCollector)
+	gatherer.RegisterConsoleCollector(configv1.AzurePlatformType, newAzureConsoleCollector)
+	// GCP is not registered: reading the serial port output requires the GCP compute API client, which is not vendored.
+}
+
+// GetConsoleOutput returns the console output of the EC2 instance with the given ID.
+// The latest output is requested first, which only Nitro instances support, then the output buffered by EC2.
+func (a *AwsClient) GetConsoleOutput(instanceID string) (string, error) {
+	result, err := a.svc.GetConsoleOutputWithContext(a.ctx, &ec2.GetConsoleOutputInput{
+		InstanceId: aws.String(instanceID),
+		Latest:     aws.Bool(true),
+	})
+	if err != nil {
+		result, err = a.svc.GetConsoleOutputWithContext(a.ctx, &ec2.GetConsoleOutputInput{
+			InstanceId: aws.String(instanceID),
+		})
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error getting console output of instance %s: %w", instanceID, err)
+	}
+
+	if ptr.Deref(result.Output, "") == "" {
+		return "", fmt.Errorf("%w: instance %s", errConsoleOutputEmpty, instanceID)
+	}
+
+	output, err := base64.StdEncoding.DecodeString(*result.Output)
+	if err != nil {
+		return "", fmt.Errorf("error decoding console output of instance %s: %w", instanceID, err)
+	}
+
+	return string(output), nil
+}
+
+// awsConsoleCollector collects the EC2 console output of the instances.
+type awsConsoleCollector struct {
+	client *AwsClient
+}
+
+// newAWSConsoleCollector is the gatherer.ConsoleCollectorFactory of AWS. It uses the Machine API credentials.
+func newAWSConsoleCollector(ctx context.Context, oc *gatherer.CLI) (gatherer.ConsoleCollector, error) {
+	clusterRegion, err := oc.WithoutNamespace().GetJSONPath("infrastructure", "cluster", "{.status.platformStatus.aws.region}")
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster region: %w", err)
+	}
+
+	accessKeyID, secureKey, sessionToken, err := getMachineAPIAWSCredentials(oc, clusterRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &awsConsoleCollector{
+		client: NewAwsClient(accessKeyID, secureKey, clusterRegion, sessionToken).WithContext(ctx),
+	}, nil
+}
+
+// ConsoleOutput implements gatherer.ConsoleCollector.
+func (c *awsConsoleCollector) ConsoleOutput(_ context.Context, machine *machinev1.Machine) (string, error) {
+	instanceID, err := AWSInstanceIDFromProviderID(ptr.Deref(machine.Spec.ProviderID, ""))
+	if err != nil {
+		return "", err
+	}
+
+	return c.client.GetConsoleOutput(instanceID)
+}
+
+// azureConsoleCollector collects the serial console log of the boot diagnostics of the virtual machines.
+// The virtual machines must have boot diagnostics enabled.
+type azureConsoleCollector struct {
+	client *AzureClient
+}
+
+// newAzureConsoleCollector is the gatherer.ConsoleCollectorFactory of Azure. It uses the Machine API credentials.
+func newAzureConsoleCollector(ctx context.Context, _ *gatherer.CLI) (gatherer.ConsoleCollector, error) {
+	cl, err := LoadClient()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewAzureClient(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &azureConsoleCollector{client: client}, nil
+}
+
+// ConsoleOutput implements gatherer.ConsoleCollector.
+func (c *azureConsoleCollector) ConsoleOutput(_ context.Context, machine *machinev1.Machine) (string, error) {
+	var azureProviderConfig machinev1.AzureMachineProviderSpec
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, &azureProviderConfig); err != nil {
+		return "", fmt.Errorf("error unmarshalling providerSpec: %w", err)
+	}
+
+	// The virtual machines are named after their machines.
+	return c.client.GetBootDiagnosticsSerialConsoleLog(azureProviderConfig.ResourceGroup, machine.Name)
+}
+
+// httpGet returns the body of the given URL.
+func httpGet(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s getting %s", resp.Status, req.URL.Host)
+	}
+
+	return string(body), nil
+}
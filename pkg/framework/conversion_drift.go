@@ -0,0 +1,60 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// MAPIToCAPIConversionFunc converts a MAPI provider spec into the spec of the CAPI
+// infrastructure machine template the MAPI to CAPI conversion produces for it.
+type MAPIToCAPIConversionFunc[P any, T any] func(mapiProviderSpec P) (T, error)
+
+// ConversionDrift returns a human readable diff between the infrastructure machine template spec
+// produced by the conversion and the one built by hand in the tests, or an empty string when they match.
+// Fields whose path (e.g. "Spec.Template.Spec.AMI") starts with any of the ignoredPaths are not compared,
+// which allows skipping fields the tests intentionally set differently, such as names or network references.
+func ConversionDrift[T any](converted, handBuilt T, ignoredPaths ...string) string {
+	ignore := cmp.FilterPath(func(path cmp.Path) bool {
+		fieldPath := conversionDriftFieldPath(path)
+
+		for _, ignored := range ignoredPaths {
+			if fieldPath == ignored || strings.HasPrefix(fieldPath, ignored+".") {
+				return true
+			}
+		}
+
+		return false
+	}, cmp.Ignore())
+
+	return cmp.Diff(converted, handBuilt, ignore)
+}
+
+// ExpectNoConversionDrift converts the given MAPI provider spec and fails the test when the result
+// diverges from the infrastructure machine template spec the tests build by hand from the same provider spec.
+// This keeps the templates of the CAPI suites in line with the actual conversion code.
+func ExpectNoConversionDrift[P any, T any](convert MAPIToCAPIConversionFunc[P, T], mapiProviderSpec P, handBuilt T, ignoredPaths ...string) {
+	By("Comparing the hand built infrastructure machine template with the converted MAPI provider spec")
+
+	converted, err := convert(mapiProviderSpec)
+	Expect(err).ToNot(HaveOccurred(), "Failed to convert the MAPI provider spec")
+
+	diff := ConversionDrift(converted, handBuilt, ignoredPaths...)
+	Expect(diff).To(BeEmpty(), fmt.Sprintf("hand built infrastructure machine template diverges from the conversion (-converted +hand built):\n%s", diff))
+}
+
+// conversionDriftFieldPath returns the dot separated struct field path of a cmp path, ignoring indirections and indexes.
+func conversionDriftFieldPath(path cmp.Path) string {
+	var fields []string
+
+	for _, step := range path {
+		if field, ok := step.(cmp.StructField); ok {
+			fields = append(fields, field.Name())
+		}
+	}
+
+	return strings.Join(fields, ".")
+}
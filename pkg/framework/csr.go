@@ -0,0 +1,127 @@
+package framework
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeBootstrapperUser is the user the kubelets request their first client certificate as.
+const NodeBootstrapperUser = "system:serviceaccount:openshift-machine-config-operator:node-bootstrapper"
+
+// nodeCommonNamePrefix is the prefix of the common name of the certificates of the kubelets, followed by the node name.
+const nodeCommonNamePrefix = "system:node:"
+
+// CSRNodeName returns the name of the node the certificate signing request of a kubelet is for,
+// from the system:node:<name> common name of its request.
+func CSRNodeName(csr certificatesv1.CertificateSigningRequest) string {
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil {
+		return ""
+	}
+
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return ""
+	}
+
+	name, found := strings.CutPrefix(request.Subject.CommonName, nodeCommonNamePrefix)
+	if !found {
+		return ""
+	}
+
+	return strings.ToLower(name)
+}
+
+// IsCSRPending returns whether the certificate signing request is neither approved nor denied.
+func IsCSRPending(csr certificatesv1.CertificateSigningRequest) bool {
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return false
+		}
+	}
+
+	return true
+}
+
+// CSRApprovalDelay returns the time the certificate signing request waited for its approval,
+// false if it is not approved.
+func CSRApprovalDelay(csr certificatesv1.CertificateSigningRequest) (time.Duration, bool) {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == certificatesv1.CertificateApproved && condition.Status != corev1.ConditionFalse {
+			return condition.LastUpdateTime.Sub(csr.CreationTimestamp.Time), true
+		}
+	}
+
+	return 0, false
+}
+
+// GetNodeCSRs returns the certificate signing requests of the kubelet of the node with the given name.
+func GetNodeCSRs(ctx context.Context, c runtimeclient.Client, nodeName string) ([]certificatesv1.CertificateSigningRequest, error) {
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := c.List(ctx, csrs); err != nil {
+		return nil, fmt.Errorf("unable to list certificate signing requests: %w", err)
+	}
+
+	var nodeCSRs []certificatesv1.CertificateSigningRequest
+
+	for _, csr := range csrs.Items {
+		if CSRNodeName(csr) == strings.ToLower(nodeName) {
+			nodeCSRs = append(nodeCSRs, csr)
+		}
+	}
+
+	return nodeCSRs, nil
+}
+
+// CreateNodeClientCSR creates a certificate signing request for a client certificate of the kubelet
+// of the node with the given name, as the kubelets request it when they bootstrap.
+func CreateNodeClientCSR(ctx context.Context, c runtimeclient.Client, nodeName string) (*certificatesv1.CertificateSigningRequest, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate private key: %w", err)
+	}
+
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   nodeCommonNamePrefix + nodeName,
+			Organization: []string{"system:nodes"},
+		},
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create certificate request: %w", err)
+	}
+
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "e2e-csr-",
+		},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request}),
+			SignerName: certificatesv1.KubeAPIServerClientKubeletSignerName,
+			Usages: []certificatesv1.KeyUsage{
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageClientAuth,
+			},
+		},
+	}
+
+	if err := c.Create(ctx, csr); err != nil {
+		return nil, fmt.Errorf("unable to create certificate signing request for node %s: %w", nodeName, err)
+	}
+
+	return csr, nil
+}
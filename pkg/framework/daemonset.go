@@ -3,6 +3,7 @@ package framework
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	kappsapi "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -45,16 +46,19 @@ func DeleteDaemonset(ctx context.Context, c client.Client, deployment *kappsapi.
 	})
 }
 
-// UpdateDaemonset updates the specified deployment.
+// UpdateDaemonset updates the spec of the specified daemonset to the spec of the given one.
 func UpdateDaemonset(ctx context.Context, c client.Client, name, namespace string, updated *kappsapi.DaemonSet) error {
 	return wait.PollUntilContextTimeout(ctx, RetryMedium, WaitMedium, true, func(ctx context.Context) (bool, error) {
-		d, err := GetDeployment(ctx, c, name, namespace)
+		d, err := GetDaemonset(ctx, c, name, namespace)
 		if err != nil {
 			klog.Errorf("Error getting DaemonSet: %v", err)
 			return false, nil
 		}
 
-		if err := c.Patch(ctx, d, client.MergeFrom(updated)); err != nil {
+		patch := client.MergeFrom(d.DeepCopy())
+		d.Spec = updated.Spec
+
+		if err := c.Patch(ctx, d, patch); err != nil {
 			klog.Errorf("error patching DaemonSet object %q: %v, retrying...", name, err)
 			return false, nil
 		}
@@ -89,7 +93,59 @@ func IsDaemonsetAvailable(ctx context.Context, c client.Client, name, namespace
 	return true
 }
 
+// IsDaemonsetRolledOut returns true once the controller has observed the latest generation of the daemonset
+// and all its scheduled pods are updated and available. A daemonset scheduling no pods is rolled out.
+func IsDaemonsetRolledOut(ctx context.Context, c client.Client, name, namespace string) bool {
+	if err := wait.PollUntilContextTimeout(ctx, RetryMedium, WaitLong, true, func(ctx context.Context) (bool, error) {
+		d, err := GetDaemonset(ctx, c, name, namespace)
+		if err != nil {
+			klog.Errorf("Error getting DaemonSet: %v", err)
+			return false, nil
+		}
+
+		if d.Status.ObservedGeneration < d.Generation ||
+			d.Status.UpdatedNumberScheduled != d.Status.DesiredNumberScheduled ||
+			d.Status.NumberAvailable != d.Status.DesiredNumberScheduled {
+			klog.Errorf("DaemonSet %q is not rolled out. Status: %s",
+				d.Name, daemonsetInfo(d))
+
+			return false, nil
+		}
+
+		klog.Infof("DaemonSet %q is rolled out. Status: %s",
+			d.Name, daemonsetInfo(d))
+
+		return true, nil
+	}); err != nil {
+		klog.Errorf("Error checking IsDaemonsetRolledOut: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// IsDaemonsetSynced returns true if provided daemonset spec matched one found on cluster.
+func IsDaemonsetSynced(ctx context.Context, c client.Client, ds *kappsapi.DaemonSet, name, namespace string) bool {
+	d, err := GetDaemonset(ctx, c, name, namespace)
+	if err != nil {
+		klog.Errorf("Error getting DaemonSet: %v", err)
+		return false
+	}
+
+	if !reflect.DeepEqual(d.Spec, ds.Spec) {
+		klog.Errorf("DaemonSet %q is not updated. Spec is not equal to: %v",
+			d.Name, ds.Spec)
+
+		return false
+	}
+
+	klog.Infof("DaemonSet %q is updated. Spec is matched", d.Name)
+
+	return true
+}
+
 func daemonsetInfo(d *kappsapi.DaemonSet) string {
-	return fmt.Sprintf("(ready: %d, available: %d, unavailable: %d)",
-		d.Status.NumberReady, d.Status.NumberAvailable, d.Status.NumberUnavailable)
+	return fmt.Sprintf("(desired: %d, updated: %d, ready: %d, available: %d, unavailable: %d)",
+		d.Status.DesiredNumberScheduled, d.Status.UpdatedNumberScheduled, d.Status.NumberReady,
+		d.Status.NumberAvailable, d.Status.NumberUnavailable)
 }
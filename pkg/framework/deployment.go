@@ -5,11 +5,17 @@ import (
 	"fmt"
 	"reflect"
 
+	. "github.com/onsi/gomega"
+
 	kappsapi "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
 
 // GetDeployment gets deployment object by name and namespace.
@@ -64,29 +70,22 @@ func UpdateDeployment(ctx context.Context, c client.Client, name, namespace stri
 	})
 }
 
-// IsDeploymentAvailable returns true if the deployment has one or more available replicas.
+// IsDeploymentAvailable returns true if the deployment has one or more available replicas within WaitLong.
+// The failure of the wait is logged along with the status of the deployment.
 func IsDeploymentAvailable(ctx context.Context, c client.Client, name, namespace string) bool {
-	if err := wait.PollUntilContextTimeout(ctx, RetryShort, WaitLong, true, func(ctx context.Context) (bool, error) {
-		d, err := GetDeployment(ctx, c, name, namespace)
-		if err != nil {
-			klog.Errorf("Error getting deployment: %v", err)
-			return false, nil
-		}
-		if d.Status.AvailableReplicas < 1 {
-			klog.Errorf("Deployment %q is not available. Status: %s",
-				d.Name, deploymentInfo(d))
+	g := NewGomega(func(message string, _ ...int) {
+		klog.Errorf("Deployment %q is not available: %s", name, message)
+	})
 
-			return false, nil
-		}
-		klog.Infof("Deployment %q is available. Status: %s",
-			d.Name, deploymentInfo(d))
+	deployment := &kappsapi.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
 
-		return true, nil
-	}); err != nil {
-		klog.Errorf("Error checking isDeploymentAvailable: %v", err)
+	if !g.Eventually(komega.New(c).WithContext(ctx).Object(deployment), WaitLong, RetryShort).Should(
+		HaveField("Status.AvailableReplicas", BeNumerically(">=", 1))) {
 		return false
 	}
 
+	klog.Infof("Deployment %q is available. Status: %s", name, deploymentInfo(deployment))
+
 	return true
 }
 
@@ -110,6 +109,87 @@ func IsDeploymentSynced(ctx context.Context, c client.Client, dep *kappsapi.Depl
 	return true
 }
 
+// RestartDeploymentPods restarts the pods of the deployment by deleting them gracefully, so that a controller
+// releases its leader lease, and waits up to WaitLong for all the replicas to be replaced and ready.
+// The deployment itself is left unchanged, as the operator managing it would revert any change.
+func RestartDeploymentPods(ctx context.Context, c client.Client, name, namespace string) error {
+	d, err := GetDeployment(ctx, c, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector of deployment %q: %w", name, err)
+	}
+
+	listOptions := []client.ListOption{client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, listOptions...); err != nil {
+		return fmt.Errorf("error listing pods of deployment %q: %w", name, err)
+	}
+
+	restarted := sets.New[types.UID]()
+
+	for i := range pods.Items {
+		klog.Infof("Restarting pod %q of deployment %q", pods.Items[i].Name, name)
+
+		if err := c.Delete(ctx, &pods.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("error deleting pod %q of deployment %q: %w", pods.Items[i].Name, name, err)
+		}
+
+		restarted.Insert(pods.Items[i].UID)
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	return wait.PollUntilContextTimeout(ctx, RetryMedium, WaitLong, true, func(ctx context.Context) (bool, error) {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, listOptions...); err != nil {
+			klog.Errorf("Error listing pods of deployment %q: %v, retrying...", name, err)
+			return false, nil
+		}
+
+		var ready int32
+
+		for _, pod := range pods.Items {
+			if restarted.Has(pod.UID) {
+				klog.Infof("Pod %q of deployment %q is still terminating", pod.Name, name)
+				return false, nil
+			}
+
+			if isPodReady(&pod) {
+				ready++
+			}
+		}
+
+		klog.Infof("Deployment %q has %d of %d replacement pods ready", name, ready, replicas)
+
+		return ready >= replicas, nil
+	})
+}
+
+// RestartMachineAPIControllers restarts the pods of the machine-api-controllers deployment,
+// running the machine and machineset controllers, see RestartDeploymentPods.
+func RestartMachineAPIControllers(ctx context.Context, c client.Client) error {
+	return RestartDeploymentPods(ctx, c, mapiControllersDeploymentName, MachineAPINamespace)
+}
+
+// isPodReady returns true when the pod has a Ready condition with status True.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
 // DeploymentHasContainer returns true if the deployment has container with the specified name.
 func DeploymentHasContainer(deployment *kappsapi.Deployment, containerName string) bool {
 	for _, container := range deployment.Spec.Template.Spec.Containers {
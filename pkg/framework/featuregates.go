@@ -0,0 +1,100 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errFeatureGatesNotRendered = errors.New("feature gates are not rendered for the cluster version")
+
+// FeatureGateSet is the set of feature gates of the current version of the cluster.
+type FeatureGateSet struct {
+	// FeatureSet is the feature set the cluster is configured with, e.g. TechPreviewNoUpgrade.
+	FeatureSet configv1.FeatureSet
+	// Version is the cluster version the feature gates are rendered for.
+	Version string
+
+	enabled  sets.Set[configv1.FeatureGateName]
+	disabled sets.Set[configv1.FeatureGateName]
+}
+
+// FeatureGates returns the feature gates of the cluster, as rendered for the version the cluster runs or is
+// upgrading to. The FeatureGate status holds the gates of each version of an upgrade, which may differ.
+func FeatureGates(ctx context.Context, cl runtimeclient.Client) (*FeatureGateSet, error) {
+	clusterVersion := &configv1.ClusterVersion{}
+	if err := cl.Get(ctx, runtimeclient.ObjectKey{Name: "version"}, clusterVersion); err != nil {
+		return nil, fmt.Errorf("error getting ClusterVersion: %w", err)
+	}
+
+	featureGate := &configv1.FeatureGate{}
+	if err := cl.Get(ctx, runtimeclient.ObjectKey{Name: "cluster"}, featureGate); err != nil {
+		return nil, fmt.Errorf("error getting FeatureGate: %w", err)
+	}
+
+	version := clusterVersion.Status.Desired.Version
+
+	for _, details := range featureGate.Status.FeatureGates {
+		if details.Version != version {
+			continue
+		}
+
+		featureGates := &FeatureGateSet{
+			FeatureSet: featureGate.Spec.FeatureSet,
+			Version:    version,
+			enabled:    sets.New[configv1.FeatureGateName](),
+			disabled:   sets.New[configv1.FeatureGateName](),
+		}
+
+		for _, gate := range details.Enabled {
+			featureGates.enabled.Insert(gate.Name)
+		}
+
+		for _, gate := range details.Disabled {
+			featureGates.disabled.Insert(gate.Name)
+		}
+
+		return featureGates, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errFeatureGatesNotRendered, version)
+}
+
+// Enabled returns true when the feature gate is enabled.
+func (f *FeatureGateSet) Enabled(gate configv1.FeatureGateName) bool {
+	return f.enabled.Has(gate)
+}
+
+// Disabled returns true when the feature gate is disabled. Both Enabled and Disabled are false for the gates
+// the version does not know, e.g. gates removed once their feature is GA.
+func (f *FeatureGateSet) Disabled(gate configv1.FeatureGateName) bool {
+	return f.disabled.Has(gate)
+}
+
+// SkipUnlessFeatureGateEnabled skips the test unless the given feature gate is enabled in the cluster.
+func SkipUnlessFeatureGateEnabled(ctx context.Context, cl runtimeclient.Client, gate configv1.FeatureGateName) {
+	featureGates, err := FeatureGates(ctx, cl)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the feature gates of the cluster")
+
+	if !featureGates.Enabled(gate) {
+		Skip(fmt.Sprintf("Feature gate %s is not enabled in version %s, skipping", gate, featureGates.Version))
+	}
+}
+
+// SkipUnlessFeatureGateDisabled skips the test unless the given feature gate is disabled in the cluster,
+// e.g. for tests of the behaviour the feature gate replaces.
+func SkipUnlessFeatureGateDisabled(ctx context.Context, cl runtimeclient.Client, gate configv1.FeatureGateName) {
+	featureGates, err := FeatureGates(ctx, cl)
+	Expect(err).ToNot(HaveOccurred(), "Failed to get the feature gates of the cluster")
+
+	if !featureGates.Disabled(gate) {
+		Skip(fmt.Sprintf("Feature gate %s is not disabled in version %s, skipping", gate, featureGates.Version))
+	}
+}
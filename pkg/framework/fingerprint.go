@@ -0,0 +1,173 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FailureFingerprintReportEntry is the name of the report entry holding the FailureFingerprint of a failed spec.
+const FailureFingerprintReportEntry = "Failure fingerprint"
+
+// FailureKind is a failure mode the CI aggregation groups failed specs by.
+type FailureKind string
+
+const (
+	// FailureInsufficientCapacity is the cloud provider lacking the capacity for the instance type.
+	FailureInsufficientCapacity FailureKind = "insufficient-capacity"
+	// FailureQuotaExceeded is the cloud account exceeding a quota or limit.
+	FailureQuotaExceeded FailureKind = "quota-exceeded"
+	// FailureWebhookUnavailable is a webhook the API server could not call.
+	FailureWebhookUnavailable FailureKind = "webhook-unavailable"
+	// FailureMachineSetDeletionTimeout is a MachineSet or its machines not deleted in time.
+	FailureMachineSetDeletionTimeout FailureKind = "machineset-deletion-timeout"
+	// FailureNodeNeverReady is a machine whose node never became ready.
+	FailureNodeNeverReady FailureKind = "node-never-ready"
+	// FailureUnclassified is any other failure, likely a product regression.
+	FailureUnclassified FailureKind = "unclassified"
+)
+
+// FailureFingerprint is the machine-readable classification of the failure of a spec.
+// Infra is set for the failure modes caused by the cloud provider or the cluster rather than by the product.
+type FailureFingerprint struct {
+	Kind  FailureKind `json:"kind"`
+	Infra bool        `json:"infra"`
+	// Evidence is the message the failure was classified by.
+	Evidence string `json:"evidence,omitempty"`
+	// Attempt is the attempt of the spec which failed, above 1 for the specs marked LabelFlakeRetry.
+	Attempt int `json:"attempt,omitempty"`
+}
+
+// String returns the fingerprint as JSON, so that the report entry can be parsed by the CI aggregation.
+func (f FailureFingerprint) String() string {
+	out, err := json.Marshal(f)
+	if err != nil {
+		return string(f.Kind)
+	}
+
+	return string(out)
+}
+
+// FailureEvidence is the state of the cluster gathered when a spec fails, classified with the failure message.
+type FailureEvidence struct {
+	// MachineErrors are the error reasons and messages of the Failed machines.
+	MachineErrors []string
+	// NotReadyNodes are the names of the nodes which are not ready.
+	NotReadyNodes []string
+}
+
+// failureRule classifies the failures whose message or evidence matches its pattern.
+type failureRule struct {
+	kind    FailureKind
+	infra   bool
+	pattern *regexp.Regexp
+}
+
+// failureRules are tried in order: the cloud provider causes first, as they explain the timeouts matched later.
+var failureRules = []failureRule{
+	{
+		kind:    FailureInsufficientCapacity,
+		infra:   true,
+		pattern: regexp.MustCompile(`(?i)insufficient\s*(cloud provider )?(instance )?capacity|InsufficientInstanceCapacity|ZONE_RESOURCE_POOL_EXHAUSTED|SkuNotAvailable|AllocationFailed`),
+	},
+	// The cloud provider error codes only: "exceeded quota" is the message of the Kubernetes ResourceQuota admission,
+	// which the specs may expect.
+	{
+		kind:    FailureQuotaExceeded,
+		infra:   true,
+		pattern: regexp.MustCompile(`QuotaExceeded|QUOTA_EXCEEDED|Quota '[^']+' exceeded|VcpuLimitExceeded|InstanceLimitExceeded|VolumeLimitExceeded|AddressLimitExceeded|MaxSpotInstanceCountExceeded|OperationNotAllowed.*quota`),
+	},
+	{
+		kind:    FailureWebhookUnavailable,
+		infra:   true,
+		pattern: regexp.MustCompile(`(?i)failed calling webhook|webhook .* (is )?unavailable|no endpoints available for service`),
+	},
+	{
+		kind:    FailureMachineSetDeletionTimeout,
+		infra:   false,
+		pattern: regexp.MustCompile(`Machines still present for MachineSet|MachineSet \S+ still present`),
+	},
+	{
+		kind:    FailureNodeNeverReady,
+		infra:   true,
+		pattern: regexp.MustCompile(`(?i)node is not ready|node \S+ (is )?not ready|NotReady|node bootstrap failed`),
+	},
+}
+
+// notRunningPattern matches the timeouts waiting for machines, caused by a node never ready when a node is NotReady.
+var notRunningPattern = regexp.MustCompile(`(?i)not all Machines are running|timed out`)
+
+// FingerprintFailure classifies the failure of a spec from its message and the gathered evidence.
+func FingerprintFailure(message string, evidence FailureEvidence) FailureFingerprint {
+	candidates := append([]string{message}, evidence.MachineErrors...)
+
+	for _, rule := range failureRules {
+		for _, candidate := range candidates {
+			if rule.pattern.MatchString(candidate) {
+				return FailureFingerprint{Kind: rule.kind, Infra: rule.infra, Evidence: firstLine(candidate)}
+			}
+		}
+	}
+
+	if len(evidence.NotReadyNodes) > 0 && notRunningPattern.MatchString(message) {
+		return FailureFingerprint{
+			Kind:     FailureNodeNeverReady,
+			Infra:    true,
+			Evidence: fmt.Sprintf("NotReady nodes: %s", strings.Join(evidence.NotReadyNodes, ", ")),
+		}
+	}
+
+	return FailureFingerprint{Kind: FailureUnclassified}
+}
+
+// CollectFailureEvidence gathers the errors of the Failed machines of the given MachineSets, and the NotReady nodes
+// of their machines. The evidence is scoped to the MachineSets of the spec, see CleanupTracker.MachineSetsCreatedSince,
+// so that the machines other specs fail on purpose, e.g. with an invalid provider spec, do not classify its failure.
+func CollectFailureEvidence(ctx context.Context, cl runtimeclient.Client, machineSets []*machinev1.MachineSet) (FailureEvidence, error) {
+	evidence := FailureEvidence{}
+
+	for _, machineSet := range machineSets {
+		machines, err := GetMachinesFromMachineSet(ctx, cl, machineSet)
+		if err != nil {
+			return evidence, fmt.Errorf("unable to list the machines of MachineSet %s: %w", machineSet.Name, err)
+		}
+
+		for _, machine := range machines {
+			if machine.Status.ErrorReason != nil || machine.Status.ErrorMessage != nil {
+				evidence.MachineErrors = append(evidence.MachineErrors, fmt.Sprintf("machine %s: %s: %s",
+					machine.Name, ptr.Deref(machine.Status.ErrorReason, ""), ptr.Deref(machine.Status.ErrorMessage, "")))
+			}
+
+			if machine.Status.NodeRef == nil {
+				continue
+			}
+
+			node, err := GetNodeForMachine(ctx, cl, machine)
+			if apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return evidence, fmt.Errorf("unable to get the node of machine %s: %w", machine.Name, err)
+			}
+
+			if !IsNodeReady(node) {
+				evidence.NotReadyNodes = append(evidence.NotReadyNodes, node.Name)
+			}
+		}
+	}
+
+	return evidence, nil
+}
+
+// firstLine returns the first line of the message, as the failure messages carry the object dumps after it.
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+
+	return line
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -15,13 +17,17 @@ import (
 	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"github.com/tidwall/gjson"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
 )
@@ -42,15 +48,31 @@ const (
 	DefaultMachineSetReplicas  = 0
 	MachinePhaseRunning        = "Running"
 	MachinePhaseFailed         = "Failed"
+	MachinePhaseProvisioned    = "Provisioned"
+	MachinePhaseProvisioning   = "Provisioning"
+	MachinePhaseDeleting       = "Deleting"
 	MachineRoleLabel           = "machine.openshift.io/cluster-api-machine-role"
 	MachineTypeLabel           = "machine.openshift.io/cluster-api-machine-type"
 	MachineAnnotationKey       = "machine.openshift.io/machine"
 	ClusterAPIActuatorPkgTaint = "cluster-api-actuator-pkg"
 
+	// ExcludeNodeDrainingAnnotation makes the machine controller delete the Machine without draining its Node.
+	ExcludeNodeDrainingAnnotation = "machine.openshift.io/exclude-node-draining"
+
 	// Openshift CI specific env variables.
 	isCI        = "OPENSHIFT_CI"
 	artifactDir = "ARTIFACT_DIR"
 	cliDir      = "CLI_DIR"
+
+	// Machine API credentials, used when the cluster has no root credentials (e.g. STS).
+	machineAPIAWSCredentialsSecret      = "aws-cloud-credentials"
+	machineAPIControllersServiceAccount = "machine-api-controllers"
+	awsWebIdentityTokenAudience         = "openshift"
+)
+
+var (
+	errAWSCredentialsNotFound = errors.New("no AWS credentials or role to assume found")
+	errAWSRoleARNMissing      = errors.New("shared credentials have no role_arn")
 )
 
 var (
@@ -99,14 +121,52 @@ func GetPlatform(ctx context.Context, c runtimeclient.Client) (configv1.Platform
 	return platform, nil
 }
 
-// LoadClient returns a new controller-runtime client.
+// IsSNO returns true when the cluster is a single node OpenShift cluster, i.e. its control plane has a single replica.
+// Such clusters have no worker MachineSet the specs can copy to create machines.
+func IsSNO(ctx context.Context, c runtimeclient.Client) (bool, error) {
+	topology, err := getControlPlaneTopology(ctx, c)
+
+	return topology == configv1.SingleReplicaTopologyMode, err
+}
+
+// IsHostedControlPlane returns true when the control plane runs outside the cluster, e.g. on a HyperShift guest cluster.
+// Such clusters have no Machine API: their machines are managed by the NodePools of the management cluster.
+func IsHostedControlPlane(ctx context.Context, c runtimeclient.Client) (bool, error) {
+	topology, err := getControlPlaneTopology(ctx, c)
+
+	return topology == configv1.ExternalTopologyMode, err
+}
+
+// getControlPlaneTopology returns the control plane topology from the infrastructure object.
+func getControlPlaneTopology(ctx context.Context, c runtimeclient.Client) (configv1.TopologyMode, error) {
+	infra, err := GetInfrastructure(ctx, c)
+	if err != nil {
+		return "", err
+	}
+
+	return infra.Status.ControlPlaneTopology, nil
+}
+
+// LoadClient returns a new controller-runtime client, with every type the suites need registered, see RegisterAllSchemes.
+// The client is also set as the client of the komega matchers, so that the specs can use komega.Object and komega.ObjectList.
 func LoadClient() (runtimeclient.Client, error) {
+	if err := registerDefaultSchemes(); err != nil {
+		return nil, err
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return runtimeclient.New(cfg, runtimeclient.Options{})
+	cl, err := runtimeclient.New(cfg, runtimeclient.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	komega.SetClient(cl)
+
+	return cl, nil
 }
 
 // LoadClientset returns a new Kubernetes Clientset.
@@ -119,9 +179,46 @@ func LoadClientset() (*kubernetes.Clientset, error) {
 	return kubernetes.NewForConfig(cfg)
 }
 
-// GetContext returns a context.
+// LoadImpersonatingClient returns a new controller-runtime client, impersonating the given user and groups,
+// e.g. to create objects as the components of the cluster would.
+func LoadImpersonatingClient(username string, groups ...string) (runtimeclient.Client, error) {
+	if err := registerDefaultSchemes(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Impersonate = rest.ImpersonationConfig{UserName: username, Groups: groups}
+
+	return runtimeclient.New(cfg, runtimeclient.Options{})
+}
+
+var (
+	suiteContextOnce   sync.Once
+	suiteContext       context.Context
+	cancelSuiteContext context.CancelFunc
+)
+
+// GetContext returns the context of the suite.
+// Its deadline is the Ginkgo suite timeout, counted from the first call, so that the
+// API calls and polling of specs still running when the suite times out are stopped.
+// Cleanup which must run regardless should not use it.
 func GetContext() context.Context {
-	return context.Background()
+	suiteContextOnce.Do(func() {
+		suiteConfig, _ := GinkgoConfiguration()
+		suiteContext, cancelSuiteContext = context.WithTimeout(context.Background(), suiteConfig.Timeout)
+	})
+
+	return suiteContext
+}
+
+// CancelContext cancels the context returned by GetContext.
+func CancelContext() {
+	GetContext()
+	cancelSuiteContext()
 }
 
 func WaitForStatusAvailableShort(ctx context.Context, client runtimeclient.Client, name string) bool {
@@ -211,6 +308,31 @@ func WaitForEvent(ctx context.Context, c runtimeclient.Client, kind, name, reaso
 	})
 }
 
+// WaitForWarningEvent expects to find a warning event, whatever its reason, for the given object.
+func WaitForWarningEvent(ctx context.Context, c runtimeclient.Client, kind, name string) error {
+	return wait.PollUntilContextTimeout(ctx, RetryMedium, WaitMedium, true, func(ctx context.Context) (bool, error) {
+		eventList := corev1.EventList{}
+		if err := c.List(ctx, &eventList); err != nil {
+			klog.Errorf("error querying api for eventList object: %v, retrying...", err)
+			return false, nil
+		}
+
+		for _, event := range eventList.Items {
+			if event.Type != corev1.EventTypeWarning ||
+				event.InvolvedObject.Kind != kind ||
+				event.InvolvedObject.Name != name {
+				continue
+			}
+
+			klog.Infof("Found warning event for %s %q: %s: %s", kind, name, event.Reason, event.Message)
+
+			return true, nil
+		}
+
+		return false, nil
+	})
+}
+
 // NewCLI initializes oc binary wrapper helper.
 // Output and oc executable path configure depending on the environment.
 // If Openshift CI is detected, respective parameters are set up.
@@ -287,7 +409,7 @@ func DeleteObjects(ctx context.Context, cl runtimeclient.Client, objs ...runtime
 
 // SkipIfNotTechPreviewNoUpgrade skip test if a cluster is not a TechPreviewNoUpgrade cluster.
 func SkipIfNotTechPreviewNoUpgrade(oc *gatherer.CLI, cl runtimeclient.Client) {
-	featureSet, err := oc.WithoutNamespace().Run("get").Args("featuregate", "cluster", "-o=jsonpath={.spec.featureSet}").Output()
+	featureSet, err := oc.WithoutNamespace().GetJSONPath("featuregate", "cluster", "{.spec.featureSet}")
 	Expect(err).NotTo(HaveOccurred(), "Failed to get featureSet")
 
 	if featureSet != string(configv1.TechPreviewNoUpgrade) {
@@ -296,20 +418,122 @@ func SkipIfNotTechPreviewNoUpgrade(oc *gatherer.CLI, cl runtimeclient.Client) {
 }
 
 // GetCredentialsFromCluster get credentials from cluster.
-func GetCredentialsFromCluster(oc *gatherer.CLI) ([]byte, []byte, string) {
+// It returns the AWS access key ID, secret access key, region and, when the credentials are temporary, session token.
+// The spec is skipped when the cluster has no credentials the tests can use, see GetAWSCredentials.
+func GetCredentialsFromCluster(oc *gatherer.CLI) ([]byte, []byte, string, []byte) {
+	accessKeyID, secureKey, clusterRegion, sessionToken, err := GetAWSCredentials(oc)
+	if errors.Is(err, errAWSCredentialsNotFound) {
+		Skip(fmt.Sprintf("Unable to get AWS credentials, skipping the testing: %v", err))
+	}
+
+	Expect(err).NotTo(HaveOccurred(), "Failed to get AWS credentials")
+
+	return accessKeyID, secureKey, clusterRegion, sessionToken
+}
+
+// GetAWSCredentials returns the AWS access key ID, secret access key, region and, when the credentials are
+// temporary, session token of the cluster.
+// Clusters with static credentials provide them in the kube-system/aws-creds secret.
+// On clusters using short-lived credentials (STS), where that secret does not exist, the role
+// of the Machine API is assumed with a bound token of its service account.
+func GetAWSCredentials(oc *gatherer.CLI) ([]byte, []byte, string, []byte, error) {
+	clusterRegion, err := oc.WithoutNamespace().GetJSONPath("infrastructure", "cluster", "{.status.platformStatus.aws.region}")
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("error getting cluster region: %w", err)
+	}
+
 	awscreds, err := oc.WithoutNamespace().Run("get").Args("secret/aws-creds", "-n", "kube-system", "-o", "json").Output()
 	if err != nil {
-		Skip("Unable to get AWS credentials secret, skipping the testing.")
+		klog.Infof("Unable to get AWS root credentials, falling back to the Machine API credentials: %v", err)
+
+		accessKeyID, secureKey, sessionToken, err := getMachineAPIAWSCredentials(oc, clusterRegion)
+		if err != nil {
+			return nil, nil, "", nil, fmt.Errorf("%w: %w", errAWSCredentialsNotFound, err)
+		}
+
+		return accessKeyID, secureKey, clusterRegion, sessionToken, nil
 	}
 
 	accessKeyIDBase64, secureKeyBase64 := gjson.Get(awscreds, `data.aws_access_key_id`).String(), gjson.Get(awscreds, `data.aws_secret_access_key`).String()
 
 	accessKeyID, err := base64.StdEncoding.DecodeString(accessKeyIDBase64)
-	Expect(err).NotTo(HaveOccurred(), "Failed to decode accessKeyID")
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("error decoding accessKeyID: %w", err)
+	}
+
 	secureKey, err := base64.StdEncoding.DecodeString(secureKeyBase64)
-	Expect(err).NotTo(HaveOccurred(), "Failed to decode secureKey")
-	clusterRegion, err := oc.WithoutNamespace().Run("get").Args("infrastructure", "cluster", "-o=jsonpath={.status.platformStatus.aws.region}").Output()
-	Expect(err).NotTo(HaveOccurred(), "Failed to get clusterRegion")
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("error decoding secureKey: %w", err)
+	}
+
+	return accessKeyID, secureKey, clusterRegion, nil, nil
+}
+
+// getMachineAPIAWSCredentials returns the credentials of the Machine API from its credentials secret.
+// When the secret references a role to assume with a web identity (STS), a bound token of
+// the Machine API service account is exchanged for temporary credentials of that role.
+func getMachineAPIAWSCredentials(oc *gatherer.CLI, clusterRegion string) ([]byte, []byte, []byte, error) {
+	awscreds, err := oc.WithoutNamespace().Run("get").Args("secret/"+machineAPIAWSCredentialsSecret, "-n", MachineAPINamespace, "-o", "json").Output()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting secret %s/%s: %w", MachineAPINamespace, machineAPIAWSCredentialsSecret, err)
+	}
+
+	if accessKeyIDBase64 := gjson.Get(awscreds, `data.aws_access_key_id`).String(); accessKeyIDBase64 != "" {
+		accessKeyID, err := base64.StdEncoding.DecodeString(accessKeyIDBase64)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error decoding accessKeyID: %w", err)
+		}
+
+		secureKey, err := base64.StdEncoding.DecodeString(gjson.Get(awscreds, `data.aws_secret_access_key`).String())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error decoding secureKey: %w", err)
+		}
+
+		return accessKeyID, secureKey, nil, nil
+	}
+
+	sharedConfig, err := base64.StdEncoding.DecodeString(gjson.Get(awscreds, `data.credentials`).String())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error decoding shared credentials: %w", err)
+	}
+
+	roleARN := awsSharedConfigValue(string(sharedConfig), "role_arn")
+	if roleARN == "" {
+		return nil, nil, nil, fmt.Errorf("%w: secret %s/%s", errAWSRoleARNMissing, MachineAPINamespace, machineAPIAWSCredentialsSecret)
+	}
+
+	token, err := oc.WithNamespace(MachineAPINamespace).CreateToken(machineAPIControllersServiceAccount, awsWebIdentityTokenAudience)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error creating token for service account %s/%s: %w", MachineAPINamespace, machineAPIControllersServiceAccount, err)
+	}
+
+	return AssumeRoleWithWebIdentity(clusterRegion, roleARN, []byte(token))
+}
+
+// awsSharedConfigValue returns the value of the given key in an AWS shared config/credentials file.
+func awsSharedConfigValue(sharedConfig string, key string) string {
+	for _, line := range strings.Split(sharedConfig, "\n") {
+		k, v, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
+
+// createServiceAccountToken returns a bound token of the given service account valid for the given audience.
+func createServiceAccountToken(ctx context.Context, c runtimeclient.Client, namespace, serviceAccount, audience string) (string, error) {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: serviceAccount}}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences: []string{audience},
+		},
+	}
+
+	if err := c.SubResource("token").Create(ctx, sa, tokenRequest); err != nil {
+		return "", fmt.Errorf("error creating token for service account %s/%s: %w", namespace, serviceAccount, err)
+	}
 
-	return accessKeyID, secureKey, clusterRegion
+	return tokenRequest.Status.Token, nil
 }
@@ -0,0 +1,110 @@
+package gatherer
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConsoleCollector fetches the console output of the cloud instance backing a machine,
+// e.g. its serial console log or boot diagnostics.
+type ConsoleCollector interface {
+	ConsoleOutput(ctx context.Context, machine *machinev1.Machine) (string, error)
+}
+
+// ConsoleCollectorFactory builds the ConsoleCollector of a platform, using the given CLI to read its credentials.
+type ConsoleCollectorFactory func(ctx context.Context, oc *CLI) (ConsoleCollector, error)
+
+// consoleCollectors holds the console collector factories of the platforms, registered with RegisterConsoleCollector.
+var consoleCollectors = map[configv1.PlatformType]ConsoleCollectorFactory{}
+
+// RegisterConsoleCollector registers the factory of the console collector of the given platform.
+// It is meant to be called from init functions, the registrations are not synchronised.
+func RegisterConsoleCollector(platform configv1.PlatformType, factory ConsoleCollectorFactory) {
+	consoleCollectors[platform] = factory
+}
+
+// GatherConsoleOutputs collects the console output of the instances of the machines which failed
+// or never became a node, as their own logs are not reachable from the cluster.
+// Store files into '%CLI.outputBasePath%/%test_name%/console'.
+// Like GatherPodLogs, errors are only logged so that the other artifacts are gathered regardless.
+func (sg *StateGatherer) GatherConsoleOutputs() {
+	machines := &machinev1.MachineList{}
+	if err := sg.CLI.runtimeClient.List(sg.ctx, machines, runtimeclient.InNamespace(sg.CLI.Namespace())); err != nil {
+		klog.Errorf("Error listing machines: %v", err)
+		return
+	}
+
+	var stuck []*machinev1.Machine
+
+	for i := range machines.Items {
+		if needsConsoleOutput(&machines.Items[i]) {
+			stuck = append(stuck, &machines.Items[i])
+		}
+	}
+
+	if len(stuck) == 0 {
+		return
+	}
+
+	collector, err := sg.consoleCollector()
+	if err != nil {
+		klog.Errorf("Error creating console collector: %v", err)
+		return
+	}
+
+	if collector == nil {
+		return
+	}
+
+	consoleCLI := sg.CLI.WithSubPath(sg.getSubPath("console"))
+
+	for _, machine := range stuck {
+		klog.Infof("gathering console output of machine %s", machine.Name)
+
+		output, err := collector.ConsoleOutput(sg.ctx, machine)
+		if err != nil {
+			klog.Errorf("Error retrieving console output of machine %s: %v", machine.Name, err)
+			continue
+		}
+
+		if _, err := consoleCLI.WriteToFile(machine.Name+".log", output); err != nil {
+			klog.Errorf("Error writing console output of machine %s: %v", machine.Name, err)
+		}
+	}
+}
+
+// consoleCollector returns the console collector of the platform of the cluster,
+// or nil when none is registered for it.
+func (sg *StateGatherer) consoleCollector() (ConsoleCollector, error) {
+	infra := &configv1.Infrastructure{}
+	if err := sg.CLI.runtimeClient.Get(sg.ctx, runtimeclient.ObjectKey{Name: "cluster"}, infra); err != nil {
+		return nil, err
+	}
+
+	if infra.Status.PlatformStatus == nil {
+		return nil, nil
+	}
+
+	factory, ok := consoleCollectors[infra.Status.PlatformStatus.Type]
+	if !ok {
+		klog.Infof("No console collector for platform %s, skipping console outputs", infra.Status.PlatformStatus.Type)
+		return nil, nil
+	}
+
+	return factory(sg.ctx, sg.CLI)
+}
+
+// needsConsoleOutput returns true when the instance of the machine was created, but the machine
+// failed or its instance never joined the cluster as a node.
+func needsConsoleOutput(machine *machinev1.Machine) bool {
+	if machine.Spec.ProviderID == nil {
+		return false
+	}
+
+	return ptr.Deref(machine.Status.Phase, "") == "Failed" || machine.Status.NodeRef == nil
+}
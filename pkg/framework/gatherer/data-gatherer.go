@@ -96,10 +96,16 @@ func (sg *StateGatherer) GatherPodLogs() {
 	sg.CLI.WithSubPath(logsSubPath).WithNamespace(machineApproverNamespace).DumpPodLogsSinceTime(sg.ctx, sg.sinceTime)
 }
 
-// GatherAll invokes GatherResources and GatherPodLogs subsequently.
+// GatherAll invokes GatherResources, GatherPodLogs, GatherConsoleOutputs and,
+// when NodeTriageEnabled is set, GatherNodeTriage subsequently.
 func (sg *StateGatherer) GatherAll() error {
 	err := sg.GatherResources()
 	sg.GatherPodLogs()
+	sg.GatherConsoleOutputs()
+
+	if NodeTriageEnabled {
+		sg.GatherNodeTriage()
+	}
 
 	return err
 }
@@ -0,0 +1,90 @@
+package gatherer
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// maxTriagedNodes bounds the number of nodes GatherNodeTriage starts a debug pod on.
+	maxTriagedNodes = 3
+
+	// nodeTriageTimeout bounds the time a debug pod has to start and collect the triage of a node.
+	// The debug pod may never start on a node whose kubelet is down.
+	nodeTriageTimeout = 3 * time.Minute
+
+	// nodeTriageNamespace is the namespace of the debug pods, which run privileged on the host network.
+	nodeTriageNamespace = "default"
+
+	// nodeTriageScript collects the journal of the units bringing the node up and its network state.
+	nodeTriageScript = `for cmd in \
+	"journalctl --no-pager -u kubelet -n 500" \
+	"journalctl --no-pager -u crio -n 500" \
+	"journalctl --no-pager -t ignition -b -n 500" \
+	"ip addr" \
+	"ip route" \
+	"ip -6 route" \
+	"cat /etc/resolv.conf" \
+	"ss -tlnp"; do
+	echo "===== $cmd"
+	$cmd 2>&1
+done`
+)
+
+// NodeTriageEnabled turns on the collection of the node triage of the NotReady nodes by GatherAll,
+// set with the --node-triage flag. It is off by default as the debug pods require cluster-admin permissions.
+var NodeTriageEnabled bool
+
+// GatherNodeTriage collects the journal of kubelet, CRI-O and Ignition and the network state of the NotReady nodes,
+// from a debug pod started on each of them with 'oc debug node/'.
+// Store files into '%CLI.outputBasePath%/%test_name%/nodes'.
+// Like GatherPodLogs, errors are only logged so that the other artifacts are gathered regardless.
+func (sg *StateGatherer) GatherNodeTriage() {
+	nodes := &corev1.NodeList{}
+	if err := sg.CLI.runtimeClient.List(sg.ctx, nodes); err != nil {
+		klog.Errorf("Error listing nodes: %v", err)
+		return
+	}
+
+	nodesCLI := sg.CLI.WithSubPath(sg.getSubPath("nodes"))
+	triaged := 0
+
+	for _, node := range nodes.Items {
+		if isNodeReady(&node) {
+			continue
+		}
+
+		if triaged == maxTriagedNodes {
+			klog.Infof("Node triage collected for %d nodes, skipping the other NotReady nodes", maxTriagedNodes)
+			return
+		}
+
+		triaged++
+
+		klog.Infof("gathering triage of NotReady node %s", node.Name)
+
+		// The output is kept on error: it holds why the debug pod failed, or what was collected before the timeout.
+		output, err := sg.CLI.WithNamespace(nodeTriageNamespace).WithTimeout(nodeTriageTimeout).
+			DebugNode(node.Name, "bash", "-c", nodeTriageScript).Output()
+		if err != nil {
+			klog.Errorf("Error collecting triage of node %s: %v", node.Name, err)
+		}
+
+		if _, err := nodesCLI.WriteToFile(node.Name+".log", output); err != nil {
+			klog.Errorf("Error writing triage of node %s: %v", node.Name, err)
+		}
+	}
+}
+
+// isNodeReady returns true when the node has a Ready condition with status True.
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -17,6 +17,9 @@ import (
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// cliWaitDelay is how long an interrupted command has to exit before it is killed.
+const cliWaitDelay = 30 * time.Second
+
 // CLI provides function to call the OpenShift CLI, which is using to simplify state gathering during tests.
 // This wrapper was inspired by the openshift/origin version of a similar helper.
 // Origin version https://github.com/openshift/origin/blob/1ec0eb3175f25b525abb39253528e230a9a85684/test/extended/util/client.go#L80
@@ -37,6 +40,7 @@ type CLI struct {
 	stdout           io.Writer
 	stderr           io.Writer
 	verbose          bool
+	timeout          time.Duration
 }
 
 // NewCLI initializes the OC CLI wrapper.
@@ -66,7 +70,13 @@ func (oc *CLI) setOutput(out io.Writer) *CLI {
 }
 
 func (oc *CLI) outputs(stdOutBuff, stdErrBuff *bytes.Buffer) (string, string, error) {
-	cmd, err := oc.start(stdOutBuff, stdErrBuff)
+	ctx, cancel := context.WithCancel(context.Background())
+	if oc.timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), oc.timeout)
+	}
+	defer cancel()
+
+	cmd, err := oc.start(ctx, stdOutBuff, stdErrBuff)
 	if err != nil {
 		return "", "", err
 	}
@@ -86,7 +96,7 @@ func (oc *CLI) outputs(stdOutBuff, stdErrBuff *bytes.Buffer) (string, string, er
 		oc.stderr = bytes.NewBuffer(stdErrBytes)
 
 		return stdOut, stdErr, nil
-	case errors.As(err, &exitError):
+	case errors.As(err, &exitError), errors.Is(err, context.DeadlineExceeded):
 		klog.Infof("Error running %v:\nStdOut>\n%s\nStdErr>\n%s\n", cmd, stdOut, stdErr)
 
 		return stdOut, stdErr, err
@@ -95,15 +105,19 @@ func (oc *CLI) outputs(stdOutBuff, stdErrBuff *bytes.Buffer) (string, string, er
 	}
 }
 
-func (oc *CLI) start(stdOutBuff, stdErrBuff *bytes.Buffer) (*exec.Cmd, error) {
+func (oc *CLI) start(ctx context.Context, stdOutBuff, stdErrBuff *bytes.Buffer) (*exec.Cmd, error) {
 	oc.finalArgs = append(oc.globalArgs, oc.commandArgs...)
 	if oc.verbose {
 		klog.Infof("DEBUG: oc %s\n", oc.printCmd())
 	}
 
-	cmd := exec.Command(oc.execPath, oc.finalArgs...)
+	cmd := exec.CommandContext(ctx, oc.execPath, oc.finalArgs...)
 	cmd.Stdin = oc.stdin
 
+	// Interrupt rather than kill on timeout, so that the command cleans up after itself, e.g. 'oc debug' its pod.
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = cliWaitDelay
+
 	cmd.Stdout = stdOutBuff
 	cmd.Stderr = stdErrBuff
 
@@ -121,8 +135,14 @@ func (oc *CLI) OutputToFile(filename string) (string, error) {
 		return "", err
 	}
 
+	return oc.WriteToFile(oc.Namespace()+"-"+filename, content)
+}
+
+// WriteToFile stores the given content to a file under the output path of the CLI.
+// Nothing is written when the content is empty, in which case the returned path is empty.
+func (oc *CLI) WriteToFile(filename, content string) (string, error) {
 	path := filepath.Join(oc.outputBasePath, oc.subPath)
-	filePath := filepath.Join(path, oc.Namespace()+"-"+filename)
+	filePath := filepath.Join(path, filename)
 
 	if len(content) == 0 {
 		return "", nil
@@ -174,6 +194,12 @@ func (oc CLI) WithSubPath(subPath string) *CLI {
 	return &oc
 }
 
+// WithTimeout sets the time after which the command is interrupted.
+func (oc CLI) WithTimeout(timeout time.Duration) *CLI {
+	oc.timeout = timeout
+	return &oc
+}
+
 // WithExec overrides 'oc' executable path.
 func (oc CLI) WithExec(execPath string) *CLI {
 	oc.execPath = execPath
@@ -192,6 +218,8 @@ func (oc *CLI) Run(commands ...string) *CLI {
 		outputBasePath: oc.outputBasePath,
 		subPath:        oc.subPath,
 		namespace:      oc.Namespace(),
+		runtimeClient:  oc.runtimeClient,
+		timeout:        oc.timeout,
 		globalArgs:     commands,
 	}
 
@@ -0,0 +1,49 @@
+package gatherer
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Typed helpers for the OpenShift CLI commands the tests rely on, so that they do not assemble the arguments ad hoc.
+// The helpers returning a *CLI prepare the command only: run it with Output, Outputs or OutputToFile.
+
+// GetJSONPath returns the given JSONPath template, e.g. '{.status.platformStatus.type}', of the named resource.
+func (oc *CLI) GetJSONPath(resource, name, jsonPath string) (string, error) {
+	return oc.Run("get").Args(resource, name, "-o=jsonpath="+jsonPath).Output()
+}
+
+// CreateToken returns a bound token of the given service account of the namespace of the CLI,
+// valid for the given audience.
+func (oc *CLI) CreateToken(serviceAccount, audience string) (string, error) {
+	return oc.Run("create").Args("token", serviceAccount, "--audience", audience).Output()
+}
+
+// AdmTop prepares 'oc adm top' showing the resource usage of the given resource, "nodes" or "pods".
+func (oc *CLI) AdmTop(resource string, args ...string) *CLI {
+	return oc.Run("adm", "top").Args(append([]string{resource}, args...)...)
+}
+
+// DebugNode prepares 'oc debug node/' running the command on the host of the given node, from a debug pod
+// started in the namespace of the CLI, which must allow privileged pods.
+func (oc *CLI) DebugNode(nodeName string, command ...string) *CLI {
+	return oc.Run("debug").Args(append([]string{"node/" + nodeName, "--", "chroot", "/host"}, command...)...)
+}
+
+// ImageInfo prepares 'oc image info' showing the metadata of the given image, e.g. to check it is
+// pulled from a mirror.
+func (oc *CLI) ImageInfo(image string, args ...string) *CLI {
+	return oc.WithoutNamespace().Run("image", "info").Args(append([]string{image, "-o", "json"}, args...)...)
+}
+
+// MustGather runs 'oc adm must-gather' with the given arguments and returns the directory it stored the
+// data in: '%CLI.outputBasePath%/%CLI.subPath%/must-gather'. Bound it with WithTimeout, it may run for a while.
+func (oc *CLI) MustGather(args ...string) (string, error) {
+	destDir := filepath.Join(oc.outputBasePath, oc.subPath, "must-gather")
+
+	if _, err := oc.WithoutNamespace().Run("adm", "must-gather").Args(append([]string{"--dest-dir=" + destDir}, args...)...).Output(); err != nil {
+		return "", fmt.Errorf("error running must-gather: %w", err)
+	}
+
+	return destDir, nil
+}
@@ -0,0 +1,133 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// gcpInstanceTagsMetadataEndpoint is the GCE metadata server endpoint of the network tags of the instance.
+// The labels of the instance are not exposed by the metadata server.
+const gcpInstanceTagsMetadataEndpoint = "http://169.254.169.254/computeMetadata/v1/instance/tags?alt=json"
+
+// GCPNonUEFIImage is a boot image of the GCP machines which does not support UEFI, set with the
+// --gcp-non-uefi-image flag, e.g. 'projects/<project>/global/images/family/<family>'.
+// It cannot be looked up from an image family without the GCP compute API client, which is not vendored.
+var GCPNonUEFIImage string
+
+var (
+	// ErrGCPNonUEFIImageNotSet is returned when a non UEFI image is resolved without GCPNonUEFIImage.
+	ErrGCPNonUEFIImageNotSet = errors.New("the non UEFI GCP image must be set with --gcp-non-uefi-image")
+
+	errGCPBootDiskNotFound = errors.New("no boot disk in the GCP providerSpec")
+)
+
+// GetGCPBootImage returns the image of the boot disk of the given GCP ProviderSpec.
+func GetGCPBootImage(providerSpec *machinev1.ProviderSpec) (string, error) {
+	var gcpProviderConfig machinev1.GCPMachineProviderSpec
+	if err := json.Unmarshal(providerSpec.Value.Raw, &gcpProviderConfig); err != nil {
+		return "", fmt.Errorf("error unmarshalling providerSpec: %w", err)
+	}
+
+	for _, disk := range gcpProviderConfig.Disks {
+		if disk.Boot {
+			return disk.Image, nil
+		}
+	}
+
+	return "", errGCPBootDiskNotFound
+}
+
+// ResolveGCPBootImage returns the boot image of the GCP machines built from the given ProviderSpec:
+// its own image, the RHCOS image of the cluster which supports UEFI, or GCPNonUEFIImage when uefi is false.
+func ResolveGCPBootImage(providerSpec *machinev1.ProviderSpec, uefi bool) (string, error) {
+	if uefi {
+		return GetGCPBootImage(providerSpec)
+	}
+
+	if GCPNonUEFIImage == "" {
+		return "", ErrGCPNonUEFIImageNotSet
+	}
+
+	return GCPNonUEFIImage, nil
+}
+
+// UpdateProviderSpecGCPBootImage creates a new ProviderSpec booting from the given image.
+func UpdateProviderSpecGCPBootImage(providerSpec *machinev1.ProviderSpec, image string) (*machinev1.ProviderSpec, error) {
+	return updateGCPProviderSpec(providerSpec, func(gcpProviderConfig *machinev1.GCPMachineProviderSpec) {
+		for _, disk := range gcpProviderConfig.Disks {
+			if disk.Boot {
+				disk.Image = image
+			}
+		}
+	})
+}
+
+// UpdateProviderSpecGCPShieldedInstanceConfig creates a new ProviderSpec with the given Shielded VM configuration.
+func UpdateProviderSpecGCPShieldedInstanceConfig(providerSpec *machinev1.ProviderSpec, config machinev1.GCPShieldedInstanceConfig) (*machinev1.ProviderSpec, error) {
+	return updateGCPProviderSpec(providerSpec, func(gcpProviderConfig *machinev1.GCPMachineProviderSpec) {
+		gcpProviderConfig.ShieldedInstanceConfig = config
+	})
+}
+
+// updateGCPProviderSpec creates a new ProviderSpec from the given GCP ProviderSpec changed by the mutation.
+func updateGCPProviderSpec(providerSpec *machinev1.ProviderSpec, mutate func(*machinev1.GCPMachineProviderSpec)) (*machinev1.ProviderSpec, error) {
+	var gcpProviderConfig machinev1.GCPMachineProviderSpec
+	if err := json.Unmarshal(providerSpec.Value.Raw, &gcpProviderConfig); err != nil {
+		return nil, fmt.Errorf("error unmarshalling providerSpec: %w", err)
+	}
+
+	mutate(&gcpProviderConfig)
+
+	updatedProviderSpec, err := json.Marshal(gcpProviderConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling providerSpec: %w", err)
+	}
+
+	return &machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}, nil
+}
+
+// GetGCPInstanceNetworkTags queries the GCE metadata server from the given node
+// and returns the network tags of the instance backing it.
+func GetGCPInstanceNetworkTags(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node) ([]string, error) {
+	podSpec := corev1.PodSpec{
+		HostNetwork: true,
+		Containers: []corev1.Container{
+			{
+				Name:    "gcp-instance-metadata",
+				Image:   "registry.access.redhat.com/ubi8/ubi-minimal:latest",
+				Command: []string{"curl"},
+				Args: []string{
+					"--silent", "--fail",
+					"--header", "Metadata-Flavor: Google",
+					gcpInstanceTagsMetadataEndpoint,
+				},
+			},
+		},
+		Tolerations: []corev1.Toleration{
+			{
+				Operator: corev1.TolerationOpExists,
+			},
+		},
+	}
+
+	logs, err := RunPodOnNodeToCompletion(ctx, clientset, node, podSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(logs), &tags); err != nil {
+		return nil, fmt.Errorf("error unmarshalling GCP instance network tags: %w", err)
+	}
+
+	return tags, nil
+}
@@ -1,6 +1,29 @@
 package framework
 
-import "github.com/onsi/ginkgo/v2"
+import (
+	"slices"
+	"strconv"
+
+	"github.com/onsi/ginkgo/v2"
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	// MachinesLabelKey is the key of the label set recording how many machines a test creates,
+	// e.g. --label-filter='machines: isSubsetOf {0,1}'.
+	MachinesLabelKey = "machines"
+
+	// PlatformLabelKey is the key of the label set recording the platforms a test runs on,
+	// e.g. --label-filter='!platform || platform: containsAny AWS'. Tests without it run on every platform.
+	PlatformLabelKey = "platform"
+
+	// TopologyLabelKey is the key of the label set recording the control plane topologies a test runs on,
+	// e.g. --label-filter='!topology || topology: containsAny SingleReplica'. Tests without it run on every topology.
+	TopologyLabelKey = "topology"
+
+	// FlakeRetryAttempts is the number of times the tests marked LabelFlakeRetry are run before they fail.
+	FlakeRetryAttempts = 2
+)
 
 var (
 	// LabelAutoscaler applies to tests related to the cluster autoscaler functionality.
@@ -9,6 +32,9 @@ var (
 	// LabelCAPI applies to tests related to Cluster API (CAPI) functionality.
 	LabelCAPI = ginkgo.Label("capi")
 
+	// LabelChaos marks tests which kill controller pods during their operations when the suite runs with --chaos.
+	LabelChaos = ginkgo.Label("chaos")
+
 	// LabelCCM applies to tests related to the Cloud Controller Manager (CCM).
 	LabelCCM = ginkgo.Label("ccm")
 
@@ -18,6 +44,14 @@ var (
 	// LabelDisruptive marks tests that are disruptive in nature and may affect cluster stability.
 	LabelDisruptive = ginkgo.Label("disruptive")
 
+	// LabelFlakeRetry marks tests known to flake for infra reasons. They are retried once on failure, after running
+	// their AfterEach and DeferCleanup nodes, and reported as flaky when the retry passes. It bundles the flake-retry
+	// label with the FlakeAttempts decorator, so it is used like the other labels.
+	LabelFlakeRetry = []interface{}{ginkgo.Label("flake-retry"), ginkgo.FlakeAttempts(FlakeRetryAttempts)}
+
+	// LabelLargeScale marks tests which scale MachineSets to many machines, run on their own as they are slow and costly.
+	LabelLargeScale = ginkgo.Label("large-scale")
+
 	// LabelLEVEL0 indicates that the test is a basic or critical test, if failed then block release.
 	LabelLEVEL0 = ginkgo.Label("LEVEL0")
 
@@ -35,4 +69,58 @@ var (
 
 	// LabelQEOnly indicates that the test can run in qe account only.
 	LabelQEOnly = ginkgo.Label("qe-only")
+
+	// LabelReadOnly marks tests which only observe the existing state of the cluster, creating, updating or
+	// deleting nothing, so that they can run against production clusters.
+	LabelReadOnly = ginkgo.Label("read-only")
+
+	// LabelRequiresMachineManagement marks tests which create machines from the worker MachineSets,
+	// and are therefore skipped on single node clusters, see IsSNO.
+	LabelRequiresMachineManagement = ginkgo.Label("requires-machine-management")
+
+	// LabelSoak marks tests which repeat their cycles for SoakDuration to catch slow leaks, run on their own as they are long.
+	LabelSoak = ginkgo.Label("soak")
+
+	// LabelTechPreview marks tests which only run on TechPreviewNoUpgrade clusters.
+	LabelTechPreview = ginkgo.Label("techpreview")
 )
+
+// LabelMachines records the number of machines a test creates, so that tests can be scheduled
+// according to the capacity they require rather than skipping for insufficient quota at runtime.
+func LabelMachines(count int) ginkgo.Labels {
+	return ginkgo.Label(MachinesLabelKey + ":" + strconv.Itoa(count))
+}
+
+// LabelPlatforms records the platforms a test runs on, so that it can be scheduled on those platforms only
+// rather than skipping on the other platforms at runtime.
+func LabelPlatforms(platforms ...configv1.PlatformType) ginkgo.Labels {
+	labels := ginkgo.Labels{}
+
+	for _, platform := range platforms {
+		labels = append(labels, PlatformLabelKey+":"+string(platform))
+	}
+
+	return labels
+}
+
+// LabelTopologies records the control plane topologies a test runs on, e.g. to keep it off single node clusters.
+func LabelTopologies(topologies ...configv1.TopologyMode) ginkgo.Labels {
+	labels := ginkgo.Labels{}
+
+	for _, topology := range topologies {
+		labels = append(labels, TopologyLabelKey+":"+string(topology))
+	}
+
+	return labels
+}
+
+// HasLabels returns whether the labels of a spec, e.g. from CurrentSpecReport().Labels(), include all the given labels.
+func HasLabels(specLabels []string, labels ginkgo.Labels) bool {
+	for _, label := range labels {
+		if !slices.Contains(specLabels, label) {
+			return false
+		}
+	}
+
+	return true
+}
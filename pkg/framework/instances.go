@@ -0,0 +1,127 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var errInstanceNotDeleted = errors.New("cloud instance still exists")
+
+// DeleteMachineSetsAndWaitForInstances deletes the MachineSets, waits for them to be deleted like
+// WaitForMachineSetsDeleted, and then for the cloud instances of their machines to be gone like
+// WaitForCloudInstancesDeleted, catching the instances orphaned by the actuators.
+func DeleteMachineSetsAndWaitForInstances(ctx context.Context, c runtimeclient.Client, machineSets ...*machinev1.MachineSet) {
+	var machines []*machinev1.Machine
+
+	// The machines must be listed before the deletion, which removes them along with their provider IDs.
+	for _, machineSet := range machineSets {
+		machineSetMachines, err := GetMachinesFromMachineSet(ctx, c, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "listing the Machines of MachineSet %s should not error.", machineSet.GetName())
+
+		machines = append(machines, machineSetMachines...)
+	}
+
+	Expect(DeleteMachineSets(ctx, c, machineSets...)).To(Succeed(), "deleting the MachineSets should not error.")
+	WaitForMachineSetsDeleted(ctx, c, machineSets...)
+
+	WaitForCloudInstancesDeleted(ctx, c, machines...)
+}
+
+// WaitForCloudInstancesDeleted waits for the cloud instances backing the given, deleted, machines to be terminated
+// on AWS or deleted on Azure. The other platforms, and Azure clusters using a managed identity, are not verified:
+// the framework has no client for them.
+func WaitForCloudInstancesDeleted(ctx context.Context, c runtimeclient.Client, machines ...*machinev1.Machine) {
+	if len(machines) == 0 {
+		return
+	}
+
+	platform, err := GetPlatform(ctx, c)
+	Expect(err).ToNot(HaveOccurred(), "getting the platform should not error.")
+
+	var instanceDeleted func(machine *machinev1.Machine) (bool, error)
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		oc, err := NewCLI()
+		Expect(err).ToNot(HaveOccurred(), "creating the CLI should not error.")
+
+		awsClient := NewAwsClient(GetCredentialsFromCluster(oc)).WithContext(ctx)
+		instanceDeleted = func(machine *machinev1.Machine) (bool, error) {
+			return awsInstanceDeleted(awsClient, machine)
+		}
+	case configv1.AzurePlatformType:
+		azureClient, err := NewAzureClient(ctx, c)
+		if errors.Is(err, ErrAzureClientSecretMissing) {
+			klog.Infof("[cleanup] not verifying the deletion of the virtual machines: %v", err)
+			return
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "creating the Azure client should not error.")
+
+		instanceDeleted = func(machine *machinev1.Machine) (bool, error) {
+			return azureVirtualMachineDeleted(azureClient, machine)
+		}
+	default:
+		klog.Infof("[cleanup] not verifying the deletion of the cloud instances on %s", platform)
+		return
+	}
+
+	Eventually(func() error {
+		var errs []error
+
+		for _, machine := range machines {
+			deleted, err := instanceDeleted(machine)
+
+			switch {
+			case err != nil:
+				errs = append(errs, err)
+			case !deleted:
+				errs = append(errs, fmt.Errorf("%w: machine %s", errInstanceNotDeleted, machine.Name))
+			}
+		}
+
+		return errors.Join(errs...)
+	}, WaitLong, RetryMedium).Should(Succeed(), "the cloud instances of the deleted Machines should be gone.")
+}
+
+// awsInstanceDeleted returns whether the EC2 instance of the machine is terminated or gone.
+// A machine without a provider ID never had an instance.
+func awsInstanceDeleted(awsClient *AwsClient, machine *machinev1.Machine) (bool, error) {
+	providerID := ptr.Deref(machine.Spec.ProviderID, "")
+	if providerID == "" {
+		return true, nil
+	}
+
+	instance, err := awsClient.DescribeInstanceByProviderID(providerID)
+	if errors.Is(err, errInstanceNotFound) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return instance.State != nil && ptr.Deref(instance.State.Name, "") == ec2.InstanceStateNameTerminated, nil
+}
+
+// azureVirtualMachineDeleted returns whether the virtual machine of the machine is gone.
+func azureVirtualMachineDeleted(azureClient *AzureClient, machine *machinev1.Machine) (bool, error) {
+	var providerSpec machinev1.AzureMachineProviderSpec
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, &providerSpec); err != nil {
+		return false, fmt.Errorf("error unmarshalling providerSpec of machine %s: %w", machine.Name, err)
+	}
+
+	// The virtual machines are named after their machines.
+	exists, err := azureClient.VirtualMachineExists(providerSpec.ResourceGroup, machine.Name)
+
+	return !exists, err
+}
@@ -0,0 +1,219 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InterruptGracePeriod bounds the time spent deleting the tracked objects once the suite is interrupted.
+var InterruptGracePeriod = 5 * time.Minute
+
+// Tracked records the objects created by the framework which must be deleted if the suite is interrupted.
+var Tracked = NewCleanupTracker()
+
+// CleanupTracker records objects to delete when the suite is interrupted,
+// in case the DeferCleanup and AfterEach blocks of the running specs do not get to run.
+type CleanupTracker struct {
+	lock    sync.Mutex
+	objects map[string]runtimeclient.Object
+	// tracked is when the objects were first tracked, by the local clock like the spec start times.
+	tracked map[string]time.Time
+}
+
+// NewCleanupTracker returns a new, empty CleanupTracker.
+func NewCleanupTracker() *CleanupTracker {
+	return &CleanupTracker{
+		objects: map[string]runtimeclient.Object{},
+		tracked: map[string]time.Time{},
+	}
+}
+
+// Track registers the given objects for deletion.
+func (t *CleanupTracker) Track(objs ...runtimeclient.Object) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+
+	for _, obj := range objs {
+		key := trackerKey(obj)
+		t.objects[key] = obj.DeepCopyObject().(runtimeclient.Object)
+
+		if _, found := t.tracked[key]; !found {
+			t.tracked[key] = now
+		}
+	}
+}
+
+// Untrack drops the given objects, usually once they have been deleted by the spec.
+func (t *CleanupTracker) Untrack(objs ...runtimeclient.Object) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, obj := range objs {
+		delete(t.objects, trackerKey(obj))
+		delete(t.tracked, trackerKey(obj))
+	}
+}
+
+// Objects returns the tracked objects, in a stable order.
+func (t *CleanupTracker) Objects() []runtimeclient.Object {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	keys := make([]string, 0, len(t.objects))
+	for key := range t.objects {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	objs := make([]runtimeclient.Object, 0, len(keys))
+	for _, key := range keys {
+		objs = append(objs, t.objects[key])
+	}
+
+	return objs
+}
+
+// MachineSetsCreatedSince returns the tracked Machine API MachineSets created since the given time,
+// e.g. the MachineSets of the running spec from the start of the spec.
+// The time they were tracked is compared rather than their CreationTimestamp, which only has a second
+// granularity and is set by the clock of the API server.
+func (t *CleanupTracker) MachineSetsCreatedSince(since time.Time) []*machinev1.MachineSet {
+	var machineSets []*machinev1.MachineSet
+
+	for _, obj := range t.Objects() {
+		machineSet, ok := obj.(*machinev1.MachineSet)
+		if !ok || t.trackedAt(obj).Before(since) {
+			continue
+		}
+
+		machineSets = append(machineSets, machineSet)
+	}
+
+	return machineSets
+}
+
+// trackedAt returns when the given object was first tracked.
+func (t *CleanupTracker) trackedAt(obj runtimeclient.Object) time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.tracked[trackerKey(obj)]
+}
+
+// DeleteAll deletes the tracked objects and waits for them to be gone, until the context is done.
+// Objects are deleted in the foreground, so that e.g. the Machines of a MachineSet are gone too.
+func (t *CleanupTracker) DeleteAll(ctx context.Context, cl runtimeclient.Client) error {
+	var errs []error
+
+	objs := t.Objects()
+
+	for _, obj := range objs {
+		klog.Infof("[cleanup] deleting %T %s/%s", obj, obj.GetNamespace(), obj.GetName())
+
+		err := cl.Delete(ctx, obj, runtimeclient.PropagationPolicy("Foreground"))
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting %T %s/%s: %w", obj, obj.GetNamespace(), obj.GetName(), err))
+		}
+	}
+
+	for _, obj := range objs {
+		if err := wait.PollUntilContextCancel(ctx, RetryShort, true, func(ctx context.Context) (bool, error) {
+			err := cl.Get(ctx, runtimeclient.ObjectKeyFromObject(obj), obj.DeepCopyObject().(runtimeclient.Object))
+			if apierrors.IsNotFound(err) {
+				return true, nil
+			}
+
+			return false, nil
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("error waiting for %T %s/%s to be deleted: %w", obj, obj.GetNamespace(), obj.GetName(), err))
+			continue
+		}
+
+		t.Untrack(obj)
+	}
+
+	return errors.Join(errs...)
+}
+
+// trackerKey identifies an object in the tracker.
+func trackerKey(obj runtimeclient.Object) string {
+	return fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), obj.GetName())
+}
+
+var (
+	aborted          atomic.Bool
+	interruptCleanup = make(chan struct{})
+)
+
+// Aborted reports whether the suite has been interrupted.
+func Aborted() bool {
+	return aborted.Load()
+}
+
+// HandleInterrupts deletes the tracked objects when the process receives SIGINT or SIGTERM.
+// On interrupt, the run is marked as aborted, the suite context is cancelled, and the
+// tracked objects are deleted within InterruptGracePeriod. Ginkgo handles the signal too and
+// runs the cleanup nodes, which should call WaitForInterruptCleanup before the process exits.
+// The returned function stops handling interrupts.
+func HandleInterrupts(cl runtimeclient.Client) func() {
+	signals := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		select {
+		case <-stop:
+			return
+		case sig := <-signals:
+			klog.Warningf("[cleanup] received %s, aborting the run and deleting %d tracked objects", sig, len(Tracked.Objects()))
+		}
+
+		aborted.Store(true)
+		CancelContext()
+
+		ctx, cancel := context.WithTimeout(context.Background(), InterruptGracePeriod)
+		defer cancel()
+
+		if err := Tracked.DeleteAll(ctx, cl); err != nil {
+			klog.Errorf("[cleanup] error deleting tracked objects: %v", err)
+		}
+
+		close(interruptCleanup)
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() { close(stop) })
+	}
+}
+
+// WaitForInterruptCleanup blocks until the tracked objects have been deleted if the suite was interrupted.
+// It returns immediately otherwise.
+func WaitForInterruptCleanup() {
+	if !Aborted() {
+		return
+	}
+
+	<-interruptCleanup
+}
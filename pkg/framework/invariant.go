@@ -0,0 +1,151 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+// Invariant checking toolkit: an invariant is an assertion which must hold for the whole time the test waits
+// on a condition, e.g. a MachineSet never scaling out past its maximum while the cluster scales out.
+// Checking the invariant on every poll of the wait, rather than in a Consistently before or after an Eventually,
+// catches the invariant failing at any point of the wait.
+
+var (
+	errContextCancelled = errors.New("context cancelled")
+	errWhileHolds       = errors.New("while condition still holds")
+)
+
+// GomegaAssertions is a subset of the gomega.Gomega interface.
+// It is the set allowed for the invariants and conditions of the invariant checking helpers.
+type GomegaAssertions interface {
+	Ω(actual interface{}, extra ...interface{}) gomega.Assertion //nolint:asciicheck
+	Expect(actual interface{}, extra ...interface{}) gomega.Assertion
+	ExpectWithOffset(offset int, actual interface{}, extra ...interface{}) gomega.Assertion
+}
+
+// AssertionFunc is an invariant or a condition of the invariant checking helpers. It returns whether its assertions
+// succeeded, and must make them with the passed GomegaAssertions so that the helpers handle their failures.
+type AssertionFunc func(context.Context, GomegaAssertions) bool
+
+// RunCheckUntil runs the check function until the condition succeeds or the context is cancelled.
+// If the check fails before the condition succeeds, the test will fail.
+// It is RunInvariant, kept for the existing callers.
+func RunCheckUntil(ctx context.Context, check, condition func(context.Context, GomegaAssertions) bool) bool {
+	return RunInvariant(ctx, check, condition)
+}
+
+// RunInvariant runs the invariant until the condition succeeds or the context is cancelled.
+// If the invariant fails before the condition succeeds, the test fails straight away.
+func RunInvariant(ctx context.Context, invariant, condition AssertionFunc) bool {
+	return gomega.Eventually(pollInvariantUntil(ctx, invariant, condition)).WithContext(ctx).
+		Should(gomega.Succeed(), "invariant failed or condition did not succeed before the context was cancelled")
+}
+
+// EventuallyWithInvariant is RunInvariant polling every polling interval, for up to the timeout.
+func EventuallyWithInvariant(ctx context.Context, timeout, polling time.Duration, invariant, condition AssertionFunc) bool {
+	return gomega.Eventually(pollInvariantUntil(ctx, invariant, condition)).WithContext(ctx).
+		WithTimeout(timeout).WithPolling(polling).
+		Should(gomega.Succeed(), "invariant failed or condition did not succeed before the timeout")
+}
+
+// ConsistentlyWhile runs the invariant every polling interval for the given duration, as long as the while
+// condition holds. The test fails straight away if the invariant fails, and the check succeeds early once
+// the while condition no longer holds, e.g. once there is no more demand for the cluster to scale out.
+func ConsistentlyWhile(ctx context.Context, duration, polling time.Duration, invariant, while AssertionFunc) bool {
+	deadline := time.Now().Add(duration)
+
+	return gomega.Eventually(func() error {
+		if err := runAssertion(ctx, invariant); err != nil {
+			if errors.Is(err, errContextCancelled) {
+				return err
+			}
+
+			return gomega.StopTrying("Invariant failed").Wrap(err)
+		}
+
+		if err := runAssertion(ctx, while); err != nil {
+			if errors.Is(err, errContextCancelled) {
+				return err
+			}
+
+			// The while condition no longer holds.
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil
+		}
+
+		return errWhileHolds
+	}).WithContext(ctx).WithPolling(polling).
+		Should(gomega.Succeed(), "invariant failed or the context was cancelled")
+}
+
+// pollInvariantUntil returns the function polled by RunInvariant and EventuallyWithInvariant.
+func pollInvariantUntil(ctx context.Context, invariant, condition AssertionFunc) func() error {
+	return func() error {
+		invariantErr := runAssertion(ctx, invariant)
+		conditionErr := runAssertion(ctx, condition)
+
+		switch {
+		case invariantErr == nil && conditionErr == nil:
+			// The condition finally succeeded.
+			return nil
+		case errors.Is(conditionErr, errContextCancelled) || errors.Is(invariantErr, errContextCancelled):
+			// The context was cancelled.
+			// Return the context cancelled error so that the Eventually will fail with a consistent error.
+			return errContextCancelled
+		case invariantErr != nil:
+			// The invariant failed, whether or not the condition succeeded.
+			// Abort the check.
+			return gomega.StopTrying("Invariant failed before condition succeeded").Wrap(invariantErr)
+		default:
+			return conditionErr
+		}
+	}
+}
+
+// runAssertion runs the assertion function and returns an error if the assertion failed.
+func runAssertion(ctx context.Context, assertion AssertionFunc) error {
+	select {
+	case <-ctx.Done():
+		return errContextCancelled
+	default:
+	}
+
+	var err error
+
+	g := gomega.NewGomega(func(message string, callerSkip ...int) {
+		err = errors.New(message) //nolint:goerr113
+	})
+
+	if !assertion(ctx, g) {
+		if err == nil {
+			// The assertion returned false without a failed Gomega assertion.
+			err = errors.New("assertion returned false") //nolint:goerr113
+		}
+
+		return err
+	}
+
+	return nil
+}
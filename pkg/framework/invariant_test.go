@@ -0,0 +1,154 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Invariant checking", func() {
+	const (
+		timeout = time.Second
+		polling = 10 * time.Millisecond
+	)
+
+	var (
+		ctx     context.Context
+		counter int
+	)
+
+	// counterAtLeast succeeds once the counter reached n, incrementing it on every call.
+	counterAtLeast := func(n int) AssertionFunc {
+		return func(_ context.Context, g GomegaAssertions) bool {
+			counter++
+
+			return g.Expect(counter).To(BeNumerically(">=", n))
+		}
+	}
+
+	// counterBelow succeeds as long as the counter is below n.
+	counterBelow := func(n int) AssertionFunc {
+		return func(_ context.Context, g GomegaAssertions) bool {
+			return g.Expect(counter).To(BeNumerically("<", n))
+		}
+	}
+
+	holds := func(_ context.Context, g GomegaAssertions) bool {
+		return g.Expect(true).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		counter = 0
+	})
+
+	Context("RunInvariant", func() {
+		It("succeeds once the condition succeeds while the invariant holds", func() {
+			runCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			Expect(InterceptGomegaFailure(func() {
+				RunInvariant(runCtx, counterBelow(10), counterAtLeast(3))
+			})).To(Succeed())
+			Expect(counter).To(Equal(3))
+		})
+
+		It("fails as soon as the invariant fails", func() {
+			runCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			Expect(InterceptGomegaFailure(func() {
+				RunInvariant(runCtx, counterBelow(2), counterAtLeast(5))
+			})).To(MatchError(ContainSubstring("Invariant failed")))
+			Expect(counter).To(BeNumerically("<", 5), "The condition should not have been polled until it succeeded")
+		})
+
+		It("fails when the context is cancelled before the condition succeeds", func() {
+			runCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+
+			Expect(InterceptGomegaFailure(func() {
+				RunInvariant(runCtx, holds, func(_ context.Context, g GomegaAssertions) bool {
+					return g.Expect(false).To(BeTrue())
+				})
+			})).To(HaveOccurred())
+		})
+
+		It("fails when an assertion returns false without failing", func() {
+			runCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			Expect(InterceptGomegaFailure(func() {
+				RunInvariant(runCtx, func(context.Context, GomegaAssertions) bool { return false }, holds)
+			})).To(MatchError(ContainSubstring("assertion returned false")))
+		})
+	})
+
+	Context("EventuallyWithInvariant", func() {
+		It("succeeds once the condition succeeds while the invariant holds", func() {
+			Expect(InterceptGomegaFailure(func() {
+				EventuallyWithInvariant(ctx, timeout, polling, counterBelow(10), counterAtLeast(3))
+			})).To(Succeed())
+		})
+
+		It("fails when the condition does not succeed before the timeout", func() {
+			Expect(InterceptGomegaFailure(func() {
+				EventuallyWithInvariant(ctx, 50*time.Millisecond, polling, holds, counterAtLeast(1000))
+			})).To(MatchError(ContainSubstring("Timed out")))
+		})
+
+		It("fails as soon as the invariant fails", func() {
+			Expect(InterceptGomegaFailure(func() {
+				EventuallyWithInvariant(ctx, timeout, polling, counterBelow(2), counterAtLeast(5))
+			})).To(MatchError(ContainSubstring("Invariant failed")))
+			Expect(counter).To(BeNumerically("<", 5), "The condition should not have been polled until it succeeded")
+		})
+	})
+
+	Context("ConsistentlyWhile", func() {
+		It("succeeds when the invariant holds for the whole duration", func() {
+			start := time.Now()
+
+			Expect(InterceptGomegaFailure(func() {
+				ConsistentlyWhile(ctx, 100*time.Millisecond, polling, holds, holds)
+			})).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+		})
+
+		It("succeeds early once the while condition no longer holds", func() {
+			Expect(InterceptGomegaFailure(func() {
+				ConsistentlyWhile(ctx, time.Hour, polling, holds, func(_ context.Context, g GomegaAssertions) bool {
+					counter++
+
+					return g.Expect(counter).To(BeNumerically("<", 3))
+				})
+			})).To(Succeed())
+			Expect(counter).To(Equal(3))
+		})
+
+		It("fails as soon as the invariant fails", func() {
+			Expect(InterceptGomegaFailure(func() {
+				ConsistentlyWhile(ctx, time.Hour, polling, counterBelow(2), counterAtLeast(0))
+			})).To(MatchError(ContainSubstring("Invariant failed")))
+			Expect(counter).To(Equal(2))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GlobalNetworkName is the name of the cluster network configuration object.
+const GlobalNetworkName = "cluster"
+
+var (
+	errNoClusterNetwork   = errors.New("the cluster network configuration has no cluster network")
+	errMissingIPFamily    = errors.New("missing internal IP of the IP family")
+	errMachineAddressDiff = errors.New("machine addresses do not match the node internal IPs")
+)
+
+// GetClusterIPFamilies returns the IP families of the cluster network, the primary family first.
+// Single stack clusters return a single family, dual-stack clusters return both.
+func GetClusterIPFamilies(ctx context.Context, c runtimeclient.Client) ([]corev1.IPFamily, error) {
+	network := &configv1.Network{}
+	if err := c.Get(ctx, runtimeclient.ObjectKey{Name: GlobalNetworkName}, network); err != nil {
+		return nil, fmt.Errorf("error getting the cluster network configuration: %w", err)
+	}
+
+	// The status reflects the network in use, the spec is only set during installation.
+	entries := network.Status.ClusterNetwork
+	if len(entries) == 0 {
+		entries = network.Spec.ClusterNetwork
+	}
+
+	var families []corev1.IPFamily
+
+	for _, entry := range entries {
+		_, cidr, err := net.ParseCIDR(entry.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster network CIDR %q: %w", entry.CIDR, err)
+		}
+
+		if family := ipFamilyOf(cidr.IP); !slices.Contains(families, family) {
+			families = append(families, family)
+		}
+	}
+
+	if len(families) == 0 {
+		return nil, errNoClusterNetwork
+	}
+
+	return families, nil
+}
+
+// NodeInternalIPs returns the internal IPs of the node in the given IP family.
+func NodeInternalIPs(node *corev1.Node, family corev1.IPFamily) []string {
+	var ips []string
+
+	for _, address := range node.Status.Addresses {
+		if address.Type != corev1.NodeInternalIP {
+			continue
+		}
+
+		if ip := net.ParseIP(address.Address); ip != nil && ipFamilyOf(ip) == family {
+			ips = append(ips, address.Address)
+		}
+	}
+
+	return ips
+}
+
+// CheckMachineAddresses returns an error unless the node has an internal IP of each of the given IP families,
+// and the machine reports all the internal IPs of the node. Checking only the families of the cluster keeps
+// IPv4 assumptions from failing on IPv6 and dual-stack clusters.
+func CheckMachineAddresses(machine *machinev1.Machine, node *corev1.Node, families []corev1.IPFamily) error {
+	var errs []error
+
+	for _, family := range families {
+		ips := NodeInternalIPs(node, family)
+		if len(ips) == 0 {
+			errs = append(errs, fmt.Errorf("%w %s on node %s", errMissingIPFamily, family, node.Name))
+			continue
+		}
+
+		for _, ip := range ips {
+			if !slices.ContainsFunc(machine.Status.Addresses, func(address corev1.NodeAddress) bool {
+				return address.Type == corev1.NodeInternalIP && net.ParseIP(address.Address).Equal(net.ParseIP(ip))
+			}) {
+				errs = append(errs, fmt.Errorf("%w: machine %s does not report internal IP %s of node %s",
+					errMachineAddressDiff, machine.Name, ip, node.Name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ipFamilyOf returns the IP family of the given IP.
+func ipFamilyOf(ip net.IP) corev1.IPFamily {
+	if ip.To4() != nil {
+		return corev1.IPv4Protocol
+	}
+
+	return corev1.IPv6Protocol
+}
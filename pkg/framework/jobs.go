@@ -1,6 +1,8 @@
 package framework
 
 import (
+	"maps"
+
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -8,13 +10,44 @@ import (
 	"k8s.io/utils/ptr"
 )
 
+// NewWorkLoad returns a Job of njobs parallel pods each requesting the given memory and 500m of CPU,
+// to scale out the cluster by memory. The pods are scheduled on the nodes matching the node selector requirements.
 func NewWorkLoad(njobs int32, memoryRequest resource.Quantity, workloadJobName string,
+	testLabel string, podLabel string, nodeSelectorReqs ...corev1.NodeSelectorRequirement) *batchv1.Job {
+	return NewWorkLoadWithRequests(njobs, corev1.ResourceList{
+		corev1.ResourceMemory: memoryRequest,
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+	}, workloadJobName, testLabel, podLabel, nodeSelectorReqs...)
+}
+
+// NewCPUWorkLoad returns a Job of njobs parallel pods each requesting the given CPU and 64Mi of memory,
+// to scale out the cluster by CPU.
+func NewCPUWorkLoad(njobs int32, cpuRequest resource.Quantity, workloadJobName string,
+	testLabel string, podLabel string, nodeSelectorReqs ...corev1.NodeSelectorRequirement) *batchv1.Job {
+	return NewWorkLoadWithRequests(njobs, corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+		corev1.ResourceCPU:    cpuRequest,
+	}, workloadJobName, testLabel, podLabel, nodeSelectorReqs...)
+}
+
+// NewPodCountWorkLoad returns a Job of njobs parallel pods with negligible requests, to scale out
+// the cluster by the number of pods the nodes can run.
+func NewPodCountWorkLoad(njobs int32, workloadJobName string,
+	testLabel string, podLabel string, nodeSelectorReqs ...corev1.NodeSelectorRequirement) *batchv1.Job {
+	return NewWorkLoadWithRequests(njobs, corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("4Mi"),
+		corev1.ResourceCPU:    resource.MustParse("1m"),
+	}, workloadJobName, testLabel, podLabel, nodeSelectorReqs...)
+}
+
+// NewWorkLoadWithRequests returns a Job of njobs parallel pods each requesting the given resources.
+func NewWorkLoadWithRequests(njobs int32, requests corev1.ResourceList, workloadJobName string,
 	testLabel string, podLabel string, nodeSelectorReqs ...corev1.NodeSelectorRequirement) *batchv1.Job {
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workloadJobName,
 			Namespace: MachineAPINamespace,
-			Labels:    map[string]string{testLabel: ""},
+			Labels:    map[string]string{testLabel: "", ReasonKey: ReasonE2E},
 		},
 		Spec: batchv1.JobSpec{
 			Template: corev1.PodTemplateSpec{
@@ -28,10 +61,7 @@ func NewWorkLoad(njobs int32, memoryRequest resource.Quantity, workloadJobName s
 								"86400", // 1 day
 							},
 							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									"memory": memoryRequest,
-									"cpu":    resource.MustParse("500m"),
-								},
+								Requests: requests,
 							},
 						},
 					},
@@ -77,3 +107,12 @@ func NewWorkLoad(njobs int32, memoryRequest resource.Quantity, workloadJobName s
 
 	return job
 }
+
+// AddWorkLoadPodAnnotations adds the given annotations to the pods of the workload.
+func AddWorkLoadPodAnnotations(job *batchv1.Job, annotations map[string]string) {
+	if job.Spec.Template.ObjectMeta.Annotations == nil {
+		job.Spec.Template.ObjectMeta.Annotations = map[string]string{}
+	}
+
+	maps.Copy(job.Spec.Template.ObjectMeta.Annotations, annotations)
+}
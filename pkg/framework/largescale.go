@@ -0,0 +1,153 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// machineListPageSize is the number of machines fetched per request when listing the machines of large MachineSets.
+	machineListPageSize = 100
+
+	// machinesPerPollStep is the number of machines after which the scale polling interval grows by RetryMedium.
+	machinesPerPollStep = 10
+
+	// maxScalePollInterval caps the scale polling interval.
+	maxScalePollInterval = time.Minute
+)
+
+// errMachineFailed is returned when a machine of a MachineSet being scaled enters the Failed phase.
+var errMachineFailed = errors.New("machine failed")
+
+var (
+	// LargeScaleReplicas is the number of replicas the large scale specs scale a MachineSet to,
+	// set with the --large-scale-replicas flag.
+	LargeScaleReplicas = 50
+
+	// LargeScaleRunningBudget is the maximum time for all the machines of a large scale MachineSet to be Running,
+	// set with the --large-scale-running-budget flag.
+	LargeScaleRunningBudget = 20 * time.Minute
+
+	// LargeScaleNodesReadyBudget is the maximum time for all the nodes of a large scale MachineSet to be Ready,
+	// set with the --large-scale-nodes-ready-budget flag.
+	LargeScaleNodesReadyBudget = 30 * time.Minute
+)
+
+// MachineSetScaleTimings records how long a MachineSet took to scale, measured from the scale request.
+type MachineSetScaleTimings struct {
+	// AllRunning is the time until all the machines of the MachineSet were Running.
+	AllRunning time.Duration
+	// AllNodesReady is the time until the nodes of all the machines of the MachineSet were Ready.
+	AllNodesReady time.Duration
+}
+
+// ScalePollInterval returns the interval at which to poll a MachineSet being scaled to the given replicas.
+// The interval grows with the replicas, so that waiting for a large MachineSet does not list
+// all its machines every few seconds.
+func ScalePollInterval(replicas int) time.Duration {
+	interval := RetryMedium * time.Duration(1+replicas/machinesPerPollStep)
+
+	return min(interval, maxScalePollInterval)
+}
+
+// WaitForMachineSetScaled waits for the MachineSet to have all its replicas Running and then all their nodes Ready,
+// returning the time each took since start. Unlike WaitForMachineSet, it never fetches the nodes one by one:
+// machines are listed in pages and the node readiness is taken from the MachineSet status.
+// It returns an error when the timeout is exceeded or a machine fails.
+func WaitForMachineSetScaled(ctx context.Context, c runtimeclient.Client, name string, start time.Time, timeout time.Duration) (MachineSetScaleTimings, error) {
+	timings := MachineSetScaleTimings{}
+
+	machineSet, err := GetMachineSet(ctx, c, name)
+	if err != nil {
+		return timings, fmt.Errorf("error getting MachineSet %s: %w", name, err)
+	}
+
+	replicas := int(ptr.Deref(machineSet.Spec.Replicas, DefaultMachineSetReplicas))
+	interval := ScalePollInterval(replicas)
+
+	ctx, cancel := context.WithDeadline(ctx, start.Add(timeout))
+	defer cancel()
+
+	if err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		machines, err := listMachineSetMachines(ctx, c, machineSet)
+		if err != nil {
+			klog.Warningf("[scale] unable to list the machines of MachineSet %s: %v", name, err)
+			return false, nil
+		}
+
+		running := 0
+
+		for i := range machines {
+			switch ptr.Deref(machines[i].Status.Phase, "") {
+			case MachinePhaseFailed:
+				return false, fmt.Errorf("%w: machine %s", errMachineFailed, machines[i].Name)
+			case MachinePhaseRunning:
+				running++
+			}
+		}
+
+		klog.Infof("[scale] MachineSet %s: %d of %d machines running (%d listed)", name, running, replicas, len(machines))
+
+		return len(machines) == replicas && running == replicas, nil
+	}); err != nil {
+		return timings, fmt.Errorf("error waiting for the machines of MachineSet %s to be running: %w", name, err)
+	}
+
+	timings.AllRunning = time.Since(start)
+
+	if err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+		current, err := GetMachineSet(ctx, c, name)
+		if err != nil {
+			klog.Warningf("[scale] unable to get MachineSet %s: %v", name, err)
+			return false, nil
+		}
+
+		klog.Infof("[scale] MachineSet %s: %d of %d nodes ready", name, current.Status.ReadyReplicas, replicas)
+
+		return int(current.Status.ReadyReplicas) == replicas, nil
+	}); err != nil {
+		return timings, fmt.Errorf("error waiting for the nodes of MachineSet %s to be ready: %w", name, err)
+	}
+
+	timings.AllNodesReady = time.Since(start)
+
+	return timings, nil
+}
+
+// listMachineSetMachines lists the machines matching the selector of the MachineSet, machineListPageSize at a time.
+func listMachineSetMachines(ctx context.Context, c runtimeclient.Client, machineSet *machinev1.MachineSet) ([]machinev1.Machine, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&machineSet.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector of MachineSet %s: %w", machineSet.Name, err)
+	}
+
+	var machines []machinev1.Machine
+
+	opts := []runtimeclient.ListOption{
+		runtimeclient.InNamespace(machineSet.Namespace),
+		runtimeclient.MatchingLabelsSelector{Selector: selector},
+		runtimeclient.Limit(machineListPageSize),
+	}
+
+	for continueToken := ""; ; {
+		page := &machinev1.MachineList{}
+		if err := c.List(ctx, page, append(opts, runtimeclient.Continue(continueToken))...); err != nil {
+			return nil, err
+		}
+
+		machines = append(machines, page.Items...)
+
+		if continueToken = page.Continue; continueToken == "" {
+			return machines, nil
+		}
+	}
+}
@@ -2,13 +2,18 @@ package framework
 
 import (
 	"context"
+	"time"
 
 	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// E2EUnhealthyConditionType is the type of the node condition the tests mark nodes unhealthy with.
+const E2EUnhealthyConditionType = "MachineHealthCheckE2E"
+
 // MachineHealthCheckParams represents the parameters for creating a
 // new MachineHealthCheck resource for use in tests.
 type MachineHealthCheckParams struct {
@@ -16,6 +21,9 @@ type MachineHealthCheckParams struct {
 	Labels       map[string]string
 	Conditions   []machinev1.UnhealthyCondition
 	MaxUnhealthy *int
+	// NodeStartupTimeout, when set, overrides the time a Machine may stay
+	// without a linked Node before it is considered unhealthy.
+	NodeStartupTimeout *metav1.Duration
 }
 
 // CreateMHC creates a new MachineHealthCheck resource.
@@ -42,9 +50,38 @@ func CreateMHC(c client.Client, params MachineHealthCheckParams) (*machinev1.Mac
 		mhc.Spec.MaxUnhealthy = &maxUnhealthy
 	}
 
+	if params.NodeStartupTimeout != nil {
+		mhc.Spec.NodeStartupTimeout = params.NodeStartupTimeout
+	}
+
 	if err := c.Create(context.Background(), mhc); err != nil {
 		return nil, err
 	}
 
 	return mhc, nil
 }
+
+// NewE2EUnhealthyNodeCondition returns the node condition marking a node unhealthy for the
+// MachineHealthChecks with the E2EUnhealthyConditions. Add it to the node with AddNodeCondition.
+func NewE2EUnhealthyNodeCondition() corev1.NodeCondition {
+	return corev1.NodeCondition{
+		Type:               E2EUnhealthyConditionType,
+		Status:             corev1.ConditionTrue,
+		LastHeartbeatTime:  metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+		Reason:             "E2E",
+		Message:            "MachineHealthCheck E2E tests",
+	}
+}
+
+// E2EUnhealthyConditions returns the unhealthy conditions of a MachineHealthCheck remediating
+// the nodes marked with NewE2EUnhealthyNodeCondition for longer than the given timeout.
+func E2EUnhealthyConditions(timeout time.Duration) []machinev1.UnhealthyCondition {
+	return []machinev1.UnhealthyCondition{
+		{
+			Type:    E2EUnhealthyConditionType,
+			Status:  corev1.ConditionTrue,
+			Timeout: metav1.Duration{Duration: timeout},
+		},
+	}
+}
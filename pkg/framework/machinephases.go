@@ -0,0 +1,131 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// MachinePhaseTransition is a Machine phase observed by a MachinePhaseWatcher.
+type MachinePhaseTransition struct {
+	Phase string
+	// ObservedAt is the time the watcher observed the phase.
+	ObservedAt time.Time
+	// LastUpdated is the Machine status LastUpdated timestamp when the phase was observed.
+	LastUpdated *metav1.Time
+}
+
+// MachinePhaseWatcher watches Machines and records every phase they go through.
+// Unlike polling, a watch does not miss short lived phases.
+type MachinePhaseWatcher struct {
+	lock        sync.Mutex
+	transitions map[string][]MachinePhaseTransition
+
+	watcher watch.Interface
+	done    chan struct{}
+}
+
+// WatchMachinePhases starts watching the Machines in the Machine API namespace matching the given selector.
+// The watcher must be stopped once the transitions have been checked.
+func WatchMachinePhases(ctx context.Context, selector *metav1.LabelSelector) (*MachinePhaseWatcher, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error getting config: %w", err)
+	}
+
+	cl, err := runtimeclient.NewWithWatch(cfg, runtimeclient.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("error creating watch client: %w", err)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("error converting label selector: %w", err)
+	}
+
+	watcher, err := cl.Watch(ctx, &machinev1.MachineList{},
+		runtimeclient.InNamespace(MachineAPINamespace),
+		runtimeclient.MatchingLabelsSelector{Selector: labelSelector},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error watching machines: %w", err)
+	}
+
+	w := &MachinePhaseWatcher{
+		transitions: map[string][]MachinePhaseTransition{},
+		watcher:     watcher,
+		done:        make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// Stop stops watching the Machines.
+func (w *MachinePhaseWatcher) Stop() {
+	w.watcher.Stop()
+	<-w.done
+}
+
+// Transitions returns the phases observed for the given Machine, in order.
+func (w *MachinePhaseWatcher) Transitions(machineName string) []MachinePhaseTransition {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return append([]MachinePhaseTransition(nil), w.transitions[machineName]...)
+}
+
+// Phases returns the phases observed for the given Machine, in order.
+func (w *MachinePhaseWatcher) Phases(machineName string) []string {
+	transitions := w.Transitions(machineName)
+
+	phases := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		phases = append(phases, t.Phase)
+	}
+
+	return phases
+}
+
+func (w *MachinePhaseWatcher) run() {
+	defer close(w.done)
+
+	for event := range w.watcher.ResultChan() {
+		machine, ok := event.Object.(*machinev1.Machine)
+		if !ok {
+			continue
+		}
+
+		phase := ptr.Deref(machine.Status.Phase, "")
+		if phase == "" {
+			continue
+		}
+
+		w.record(machine.Name, phase, machine.Status.LastUpdated)
+	}
+}
+
+func (w *MachinePhaseWatcher) record(machineName, phase string, lastUpdated *metav1.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	transitions := w.transitions[machineName]
+	if len(transitions) > 0 && transitions[len(transitions)-1].Phase == phase {
+		return
+	}
+
+	w.transitions[machineName] = append(transitions, MachinePhaseTransition{
+		Phase:       phase,
+		ObservedAt:  time.Now(),
+		LastUpdated: lastUpdated.DeepCopy(),
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/klog"
 
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
 
 // FilterMachines returns a slice of only those Machines in the input that are
@@ -147,10 +148,10 @@ func DeleteMachines(ctx context.Context, client runtimeclient.Client, machines .
 }
 
 // WaitForMachinesDeleted polls until the given Machines are not found.
-func WaitForMachinesDeleted(c runtimeclient.Client, machines ...*machinev1.Machine) {
-	Eventually(func() bool {
+func WaitForMachinesDeleted(ctx context.Context, c runtimeclient.Client, machines ...*machinev1.Machine) {
+	Eventually(ctx, func() bool {
 		for _, m := range machines {
-			if err := c.Get(context.Background(), runtimeclient.ObjectKey{
+			if err := c.Get(ctx, runtimeclient.ObjectKey{
 				Name:      m.GetName(),
 				Namespace: m.GetNamespace(),
 			}, &machinev1.Machine{}); !apierrors.IsNotFound(err) {
@@ -161,3 +162,53 @@ func WaitForMachinesDeleted(c runtimeclient.Client, machines ...*machinev1.Machi
 		return true // Everything was deleted.
 	}, WaitLong, RetryMedium).Should(BeTrue(), "error encountered while waiting for Machines to be deleted.")
 }
+
+// WaitForMachineDrainBlocked waits until the given Machine is in the "Deleting" phase and its
+// Drained condition is false, e.g. because a PodDisruptionBudget prevents the eviction of its pods.
+func WaitForMachineDrainBlocked(ctx context.Context, c runtimeclient.Client, machine *machinev1.Machine) {
+	Eventually(komega.New(c).WithContext(ctx).Object(machineKey(machine)), WaitLong, RetryMedium).Should(
+		Satisfy(isMachineDrainBlocked), "Machine %q should be deleting with a blocked drain", machine.GetName())
+}
+
+// ExpectMachineDrainBlocked asserts that the given Machine stays in the "Deleting" phase
+// with a false Drained condition for the given duration.
+func ExpectMachineDrainBlocked(ctx context.Context, c runtimeclient.Client, machine *machinev1.Machine, duration time.Duration) {
+	Consistently(komega.New(c).WithContext(ctx).Object(machineKey(machine)), duration, RetryMedium).Should(
+		Satisfy(isMachineDrainBlocked), "Machine %q should stay deleting with a blocked drain", machine.GetName())
+}
+
+// WaitForMachinePhase waits up to the timeout for the given Machine to be in one of the phases,
+// and returns its latest version.
+func WaitForMachinePhase(ctx context.Context, c runtimeclient.Client, machine *machinev1.Machine, timeout time.Duration, phases ...string) *machinev1.Machine {
+	current := machineKey(machine)
+
+	Eventually(komega.New(c).WithContext(ctx).Object(current), timeout, RetryMedium).Should(
+		HaveField("Status.Phase", HaveValue(BeElementOf(phases))), "Machine %q should be in phase %v", machine.GetName(), phases)
+
+	return current
+}
+
+// machineKey returns an empty Machine with the name and namespace of the given one, for the komega matchers
+// to fetch without overwriting the given Machine.
+func machineKey(machine *machinev1.Machine) *machinev1.Machine {
+	return &machinev1.Machine{ObjectMeta: metav1.ObjectMeta{Name: machine.GetName(), Namespace: machine.GetNamespace()}}
+}
+
+// isMachineDrainBlocked returns true if the given Machine is deleting and has not been drained.
+func isMachineDrainBlocked(machine *machinev1.Machine) bool {
+	if machine.Status.Phase == nil || *machine.Status.Phase != MachinePhaseDeleting {
+		klog.Infof("Machine %q is not in phase %q yet", machine.Name, MachinePhaseDeleting)
+		return false
+	}
+
+	for _, condition := range machine.Status.Conditions {
+		if condition.Type == machinev1.MachineDrained {
+			klog.Infof("Machine %q condition %s is %s: %s", machine.Name, condition.Type, condition.Status, condition.Message)
+			return condition.Status == corev1.ConditionFalse
+		}
+	}
+
+	klog.Infof("Machine %q has no %s condition yet", machine.Name, machinev1.MachineDrained)
+
+	return false
+}
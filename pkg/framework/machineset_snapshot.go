@@ -0,0 +1,125 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachineSetSnapshot holds the serialized MachineSets of the cluster which were not created by the e2e
+// framework, so that specs mutating or deleting them can restore them even when they fail midway.
+type MachineSetSnapshot struct {
+	originals map[string][]byte
+}
+
+// SnapshotMachineSets serializes the MachineSets of the Machine API namespace not labelled with ReasonE2E.
+func SnapshotMachineSets(ctx context.Context, cl runtimeclient.Client) (*MachineSetSnapshot, error) {
+	machineSets := &machinev1.MachineSetList{}
+	if err := cl.List(ctx, machineSets, runtimeclient.InNamespace(MachineAPINamespace)); err != nil {
+		return nil, fmt.Errorf("error listing MachineSets: %w", err)
+	}
+
+	snapshot := &MachineSetSnapshot{originals: map[string][]byte{}}
+
+	for i := range machineSets.Items {
+		machineSet := &machineSets.Items[i]
+		if machineSet.Labels[ReasonKey] == ReasonE2E {
+			continue
+		}
+
+		original, err := json.Marshal(machineSet)
+		if err != nil {
+			return nil, fmt.Errorf("error serializing MachineSet %s: %w", machineSet.Name, err)
+		}
+
+		snapshot.originals[machineSet.Name] = original
+	}
+
+	return snapshot, nil
+}
+
+// Restore re-applies the spec, labels and annotations of the MachineSets of the snapshot which were
+// changed, and recreates the ones which were deleted, waiting for them to be deleted first.
+func (s *MachineSetSnapshot) Restore(ctx context.Context, cl runtimeclient.Client) error {
+	for name, serialized := range s.originals {
+		original := &machinev1.MachineSet{}
+		if err := json.Unmarshal(serialized, original); err != nil {
+			return fmt.Errorf("error deserializing MachineSet %s: %w", name, err)
+		}
+
+		if err := restoreMachineSet(ctx, cl, original); err != nil {
+			return fmt.Errorf("error restoring MachineSet %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// restoreMachineSet updates the MachineSet back to the original, or recreates it.
+func restoreMachineSet(ctx context.Context, cl runtimeclient.Client, original *machinev1.MachineSet) error {
+	current := &machinev1.MachineSet{}
+
+	err := cl.Get(ctx, runtimeclient.ObjectKeyFromObject(original), current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if err == nil && current.DeletionTimestamp.IsZero() {
+		if apiequality.Semantic.DeepEqual(current.Spec, original.Spec) &&
+			apiequality.Semantic.DeepEqual(current.Labels, original.Labels) &&
+			apiequality.Semantic.DeepEqual(current.Annotations, original.Annotations) {
+			return nil
+		}
+
+		klog.Infof("[snapshot] restoring the spec of MachineSet %s", original.Name)
+
+		// An update rather than an apply, so that the labels and annotations added by the specs are removed too.
+		// It is retried on conflict with the controllers updating the MachineSet, up to WaitShort.
+		return wait.PollUntilContextTimeout(ctx, RetryShort, WaitShort, true, func(ctx context.Context) (bool, error) {
+			if err := cl.Get(ctx, runtimeclient.ObjectKeyFromObject(original), current); err != nil {
+				return false, err
+			}
+
+			current.Spec = original.Spec
+			current.Labels = original.Labels
+			current.Annotations = original.Annotations
+
+			if err := cl.Update(ctx, current); err != nil {
+				if apierrors.IsConflict(err) {
+					return false, nil
+				}
+
+				return false, err
+			}
+
+			return true, nil
+		})
+	}
+
+	if err == nil {
+		WaitForMachineSetsDeleted(ctx, cl, current)
+	}
+
+	klog.Infof("[snapshot] recreating deleted MachineSet %s", original.Name)
+
+	recreated := &machinev1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            original.Name,
+			Namespace:       original.Namespace,
+			Labels:          original.Labels,
+			Annotations:     original.Annotations,
+			OwnerReferences: original.OwnerReferences,
+		},
+		Spec: original.Spec,
+	}
+
+	return cl.Create(ctx, recreated)
+}
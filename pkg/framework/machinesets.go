@@ -6,9 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -29,6 +29,7 @@ import (
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
 
 // MachineSetParams represents the parameters for creating a new MachineSet
@@ -54,9 +55,6 @@ var (
 	// ErrMachineNotProvisionedInsufficientCloudCapacity is used when we detect that the machine is not being provisioned due to insufficient provider capacity.
 	ErrMachineNotProvisionedInsufficientCloudCapacity = errors.New("machine creation failed due to insufficient cloud provider capacity")
 
-	// errTestForPlatformNotImplemented is used when platform specific test is run on a platform that does not have it implemented.
-	errTestForPlatformNotImplemented = errors.New("test for current platform not implemented")
-
 	// errMachineInMachineSetFailed is used when one of the machines in the machine set is in a failed state.
 	errMachineInMachineSetFailed = errors.New("machine in the machineset is in a failed phase")
 )
@@ -109,7 +107,7 @@ func buildMachineSetParamsFromMachineSet(ctx context.Context, client runtimeclie
 	Expect(err).NotTo(HaveOccurred(), "getting infrastructure global object should not error.")
 	Expect(clusterInfra.Status.InfrastructureName).ShouldNot(BeEmpty(), "infrastructure name was empty on Infrastructure.Status.")
 
-	name := clusterInfra.Status.InfrastructureName + "-" + uuid.New().String()[0:5]
+	name := Names.GenerateName(clusterInfra.Status.InfrastructureName + "-")
 
 	return MachineSetParams{
 		Name:         name,
@@ -138,6 +136,29 @@ func BuildMachineSetParams(ctx context.Context, client runtimeclient.Client, rep
 	return buildMachineSetParamsFromMachineSet(ctx, client, replicas, workers[0])
 }
 
+// BuildValidationMachineSetParams builds a MachineSetParams object for specs which only exercise the API
+// validation of MachineSets, and can therefore run on single node clusters. It is built from the first worker
+// MachineSet like BuildMachineSetParams when there is one. Otherwise, the provider spec is copied from a
+// control plane Machine and the replicas are set to 0, as the control plane provider spec must not run workers.
+func BuildValidationMachineSetParams(ctx context.Context, client runtimeclient.Client, replicas int) MachineSetParams {
+	if workers, err := GetWorkerMachineSets(ctx, client); err == nil {
+		return buildMachineSetParamsFromMachineSet(ctx, client, replicas, workers[0])
+	}
+
+	controlPlaneMachines, err := GetMachines(ctx, client, &metav1.LabelSelector{
+		MatchLabels: map[string]string{MachineRoleLabel: "master"},
+	})
+	Expect(err).ToNot(HaveOccurred(), "listing control plane Machines should not error.")
+	Expect(controlPlaneMachines).ToNot(BeEmpty(), "there should be a worker MachineSet or a control plane Machine.")
+
+	controlPlane := controlPlaneMachines[0]
+	machineSet := &machinev1.MachineSet{}
+	machineSet.Spec.Template.Labels = map[string]string{ClusterKey: controlPlane.Labels[ClusterKey]}
+	machineSet.Spec.Template.Spec.ProviderSpec = controlPlane.Spec.ProviderSpec
+
+	return buildMachineSetParamsFromMachineSet(ctx, client, 0, machineSet)
+}
+
 // CreateMachineSet creates a new MachineSet resource.
 func CreateMachineSet(c runtimeclient.Client, params MachineSetParams) (*machinev1.MachineSet, error) {
 	labels := params.Labels
@@ -176,9 +197,19 @@ func CreateMachineSet(c runtimeclient.Client, params MachineSetParams) (*machine
 		return nil, err
 	}
 
+	Tracked.Track(ms)
+
 	return ms, nil
 }
 
+// alternativeInstanceTypes are the instance types, per platform, MachineSets can be moved to
+// when their own instance type lacks capacity or to exercise an instance type change.
+var alternativeInstanceTypes = map[configv1.PlatformType][]string{
+	// Using cheapest compute optimized instances that meet openshift minimum requirements (4 vCPU, 8GiB RAM)
+	configv1.AWSPlatformType:   {"c5.xlarge", "c5a.xlarge", "m5.xlarge"},
+	configv1.AzurePlatformType: {"Standard_F4s_v2", "Standard_D4as_v5", "Standard_D4as_v4"},
+}
+
 // BuildMachineSetParamsList creates a list of MachineSetParams based on the given machineSetParams with modified instance type.
 func BuildAlternativeMachineSetParams(machineSetParams MachineSetParams, platform configv1.PlatformType) ([]MachineSetParams, error) {
 	baseMachineSetParams := machineSetParams
@@ -188,9 +219,7 @@ func BuildAlternativeMachineSetParams(machineSetParams MachineSetParams, platfor
 
 	switch platform {
 	case configv1.AWSPlatformType:
-		// Using cheapest compute optimized instances that meet openshift minimum requirements (4 vCPU, 8GiB RAM)
-		alternativeInstanceTypes := []string{"c5.xlarge", "c5a.xlarge", "m5.xlarge"}
-		for _, instanceType := range alternativeInstanceTypes {
+		for _, instanceType := range alternativeInstanceTypes[platform] {
 			updatedProviderSpec, err := updateProviderSpecAWSInstanceType(baseProviderSpec, instanceType)
 			if err != nil {
 				return nil, fmt.Errorf("failed to update provider spec with instance type %s: %w", instanceType, err)
@@ -200,8 +229,7 @@ func BuildAlternativeMachineSetParams(machineSetParams MachineSetParams, platfor
 			output = append(output, baseMachineSetParams)
 		}
 	case configv1.AzurePlatformType:
-		alternativeVMSizes := []string{"Standard_F4s_v2", "Standard_D4as_v5", "Standard_D4as_v4"}
-		for _, VMSize := range alternativeVMSizes {
+		for _, VMSize := range alternativeInstanceTypes[platform] {
 			updatedProviderSpec, err := updateProviderSpecAzureVMSize(baseProviderSpec, VMSize)
 			if err != nil {
 				return nil, fmt.Errorf("failed to update provider spec with VM size %s: %w", VMSize, err)
@@ -217,6 +245,37 @@ func BuildAlternativeMachineSetParams(machineSetParams MachineSetParams, platfor
 	return output, nil
 }
 
+// BuildGPUMachineSetParams creates MachineSetParams based on the given machineSetParams with an instance type
+// providing a single NVIDIA GPU.
+func BuildGPUMachineSetParams(machineSetParams MachineSetParams, platform configv1.PlatformType) (MachineSetParams, error) {
+	gpuMachineSetParams := machineSetParams
+	baseProviderSpec := machineSetParams.ProviderSpec.DeepCopy()
+
+	var (
+		updatedProviderSpec machinev1.ProviderSpec
+		err                 error
+	)
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		updatedProviderSpec, err = updateProviderSpecAWSInstanceType(baseProviderSpec, "g4dn.xlarge")
+	case configv1.AzurePlatformType:
+		updatedProviderSpec, err = updateProviderSpecAzureVMSize(baseProviderSpec, "Standard_NC4as_T4_v3")
+	case configv1.GCPPlatformType:
+		updatedProviderSpec, err = updateProviderSpecGCPGPU(baseProviderSpec, "n1-standard-4", "nvidia-tesla-t4")
+	default:
+		return MachineSetParams{}, fmt.Errorf("GPU instance types for platform %s not set", platform)
+	}
+
+	if err != nil {
+		return MachineSetParams{}, fmt.Errorf("failed to update provider spec with a GPU instance type: %w", err)
+	}
+
+	gpuMachineSetParams.ProviderSpec = &updatedProviderSpec
+
+	return gpuMachineSetParams, nil
+}
+
 // updateProviderSpecAWSInstanceType creates a new ProviderSpec with the given instance type.
 func updateProviderSpecAWSInstanceType(providerSpec *machinev1.ProviderSpec, instanceType string) (machinev1.ProviderSpec, error) {
 	var awsProviderConfig machinev1.AWSMachineProviderConfig
@@ -259,6 +318,104 @@ func updateProviderSpecAzureVMSize(providerSpec *machinev1.ProviderSpec, vmSize
 	return newProviderSpec, nil
 }
 
+// updateProviderSpecGCPGPU creates a new ProviderSpec with the given machine type and a single GPU of the given type.
+func updateProviderSpecGCPGPU(providerSpec *machinev1.ProviderSpec, machineType, gpuType string) (machinev1.ProviderSpec, error) {
+	var gcpProviderConfig machinev1.GCPMachineProviderSpec
+	if err := json.Unmarshal(providerSpec.Value.Raw, &gcpProviderConfig); err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	gcpProviderConfig.MachineType = machineType
+	gcpProviderConfig.GPUs = []machinev1.GCPGPUConfig{{Type: gpuType, Count: 1}}
+	// Instances with GPUs cannot be live migrated.
+	gcpProviderConfig.OnHostMaintenance = machinev1.TerminateHostMaintenanceType
+
+	updatedProviderSpec, err := json.Marshal(gcpProviderConfig)
+	if err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	newProviderSpec := machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}
+
+	return newProviderSpec, nil
+}
+
+// UpdateProviderSpecUserDataSecret creates a new ProviderSpec referencing the given user data secret.
+// All Machine API providers store the reference in the userDataSecret field of their provider spec.
+func UpdateProviderSpecUserDataSecret(providerSpec *machinev1.ProviderSpec, secretName string) (*machinev1.ProviderSpec, error) {
+	var providerConfig map[string]interface{}
+	if err := json.Unmarshal(providerSpec.Value.Raw, &providerConfig); err != nil {
+		return nil, err
+	}
+
+	providerConfig["userDataSecret"] = map[string]interface{}{"name": secretName}
+
+	updatedProviderSpec, err := json.Marshal(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}, nil
+}
+
+// UpdateProviderSpecMetadataAnnotations creates a new ProviderSpec with the given annotations added to the metadata
+// of its value. The AWS, Azure, GCP and vSphere provider specs embed an ObjectMeta, which the providers keep but ignore.
+func UpdateProviderSpecMetadataAnnotations(providerSpec *machinev1.ProviderSpec, annotations map[string]string) (*machinev1.ProviderSpec, error) {
+	var providerConfig map[string]interface{}
+	if err := json.Unmarshal(providerSpec.Value.Raw, &providerConfig); err != nil {
+		return nil, err
+	}
+
+	metadata, _ := providerConfig["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	existing, _ := metadata["annotations"].(map[string]interface{})
+	if existing == nil {
+		existing = map[string]interface{}{}
+	}
+
+	for key, value := range annotations {
+		existing[key] = value
+	}
+
+	metadata["annotations"] = existing
+	providerConfig["metadata"] = metadata
+
+	updatedProviderSpec, err := json.Marshal(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}, nil
+}
+
+// GetProviderSpecMetadataAnnotations returns the annotations of the metadata of the ProviderSpec value.
+func GetProviderSpecMetadataAnnotations(providerSpec machinev1.ProviderSpec) (map[string]string, error) {
+	if providerSpec.Value == nil {
+		return nil, nil
+	}
+
+	var providerConfig struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}
+
+	if err := json.Unmarshal(providerSpec.Value.Raw, &providerConfig); err != nil {
+		return nil, fmt.Errorf("error unmarshalling providerSpec: %w", err)
+	}
+
+	return providerConfig.Metadata.Annotations, nil
+}
+
 // GetMachineSets gets a list of machinesets from the default machine API namespace.
 // Optionaly, labels may be used to constrain listed machinesets.
 func GetMachineSets(client runtimeclient.Client, selectors ...*metav1.LabelSelector) ([]*machinev1.MachineSet, error) {
@@ -509,12 +666,17 @@ func getScaleClient() (scale.ScalesGetter, error) {
 // WaitForMachineSet waits for the all Machines belonging to the named
 // MachineSet to enter the "Running" phase, and for all nodes belonging to those
 // Machines to be ready. If a Machine is detected in "Failed" phase, the test
-// will exit early.
+// will exit early. The provider IDs of the Machines and their nodes must match
+// the format of the platform and each other.
+// On timeout, the reason the nodes never became ready is classified, see ClassifyBootstrapFailures.
 func WaitForMachineSet(ctx context.Context, c runtimeclient.Client, name string) {
 	machineSet, err := GetMachineSet(ctx, c, name)
 	Expect(err).ToNot(HaveOccurred(), "listing MachineSets should not error.")
 
-	Eventually(func() error {
+	platform, err := GetPlatform(ctx, c)
+	Expect(err).ToNot(HaveOccurred(), "getting the platform should not error.")
+
+	waitCached(ctx, c, func(c runtimeclient.Client) error {
 		machines, err := GetMachinesFromMachineSet(ctx, c, machineSet)
 		if err != nil {
 			return err
@@ -565,12 +727,52 @@ func WaitForMachineSet(ctx context.Context, c runtimeclient.Client, name string)
 		}
 
 		return nil
-	}, WaitOverLong, RetryMedium).ShouldNot(HaveOccurred())
+	}, WaitOverLong, RetryMedium, func() string {
+		// Evaluated on timeout only, to tell why the nodes never joined.
+		return describeBootstrapFailures(ctx, c, machineSet)
+	})
+
+	// Validated once all the nodes are ready rather than while polling, so a mismatch fails right away.
+	machines, err := GetMachinesFromMachineSet(ctx, c, machineSet)
+	Expect(err).ToNot(HaveOccurred(), "listing Machines should not error.")
+
+	for _, m := range machines {
+		node, err := GetNodeForMachine(ctx, c, m)
+		Expect(err).ToNot(HaveOccurred(), "getting the node of Machine %q should not error.", m.Name)
+
+		Expect(ValidateMachineProviderID(platform, m, node)).To(Succeed(), "machine and node provider IDs should match the format of the platform and each other")
+	}
+}
+
+// WaitForMachineSetReplicas waits up to the timeout for the given MachineSet to be scaled to the replicas,
+// e.g. by the autoscaler.
+func WaitForMachineSetReplicas(ctx context.Context, c runtimeclient.Client, machineSet *machinev1.MachineSet, replicas int32, timeout time.Duration) {
+	current := &machinev1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: machineSet.GetName(), Namespace: machineSet.GetNamespace()}}
+
+	Eventually(komega.New(c).WithContext(ctx).Object(current), timeout, RetryShort).Should(
+		HaveField("Spec.Replicas", HaveValue(Equal(replicas))), "MachineSet %q should be scaled to %d replicas", machineSet.GetName(), replicas)
+}
+
+// insufficientCapacityErrorKeys are the error codes the cloud providers report when they lack the capacity
+// for an instance type, in a zone or for the Spot market.
+var insufficientCapacityErrorKeys = map[configv1.PlatformType][]string{
+	configv1.AWSPlatformType:   {"InsufficientInstanceCapacity", "InsufficientHostCapacity"},
+	configv1.AzurePlatformType: {"SkuNotAvailable", "ZonalAllocationFailed", "AllocationFailed"},
+	configv1.GCPPlatformType:   {"ZONE_RESOURCE_POOL_EXHAUSTED"},
 }
 
-// WaitForSpotMachineSet waits for all Machines belonging to the machineSet to be running and their nodes to be ready.
-// Unlike WaitForMachineSet, this function does not fail the test when machine cannoct be provisioned due to insufficient spot capacity.
-func WaitForSpotMachineSet(ctx context.Context, c runtimeclient.Client, name string) error {
+// InsufficientCapacityErrorKeys returns the error codes the cloud provider of the platform reports when it lacks
+// the capacity for an instance type, for WaitForMachineSetWithCapacityRetry.
+func InsufficientCapacityErrorKeys(platform configv1.PlatformType) []string {
+	return insufficientCapacityErrorKeys[platform]
+}
+
+// WaitForMachineSetWithCapacityRetry waits for all Machines belonging to the named MachineSet to be running and
+// their nodes to be ready. Unlike WaitForMachineSet, it does not fail the test when a Machine cannot be provisioned
+// for lack of cloud capacity, i.e. when its error or its creation condition mentions one of the error keys,
+// e.g. from InsufficientCapacityErrorKeys. It returns ErrMachineNotProvisionedInsufficientCloudCapacity instead,
+// so that the caller can retry with another instance type or zone, or skip.
+func WaitForMachineSetWithCapacityRetry(ctx context.Context, c runtimeclient.Client, name string, errorKeys []string) error {
 	machineSet, err := GetMachineSet(ctx, c, name)
 	if err != nil {
 		return fmt.Errorf("could not get machineset %s: %w", name, err)
@@ -589,6 +791,19 @@ func WaitForSpotMachineSet(ctx context.Context, c runtimeclient.Client, name str
 			return false, nil
 		}
 
+		// Check if any machine did not get provisioned because of insufficient capacity first,
+		// as the cloud providers may also fail the machine.
+		for _, m := range machines {
+			insufficientCapacityResult, err := hasInsufficientCapacity(m, errorKeys)
+			if err != nil {
+				return false, fmt.Errorf("error checking if machine %s has insufficient capacity: %w", m.Name, err)
+			}
+
+			if insufficientCapacityResult {
+				return false, fmt.Errorf("%w: machine %s", ErrMachineNotProvisionedInsufficientCloudCapacity, m.Name)
+			}
+		}
+
 		failed := FilterMachines(machines, MachinePhaseFailed)
 		if len(failed) > 0 {
 			// if there are failed machines, print them out before we exit
@@ -604,18 +819,6 @@ func WaitForSpotMachineSet(ctx context.Context, c runtimeclient.Client, name str
 			return false, errMachineInMachineSetFailed
 		}
 
-		// Check if any machine did not get provisioned because of insufficient spot capacity.
-		for _, m := range machines {
-			insufficientCapacityResult, err := hasInsufficientCapacity(m, platform)
-			if err != nil {
-				return false, fmt.Errorf("error checking if machine %s has insufficient capacity: %w", m.Name, err)
-			}
-
-			if insufficientCapacityResult {
-				return false, ErrMachineNotProvisionedInsufficientCloudCapacity
-			}
-		}
-
 		running := FilterRunningMachines(machines)
 		// This could probably be smarter, but seems fine for now.
 		if len(running) != len(machines) {
@@ -640,46 +843,60 @@ func WaitForSpotMachineSet(ctx context.Context, c runtimeclient.Client, name str
 	})
 }
 
-// hasInsufficientCapacity return true if the machine cannot be provisioned due to insufficient spot capacity.
-func hasInsufficientCapacity(m *machinev1.Machine, platform configv1.PlatformType) (bool, error) {
-	switch platform {
-	case configv1.AWSPlatformType:
-		awsProviderStatus := machinev1.AWSMachineProviderStatus{}
-		if m.Status.ProviderStatus != nil {
-			if err := json.Unmarshal(m.Status.ProviderStatus.Raw, &awsProviderStatus); err != nil {
-				return false, fmt.Errorf("error unmarshalling provider status: %w", err)
-			}
+// CreateMachineSetWithCapacityRetry creates a MachineSet from each of the given params in turn, until the machines
+// of one of them are running, see WaitForMachineSetWithCapacityRetry. The MachineSets which could not be provisioned
+// for lack of cloud capacity are deleted before the next params are tried. It returns the running MachineSet,
+// or ErrMachineNotProvisionedInsufficientCloudCapacity when no params could be provisioned.
+func CreateMachineSetWithCapacityRetry(ctx context.Context, c runtimeclient.Client, paramsList []MachineSetParams, errorKeys []string) (*machinev1.MachineSet, error) {
+	for _, params := range paramsList {
+		machineSet, err := CreateMachineSet(c, params)
+		if err != nil {
+			return nil, fmt.Errorf("error creating MachineSet %s: %w", params.Name, err)
+		}
 
-			return hasInsufficientCapacityCondition(awsProviderStatus.Conditions, configv1.AWSPlatformType)
+		err = WaitForMachineSetWithCapacityRetry(ctx, c, machineSet.GetName(), errorKeys)
+		if !errors.Is(err, ErrMachineNotProvisionedInsufficientCloudCapacity) {
+			return machineSet, err
 		}
-	case configv1.AzurePlatformType:
-		azureProviderStatus := machinev1.AzureMachineProviderStatus{}
-		if m.Status.ProviderStatus != nil {
-			if err := json.Unmarshal(m.Status.ProviderStatus.Raw, &azureProviderStatus); err != nil {
-				return false, fmt.Errorf("error unmarshalling provider status: %w", err)
-			}
 
-			return hasInsufficientCapacityCondition(azureProviderStatus.Conditions, configv1.AzurePlatformType)
+		klog.Infof("MachineSet %s could not be provisioned, trying the next alternative: %v", machineSet.GetName(), err)
+
+		if err := DeleteMachineSets(ctx, c, machineSet); err != nil {
+			return nil, fmt.Errorf("error deleting MachineSet %s: %w", machineSet.GetName(), err)
 		}
-	default:
-		return false, errTestForPlatformNotImplemented
+
+		WaitForMachineSetsDeleted(ctx, c, machineSet)
 	}
 
-	return false, nil
+	return nil, fmt.Errorf("%w: no alternative out of %d could be provisioned", ErrMachineNotProvisionedInsufficientCloudCapacity, len(paramsList))
 }
 
-// hasInsufficientCapacity return true if there is an insufficient spot capacity condition.
-func hasInsufficientCapacityCondition(conditions []metav1.Condition, platform configv1.PlatformType) (bool, error) {
-	for _, condition := range conditions {
-		if (condition.Type == string(machinev1.MachineCreation) || condition.Type == string(machinev1.MachineCreated)) &&
-			condition.Status == metav1.ConditionFalse {
-			switch platform {
-			case configv1.AWSPlatformType:
-				return strings.Contains(condition.Message, "InsufficientInstanceCapacity"), nil
-			case configv1.AzurePlatformType:
-				return strings.Contains(condition.Message, "SkuNotAvailable"), nil
-			default:
-				return false, errTestForPlatformNotImplemented
+// hasInsufficientCapacity returns true if the error of the machine or the message of its failed creation
+// condition mentions one of the error keys.
+func hasInsufficientCapacity(m *machinev1.Machine, errorKeys []string) (bool, error) {
+	messages := []string{ptr.Deref(m.Status.ErrorMessage, "")}
+
+	if m.Status.ProviderStatus != nil {
+		// The provider statuses of every platform report the creation of the instance in their conditions.
+		providerStatus := struct {
+			Conditions []metav1.Condition `json:"conditions"`
+		}{}
+		if err := json.Unmarshal(m.Status.ProviderStatus.Raw, &providerStatus); err != nil {
+			return false, fmt.Errorf("error unmarshalling provider status: %w", err)
+		}
+
+		for _, condition := range providerStatus.Conditions {
+			if (condition.Type == string(machinev1.MachineCreation) || condition.Type == string(machinev1.MachineCreated)) &&
+				condition.Status == metav1.ConditionFalse {
+				messages = append(messages, condition.Message)
+			}
+		}
+	}
+
+	for _, message := range messages {
+		for _, key := range errorKeys {
+			if strings.Contains(message, key) {
+				return true, nil
 			}
 		}
 	}
@@ -689,68 +906,106 @@ func hasInsufficientCapacityCondition(conditions []metav1.Condition, platform co
 
 // WaitForMachineSetsDeleted polls until the given MachineSets are not found, and
 // there are zero Machines found matching the MachineSet's label selector.
+// The MachineSets are waited for together, within a single WaitLong deadline,
+// so that specs creating several MachineSets do not wait for each in turn.
 func WaitForMachineSetsDeleted(ctx context.Context, c runtimeclient.Client, machineSets ...*machinev1.MachineSet) {
-	for _, ms := range machineSets {
-		// Run a short check to wait for the deletion timestamp to show up.
-		// If it doesn't show there's no reason to run the longer check.
-		Eventually(func() error {
+	if len(machineSets) == 0 {
+		return
+	}
+
+	// Run a short check to wait for the deletion timestamps to show up.
+	// If they don't show there's no reason to run the longer check.
+	Eventually(ctx, func() error {
+		var errs []error
+
+		for _, ms := range machineSets {
 			machineSet := &machinev1.MachineSet{}
 			err := c.Get(ctx, runtimeclient.ObjectKey{
 				Name:      ms.GetName(),
 				Namespace: ms.GetNamespace(),
 			}, machineSet)
-			if err != nil && !apierrors.IsNotFound(err) {
-				return fmt.Errorf("could not fetch MachineSet %s: %w", ms.GetName(), err)
-			} else if apierrors.IsNotFound(err) {
-				return nil
-			}
 
-			if machineSet.DeletionTimestamp.IsZero() {
-				return fmt.Errorf("MachineSet %s still exists and does not have a deletion timestamp", ms.GetName())
+			switch {
+			case apierrors.IsNotFound(err):
+				continue
+			case err != nil:
+				errs = append(errs, fmt.Errorf("could not fetch MachineSet %s: %w", ms.GetName(), err))
+			case machineSet.DeletionTimestamp.IsZero():
+				errs = append(errs, fmt.Errorf("MachineSet %s still exists and does not have a deletion timestamp", ms.GetName()))
 			}
+		}
 
-			// Deletion timestamp is set, so we can move on to the longer check.
-			return nil
-		}, WaitShort).Should(Succeed())
+		// Deletion timestamps are set, so we can move on to the longer check.
+		return errors.Join(errs...)
+	}, WaitShort).Should(Succeed())
 
-		Eventually(func() error {
-			selector := ms.Spec.Selector
+	waitCached(ctx, c, func(c runtimeclient.Client) error {
+		var errs []error
 
-			machines, err := GetMachines(ctx, c, &selector)
-			if err != nil {
-				return fmt.Errorf("could not fetch Machines for MachineSet %s: %w", ms.GetName(), err)
+		for _, ms := range machineSets {
+			if err := machineSetDeleted(ctx, c, ms); err != nil {
+				errs = append(errs, err)
 			}
+		}
 
-			if len(machines) != 0 {
-				return fmt.Errorf("%d Machines still present for MachineSet %s", len(machines), ms.GetName())
-			}
+		return errors.Join(errs...)
+	}, WaitLong, RetryMedium)
+}
 
-			machineSetErr := c.Get(ctx, runtimeclient.ObjectKey{
-				Name:      ms.GetName(),
-				Namespace: ms.GetNamespace(),
-			}, &machinev1.MachineSet{})
-			if machineSetErr != nil && !apierrors.IsNotFound(machineSetErr) {
-				return fmt.Errorf("could not fetch MachineSet %s: %w", ms.GetName(), err)
-			}
+// machineSetDeleted returns an error until the MachineSet and its Machines are deleted.
+func machineSetDeleted(ctx context.Context, c runtimeclient.Client, ms *machinev1.MachineSet) error {
+	selector := ms.Spec.Selector
 
-			// No error means the MachineSet still exists.
-			if machineSetErr == nil {
-				return fmt.Errorf("MachineSet %s still present, but has no Machines", ms.GetName())
-			}
+	machines, err := GetMachines(ctx, c, &selector)
+	if err != nil {
+		return fmt.Errorf("could not fetch Machines for MachineSet %s: %w", ms.GetName(), err)
+	}
+
+	if len(machines) != 0 {
+		return fmt.Errorf("%d Machines still present for MachineSet %s", len(machines), ms.GetName())
+	}
 
-			return nil // MachineSet and Machines were deleted.
-		}, WaitLong, RetryMedium).ShouldNot(HaveOccurred())
+	machineSetErr := c.Get(ctx, runtimeclient.ObjectKey{
+		Name:      ms.GetName(),
+		Namespace: ms.GetNamespace(),
+	}, &machinev1.MachineSet{})
+	if machineSetErr != nil && !apierrors.IsNotFound(machineSetErr) {
+		return fmt.Errorf("could not fetch MachineSet %s: %w", ms.GetName(), machineSetErr)
 	}
+
+	// No error means the MachineSet still exists.
+	if machineSetErr == nil {
+		return fmt.Errorf("MachineSet %s still present, but has no Machines", ms.GetName())
+	}
+
+	return nil // MachineSet and Machines were deleted.
 }
 
-// DeleteMachineSets deletes the specified machinesets and returns an error on failure.
-func DeleteMachineSets(client runtimeclient.Client, machineSets ...*machinev1.MachineSet) error {
-	for _, ms := range machineSets {
-		if err := client.Delete(context.TODO(), ms); err != nil {
-			klog.Errorf("Error querying api for machine object %q: %v, retrying...", ms.Name, err)
-			return err
-		}
+// DeleteMachineSets deletes the specified machinesets in parallel and returns an error on failure.
+// All the deletions are attempted, and the errors of those which failed are joined.
+func DeleteMachineSets(ctx context.Context, client runtimeclient.Client, machineSets ...*machinev1.MachineSet) error {
+	errs := make([]error, len(machineSets))
+
+	var wg sync.WaitGroup
+
+	for i, ms := range machineSets {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := client.Delete(ctx, ms); err != nil {
+				klog.Errorf("Error querying api for machine object %q: %v, retrying...", ms.Name, err)
+				errs[i] = err
+
+				return
+			}
+
+			Tracked.Untrack(ms)
+		}()
 	}
 
-	return nil
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,66 @@
+package framework
+
+import (
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+var (
+	// ManagementKubeconfig is the kubeconfig of the management cluster, set with the --management-kubeconfig flag,
+	// for the topologies where the machines live in a different cluster than their nodes, e.g. the NodePools of
+	// HyperShift or a centralized Cluster API. The default kubeconfig is then the kubeconfig of the guest cluster.
+	ManagementKubeconfig string
+	// ManagementKubeconfigContext is the context of the management cluster in its kubeconfig, set with the
+	// --management-kubeconfig-context flag. It allows both clusters to be contexts of the default kubeconfig.
+	ManagementKubeconfigContext string
+)
+
+// HasManagementCluster returns true when the management cluster is configured apart from the guest cluster.
+func HasManagementCluster() bool {
+	return ManagementKubeconfig != "" || ManagementKubeconfigContext != ""
+}
+
+// LoadManagementClient returns a new controller-runtime client of the management cluster, with every type the suites
+// need registered, see RegisterAllSchemes. Without a management cluster configured, it is a client of the cluster itself,
+// which manages its own machines.
+func LoadManagementClient() (runtimeclient.Client, error) {
+	if err := registerDefaultSchemes(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := getManagementConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return runtimeclient.New(cfg, runtimeclient.Options{})
+}
+
+// LoadManagementClientset returns a new Kubernetes Clientset of the management cluster, see LoadManagementClient.
+func LoadManagementClientset() (*kubernetes.Clientset, error) {
+	cfg, err := getManagementConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(cfg)
+}
+
+// getManagementConfig returns the config of the management cluster, from its kubeconfig and context when set,
+// and the config of the cluster itself otherwise.
+func getManagementConfig() (*rest.Config, error) {
+	if !HasManagementCluster() {
+		return config.GetConfig()
+	}
+
+	// The default loading rules read the KUBECONFIG environment variable when the kubeconfig is not set.
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = ManagementKubeconfig
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: ManagementKubeconfigContext,
+	}).ClientConfig()
+}
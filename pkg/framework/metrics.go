@@ -0,0 +1,107 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// MonitoringNamespace is the namespace of the cluster monitoring stack.
+	MonitoringNamespace = "openshift-monitoring"
+
+	// PrometheusServiceAccount is the service account the cluster Prometheus scrapes the metrics endpoints with.
+	PrometheusServiceAccount = "prometheus-k8s"
+)
+
+// MachineAPIMetricsServices are the Services of the Machine API exposing metrics, all of their ports
+// serving metrics behind kube-rbac-proxy.
+var MachineAPIMetricsServices = []string{"machine-api-operator", "machine-api-controllers"}
+
+// MetricsEndpoint is a metrics endpoint of a Service.
+type MetricsEndpoint struct {
+	Service string
+	Port    corev1.ServicePort
+	URL     string
+}
+
+// GetMetricsEndpoints returns the metrics endpoints of every port of the given Services of the namespace.
+func GetMetricsEndpoints(ctx context.Context, clientset *kubernetes.Clientset, namespace string, services ...string) ([]MetricsEndpoint, error) {
+	var endpoints []MetricsEndpoint
+
+	for _, name := range services {
+		service, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting Service %s/%s: %w", namespace, name, err)
+		}
+
+		for _, port := range service.Spec.Ports {
+			endpoints = append(endpoints, MetricsEndpoint{
+				Service: name,
+				Port:    port,
+				URL:     fmt.Sprintf("https://%s.%s.svc:%d/metrics", name, namespace, port.Port),
+			})
+		}
+	}
+
+	return endpoints, nil
+}
+
+// CreateServiceAccountToken returns a short-lived token of the given service account.
+func CreateServiceAccountToken(ctx context.Context, clientset *kubernetes.Clientset, namespace, serviceAccount string) (string, error) {
+	tokenRequest, err := clientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, serviceAccount, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To[int64](600),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error creating token for service account %s/%s: %w", namespace, serviceAccount, err)
+	}
+
+	return tokenRequest.Status.Token, nil
+}
+
+// ScrapeMetricsEndpoint scrapes the metrics endpoint from a pod on the given node and returns the HTTP status
+// code of the response. The scrape is anonymous when the bearer token is empty.
+// The serving certificate is not verified, the endpoint is only checked for authentication and authorization.
+func ScrapeMetricsEndpoint(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node, url, token string) (int, error) {
+	args := []string{"--silent", "--insecure", "--output", "/dev/null", "--write-out", "%{http_code}"}
+	if token != "" {
+		args = append(args, "--header", "Authorization: Bearer "+token)
+	}
+
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:    "scrape-metrics",
+				Image:   "registry.access.redhat.com/ubi8/ubi-minimal:latest",
+				Command: []string{"curl"},
+				Args:    append(args, url),
+			},
+		},
+		Tolerations: []corev1.Toleration{
+			{
+				Operator: corev1.TolerationOpExists,
+			},
+		},
+	}
+
+	logs, err := RunPodOnNodeToCompletion(ctx, clientset, node, podSpec)
+	if err != nil {
+		return 0, err
+	}
+
+	statusCode, err := strconv.Atoi(strings.TrimSpace(logs))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing the status code of %s: %w", url, err)
+	}
+
+	return statusCode, nil
+}
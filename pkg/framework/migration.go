@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"context"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	machinecontroller "github.com/openshift/machine-api-operator/pkg/controller/machine"
+	corev1 "k8s.io/api/core/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetMachineSetAuthoritativeAPI sets the API authoritative for the MachineSet, which the migration
+// controllers then hand the MachineSet over to.
+func SetMachineSetAuthoritativeAPI(ctx context.Context, cl runtimeclient.Client, ms *machinev1.MachineSet, authority machinev1.MachineAuthority) error {
+	patch := runtimeclient.MergeFrom(ms.DeepCopy())
+	ms.Spec.AuthoritativeAPI = authority
+
+	return cl.Patch(ctx, ms, patch)
+}
+
+// WaitForMachineSetAuthoritativeAPI waits for the status of the MachineSet to report the given authoritative API,
+// with the Paused condition of the Machine API controllers matching it.
+func WaitForMachineSetAuthoritativeAPI(ctx context.Context, cl runtimeclient.Client, ms *machinev1.MachineSet, authority machinev1.MachineAuthority) {
+	Eventually(func() (*machinev1.MachineSet, error) {
+		current := &machinev1.MachineSet{}
+		err := cl.Get(ctx, runtimeclient.ObjectKeyFromObject(ms), current)
+
+		return current, err
+	}, WaitMedium, RetryMedium).Should(SatisfyAll(
+		HaveField("Status.AuthoritativeAPI", Equal(authority)),
+		HaveField("Status.Conditions", ContainElement(pausedConditionMatcher(authority))),
+	), "MachineSet %s should report %s as its authoritative API", ms.GetName(), authority)
+}
+
+// WaitForMachineAuthoritativeAPI waits for the status of the Machine to report the given authoritative API,
+// with the Paused condition of the Machine API controllers matching it.
+func WaitForMachineAuthoritativeAPI(ctx context.Context, cl runtimeclient.Client, machine *machinev1.Machine, authority machinev1.MachineAuthority) {
+	Eventually(func() (*machinev1.Machine, error) {
+		current := &machinev1.Machine{}
+		err := cl.Get(ctx, runtimeclient.ObjectKeyFromObject(machine), current)
+
+		return current, err
+	}, WaitMedium, RetryMedium).Should(SatisfyAll(
+		HaveField("Status.AuthoritativeAPI", Equal(authority)),
+		HaveField("Status.Conditions", ContainElement(pausedConditionMatcher(authority))),
+	), "Machine %s should report %s as its authoritative API", machine.GetName(), authority)
+}
+
+// pausedConditionMatcher matches the Paused condition the Machine API controllers set for the given authoritative API:
+// they only reconcile the resources the Machine API is authoritative for.
+func pausedConditionMatcher(authority machinev1.MachineAuthority) types.GomegaMatcher {
+	status, reason := corev1.ConditionTrue, machinecontroller.PausedConditionReason
+	if authority == machinev1.MachineAuthorityMachineAPI {
+		status, reason = corev1.ConditionFalse, machinecontroller.NotPausedConditionReason
+	}
+
+	return SatisfyAll(
+		HaveField("Type", Equal(machinecontroller.PausedCondition)),
+		HaveField("Status", Equal(status)),
+		HaveField("Reason", Equal(reason)),
+	)
+}
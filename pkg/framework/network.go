@@ -0,0 +1,207 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	errNetworkNotFound              = errors.New("network not found")
+	errAmbiguousNetwork             = errors.New("ambiguous network")
+	errNetworkDiscoveryNotSupported = errors.New("network discovery is not supported")
+)
+
+// ZoneNetwork describes the network the worker machines of a zone are attached to.
+type ZoneNetwork struct {
+	Zone string
+
+	// SubnetID and SecurityGroupIDs are only set on AWS.
+	SubnetID         string
+	SecurityGroupIDs []string
+
+	// Network and Subnetwork are only set on GCP.
+	Network    string
+	Subnetwork string
+}
+
+// ClusterNetwork describes the network of the worker machines of the cluster, per zone.
+type ClusterNetwork struct {
+	Platform configv1.PlatformType
+	Zones    map[string]ZoneNetwork
+}
+
+// ForZone returns the network of the given zone.
+func (n *ClusterNetwork) ForZone(zone string) (ZoneNetwork, error) {
+	zoneNetwork, ok := n.Zones[zone]
+	if !ok {
+		return ZoneNetwork{}, fmt.Errorf("%w: zone %q", errNetworkNotFound, zone)
+	}
+
+	return zoneNetwork, nil
+}
+
+// DiscoverNetwork returns the network of the worker MachineSets of the cluster, per zone.
+// Subnets and security groups referenced by filters are resolved to IDs by querying the cloud,
+// so that they are unambiguous on clusters with a BYO-VPC or several subnets per zone.
+func DiscoverNetwork(ctx context.Context, cl runtimeclient.Client, platform configv1.PlatformType) (*ClusterNetwork, error) {
+	machineSets, err := GetWorkerMachineSets(ctx, cl)
+	if err != nil {
+		return nil, fmt.Errorf("error getting worker MachineSets: %w", err)
+	}
+
+	network := &ClusterNetwork{
+		Platform: platform,
+		Zones:    map[string]ZoneNetwork{},
+	}
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		err = discoverAWSNetwork(ctx, network, machineSets)
+	case configv1.GCPPlatformType:
+		err = discoverGCPNetwork(network, machineSets)
+	default:
+		err = fmt.Errorf("%w on platform %s", errNetworkDiscoveryNotSupported, platform)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return network, nil
+}
+
+// discoverAWSNetwork adds the subnet and security groups of the given MachineSets to the network.
+func discoverAWSNetwork(ctx context.Context, network *ClusterNetwork, machineSets []*machinev1.MachineSet) error {
+	var awsClient *AwsClient
+
+	// The cloud is only queried when a resource is referenced by filters.
+	resolve := func(kind string, ref machinev1.AWSResourceReference, extraFilters ...*ec2.Filter) (string, error) {
+		if ref.ID != nil {
+			return *ref.ID, nil
+		}
+
+		if len(ref.Filters) == 0 {
+			return "", fmt.Errorf("%w: %s has neither ID nor filters", errNetworkNotFound, kind)
+		}
+
+		if awsClient == nil {
+			oc, err := NewCLI()
+			if err != nil {
+				return "", fmt.Errorf("error creating CLI: %w", err)
+			}
+
+			awsClient = NewAwsClient(GetCredentialsFromCluster(oc)).WithContext(ctx)
+		}
+
+		filters := extraFilters
+		for _, filter := range ref.Filters {
+			filters = append(filters, &ec2.Filter{Name: aws.String(filter.Name), Values: aws.StringSlice(filter.Values)})
+		}
+
+		describe := awsClient.DescribeSecurityGroupIDs
+		if kind == "subnet" {
+			describe = awsClient.DescribeSubnetIDs
+		}
+
+		ids, err := describe(filters)
+		if err != nil {
+			return "", err
+		}
+
+		switch len(ids) {
+		case 0:
+			return "", fmt.Errorf("%w: no %s matches filters %v", errNetworkNotFound, kind, ref.Filters)
+		case 1:
+			return ids[0], nil
+		default:
+			return "", fmt.Errorf("%w: %s filters %v match %v", errAmbiguousNetwork, kind, ref.Filters, ids)
+		}
+	}
+
+	for _, machineSet := range machineSets {
+		spec := machinev1.AWSMachineProviderConfig{}
+		if err := unmarshalMachineSetProviderSpec(machineSet, &spec); err != nil {
+			return err
+		}
+
+		zone := spec.Placement.AvailabilityZone
+		if _, ok := network.Zones[zone]; ok {
+			klog.V(2).Infof("[network] zone %q already discovered, ignoring MachineSet %s", zone, machineSet.Name)
+			continue
+		}
+
+		subnetID, err := resolve("subnet", spec.Subnet, &ec2.Filter{Name: aws.String("availability-zone"), Values: aws.StringSlice([]string{zone})})
+		if err != nil {
+			return fmt.Errorf("error resolving subnet of MachineSet %s: %w", machineSet.Name, err)
+		}
+
+		zoneNetwork := ZoneNetwork{Zone: zone, SubnetID: subnetID}
+
+		for _, securityGroup := range spec.SecurityGroups {
+			groupID, err := resolve("security group", securityGroup)
+			if err != nil {
+				return fmt.Errorf("error resolving security groups of MachineSet %s: %w", machineSet.Name, err)
+			}
+
+			zoneNetwork.SecurityGroupIDs = append(zoneNetwork.SecurityGroupIDs, groupID)
+		}
+
+		network.Zones[zone] = zoneNetwork
+	}
+
+	return nil
+}
+
+// discoverGCPNetwork adds the network and subnetwork of the given MachineSets to the network.
+func discoverGCPNetwork(network *ClusterNetwork, machineSets []*machinev1.MachineSet) error {
+	for _, machineSet := range machineSets {
+		spec := machinev1.GCPMachineProviderSpec{}
+		if err := unmarshalMachineSetProviderSpec(machineSet, &spec); err != nil {
+			return err
+		}
+
+		if len(spec.NetworkInterfaces) == 0 || spec.NetworkInterfaces[0].Subnetwork == "" {
+			return fmt.Errorf("%w: MachineSet %s has no subnetwork", errNetworkNotFound, machineSet.Name)
+		}
+
+		networkInterface := spec.NetworkInterfaces[0]
+
+		if existing, ok := network.Zones[spec.Zone]; ok {
+			if existing.Subnetwork != networkInterface.Subnetwork {
+				return fmt.Errorf("%w: zone %q uses subnetworks %q and %q", errAmbiguousNetwork, spec.Zone, existing.Subnetwork, networkInterface.Subnetwork)
+			}
+
+			continue
+		}
+
+		network.Zones[spec.Zone] = ZoneNetwork{
+			Zone:       spec.Zone,
+			Network:    networkInterface.Network,
+			Subnetwork: networkInterface.Subnetwork,
+		}
+	}
+
+	return nil
+}
+
+// unmarshalMachineSetProviderSpec unmarshals the providerSpec of the given MachineSet into the given spec.
+func unmarshalMachineSetProviderSpec(machineSet *machinev1.MachineSet, spec interface{}) error {
+	if machineSet.Spec.Template.Spec.ProviderSpec.Value == nil {
+		return fmt.Errorf("%w: MachineSet %s has no providerSpec", errNetworkNotFound, machineSet.Name)
+	}
+
+	if err := json.Unmarshal(machineSet.Spec.Template.Spec.ProviderSpec.Value.Raw, spec); err != nil {
+		return fmt.Errorf("error unmarshalling providerSpec of MachineSet %s: %w", machineSet.Name, err)
+	}
+
+	return nil
+}
@@ -3,11 +3,14 @@ package framework
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	machinev1 "github.com/openshift/api/machine/v1beta1"
+	machinecontroller "github.com/openshift/machine-api-operator/pkg/controller/machine"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +21,48 @@ import (
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// CloudProviderUninitializedTaint is the taint the kubelet registers the nodes with when the cloud provider is external,
+// until the cloud-controller-manager initializes them.
+const CloudProviderUninitializedTaint = "node.cloudprovider.kubernetes.io/uninitialized"
+
+// errNodeNotInitialized is returned when the cloud-controller-manager has not initialized a node yet.
+var errNodeNotInitialized = errors.New("node not initialized by the cloud-controller-manager")
+
+// CheckNodeCloudInitialization checks the cloud-controller-manager initialized the node of the given machine:
+// the node has no CloudProviderUninitializedTaint, and has the instance type, region and zone labels the
+// Machine API labelled the machine with. The node must have an instance type label even when the machine does not.
+func CheckNodeCloudInitialization(machine *machinev1.Machine, node *corev1.Node) error {
+	var problems []string
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == CloudProviderUninitializedTaint {
+			problems = append(problems, fmt.Sprintf("taint %s is not removed", CloudProviderUninitializedTaint))
+		}
+	}
+
+	if node.Labels[corev1.LabelInstanceTypeStable] == "" {
+		problems = append(problems, fmt.Sprintf("label %s is missing", corev1.LabelInstanceTypeStable))
+	}
+
+	for machineLabel, nodeLabel := range map[string]string{
+		machinecontroller.MachineInstanceTypeLabelName: corev1.LabelInstanceTypeStable,
+		machinecontroller.MachineRegionLabelName:       corev1.LabelTopologyRegion,
+		machinecontroller.MachineAZLabelName:           corev1.LabelTopologyZone,
+	} {
+		if expected := machine.Labels[machineLabel]; expected != "" && node.Labels[nodeLabel] != expected {
+			problems = append(problems, fmt.Sprintf("label %s is %q, expected %q as the machine label %s",
+				nodeLabel, node.Labels[nodeLabel], expected, machineLabel))
+		}
+	}
+
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("%w: node %s: %s", errNodeNotInitialized, node.Name, strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
 // AddNodeCondition adds a condition in the given Node's status.
 func AddNodeCondition(c runtimeclient.Client, node *corev1.Node, cond corev1.NodeCondition) error {
 	nodeCopy := node.DeepCopy()
@@ -54,7 +99,7 @@ func FilterSchedulableNodes(nodes []corev1.Node) []corev1.Node {
 
 // GetNodes gets a list of nodes from a running cluster
 // Optionaly, labels may be used to constrain listed nodes.
-func GetNodes(c runtimeclient.Client, selectors ...*metav1.LabelSelector) ([]corev1.Node, error) {
+func GetNodes(ctx context.Context, c runtimeclient.Client, selectors ...*metav1.LabelSelector) ([]corev1.Node, error) {
 	var listOpts []runtimeclient.ListOption
 
 	nodeList := corev1.NodeList{}
@@ -70,7 +115,7 @@ func GetNodes(c runtimeclient.Client, selectors ...*metav1.LabelSelector) ([]cor
 		)
 	}
 
-	if err := c.List(context.TODO(), &nodeList, listOpts...); err != nil {
+	if err := c.List(ctx, &nodeList, listOpts...); err != nil {
 		return nil, fmt.Errorf("error querying api for nodeList object: %w", err)
 	}
 
@@ -138,8 +183,8 @@ func GetCAPINodeForMachine(ctx context.Context, c runtimeclient.Client, m *clust
 }
 
 // GetReadyAndSchedulableNodes returns all the nodes that have the Ready condition and can schedule workloads.
-func GetReadyAndSchedulableNodes(c runtimeclient.Client) ([]corev1.Node, error) {
-	nodes, err := GetNodes(c)
+func GetReadyAndSchedulableNodes(ctx context.Context, c runtimeclient.Client) ([]corev1.Node, error) {
+	nodes, err := GetNodes(ctx, c)
 	if err != nil {
 		return nodes, err
 	}
@@ -151,9 +196,9 @@ func GetReadyAndSchedulableNodes(c runtimeclient.Client) ([]corev1.Node, error)
 }
 
 // GetWorkerNodes returns all nodes with the nodeWorkerRoleLabel label.
-func GetWorkerNodes(c runtimeclient.Client) ([]corev1.Node, error) {
+func GetWorkerNodes(ctx context.Context, c runtimeclient.Client) ([]corev1.Node, error) {
 	workerNodes := &corev1.NodeList{}
-	if err := c.List(context.TODO(), workerNodes,
+	if err := c.List(ctx, workerNodes,
 		runtimeclient.InNamespace(MachineAPINamespace),
 		runtimeclient.MatchingLabels(map[string]string{WorkerNodeRoleLabel: ""}),
 	); err != nil {
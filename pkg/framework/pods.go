@@ -3,18 +3,33 @@ package framework
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"regexp"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+var (
+	errPodFailed = errors.New("pod failed")
+
+	// lsblkPairRegexp matches a single KEY="value" pair of the lsblk --pairs output.
+	lsblkPairRegexp = regexp.MustCompile(`([A-Z]+)="([^"]*)"`)
+)
+
 // GetPods returns a list of pods matching the provided selector.
-func GetPods(client runtimeclient.Client, selector map[string]string) (*corev1.PodList, error) {
+func GetPods(ctx context.Context, client runtimeclient.Client, selector map[string]string) (*corev1.PodList, error) {
 	pods := &corev1.PodList{}
-	err := client.List(context.TODO(), pods, runtimeclient.MatchingLabels(selector))
+	err := client.List(ctx, pods, runtimeclient.MatchingLabels(selector))
 
 	return pods, err
 }
@@ -25,7 +40,7 @@ type PodLastLogFunc func(container string, lines int, previous bool) (string, er
 
 // RunPodOnNode runs a pod according passed spec on particular node.
 // returns created pod object, function for retrieve last logs, cleanup function and error if occurred.
-func RunPodOnNode(clientset *kubernetes.Clientset, node *corev1.Node, namespace string, podSpec corev1.PodSpec) (*corev1.Pod, PodLastLogFunc, PodCleanupFunc, error) {
+func RunPodOnNode(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node, namespace string, podSpec corev1.PodSpec) (*corev1.Pod, PodLastLogFunc, PodCleanupFunc, error) {
 	var err error
 
 	podSpec.NodeName = node.Name
@@ -37,13 +52,14 @@ func RunPodOnNode(clientset *kubernetes.Clientset, node *corev1.Node, namespace
 		},
 	}
 
-	pod, err = clientset.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	pod, err = clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	// The cleanup must be able to run once ctx is cancelled.
 	cleanup := func() error {
-		return clientset.CoreV1().Pods(namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
+		return clientset.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
 	}
 
 	lastLog := func(container string, lines int, previous bool) (string, error) {
@@ -55,7 +71,7 @@ func RunPodOnNode(clientset *kubernetes.Clientset, node *corev1.Node, namespace
 			TailLines: &tailLines,
 		})
 
-		podLogs, err := req.Stream(context.TODO())
+		podLogs, err := req.Stream(ctx)
 		if err != nil {
 			return "", err
 		}
@@ -76,3 +92,186 @@ func RunPodOnNode(clientset *kubernetes.Clientset, node *corev1.Node, namespace
 
 	return pod, lastLog, cleanup, err
 }
+
+// RunPodOnNodeToCompletion runs a pod according to the passed spec on the given node,
+// waits for it to succeed and returns the logs of its first container.
+// The pod is deleted once it completed.
+func RunPodOnNodeToCompletion(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node, podSpec corev1.PodSpec) (string, error) {
+	podSpec.RestartPolicy = corev1.RestartPolicyNever
+
+	pod, lastLog, cleanup, err := RunPodOnNode(ctx, clientset, node, MachineAPINamespace, podSpec)
+	if err != nil {
+		return "", fmt.Errorf("error running pod on node %s: %w", node.Name, err)
+	}
+
+	defer func() {
+		if err := cleanup(); err != nil {
+			klog.Warningf("unable to delete pod %s: %v", pod.Name, err)
+		}
+	}()
+
+	if err := wait.PollUntilContextTimeout(ctx, RetryShort, WaitMedium, true, func(ctx context.Context) (bool, error) {
+		p, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("Error querying api for Pod object %q: %v, retrying...", pod.Name, err)
+			return false, nil
+		}
+
+		switch p.Status.Phase {
+		case corev1.PodSucceeded:
+			return true, nil
+		case corev1.PodFailed:
+			return false, fmt.Errorf("%w: pod %s failed", errPodFailed, pod.Name)
+		default:
+			return false, nil
+		}
+	}); err != nil {
+		return "", fmt.Errorf("error waiting for pod %s to complete: %w", pod.Name, err)
+	}
+
+	logs, err := lastLog(podSpec.Containers[0].Name, 100, false)
+	if err != nil {
+		return "", fmt.Errorf("error getting logs of pod %s: %w", pod.Name, err)
+	}
+
+	return logs, nil
+}
+
+// StopKubeletOnNode runs a privileged pod on the given node which stops the kubelet service.
+// Once the kubelet stops reporting, the node controller marks the node as unreachable.
+// The kubelet is never restarted, the node is expected to be replaced and the pod
+// is garbage collected together with it.
+func StopKubeletOnNode(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node) error {
+	podSpec := hostCommandPodSpec("stop-kubelet", "systemctl", "stop", "kubelet")
+
+	_, _, _, err := RunPodOnNode(ctx, clientset, node, MachineAPINamespace, podSpec)
+
+	return err
+}
+
+// RunHostCommandOnNode runs the given command on the host of the given node
+// through a privileged pod and returns its output.
+func RunHostCommandOnNode(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node, command ...string) (string, error) {
+	return RunPodOnNodeToCompletion(ctx, clientset, node, hostCommandPodSpec("host-command", command...))
+}
+
+// nodeFirmwareScript prints whether the host booted with UEFI, with Secure Boot enabled, and has a TPM.
+// The Secure Boot state is the byte following the 4 attribute bytes of the SecureBoot EFI variable.
+const nodeFirmwareScript = `uefi=0; secureboot=0; tpm=0
+[ -d /sys/firmware/efi ] && uefi=1
+[ "$(od -An -t u1 -j 4 -N 1 /sys/firmware/efi/efivars/SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c 2>/dev/null | tr -d ' ')" = 1 ] && secureboot=1
+[ -e /sys/class/tpm/tpm0 ] && tpm=1
+echo "$uefi $secureboot $tpm"`
+
+// NodeFirmware is the firmware of the host of a node.
+type NodeFirmware struct {
+	UEFI       bool
+	SecureBoot bool
+	TPM        bool
+}
+
+// GetNodeFirmware runs a privileged pod on the given node reporting whether its host booted with UEFI
+// and Secure Boot, and has a TPM, e.g. the virtual TPM of a GCP Shielded VM.
+func GetNodeFirmware(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node) (NodeFirmware, error) {
+	logs, err := RunHostCommandOnNode(ctx, clientset, node, "sh", "-c", nodeFirmwareScript)
+	if err != nil {
+		return NodeFirmware{}, err
+	}
+
+	var uefi, secureBoot, tpm int
+	if _, err := fmt.Sscanf(strings.TrimSpace(logs), "%d %d %d", &uefi, &secureBoot, &tpm); err != nil {
+		return NodeFirmware{}, fmt.Errorf("error parsing the firmware of node %s: %w", node.Name, err)
+	}
+
+	return NodeFirmware{UEFI: uefi == 1, SecureBoot: secureBoot == 1, TPM: tpm == 1}, nil
+}
+
+// NodeDisk is a block device of type disk attached to a node.
+type NodeDisk struct {
+	Name      string
+	SizeBytes int64
+	Model     string
+}
+
+// GetNodeDisks runs a privileged pod on the given node listing the disks attached to it,
+// as reported by lsblk on the host. Partitions and other non disk devices are ignored.
+func GetNodeDisks(ctx context.Context, clientset *kubernetes.Clientset, node *corev1.Node) ([]NodeDisk, error) {
+	logs, err := RunHostCommandOnNode(ctx, clientset, node, "lsblk", "--nodeps", "--noheadings", "--bytes", "--pairs", "--output", "NAME,SIZE,TYPE,MODEL")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLsblkPairs(logs)
+}
+
+// parseLsblkPairs parses the output of lsblk --pairs, returning the devices of type disk.
+func parseLsblkPairs(output string) ([]NodeDisk, error) {
+	var disks []NodeDisk
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := map[string]string{}
+
+		for _, match := range lsblkPairRegexp.FindAllStringSubmatch(line, -1) {
+			fields[match[1]] = match[2]
+		}
+
+		if fields["TYPE"] != "disk" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields["SIZE"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing size of disk %s: %w", fields["NAME"], err)
+		}
+
+		disks = append(disks, NodeDisk{
+			Name:      fields["NAME"],
+			SizeBytes: size,
+			Model:     strings.TrimSpace(fields["MODEL"]),
+		})
+	}
+
+	return disks, nil
+}
+
+// hostCommandPodSpec returns the spec of a privileged pod running the given command
+// chrooted into the host filesystem.
+func hostCommandPodSpec(name string, command ...string) corev1.PodSpec {
+	return corev1.PodSpec{
+		HostPID: true,
+		Containers: []corev1.Container{
+			{
+				Name:    name,
+				Image:   "registry.access.redhat.com/ubi8/ubi-minimal:latest",
+				Command: append([]string{"chroot", "/host"}, command...),
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.To(true),
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "host",
+						MountPath: "/host",
+					},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "host",
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+				},
+			},
+		},
+		RestartPolicy: corev1.RestartPolicyNever,
+		Tolerations: []corev1.Toleration{
+			{
+				Operator: corev1.TolerationOpExists,
+			},
+		},
+	}
+}
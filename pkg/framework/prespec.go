@@ -0,0 +1,104 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PreSpecCheck verifies that the cluster is in the baseline state a disruptive spec expects:
+// all ClusterOperators are available, all nodes are ready and schedulable, and no machines
+// left behind by previous specs remain. Problems are given WaitMedium to resolve, e.g. while
+// the nodes of a previous spec finish being removed, after which the suite is aborted with
+// the list of problems, rather than letting the spec time out on an already unhealthy cluster.
+//
+// When specs run in parallel, the nodes and machines of the other running specs are expected
+// to come and go, so only the ClusterOperators are checked.
+// It must be called from within a Ginkgo node.
+func PreSpecCheck(ctx context.Context, cl runtimeclient.Client) {
+	suiteConfig, _ := GinkgoConfiguration()
+	serial := suiteConfig.ParallelTotal <= 1
+
+	var problems []string
+
+	err := wait.PollUntilContextTimeout(ctx, RetryMedium, WaitMedium, true, func(ctx context.Context) (bool, error) {
+		var err error
+
+		problems, err = baselineClusterProblems(ctx, cl, serial)
+		if err != nil {
+			klog.Warningf("[pre-spec] unable to check the cluster state: %v", err)
+			problems = []string{err.Error()}
+
+			return false, nil
+		}
+
+		return len(problems) == 0, nil
+	})
+	if err != nil {
+		AbortSuite(fmt.Sprintf("Cluster is not in its baseline state before running a disruptive spec, aborting the suite:\n%s",
+			strings.Join(problems, "\n")))
+	}
+}
+
+// baselineClusterProblems returns the deviations of the cluster from its baseline state.
+// Nodes and leftover machines are only checked when checkWorkload is true.
+func baselineClusterProblems(ctx context.Context, cl runtimeclient.Client, checkWorkload bool) ([]string, error) {
+	var problems []string
+
+	clusterOperators := &configv1.ClusterOperatorList{}
+	if err := cl.List(ctx, clusterOperators); err != nil {
+		return nil, fmt.Errorf("unable to list ClusterOperators: %w", err)
+	}
+
+	for _, co := range clusterOperators.Items {
+		if !cov1helpers.IsStatusConditionTrue(co.Status.Conditions, configv1.OperatorAvailable) {
+			problems = append(problems, fmt.Sprintf("ClusterOperator %q is not available", co.Name))
+		}
+	}
+
+	if !checkWorkload {
+		return problems, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := cl.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("unable to list nodes: %w", err)
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+
+		switch {
+		case !IsNodeReady(node):
+			problems = append(problems, fmt.Sprintf("node %q is not ready", node.Name))
+		case !IsNodeSchedulable(node):
+			problems = append(problems, fmt.Sprintf("node %q is not schedulable", node.Name))
+		}
+	}
+
+	machines := &machinev1.MachineList{}
+	if err := cl.List(ctx, machines, runtimeclient.InNamespace(MachineAPINamespace), e2eResourceLabels); err != nil {
+		return nil, fmt.Errorf("unable to list e2e machines: %w", err)
+	}
+
+	for _, machine := range machines.Items {
+		// The machines of the shared MachineSet are expected to outlive the specs using them.
+		if SharedMachineSetName != "" && machine.Labels[MachineSetKey] == SharedMachineSetName {
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf("machine %q was left behind by a previous spec", machine.Name))
+	}
+
+	return problems, nil
+}
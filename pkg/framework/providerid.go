@@ -0,0 +1,79 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+var (
+	errProviderIDMissing  = errors.New("provider ID is not set")
+	errProviderIDFormat   = errors.New("provider ID does not match the format of the platform")
+	errProviderIDMismatch = errors.New("provider IDs of the machine and its node differ")
+)
+
+// providerIDPatterns are the formats of the provider IDs set by the Machine API providers and the cloud
+// controller managers of each platform. The cloud controller managers find the instance of a node from its
+// provider ID, so a provider ID in another format breaks their integration.
+var providerIDPatterns = map[configv1.PlatformType]*regexp.Regexp{
+	// aws:///<availability zone>/<instance ID>
+	configv1.AWSPlatformType: regexp.MustCompile(`^aws:///[a-z0-9-]+/i-[0-9a-f]+$`),
+	// azure:///subscriptions/<subscription>/resourceGroups/<resource group>/providers/Microsoft.Compute/virtualMachines/<name>
+	configv1.AzurePlatformType: regexp.MustCompile(`(?i)^azure:///subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Compute/virtualMachines/[^/]+$`),
+	// gce://<project>/<zone>/<instance name>
+	configv1.GCPPlatformType: regexp.MustCompile(`^gce://[^/]+/[a-z0-9-]+/[a-z0-9-]+$`),
+	// vsphere://<virtual machine BIOS UUID>
+	configv1.VSpherePlatformType: regexp.MustCompile(`(?i)^vsphere://[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+	// openstack://<region, may be empty>/<server UUID>
+	configv1.OpenStackPlatformType: regexp.MustCompile(`^openstack://[^/]*/[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+	// nutanix://<virtual machine UUID>
+	configv1.NutanixPlatformType: regexp.MustCompile(`^nutanix://[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+	// ibmpowervs://<region>/<zone>/<service instance ID>/<instance ID>
+	configv1.PowerVSPlatformType: regexp.MustCompile(`^ibmpowervs://[^/]+/[^/]+/[^/]+/[^/]+$`),
+}
+
+// ValidateProviderID returns an error when the provider ID is not set or does not match the format of the platform.
+// The provider IDs of the platforms without a known format are only checked to be set.
+func ValidateProviderID(platform configv1.PlatformType, providerID string) error {
+	if providerID == "" {
+		return errProviderIDMissing
+	}
+
+	if pattern, ok := providerIDPatterns[platform]; ok && !pattern.MatchString(providerID) {
+		return fmt.Errorf("%w %s: %q", errProviderIDFormat, platform, providerID)
+	}
+
+	return nil
+}
+
+// ValidateMachineProviderID returns an error when the provider ID of the machine or of its node does not match
+// the format of the platform, or when they differ.
+func ValidateMachineProviderID(platform configv1.PlatformType, machine *machinev1.Machine, node *corev1.Node) error {
+	machineProviderID := ptr.Deref(machine.Spec.ProviderID, "")
+
+	if err := ValidateProviderID(platform, machineProviderID); err != nil {
+		return fmt.Errorf("machine %s: %w", machine.Name, err)
+	}
+
+	if err := ValidateProviderID(platform, node.Spec.ProviderID); err != nil {
+		return fmt.Errorf("node %s: %w", node.Name, err)
+	}
+
+	equal := machineProviderID == node.Spec.ProviderID
+	if platform == configv1.AzurePlatformType {
+		// Azure resource IDs are case-insensitive, the cloud controller manager may not preserve their case.
+		equal = strings.EqualFold(machineProviderID, node.Spec.ProviderID)
+	}
+
+	if !equal {
+		return fmt.Errorf("%w: machine %s has %q, node %s has %q", errProviderIDMismatch, machine.Name, machineProviderID, node.Name, node.Spec.ProviderID)
+	}
+
+	return nil
+}
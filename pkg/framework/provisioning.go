@@ -0,0 +1,228 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+// provisioningClockSkewThreshold is the clock skew above which the nodes are listed in the provisioning report.
+const provisioningClockSkewThreshold = 30 * time.Second
+
+// provisioningBuckets are the upper bounds of the buckets of the provisioning histograms, the last bucket
+// holding the durations above them.
+var provisioningBuckets = []time.Duration{
+	time.Minute, 2 * time.Minute, 3 * time.Minute, 5 * time.Minute, 7 * time.Minute,
+	10 * time.Minute, 15 * time.Minute, 20 * time.Minute, 30 * time.Minute,
+}
+
+// MachineProvisioning records when a Machine created by the suite went through provisioning.
+// Created is set by the API server, the other times are observed by the suite.
+type MachineProvisioning struct {
+	Machine string
+	Node    string
+
+	Created         time.Time
+	InstanceRunning time.Time
+	NodeReady       time.Time
+
+	// NodeClockSkew is the Ready condition transition time reported by the kubelet minus NodeReady,
+	// which includes the watch latency.
+	NodeClockSkew time.Duration
+}
+
+// nodeReadiness is when a node was observed becoming ready, and when its kubelet reported it.
+type nodeReadiness struct {
+	observed time.Time
+	reported time.Time
+}
+
+// ProvisioningRecorder records the creation, instance running and node Ready times of the Machines created
+// by the suite, i.e. labelled with ReasonKey, to report the distribution of their provisioning durations.
+type ProvisioningRecorder struct {
+	lock     sync.Mutex
+	started  time.Time
+	machines map[string]*MachineProvisioning
+	nodes    map[string]nodeReadiness
+}
+
+// NewProvisioningRecorder returns a new, empty ProvisioningRecorder.
+func NewProvisioningRecorder() *ProvisioningRecorder {
+	return &ProvisioningRecorder{
+		machines: map[string]*MachineProvisioning{},
+		nodes:    map[string]nodeReadiness{},
+	}
+}
+
+// Start feeds the recorder from the Machine and Node informers of the suite cache, which must be running.
+// Only the Machines created after it started are recorded, until the suite cache is stopped.
+func (r *ProvisioningRecorder) Start(ctx context.Context) error {
+	r.lock.Lock()
+	r.started = time.Now()
+	r.lock.Unlock()
+
+	return addSuiteCacheHandlers(ctx,
+		toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { r.onMachine(obj) },
+			UpdateFunc: func(_, newObj interface{}) { r.onMachine(newObj) },
+		},
+		toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { r.onNode(obj) },
+			UpdateFunc: func(_, newObj interface{}) { r.onNode(newObj) },
+		},
+	)
+}
+
+// Provisionings returns the Machines whose node has been observed ready, ordered by creation.
+func (r *ProvisioningRecorder) Provisionings() []MachineProvisioning {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var provisionings []MachineProvisioning
+
+	for _, machine := range r.machines {
+		readiness, found := r.nodes[machine.Node]
+		if machine.Node == "" || !found || machine.InstanceRunning.IsZero() {
+			continue
+		}
+
+		provisioning := *machine
+		provisioning.NodeReady = readiness.observed
+		provisioning.NodeClockSkew = readiness.reported.Sub(readiness.observed)
+		provisionings = append(provisionings, provisioning)
+	}
+
+	slices.SortFunc(provisionings, func(a, b MachineProvisioning) int {
+		return a.Created.Compare(b.Created)
+	})
+
+	return provisionings
+}
+
+// Format returns the histograms of the provisioning durations of the recorded Machines, compared to WaitLong,
+// followed by the nodes whose clock is skewed.
+func (r *ProvisioningRecorder) Format(platform configv1.PlatformType) string {
+	provisionings := r.Provisionings()
+	if len(provisionings) == 0 {
+		return fmt.Sprintf("no Machine provisioned on %s", platform)
+	}
+
+	var creationToRunning, runningToReady, creationToReady []time.Duration
+
+	var skewed []string
+
+	for _, p := range provisionings {
+		creationToRunning = append(creationToRunning, p.InstanceRunning.Sub(p.Created))
+		runningToReady = append(runningToReady, p.NodeReady.Sub(p.InstanceRunning))
+		creationToReady = append(creationToReady, p.NodeReady.Sub(p.Created))
+
+		if p.NodeClockSkew.Abs() > provisioningClockSkewThreshold {
+			skewed = append(skewed, fmt.Sprintf("  %s (machine %s): %s", p.Node, p.Machine, p.NodeClockSkew))
+		}
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d Machines provisioned on %s, WaitLong is %s\n", len(provisionings), platform, WaitLong)
+	formatDurationHistogram(&b, "creation to instance running", creationToRunning)
+	formatDurationHistogram(&b, "instance running to node ready", runningToReady)
+	formatDurationHistogram(&b, "creation to node ready", creationToReady)
+
+	if len(skewed) > 0 {
+		fmt.Fprintf(&b, "nodes whose clock is skewed by more than %s:\n%s\n", provisioningClockSkewThreshold, strings.Join(skewed, "\n"))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatDurationHistogram writes the percentiles and the histogram of the given durations.
+func formatDurationHistogram(b *strings.Builder, title string, durations []time.Duration) {
+	slices.Sort(durations)
+
+	percentile := func(p int) time.Duration {
+		return durations[(len(durations)-1)*p/100].Round(time.Second)
+	}
+
+	fmt.Fprintf(b, "%s: p50 %s, p90 %s, max %s\n", title, percentile(50), percentile(90), percentile(100))
+
+	counts := make([]int, len(provisioningBuckets)+1)
+
+	for _, d := range durations {
+		bucket, _ := slices.BinarySearch(provisioningBuckets, d)
+		counts[bucket]++
+	}
+
+	for i, count := range counts {
+		bound := "> " + provisioningBuckets[len(provisioningBuckets)-1].String()
+		if i < len(provisioningBuckets) {
+			bound = "<= " + provisioningBuckets[i].String()
+		}
+
+		fmt.Fprintf(b, "  %-10s %4d %s\n", bound, count, strings.Repeat("#", count))
+	}
+}
+
+func (r *ProvisioningRecorder) onMachine(obj interface{}) {
+	machine, ok := obj.(*machinev1.Machine)
+	if !ok || machine.Labels[ReasonKey] != ReasonE2E {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if machine.CreationTimestamp.Time.Before(r.started) {
+		return
+	}
+
+	provisioning, found := r.machines[machine.Name]
+	if !found {
+		provisioning = &MachineProvisioning{
+			Machine: machine.Name,
+			Created: machine.CreationTimestamp.Time,
+		}
+		r.machines[machine.Name] = provisioning
+	}
+
+	// The instance is running once the Machine is Provisioned, which may be missed when the node joins quickly.
+	phase := ptr.Deref(machine.Status.Phase, "")
+	if provisioning.InstanceRunning.IsZero() && (phase == MachinePhaseProvisioned || phase == MachinePhaseRunning) {
+		provisioning.InstanceRunning = time.Now()
+	}
+
+	if machine.Status.NodeRef != nil {
+		provisioning.Node = machine.Status.NodeRef.Name
+	}
+}
+
+func (r *ProvisioningRecorder) onNode(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.nodes[node.Name]; found {
+		return
+	}
+
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
+			r.nodes[node.Name] = nodeReadiness{
+				observed: time.Now(),
+				reported: c.LastTransitionTime.Time,
+			}
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -32,6 +33,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apitypes "k8s.io/apimachinery/pkg/types"
@@ -55,8 +57,27 @@ const (
 	mitmCustomPKINamespace                = "openshift-config"
 	mitmDaemonsetName                     = proxyName
 	mitmServiceName                       = proxyName
+	trustedCABundleKey                    = "ca-bundle.crt"
+	injectTrustedCABundleLabel            = "config.openshift.io/inject-trusted-cabundle"
+
+	// UserCABundleConfigMapName is the ConfigMap in openshift-config holding the additional trust bundle
+	// of the cluster, created by the installer when an additionalTrustBundle is configured.
+	UserCABundleConfigMapName = "user-ca-bundle"
 )
 
+// ProxyOptions configures how the cluster is made to trust the CA of the MITM proxy.
+type ProxyOptions struct {
+	// TrustedCAConfigMapName is the name of the ConfigMap in openshift-config which holds the MITM proxy CA
+	// and is referenced by the trustedCA of the cluster-wide Proxy. When the ConfigMap already exists,
+	// e.g. the user-ca-bundle of a cluster installed with an additionalTrustBundle, the CA is appended
+	// to its bundle and removed from it again when the proxy is deleted.
+	TrustedCAConfigMapName string
+}
+
+// DefaultProxyOptions are the options used by DeployProxy, ConfigureClusterWideProxy and DeleteProxy:
+// the MITM proxy CA is held by a dedicated ConfigMap.
+var DefaultProxyOptions = ProxyOptions{TrustedCAConfigMapName: mitmCustomPKIName}
+
 const proxySetup = `
 cd /.mitmproxy
 cat /root/certs/tls.key /root/certs/tls.crt > /.mitmproxy/mitmproxy-ca.pem
@@ -67,6 +88,11 @@ HOME=/.mitmproxy ./mitmdump --ssl-insecure
 
 // DeployProxy deploys a MITM Proxy to the cluster.
 func DeployProxy(c client.Client, gomegaArgs ...interface{}) {
+	DeployProxyWithOptions(c, DefaultProxyOptions)
+}
+
+// DeployProxyWithOptions deploys a MITM Proxy to the cluster, adding its CA to the trusted CA ConfigMap of the options.
+func DeployProxyWithOptions(c client.Client, opts ProxyOptions) {
 	ctx := context.Background()
 	kom := komega.New(c)
 
@@ -88,9 +114,6 @@ func DeployProxy(c client.Client, gomegaArgs ...interface{}) {
 	mitmBootstrapConfigMap := corev1resourcebuilder.ConfigMap().WithName(mitmBootstrapName).WithNamespace(proxyNamespace).WithLabels(proxyLabels).
 		WithData(map[string]string{"startup.sh": proxySetup}).Build()
 
-	mitmCustomPkiConfigMap := corev1resourcebuilder.ConfigMap().WithName(mitmCustomPKIName).WithNamespace(mitmCustomPKINamespace).
-		WithData(map[string]string{"ca-bundle.crt": string(mitmSignerCert)}).Build()
-
 	mitmDaemonset := appsv1resourcebuilder.DaemonSet().WithName(proxyName).WithNamespace(proxyNamespace).WithLabels(proxyLabels).
 		WithVolumes(buildDaemonSetVolumes()).WithContainers(buildDaemonSetContainers()).Build()
 
@@ -102,7 +125,11 @@ func DeployProxy(c client.Client, gomegaArgs ...interface{}) {
 
 	By("Creating the MITM proxy ConfigMaps")
 	Eventually(c.Create(ctx, mitmBootstrapConfigMap)).Should(Succeed(), "timed out creating the MITM proxy Bootstrap ConfigMap.")
-	Eventually(c.Create(ctx, mitmCustomPkiConfigMap)).Should(Succeed(), "timed out creating the MITM proxy Custom PKI ConfigMap.")
+
+	By(fmt.Sprintf("Adding the MITM proxy CA to the %s/%s ConfigMap", mitmCustomPKINamespace, opts.TrustedCAConfigMapName))
+	Eventually(func() error {
+		return addProxyCAToTrustedBundle(ctx, c, opts.TrustedCAConfigMapName, string(mitmSignerCert), proxyLabels)
+	}).Should(Succeed(), "timed out adding the MITM proxy CA to the trusted CA ConfigMap.")
 
 	By("Creating the MITM proxy DaemonSet")
 	Eventually(c.Create(ctx, mitmDaemonset)).Should(Succeed(), "timed out creating the MITM proxy DaemonSet.")
@@ -125,6 +152,12 @@ func DeployProxy(c client.Client, gomegaArgs ...interface{}) {
 
 // ConfigureClusterWideProxy configures the Cluster-Wide Proxy to use the MITM Proxy.
 func ConfigureClusterWideProxy(c client.Client, gomegaArgs ...interface{}) {
+	ConfigureClusterWideProxyWithOptions(c, DefaultProxyOptions, gomegaArgs...)
+}
+
+// ConfigureClusterWideProxyWithOptions configures the Cluster-Wide Proxy to use the MITM Proxy,
+// trusting the CA ConfigMap of the options.
+func ConfigureClusterWideProxyWithOptions(c client.Client, opts ProxyOptions, gomegaArgs ...interface{}) {
 	ctx := context.Background()
 	kom := komega.New(c)
 
@@ -139,7 +172,7 @@ func ConfigureClusterWideProxy(c client.Client, gomegaArgs ...interface{}) {
 		proxy.Spec.HTTPSProxy = "http://" + services.Items[0].Spec.ClusterIP + ":8080"
 		proxy.Spec.NoProxy = ".org,.com,.net,quay.io,registry.redhat.io"
 		proxy.Spec.TrustedCA = configv1.ConfigMapNameReference{
-			Name: mitmCustomPKIName,
+			Name: opts.TrustedCAConfigMapName,
 		}
 	}), gomegaArgs...).Should(Succeed(), "cluster wide proxy set be able to be updated")
 
@@ -218,21 +251,29 @@ func UnconfigureClusterWideProxy(c client.Client, gomegaArgs ...interface{}) {
 
 // DeleteProxy delete the MITM Proxy from the cluster.
 func DeleteProxy(c client.Client, gomegaArgs ...interface{}) {
+	DeleteProxyWithOptions(c, DefaultProxyOptions)
+}
+
+// DeleteProxyWithOptions deletes the MITM Proxy from the cluster and removes its CA from the trusted CA ConfigMap of the options.
+func DeleteProxyWithOptions(c client.Client, opts ProxyOptions) {
 	ctx := context.Background()
 	kom := komega.New(c)
 
 	mitmSignerSecret := corev1resourcebuilder.Secret().WithName(mitmSignerName).WithNamespace(proxyNamespace).Build()
 	mitmBootstrapConfigMap := corev1resourcebuilder.ConfigMap().WithName(mitmBootstrapName).WithNamespace(proxyNamespace).Build()
-	mitmCustomPkiConfigMap := corev1resourcebuilder.ConfigMap().WithName(mitmCustomPKIName).WithNamespace(mitmCustomPKINamespace).Build()
 	mitmDaemonset := appsv1resourcebuilder.DaemonSet().WithName(mitmDaemonsetName).WithNamespace(proxyNamespace).Build()
 	mitmService := corev1resourcebuilder.Service().WithName(mitmServiceName).WithNamespace(proxyNamespace).Build()
 
+	By(fmt.Sprintf("Removing the MITM proxy CA from the %s/%s ConfigMap", mitmCustomPKINamespace, opts.TrustedCAConfigMapName))
+	Eventually(func() error {
+		return removeProxyCAFromTrustedBundle(ctx, c, opts.TrustedCAConfigMapName)
+	}).Should(Succeed(), "timed out removing the MITM proxy CA from the trusted CA ConfigMap.")
+
 	By("Deleting the MITM proxy Secret")
 	Eventually(c.Delete(ctx, mitmSignerSecret)).Should(Succeed(), "timed out deleting the MITM proxy Secret.")
 
-	By("Deleting the MITM proxy ConfigMaps")
+	By("Deleting the MITM proxy Bootstrap ConfigMap")
 	Eventually(c.Delete(ctx, mitmBootstrapConfigMap)).Should(Succeed(), "timed out deleting the MITM proxy Bootstrap ConfigMap.")
-	Eventually(c.Delete(ctx, mitmCustomPkiConfigMap)).Should(Succeed(), "timed out deleting the MITM proxy Custom PKI ConfigMap.")
 
 	By("Deleting the MITM proxy DaemonSet")
 	Eventually(c.Delete(ctx, mitmDaemonset)).Should(Succeed(), "timed out deleting the MITM proxy DaemonSet.")
@@ -248,9 +289,6 @@ func DeleteProxy(c client.Client, gomegaArgs ...interface{}) {
 	Eventually(kom.Get(mitmBootstrapConfigMap)).
 		Should(MatchError(ContainSubstring("not found")), "expected MITM proxy Bootstrap ConfigMap to be removed from the cluster")
 
-	Eventually(kom.Get(mitmCustomPkiConfigMap)).
-		Should(MatchError(ContainSubstring("not found")), "expected MITM proxy PKI ConfigMap to be removed from the cluster")
-
 	Eventually(kom.Get(mitmDaemonset)).
 		Should(MatchError(ContainSubstring("not found")), "expected MITM proxy DaemonSet to be removed from the cluster")
 
@@ -258,6 +296,99 @@ func DeleteProxy(c client.Client, gomegaArgs ...interface{}) {
 		Should(MatchError(ContainSubstring("not found")), "expected MITM proxy Service to be removed from the cluster")
 }
 
+// WaitForProxyCATrustedByMachineAPI waits until the MITM proxy CA is part of the trusted CA bundle
+// injected into the ConfigMaps of the machine API namespace, which the machine API controllers mount.
+func WaitForProxyCATrustedByMachineAPI(c client.Client) {
+	ctx := context.Background()
+
+	mitmSignerSecret := &corev1.Secret{}
+	Expect(c.Get(ctx, client.ObjectKey{Namespace: proxyNamespace, Name: mitmSignerName}, mitmSignerSecret)).To(Succeed(),
+		"failed to get the MITM proxy Secret.")
+
+	proxyCA := strings.TrimSpace(string(mitmSignerSecret.Data["tls.crt"]))
+
+	By("Waiting for the MITM proxy CA to be injected into the machine API trusted CA bundle")
+	Eventually(func() ([]string, error) {
+		configMaps := &corev1.ConfigMapList{}
+		if err := c.List(ctx, configMaps, client.InNamespace(MachineAPINamespace), client.MatchingLabels{injectTrustedCABundleLabel: "true"}); err != nil {
+			return nil, err
+		}
+
+		var bundles []string
+		for _, configMap := range configMaps.Items {
+			bundles = append(bundles, configMap.Data[trustedCABundleKey])
+		}
+
+		return bundles, nil
+	}, WaitMedium, RetryMedium).Should(ContainElement(ContainSubstring(proxyCA)),
+		"expected the MITM proxy CA to be injected into a trusted CA bundle ConfigMap of the machine API namespace.")
+}
+
+// addProxyCAToTrustedBundle adds the proxy CA to the bundle of the named ConfigMap in openshift-config,
+// creating the ConfigMap with the given labels when it does not exist.
+func addProxyCAToTrustedBundle(ctx context.Context, c client.Client, name, proxyCA string, labels map[string]string) error {
+	configMap := &corev1.ConfigMap{}
+
+	err := c.Get(ctx, client.ObjectKey{Namespace: mitmCustomPKINamespace, Name: name}, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = corev1resourcebuilder.ConfigMap().WithName(name).WithNamespace(mitmCustomPKINamespace).WithLabels(labels).
+			WithData(map[string]string{trustedCABundleKey: proxyCA}).Build()
+
+		return c.Create(ctx, configMap)
+	} else if err != nil {
+		return err
+	}
+
+	bundle := configMap.Data[trustedCABundleKey]
+	if strings.Contains(bundle, proxyCA) {
+		return nil
+	}
+
+	if bundle != "" && !strings.HasSuffix(bundle, "\n") {
+		bundle += "\n"
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+
+	configMap.Data[trustedCABundleKey] = bundle + proxyCA
+
+	return c.Update(ctx, configMap)
+}
+
+// removeProxyCAFromTrustedBundle removes the proxy CA from the named ConfigMap in openshift-config.
+// The ConfigMap is deleted when it was created for the proxy, otherwise the rest of its bundle is preserved.
+func removeProxyCAFromTrustedBundle(ctx context.Context, c client.Client, name string) error {
+	configMap := &corev1.ConfigMap{}
+
+	err := c.Get(ctx, client.ObjectKey{Namespace: mitmCustomPKINamespace, Name: name}, configMap)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if configMap.Labels["app"] == proxyName {
+		return client.IgnoreNotFound(c.Delete(ctx, configMap))
+	}
+
+	// The CA is only known from the MITM proxy Secret, without it there is nothing left to remove.
+	mitmSignerSecret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: proxyNamespace, Name: mitmSignerName}, mitmSignerSecret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	proxyCA := string(mitmSignerSecret.Data["tls.crt"])
+	if proxyCA == "" || !strings.Contains(configMap.Data[trustedCABundleKey], proxyCA) {
+		return nil
+	}
+
+	configMap.Data[trustedCABundleKey] = strings.Replace(configMap.Data[trustedCABundleKey], proxyCA, "", 1)
+
+	return c.Update(ctx, configMap)
+}
+
 func buildDaemonSetVolumes() []corev1.Volume {
 	mitmBootstrapPerms := int32(511)
 
@@ -0,0 +1,90 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+const (
+	awsEC2ServiceCode = "ec2"
+
+	// awsStandardOnDemandQuotaCode is the "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances" quota, in vCPUs.
+	awsStandardOnDemandQuotaCode = "L-1216C47A"
+)
+
+// awsStandardInstanceFamilies are the instance families counted against the standard On-Demand quota.
+// The families sharing a first letter with them but counted against their own quotas, e.g. inf, trn, mac,
+// hpc and dl, or those of the accelerated instances such as f, g, p, vt and x, are not part of it.
+var awsStandardInstanceFamilies = map[string]bool{"a": true, "c": true, "d": true, "h": true, "i": true, "m": true, "r": true, "t": true, "z": true}
+
+// awsChecker checks requests against the AWS vCPU based On-Demand instance quotas.
+type awsChecker struct {
+	client *framework.AwsClient
+}
+
+func newAWSChecker() (*awsChecker, error) {
+	oc, err := framework.NewCLI()
+	if err != nil {
+		return nil, fmt.Errorf("error creating CLI: %w", err)
+	}
+
+	accessKeyID, secureKey, region, sessionToken, err := framework.GetAWSCredentials(oc)
+	if err != nil {
+		return nil, fmt.Errorf("error getting AWS credentials: %w", err)
+	}
+
+	return &awsChecker{
+		client: framework.NewAwsClient(accessKeyID, secureKey, region, sessionToken),
+	}, nil
+}
+
+// Check implements Checker.
+func (c *awsChecker) Check(ctx context.Context, req Request) error {
+	if !isAWSStandardInstanceType(req.InstanceType) {
+		// Other instance families are counted against dedicated quotas, e.g. G and VT for GPUs.
+		return nil
+	}
+
+	client := c.client.WithContext(ctx)
+
+	limit, err := client.GetServiceQuota(awsEC2ServiceCode, awsStandardOnDemandQuotaCode)
+	if err != nil {
+		return err
+	}
+
+	used, err := client.GetRunningVCPUs(isAWSStandardInstanceType)
+	if err != nil {
+		return err
+	}
+
+	perInstance, err := client.GetInstanceTypeVCPUs(req.InstanceType)
+	if err != nil {
+		return err
+	}
+
+	requested := perInstance * int64(req.Replicas)
+	if float64(used+requested) > limit {
+		return fmt.Errorf("%w: %s requires %d vCPUs, but only %.0f of the %.0f standard On-Demand vCPUs are available",
+			ErrInsufficientQuota, req, requested, limit-float64(used), limit)
+	}
+
+	return nil
+}
+
+// isAWSStandardInstanceType returns true when the instance type belongs to a standard instance family.
+func isAWSStandardInstanceType(instanceType string) bool {
+	return awsStandardInstanceFamilies[awsInstanceFamily(instanceType)]
+}
+
+// awsInstanceFamily returns the family of the instance type, the letters preceding its generation,
+// e.g. "m" for "m6i.xlarge" and "inf" for "inf2.xlarge".
+func awsInstanceFamily(instanceType string) string {
+	if i := strings.IndexAny(instanceType, "0123456789"); i >= 0 {
+		return instanceType[:i]
+	}
+
+	return instanceType
+}
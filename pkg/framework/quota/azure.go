@@ -0,0 +1,60 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+// azureRegionalVCPUsUsage is the "Total Regional vCPUs" usage, counting the vCPUs of every VM size family.
+const azureRegionalVCPUsUsage = "cores"
+
+// azureChecker checks requests against the Azure regional and VM size family vCPU quotas.
+type azureChecker struct {
+	client *framework.AzureClient
+}
+
+func newAzureChecker(ctx context.Context, cl runtimeclient.Client) (*azureChecker, error) {
+	client, err := framework.NewAzureClient(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &azureChecker{client: client}, nil
+}
+
+// Check implements Checker.
+func (c *azureChecker) Check(ctx context.Context, req Request) error {
+	client := c.client.WithContext(ctx)
+
+	family, perInstance, err := client.GetVirtualMachineSizeVCPUs(req.Region, req.InstanceType)
+	if err != nil {
+		return err
+	}
+
+	usages, err := client.GetComputeUsages(req.Region)
+	if err != nil {
+		return err
+	}
+
+	requested := perInstance * int64(req.Replicas)
+
+	for _, name := range []string{family, azureRegionalVCPUsUsage} {
+		usage, ok := usages[name]
+		if !ok {
+			continue
+		}
+
+		used, limit := int64(ptr.Deref(usage.CurrentValue, 0)), ptr.Deref(usage.Limit, 0)
+		if used+requested > limit {
+			return fmt.Errorf("%w: %s requires %d vCPUs, but only %d of the %d %s vCPUs are available",
+				ErrInsufficientQuota, req, requested, limit-used, limit, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package quota implements pre-flight cloud quota checks, so that specs requiring
+// more capacity than available are skipped with a clear reason instead of timing
+// out while waiting for machines which can never be provisioned.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+var (
+	// ErrInsufficientQuota is returned when the cloud provider quota cannot accommodate a Request.
+	ErrInsufficientQuota = errors.New("insufficient quota")
+
+	// errUnsupportedPlatform is returned when quotas cannot be checked on the platform.
+	errUnsupportedPlatform = errors.New("quota checks are not supported on this platform")
+)
+
+// Request describes the capacity a spec is about to request from the cloud provider.
+type Request struct {
+	InstanceType string
+	Zone         string
+	// Region is only set on Azure, where the zone does not identify it.
+	Region   string
+	Replicas int
+}
+
+// String returns a human readable representation of the request.
+func (r Request) String() string {
+	return fmt.Sprintf("%d x %s in %q", r.Replicas, r.InstanceType, r.Zone)
+}
+
+// Checker checks whether the cloud provider quota can accommodate a Request.
+type Checker interface {
+	// Check returns an error wrapping ErrInsufficientQuota when the quota cannot accommodate the request.
+	Check(ctx context.Context, req Request) error
+}
+
+// NewChecker returns the quota Checker for the platform of the cluster.
+// On platforms where quotas cannot be checked, the returned Checker accepts every request.
+func NewChecker(ctx context.Context, cl runtimeclient.Client) (Checker, error) {
+	platform, err := framework.GetPlatform(ctx, cl)
+	if err != nil {
+		return nil, fmt.Errorf("error getting platform: %w", err)
+	}
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		return newAWSChecker()
+	case configv1.AzurePlatformType:
+		return newAzureChecker(ctx, cl)
+	default:
+		// GCP quotas require the GCP compute API, which the suite has no client for.
+		return unsupportedChecker{platform: platform}, nil
+	}
+}
+
+// RequestForMachineSetParams returns the Request matching the given MachineSet parameters scaled to the given replicas.
+func RequestForMachineSetParams(platform configv1.PlatformType, params framework.MachineSetParams, replicas int) (Request, error) {
+	req := Request{Replicas: replicas}
+
+	if params.ProviderSpec == nil || params.ProviderSpec.Value == nil {
+		return req, fmt.Errorf("%w: missing providerSpec", errUnsupportedPlatform)
+	}
+
+	raw := params.ProviderSpec.Value.Raw
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		spec := machinev1.AWSMachineProviderConfig{}
+		if err := json.Unmarshal(raw, &spec); err != nil {
+			return req, fmt.Errorf("error unmarshalling providerSpec: %w", err)
+		}
+
+		req.InstanceType, req.Zone = spec.InstanceType, spec.Placement.AvailabilityZone
+	case configv1.GCPPlatformType:
+		spec := machinev1.GCPMachineProviderSpec{}
+		if err := json.Unmarshal(raw, &spec); err != nil {
+			return req, fmt.Errorf("error unmarshalling providerSpec: %w", err)
+		}
+
+		req.InstanceType, req.Zone = spec.MachineType, spec.Zone
+	case configv1.AzurePlatformType:
+		spec := machinev1.AzureMachineProviderSpec{}
+		if err := json.Unmarshal(raw, &spec); err != nil {
+			return req, fmt.Errorf("error unmarshalling providerSpec: %w", err)
+		}
+
+		req.InstanceType, req.Zone, req.Region = spec.VMSize, spec.Zone, spec.Location
+	default:
+		return req, fmt.Errorf("%w: %s", errUnsupportedPlatform, platform)
+	}
+
+	return req, nil
+}
+
+// SkipIfInsufficientQuota skips the current spec when the cloud provider quota cannot
+// accommodate the given MachineSet parameters scaled to the given replicas.
+// Failures to check the quota are logged and do not prevent the spec from running.
+func SkipIfInsufficientQuota(ctx context.Context, cl runtimeclient.Client, params framework.MachineSetParams, replicas int) {
+	if _, err := SelectMachineSetParams(ctx, cl, []framework.MachineSetParams{params}, replicas); errors.Is(err, ErrInsufficientQuota) {
+		Skip(fmt.Sprintf("Skipping: %v", err))
+	}
+}
+
+// SelectMachineSetParams returns the first of the given MachineSet parameters for which the cloud
+// provider quota can accommodate the given replicas. It returns an error wrapping ErrInsufficientQuota
+// when none of them can be accommodated.
+// When the quota cannot be checked, the first parameters are returned.
+func SelectMachineSetParams(ctx context.Context, cl runtimeclient.Client, paramsList []framework.MachineSetParams, replicas int) (framework.MachineSetParams, error) {
+	if len(paramsList) == 0 {
+		return framework.MachineSetParams{}, fmt.Errorf("%w: no MachineSet parameters to choose from", ErrInsufficientQuota)
+	}
+
+	platform, err := framework.GetPlatform(ctx, cl)
+	if err != nil {
+		klog.Warningf("[quota] unable to get platform, skipping quota checks: %v", err)
+		return paramsList[0], nil
+	}
+
+	checker, err := NewChecker(ctx, cl)
+	if err != nil {
+		klog.Warningf("[quota] unable to create quota checker, skipping quota checks: %v", err)
+		return paramsList[0], nil
+	}
+
+	var errs []error
+
+	for _, params := range paramsList {
+		req, err := RequestForMachineSetParams(platform, params, replicas)
+		if err != nil {
+			klog.Warningf("[quota] unable to build quota request, skipping quota checks: %v", err)
+			return params, nil
+		}
+
+		switch err := checker.Check(ctx, req); {
+		case err == nil:
+			return params, nil
+		case errors.Is(err, ErrInsufficientQuota):
+			klog.Infof("[quota] %v", err)
+			errs = append(errs, err)
+		default:
+			klog.Warningf("[quota] unable to check quota for %s, skipping quota checks: %v", req, err)
+			return params, nil
+		}
+	}
+
+	return framework.MachineSetParams{}, errors.Join(errs...)
+}
+
+// unsupportedChecker accepts every request.
+type unsupportedChecker struct {
+	platform configv1.PlatformType
+}
+
+// Check implements Checker.
+func (c unsupportedChecker) Check(_ context.Context, req Request) error {
+	klog.V(2).Infof("[quota] quota checks are not supported on platform %s, assuming %s fits", c.platform, req)
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// randomAlphabet are the characters used in random strings, valid in Kubernetes object names.
+const randomAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+
+// Random is a goroutine safe randomness source.
+type Random struct {
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+// Rand is the randomness source of the framework. It is seeded from the Ginkgo random seed
+// and the text of the current spec, so that rerunning a spec with the same -seed reproduces
+// the same object names and choices.
+var Rand = &Random{}
+
+// Names generates object names from Rand.
+var Names = NameGenerator{rand: Rand}
+
+// Reseed seeds the source from the Ginkgo random seed and the given text, usually the full text of the current spec.
+func (r *Random) Reseed(text string) {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(text))
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rand = rand.New(rand.NewSource(GinkgoRandomSeed() ^ int64(hash.Sum64())))
+}
+
+// Intn returns a random number in [0,n).
+func (r *Random) Intn(n int) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.source().Intn(n)
+}
+
+// String returns a random string of the given length, valid in Kubernetes object names.
+func (r *Random) String(n int) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randomAlphabet[r.source().Intn(len(randomAlphabet))]
+	}
+
+	return string(b)
+}
+
+// source returns the underlying source, seeded from the Ginkgo random seed alone if Reseed was never called.
+// The lock must be held by the caller.
+func (r *Random) source() *rand.Rand {
+	if r.rand == nil {
+		r.rand = rand.New(rand.NewSource(GinkgoRandomSeed()))
+	}
+
+	return r.rand
+}
+
+// NameGenerator generates object names from a base and a random suffix.
+type NameGenerator struct {
+	rand *Random
+}
+
+// nameSuffixLength is the length of the random suffix of generated names.
+const nameSuffixLength = 5
+
+// GenerateName returns the base followed by a random suffix.
+func (g NameGenerator) GenerateName(base string) string {
+	return base + g.rand.String(nameSuffixLength)
+}
@@ -0,0 +1,192 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultRollMaxSurge is the number of machines RollMachineSet replaces at a time.
+	DefaultRollMaxSurge = 1
+
+	// MachineDeleteAnnotation marks the machines a MachineSet deletes first when scaled down.
+	MachineDeleteAnnotation = "machine.openshift.io/cluster-api-delete-machine"
+)
+
+var (
+	errInvalidMaxSurge       = errors.New("maxSurge must be at least 1")
+	errMachineNotReplaced    = errors.New("machine was not replaced")
+	errNoAlternativeInstance = errors.New("no alternative instance type")
+)
+
+// ProviderSpecMutateFunc modifies a provider spec in place.
+type ProviderSpecMutateFunc func(providerSpec *machinev1.ProviderSpec) error
+
+// RollMachineSet applies the mutation to the provider spec of the MachineSet, then replaces its existing machines
+// DefaultRollMaxSurge at a time, the way a MachineSet is resized by hand. See RollMachineSetWithSurge.
+func RollMachineSet(ctx context.Context, c runtimeclient.Client, ms *machinev1.MachineSet, mutateProviderSpec ProviderSpecMutateFunc) error {
+	return RollMachineSetWithSurge(ctx, c, ms, mutateProviderSpec, DefaultRollMaxSurge)
+}
+
+// RollMachineSetWithSurge applies the mutation to the provider spec of the MachineSet, then replaces its existing
+// machines maxSurge at a time: the MachineSet is scaled up by maxSurge, and once the new machines are running and
+// their nodes ready, scaled back down with the replaced machines marked for deletion, so that they are drained.
+// The capacity of the MachineSet therefore never drops below its replicas during the roll.
+func RollMachineSetWithSurge(ctx context.Context, c runtimeclient.Client, ms *machinev1.MachineSet, mutateProviderSpec ProviderSpecMutateFunc, maxSurge int) error {
+	if maxSurge < 1 {
+		return errInvalidMaxSurge
+	}
+
+	current, err := GetMachineSet(ctx, c, ms.GetName())
+	if err != nil {
+		return fmt.Errorf("error getting MachineSet %s: %w", ms.GetName(), err)
+	}
+
+	replicas := int(ptr.Deref(current.Spec.Replicas, DefaultMachineSetReplicas))
+
+	oldMachines, err := GetMachinesFromMachineSet(ctx, c, current)
+	if err != nil {
+		return fmt.Errorf("error getting the machines of MachineSet %s: %w", current.Name, err)
+	}
+
+	patch := runtimeclient.MergeFrom(current.DeepCopy())
+	if err := mutateProviderSpec(&current.Spec.Template.Spec.ProviderSpec); err != nil {
+		return fmt.Errorf("error mutating the provider spec of MachineSet %s: %w", current.Name, err)
+	}
+
+	if err := c.Patch(ctx, current, patch); err != nil {
+		return fmt.Errorf("error patching the provider spec of MachineSet %s: %w", current.Name, err)
+	}
+
+	for len(oldMachines) > 0 {
+		batch := oldMachines[:min(maxSurge, len(oldMachines))]
+		oldMachines = oldMachines[len(batch):]
+
+		klog.Infof("[roll] MachineSet %s: replacing %d of %d machines", current.Name, len(batch), replicas)
+
+		if err := scaleMachineSetAndWait(ctx, c, current.Name, replicas+len(batch)); err != nil {
+			return err
+		}
+
+		for _, machine := range batch {
+			if err := MarkMachineForDeletion(ctx, c, machine); err != nil {
+				return err
+			}
+		}
+
+		if err := scaleMachineSetAndWait(ctx, c, current.Name, replicas); err != nil {
+			return err
+		}
+
+		for _, machine := range batch {
+			err := c.Get(ctx, runtimeclient.ObjectKeyFromObject(machine), &machinev1.Machine{})
+			switch {
+			case err == nil:
+				return fmt.Errorf("%w: MachineSet %s deleted another machine than %s", errMachineNotReplaced, current.Name, machine.Name)
+			case !apierrors.IsNotFound(err):
+				return fmt.Errorf("error getting machine %s: %w", machine.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// scaleMachineSetAndWait scales the MachineSet and waits for all its machines to be running and their nodes ready.
+func scaleMachineSetAndWait(ctx context.Context, c runtimeclient.Client, name string, replicas int) error {
+	start := time.Now()
+
+	if err := ScaleMachineSet(name, replicas); err != nil {
+		return fmt.Errorf("error scaling MachineSet %s to %d replicas: %w", name, replicas, err)
+	}
+
+	if _, err := WaitForMachineSetScaled(ctx, c, name, start, WaitOverLong); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkMachineForDeletion annotates the machine so that its MachineSet deletes it first when scaled down.
+func MarkMachineForDeletion(ctx context.Context, c runtimeclient.Client, machine *machinev1.Machine) error {
+	patch := runtimeclient.MergeFrom(machine.DeepCopy())
+
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+
+	machine.Annotations[MachineDeleteAnnotation] = "true"
+
+	if err := c.Patch(ctx, machine, patch); err != nil {
+		return fmt.Errorf("error marking machine %s for deletion: %w", machine.Name, err)
+	}
+
+	return nil
+}
+
+// GetProviderSpecInstanceType returns the instance type of the provider spec: the instance type on AWS
+// and the VM size on Azure.
+func GetProviderSpecInstanceType(providerSpec machinev1.ProviderSpec, platform configv1.PlatformType) (string, error) {
+	switch platform {
+	case configv1.AWSPlatformType:
+		var awsProviderConfig machinev1.AWSMachineProviderConfig
+		if err := json.Unmarshal(providerSpec.Value.Raw, &awsProviderConfig); err != nil {
+			return "", err
+		}
+
+		return awsProviderConfig.InstanceType, nil
+	case configv1.AzurePlatformType:
+		var azureProviderConfig machinev1.AzureMachineProviderSpec
+		if err := json.Unmarshal(providerSpec.Value.Raw, &azureProviderConfig); err != nil {
+			return "", err
+		}
+
+		return azureProviderConfig.VMSize, nil
+	default:
+		return "", fmt.Errorf("instance types for platform %s not supported", platform)
+	}
+}
+
+// WithAlternativeInstanceType returns a ProviderSpecMutateFunc changing the instance type of the provider spec
+// to the first alternative instance type of the platform which it does not already use.
+func WithAlternativeInstanceType(platform configv1.PlatformType) ProviderSpecMutateFunc {
+	return func(providerSpec *machinev1.ProviderSpec) error {
+		instanceType, err := GetProviderSpecInstanceType(*providerSpec, platform)
+		if err != nil {
+			return err
+		}
+
+		index := slices.IndexFunc(alternativeInstanceTypes[platform], func(alternative string) bool { return alternative != instanceType })
+		if index < 0 {
+			return fmt.Errorf("%w for instance type %s on platform %s", errNoAlternativeInstance, instanceType, platform)
+		}
+
+		var updated machinev1.ProviderSpec
+
+		switch platform {
+		case configv1.AWSPlatformType:
+			updated, err = updateProviderSpecAWSInstanceType(providerSpec, alternativeInstanceTypes[platform][index])
+		case configv1.AzurePlatformType:
+			updated, err = updateProviderSpecAzureVMSize(providerSpec, alternativeInstanceTypes[platform][index])
+		}
+
+		if err != nil {
+			return err
+		}
+
+		*providerSpec = updated
+
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package framework
+
+import (
+	"fmt"
+	"sync"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	caov1alpha1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	awsv1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	azurev1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	gcpv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// schemeBuilders register the types the suites handle: the Machine API, the cluster autoscaler operator, the
+// OpenShift configuration, Cluster API and its infrastructure providers. Each registers its own group versions,
+// none is registered twice. The vSphere, Nutanix and OpenStack infrastructure providers are not vendored yet.
+var schemeBuilders = []func(*runtime.Scheme) error{
+	machinev1.AddToScheme,
+	caov1alpha1.AddToScheme,
+	configv1.AddToScheme,
+	clusterv1.AddToScheme,
+	awsv1.AddToScheme,
+	azurev1.AddToScheme,
+	gcpv1.AddToScheme,
+}
+
+var (
+	defaultSchemeOnce sync.Once
+	errDefaultScheme  error
+)
+
+// RegisterAllSchemes registers every type the suites need in the given scheme.
+func RegisterAllSchemes(s *runtime.Scheme) error {
+	for _, addToScheme := range schemeBuilders {
+		if err := addToScheme(s); err != nil {
+			return fmt.Errorf("error registering scheme: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// registerDefaultSchemes registers every type the suites need in the client-go scheme, the one of the clients
+// created by the framework, once.
+func registerDefaultSchemes() error {
+	defaultSchemeOnce.Do(func() {
+		errDefaultScheme = RegisterAllSchemes(scheme.Scheme)
+	})
+
+	return errDefaultScheme
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	caov1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1"
+	caov1beta1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	awsv1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	azurev1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	gcpv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("Scheme registration", func() {
+	It("registers the types of every suite", func() {
+		s := runtime.NewScheme()
+		Expect(RegisterAllSchemes(s)).To(Succeed())
+
+		for _, obj := range []runtime.Object{
+			&machinev1.MachineSet{},
+			&caov1.ClusterAutoscaler{},
+			&caov1beta1.MachineAutoscaler{},
+			&configv1.Infrastructure{},
+			&clusterv1.MachineSet{},
+			&awsv1.AWSMachineTemplate{},
+			&azurev1.AzureMachineTemplate{},
+			&gcpv1.GCPMachineTemplate{},
+		} {
+			Expect(s.ObjectKinds(obj)).ToNot(BeEmpty(), "%T should be registered", obj)
+		}
+	})
+
+	It("can register the types in the same scheme again", func() {
+		s := runtime.NewScheme()
+		Expect(RegisterAllSchemes(s)).To(Succeed())
+		Expect(RegisterAllSchemes(s)).To(Succeed())
+	})
+
+	It("registers each kind with a single scheme builder", func() {
+		registeredBy := map[schema.GroupVersionKind]int{}
+
+		for i, addToScheme := range schemeBuilders {
+			s := runtime.NewScheme()
+			Expect(addToScheme(s)).To(Succeed())
+
+			for gvk, t := range s.AllKnownTypes() {
+				// The meta types, e.g. ListOptions, are registered along with every group version,
+				// and the infrastructure providers share a group version.
+				if strings.HasPrefix(t.PkgPath(), "k8s.io/apimachinery/pkg/apis/meta/") {
+					continue
+				}
+
+				if builder, found := registeredBy[gvk]; found {
+					Expect(builder).To(Equal(i), "%s should be registered by a single scheme builder", gvk)
+				}
+
+				registeredBy[gvk] = i
+			}
+		}
+	})
+})
@@ -0,0 +1,188 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// errSharedMachineSetNoReplicas is returned when the pre-provisioned shared MachineSet is scaled to zero.
+var errSharedMachineSetNoReplicas = errors.New("shared MachineSet has no replicas")
+
+// SharedMachineSetName is the name of the MachineSet reused by the specs which only need a running machine,
+// set with the --reuse-machineset flag. When empty, such specs create and delete their own MachineSet.
+var SharedMachineSetName string
+
+// SharedMachineSetCreatedLabel marks the shared MachineSet created by the suite, rather than pre-provisioned,
+// so that it is deleted once the suite is done.
+const SharedMachineSetCreatedLabel = "e2e.openshift.io/shared-machineset-created"
+
+// SharedMachineSetManager manages a MachineSet shared by several specs, across the parallel processes of the suite.
+// The processes coordinate through the API server: the first one to acquire the MachineSet creates it,
+// the others get it, scaled up when it has fewer replicas than they need. A pre-provisioned MachineSet is never
+// deleted. Otherwise the MachineSet is marked with SharedMachineSetCreatedLabel, and deleted by Cleanup once all
+// the processes are done.
+type SharedMachineSetManager struct {
+	name string
+}
+
+// NewSharedMachineSetManager returns a manager for the shared MachineSet with the given name.
+func NewSharedMachineSetManager(name string) *SharedMachineSetManager {
+	return &SharedMachineSetManager{name: name}
+}
+
+// Acquire returns the shared MachineSet, creating it from the given parameters if it does not exist yet,
+// or scaling it up to the replicas of the parameters if it has fewer.
+func (m *SharedMachineSetManager) Acquire(ctx context.Context, cl runtimeclient.Client, params MachineSetParams) (*machinev1.MachineSet, error) {
+	params.Name = m.name
+	// The labels belong to the caller, which may still use them.
+	params.Labels = maps.Clone(params.Labels)
+	if params.Labels == nil {
+		params.Labels = map[string]string{}
+	}
+	params.Labels[MachineSetKey] = m.name
+	params.Labels[SharedMachineSetCreatedLabel] = "true"
+
+	machineSet, err := CreateMachineSet(cl, params)
+	if err == nil {
+		klog.Infof("[shared] created shared MachineSet %s", m.name)
+		return machineSet, nil
+	}
+
+	// Another process, or the user, created it first.
+	if !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("error creating shared MachineSet %s: %w", m.name, err)
+	}
+
+	machineSet, err = GetMachineSet(ctx, cl, m.name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting shared MachineSet %s: %w", m.name, err)
+	}
+
+	replicas := ptr.Deref(machineSet.Spec.Replicas, DefaultMachineSetReplicas)
+	if replicas < 1 {
+		return nil, fmt.Errorf("%w: %s", errSharedMachineSetNoReplicas, m.name)
+	}
+
+	if replicas >= params.Replicas {
+		return machineSet, nil
+	}
+
+	return m.scaleUp(ctx, cl, params.Replicas)
+}
+
+// scaleUp scales the shared MachineSet up to the given replicas, unless another process already scaled it
+// to at least as many.
+func (m *SharedMachineSetManager) scaleUp(ctx context.Context, cl runtimeclient.Client, replicas int32) (*machinev1.MachineSet, error) {
+	var machineSet *machinev1.MachineSet
+
+	err := wait.PollUntilContextTimeout(ctx, RetryShort, WaitShort, true, func(ctx context.Context) (bool, error) {
+		var err error
+
+		machineSet, err = GetMachineSet(ctx, cl, m.name)
+		if err != nil {
+			return false, err
+		}
+
+		if ptr.Deref(machineSet.Spec.Replicas, DefaultMachineSetReplicas) >= replicas {
+			return true, nil
+		}
+
+		klog.Infof("[shared] scaling shared MachineSet %s up to %d replicas", m.name, replicas)
+
+		// Conflicts with another process scaling it, which is checked again on the next attempt.
+		if err := ScaleMachineSet(m.name, int(replicas)); err != nil && !apierrors.IsConflict(err) {
+			return false, err
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scaling shared MachineSet %s up to %d replicas: %w", m.name, replicas, err)
+	}
+
+	return machineSet, nil
+}
+
+// Cleanup deletes the shared MachineSet when it was created by the suite.
+// It must only run once all the processes of the suite are done with the MachineSet.
+func (m *SharedMachineSetManager) Cleanup(ctx context.Context, cl runtimeclient.Client) error {
+	machineSet, err := GetMachineSet(ctx, cl, m.name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error getting shared MachineSet %s: %w", m.name, err)
+	}
+
+	if _, created := machineSet.Labels[SharedMachineSetCreatedLabel]; !created {
+		return nil
+	}
+
+	klog.Infof("[shared] deleting shared MachineSet %s", m.name)
+
+	if err := DeleteMachineSets(ctx, cl, machineSet); err != nil {
+		return err
+	}
+
+	WaitForMachineSetsDeleted(ctx, cl, machineSet)
+
+	return nil
+}
+
+var (
+	sharedMachineSetsOnce sync.Once
+	sharedMachineSets     *SharedMachineSetManager
+)
+
+// SharedMachineSets returns the manager of the MachineSet named by SharedMachineSetName,
+// or nil when no shared MachineSet is configured.
+func SharedMachineSets() *SharedMachineSetManager {
+	sharedMachineSetsOnce.Do(func() {
+		if SharedMachineSetName != "" {
+			sharedMachineSets = NewSharedMachineSetManager(SharedMachineSetName)
+		}
+	})
+
+	return sharedMachineSets
+}
+
+// AcquireMachineSet returns a MachineSet for a spec which only needs a running machine.
+// The shared MachineSet is used when configured, otherwise a new MachineSet is created from the given parameters.
+// The returned function must be called once the spec is done: it deletes the MachineSet created for the spec,
+// the shared MachineSet being deleted by SharedMachineSetManager.Cleanup once the suite is done.
+// As with CreateMachineSet, callers are expected to wait for the machines of the MachineSet to be running.
+func AcquireMachineSet(ctx context.Context, cl runtimeclient.Client, params MachineSetParams) (*machinev1.MachineSet, func(), error) {
+	if shared := SharedMachineSets(); shared != nil {
+		machineSet, err := shared.Acquire(ctx, cl, params)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return machineSet, func() {}, nil
+	}
+
+	machineSet, err := CreateMachineSet(cl, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() {
+		if err := DeleteMachineSets(ctx, cl, machineSet); err != nil {
+			klog.Errorf("[cleanup] error deleting MachineSet %s: %v", machineSet.GetName(), err)
+			return
+		}
+
+		WaitForMachineSetsDeleted(ctx, cl, machineSet)
+	}
+
+	return machineSet, release, nil
+}
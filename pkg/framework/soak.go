@@ -0,0 +1,159 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SoakDuration is the time the soak specs repeat their cycles for, set with the --soak-duration flag.
+var SoakDuration = 2 * time.Hour
+
+// SoakReport records the health of the cluster over the cycles of a soak spec, to catch the slow leaks
+// the short specs never see.
+type SoakReport struct {
+	lock sync.Mutex
+
+	// Cycles is the number of completed cycles.
+	Cycles int
+	// LeakedMachines is the number of machines left over by the last cycle, MaxLeakedMachines the most left over by any.
+	LeakedMachines    int
+	MaxLeakedMachines int
+	// Restarts is the number of restarts of the watched pods, see PodRestartTracker.
+	Restarts int
+	// APICalls is the number of API calls made while waiting, APIErrors the number of them which failed.
+	APICalls  int
+	APIErrors int
+}
+
+// RecordCycle records a completed cycle and the machines it left over.
+func (r *SoakReport) RecordCycle(leakedMachines int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.Cycles++
+	r.LeakedMachines = leakedMachines
+	r.MaxLeakedMachines = max(r.MaxLeakedMachines, leakedMachines)
+}
+
+// RecordRestarts records the restarts of the watched pods so far.
+func (r *SoakReport) RecordRestarts(restarts int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.Restarts = restarts
+}
+
+// RecordAPICall records the result of an API call.
+func (r *SoakReport) RecordAPICall(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.APICalls++
+
+	if err != nil {
+		r.APIErrors++
+	}
+}
+
+// APIErrorRate returns the fraction of the API calls which failed.
+func (r *SoakReport) APIErrorRate() float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.APICalls == 0 {
+		return 0
+	}
+
+	return float64(r.APIErrors) / float64(r.APICalls)
+}
+
+// Format returns the report, one metric per line.
+func (r *SoakReport) Format() string {
+	rate := r.APIErrorRate()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	lines := []string{
+		fmt.Sprintf("cycles: %d", r.Cycles),
+		fmt.Sprintf("leaked machines: %d (max %d)", r.LeakedMachines, r.MaxLeakedMachines),
+		fmt.Sprintf("restarts: %d", r.Restarts),
+		fmt.Sprintf("API errors: %d of %d calls (%.2f%%)", r.APIErrors, r.APICalls, 100*rate),
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// PodRestartTracker counts the restarts of the pods matching a selector across samples: the restarts of their
+// containers, and the pods replaced since the first sample, e.g. after an eviction or a crash of the node.
+type PodRestartTracker struct {
+	selector map[string]string
+
+	initial    map[types.UID]int32
+	containers map[types.UID]int32
+}
+
+// NewPodRestartTracker returns a tracker of the pods matching the selector.
+func NewPodRestartTracker(selector map[string]string) *PodRestartTracker {
+	return &PodRestartTracker{selector: selector}
+}
+
+// Sample lists the pods, the first sample being the baseline the restarts are counted from.
+func (t *PodRestartTracker) Sample(ctx context.Context, cl runtimeclient.Client) error {
+	pods, err := GetPods(ctx, cl, t.selector)
+	if err != nil {
+		return fmt.Errorf("error listing pods: %w", err)
+	}
+
+	first := t.initial == nil
+	if first {
+		t.initial = map[types.UID]int32{}
+		t.containers = map[types.UID]int32{}
+	}
+
+	for _, pod := range pods.Items {
+		restarts := containerRestarts(pod)
+		if first {
+			t.initial[pod.UID] = restarts
+		}
+
+		t.containers[pod.UID] = max(t.containers[pod.UID], restarts)
+	}
+
+	return nil
+}
+
+// Restarts returns the restarts since the first sample.
+func (t *PodRestartTracker) Restarts() int {
+	restarts := 0
+
+	for uid, count := range t.containers {
+		initial, found := t.initial[uid]
+		if !found {
+			// A replacement pod counts as a restart, as do the restarts of its containers.
+			restarts++
+		}
+
+		restarts += int(count - initial)
+	}
+
+	return restarts
+}
+
+// containerRestarts returns the sum of the restarts of the containers of the pod.
+func containerRestarts(pod corev1.Pod) int32 {
+	var restarts int32
+
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+
+	return restarts
+}
@@ -0,0 +1,117 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+// UpdateProviderSpecTags creates a new ProviderSpec with the given user tags added to, or updated in, its existing ones:
+// the tags on AWS and Azure, and the labels on GCP.
+func UpdateProviderSpecTags(providerSpec *machinev1.ProviderSpec, platform configv1.PlatformType, tags map[string]string) (*machinev1.ProviderSpec, error) {
+	var (
+		providerConfig interface{}
+		err            error
+	)
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		providerConfig, err = withAWSTags(providerSpec, tags)
+	case configv1.AzurePlatformType:
+		providerConfig, err = withAzureTags(providerSpec, tags)
+	case configv1.GCPPlatformType:
+		providerConfig, err = withGCPLabels(providerSpec, tags)
+	default:
+		return nil, fmt.Errorf("user tags for platform %s not supported", platform)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedProviderSpec, err := json.Marshal(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}, nil
+}
+
+// withAWSTags returns the AWS provider config of the provider spec with the given tags.
+func withAWSTags(providerSpec *machinev1.ProviderSpec, tags map[string]string) (*machinev1.AWSMachineProviderConfig, error) {
+	var awsProviderConfig machinev1.AWSMachineProviderConfig
+	if err := json.Unmarshal(providerSpec.Value.Raw, &awsProviderConfig); err != nil {
+		return nil, err
+	}
+
+	for name, value := range tags {
+		updated := false
+
+		for i := range awsProviderConfig.Tags {
+			if awsProviderConfig.Tags[i].Name == name {
+				awsProviderConfig.Tags[i].Value = value
+				updated = true
+			}
+		}
+
+		if !updated {
+			awsProviderConfig.Tags = append(awsProviderConfig.Tags, machinev1.TagSpecification{Name: name, Value: value})
+		}
+	}
+
+	return &awsProviderConfig, nil
+}
+
+// withAzureTags returns the Azure provider spec of the provider spec with the given tags.
+func withAzureTags(providerSpec *machinev1.ProviderSpec, tags map[string]string) (*machinev1.AzureMachineProviderSpec, error) {
+	var azureProviderConfig machinev1.AzureMachineProviderSpec
+	if err := json.Unmarshal(providerSpec.Value.Raw, &azureProviderConfig); err != nil {
+		return nil, err
+	}
+
+	if azureProviderConfig.Tags == nil {
+		azureProviderConfig.Tags = map[string]string{}
+	}
+
+	for name, value := range tags {
+		azureProviderConfig.Tags[name] = value
+	}
+
+	return &azureProviderConfig, nil
+}
+
+// withGCPLabels returns the GCP provider spec of the provider spec with the given labels.
+func withGCPLabels(providerSpec *machinev1.ProviderSpec, labels map[string]string) (*machinev1.GCPMachineProviderSpec, error) {
+	var gcpProviderConfig machinev1.GCPMachineProviderSpec
+	if err := json.Unmarshal(providerSpec.Value.Raw, &gcpProviderConfig); err != nil {
+		return nil, err
+	}
+
+	if gcpProviderConfig.Labels == nil {
+		gcpProviderConfig.Labels = map[string]string{}
+	}
+
+	for name, value := range labels {
+		gcpProviderConfig.Labels[name] = value
+	}
+
+	return &gcpProviderConfig, nil
+}
+
+// AWSInstanceTags returns the tags of the EC2 instance, keyed by name.
+func AWSInstanceTags(instance *ec2.Instance) map[string]string {
+	tags := make(map[string]string, len(instance.Tags))
+
+	for _, tag := range instance.Tags {
+		tags[ptr.Deref(tag.Key, "")] = ptr.Deref(tag.Value, "")
+	}
+
+	return tags
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"net/http"
+)
+
+// metadataSchema mirrors termination.MetadataSchema.
+// The mock runs as a standalone program and cannot import the framework.
+type metadataSchema struct {
+	Path    string              `json:"path"`
+	Headers map[string]string   `json:"headers,omitempty"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Body    string              `json:"body,omitempty"`
+	Token   *tokenSchema        `json:"token,omitempty"`
+}
+
+// tokenSchema mirrors termination.TokenSchema.
+type tokenSchema struct {
+	Path      string `json:"path"`
+	TTLHeader string `json:"ttlHeader"`
+	Header    string `json:"header"`
+	Value     string `json:"value"`
+}
+
+func main() {
+	schemaPath := flag.String("schema", "", "Path to the JSON metadata schema of the cloud provider to mock")
+	listenAddr := flag.String("listen-addr", "0.0.0.0:80", "Address on which metadata mock service should listen")
+	flag.Parse()
+
+	// The mock runs on golang:1.14, which predates os.ReadFile.
+	data, err := ioutil.ReadFile(*schemaPath) //nolint:staticcheck
+	if err != nil {
+		log.Fatalf("--schema must point to a readable file: %v", err)
+	}
+
+	schema := metadataSchema{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		log.Fatalf("unable to decode metadata schema: %v", err)
+	}
+
+	log.Printf("Starting mock metadata service for endpoint: %s", schema.Path)
+
+	if err := http.ListenAndServe(*listenAddr, logRequests(metadataMockHandler(schema))); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// metadataMockHandler serves the termination response described by the schema.
+// Requests missing the required headers, query parameters or session token are rejected.
+func metadataMockHandler(schema metadataSchema) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(schema.Path, func(rw http.ResponseWriter, req *http.Request) {
+		if schema.Token != nil && req.Header.Get(schema.Token.Header) != schema.Token.Value {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		for header, value := range schema.Headers {
+			if req.Header.Get(header) != value {
+				// Require the header to be correct
+				writeBadRequest(rw)
+				return
+			}
+		}
+
+		for key, values := range schema.Query {
+			if !contains(values, req.URL.Query().Get(key)) {
+				// Require the query string to be correct
+				writeBadRequest(rw)
+				return
+			}
+		}
+
+		if _, err := rw.Write([]byte(schema.Body)); err != nil {
+			log.Fatal(err)
+		}
+	})
+
+	if schema.Token != nil {
+		token := schema.Token
+
+		mux.HandleFunc(token.Path, func(rw http.ResponseWriter, req *http.Request) {
+			if req.Header.Get(token.TTLHeader) == "" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			rw.Header().Add(token.TTLHeader, "21600")
+			rw.WriteHeader(http.StatusOK)
+
+			if _, err := rw.Write([]byte(token.Value)); err != nil {
+				log.Fatal(err)
+			}
+		})
+	}
+
+	return mux
+}
+
+func writeBadRequest(rw http.ResponseWriter) {
+	rw.WriteHeader(http.StatusBadRequest)
+
+	if _, err := rw.Write([]byte("400 Bad Request")); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		w := wrapResponseWriter(rw)
+		next.ServeHTTP(w, req)
+
+		log.Printf("Request: status=%d path=%q method=%q request-headers=%v", w.Status(), req.URL.EscapedPath(), req.Method, req.Header)
+	})
+}
+
+// responseWriter is a minimal wrapper for http.ResponseWriter that allows the
+// written HTTP status code to be captured for logging.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func wrapResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w}
+}
+
+func (rw *responseWriter) Status() int {
+	return rw.status
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+
+	rw.status = code
+	rw.ResponseWriter.WriteHeader(code)
+	rw.wroteHeader = true
+}
+
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.status = http.StatusOK
+		rw.wroteHeader = true
+	}
+
+	return rw.ResponseWriter.Write(data)
+}
@@ -0,0 +1,139 @@
+package termination
+
+import (
+	"encoding/json"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// MetadataSchema describes the metadata endpoint polled by the termination handler of a platform
+// and the response signalling that the instance is about to be terminated.
+// It is serialised to JSON and consumed by the metadata mock, so the mock itself
+// does not need to change when a platform metadata service evolves.
+type MetadataSchema struct {
+	// Path is the metadata endpoint polled by the termination handler.
+	Path string `json:"path"`
+	// Headers are the request headers, and their values, required by the metadata service.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Query are the query parameters, and their accepted values, required by the metadata service.
+	Query map[string][]string `json:"query,omitempty"`
+	// Body is the response body signalling the termination.
+	Body string `json:"body,omitempty"`
+	// Token, when set, describes the session token endpoint the termination handler
+	// must call before polling Path.
+	Token *TokenSchema `json:"token,omitempty"`
+}
+
+// TokenSchema describes a metadata service session token endpoint, e.g. AWS IMDSv2.
+type TokenSchema struct {
+	// Path is the endpoint issuing the token.
+	Path string `json:"path"`
+	// TTLHeader is the request header carrying the requested token TTL.
+	TTLHeader string `json:"ttlHeader"`
+	// Header is the request header carrying the token on subsequent requests.
+	Header string `json:"header"`
+	// Value is the token issued by the mock.
+	Value string `json:"value"`
+}
+
+// schemaBuilders holds the metadata schemas of the platforms supporting termination simulation.
+var schemaBuilders = map[configv1.PlatformType]func() (MetadataSchema, error){
+	configv1.AWSPlatformType:   awsSchema,
+	configv1.AzurePlatformType: azureSchema,
+	configv1.GCPPlatformType:   gcpSchema,
+}
+
+// SchemaForPlatform returns the metadata schema for the given platform.
+func SchemaForPlatform(platform configv1.PlatformType) (MetadataSchema, error) {
+	build, ok := schemaBuilders[platform]
+	if !ok {
+		return MetadataSchema{}, fmt.Errorf("termination simulation is not supported on platform %s", platform)
+	}
+
+	return build()
+}
+
+// AWS instances expect an OK response to indicate that the instance has been scheduled for termination.
+// Requests must be authenticated with an IMDSv2 session token.
+func awsSchema() (MetadataSchema, error) {
+	return MetadataSchema{
+		Path: "/latest/meta-data/spot/termination-time",
+		Token: &TokenSchema{
+			Path:      "/latest/api/token",
+			TTLHeader: "X-aws-ec2-metadata-token-ttl-seconds",
+			Header:    "X-aws-ec2-metadata-token",
+			Value:     "FOO",
+		},
+	}, nil
+}
+
+// Azure instances expect an OK response with a Json body containing a scheduled preemption event to
+// indicate that the instance has been scheduled for termination.
+// Requires the header "Metadata: true" and a query with a supported api version.
+func azureSchema() (MetadataSchema, error) {
+	events := AzureScheduledEvents{
+		DocumentIncarnation: 1,
+		Events: []AzureScheduledEvent{
+			{
+				EventID:           "e2e-preempt-event",
+				EventStatus:       "Scheduled",
+				EventType:         AzurePreemptEventType,
+				ResourceType:      "VirtualMachine",
+				EventSource:       "Platform",
+				Description:       "Virtual machine is being preempted.",
+				DurationInSeconds: -1,
+			},
+		},
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		return MetadataSchema{}, fmt.Errorf("error marshalling Azure scheduled events: %w", err)
+	}
+
+	return MetadataSchema{
+		Path:    "/metadata/scheduledevents",
+		Headers: map[string]string{"Metadata": "true"},
+		Query:   map[string][]string{"api-version": AzureScheduledEventsAPIVersions},
+		Body:    string(body),
+	}, nil
+}
+
+// GCP instances expect an OK response with the body TRUE to indicate that the instance has been
+// scheduled for termination. Requires the header "Metadata-Flavor: Google".
+// Note that the GCP simulation cannot run yet: GCP relies on the metadata IP for DNS,
+// which is rerouted to the mock by the simulator.
+func gcpSchema() (MetadataSchema, error) {
+	return MetadataSchema{
+		Path:    "/computeMetadata/v1/instance/preempted",
+		Headers: map[string]string{"Metadata-Flavor": "Google"},
+		Body:    "TRUE",
+	}, nil
+}
+
+// AzurePreemptEventType is the type of the Azure scheduled event announcing a Spot VM eviction.
+const AzurePreemptEventType = "Preempt"
+
+// AzureScheduledEventsAPIVersions are the Azure Scheduled Events API versions accepted by the mock.
+var AzureScheduledEventsAPIVersions = []string{"2019-08-01", "2020-07-01"}
+
+// AzureScheduledEvents represents metadata response, more detailed info can be found here:
+// https://learn.microsoft.com/en-us/azure/virtual-machines/linux/scheduled-events#query-for-events
+type AzureScheduledEvents struct {
+	DocumentIncarnation int                   `json:"DocumentIncarnation"`
+	Events              []AzureScheduledEvent `json:"Events"`
+}
+
+// AzureScheduledEvent is a single Azure scheduled event.
+type AzureScheduledEvent struct {
+	EventID           string   `json:"EventId"`
+	EventStatus       string   `json:"EventStatus"`
+	EventType         string   `json:"EventType"`
+	ResourceType      string   `json:"ResourceType"`
+	Resources         []string `json:"Resources"`
+	EventSource       string   `json:"EventSource"`
+	NotBefore         string   `json:"NotBefore"`
+	Description       string   `json:"Description"`
+	DurationInSeconds int      `json:"DurationInSeconds"`
+}
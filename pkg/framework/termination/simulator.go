@@ -0,0 +1,404 @@
+package termination
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+)
+
+// metadataMockSource is the source of the metadata mock, run from a ConfigMap by the mock Deployment.
+//
+//go:embed mock/metadata_mock.go
+var metadataMockSource []byte
+
+// MetadataMockObjects returns the objects deploying a mock of the platform metadata service,
+// which reports that the instance is about to be terminated.
+func MetadataMockObjects(platform configv1.PlatformType) ([]runtimeclient.Object, error) {
+	schema, err := SchemaForPlatform(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := getMetadataMockConfigMap(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return []runtimeclient.Object{
+		configMap,
+		getMetadataMockService(),
+		getMetadataMockDeployment(),
+	}, nil
+}
+
+// SimulatorObjects returns the objects rerouting the metadata service traffic of the given node to the mock,
+// for the metadata service address of the platform in the given IP family.
+func SimulatorObjects(platform configv1.PlatformType, nodeName string, family corev1.IPFamily) ([]runtimeclient.Object, error) {
+	address, err := MetadataAddress(platform, family)
+	if err != nil {
+		return nil, err
+	}
+
+	return []runtimeclient.Object{
+		getTerminationSimulatorServiceAccount(),
+		getTerminationSimulatorRole(),
+		getTerminationSimulatorRoleBinding(),
+		getTerminationSimulatorJob(nodeName, family, address),
+	}, nil
+}
+
+// metadataIPv4Address is the IPv4 address of the metadata service, the same on all the supported platforms.
+const metadataIPv4Address = "169.254.169.254"
+
+// metadataIPv6Addresses holds the IPv6 addresses of the metadata services of the platforms which have one.
+var metadataIPv6Addresses = map[configv1.PlatformType]string{
+	configv1.AWSPlatformType: "fd00:ec2::254",
+}
+
+// MetadataAddress returns the address of the metadata service of the platform in the given IP family.
+// It returns an error when the platform has no metadata service in that family, e.g. IPv6 on Azure.
+func MetadataAddress(platform configv1.PlatformType, family corev1.IPFamily) (string, error) {
+	if _, err := SchemaForPlatform(platform); err != nil {
+		return "", err
+	}
+
+	switch family {
+	case corev1.IPv4Protocol:
+		return metadataIPv4Address, nil
+	case corev1.IPv6Protocol:
+		if address, ok := metadataIPv6Addresses[platform]; ok {
+			return address, nil
+		}
+	}
+
+	return "", fmt.Errorf("platform %s has no %s metadata service", platform, family)
+}
+
+// MetadataMockLabels returns the labels of the metadata mock objects.
+func MetadataMockLabels() map[string]string {
+	return map[string]string{
+		"app": "metadata-mock",
+	}
+}
+
+const (
+	// MetadataMockName is the name of the metadata mock Deployment.
+	MetadataMockName = metadataServiceMockName
+
+	metadataServiceMockName          = "metadata-service-mock"
+	metadataServiceMockServiceName   = metadataServiceMockName + "-service"
+	metadataServiceMockConfigMapName = metadataServiceMockName + "-configmap"
+	metadataServiceMockPort          = 8082
+	metadataMockSourceKey            = "metadata_mock.go"
+	metadataMockSchemaKey            = "schema.json"
+)
+
+func getMetadataMockDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metadataServiceMockName,
+			Namespace: framework.MachineAPINamespace,
+			Labels:    MetadataMockLabels(),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: MetadataMockLabels(),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: MetadataMockLabels(),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    "metadata-mock",
+							Image:   "golang:1.14",
+							Command: []string{"/usr/local/go/bin/go"},
+							Args: []string{
+								"run",
+								"/mock/" + metadataMockSourceKey,
+								"--schema=/mock/" + metadataMockSchemaKey,
+								fmt.Sprintf("--listen-addr=0.0.0.0:%d", metadataServiceMockPort),
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "GOCACHE",
+									Value: "/go/.cache",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "mock-server",
+									MountPath: "/mock",
+								},
+							},
+						},
+					},
+					DNSPolicy: corev1.DNSClusterFirst,
+					Volumes: []corev1.Volume{
+						{
+							Name: "mock-server",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: metadataServiceMockConfigMapName,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getMetadataMockService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metadataServiceMockServiceName,
+			Namespace: framework.MachineAPINamespace,
+			Labels:    MetadataMockLabels(),
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       metadataServiceMockPort,
+					Protocol:   "TCP",
+					TargetPort: intstr.FromInt(metadataServiceMockPort),
+				},
+			},
+			Selector:        MetadataMockLabels(),
+			SessionAffinity: corev1.ServiceAffinityNone,
+			ClusterIP:       "None",
+			Type:            corev1.ServiceTypeClusterIP,
+			// Dual-stack clusters publish the mock in both families, so that either simulator can resolve it.
+			IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicyPreferDualStack),
+		},
+	}
+}
+
+func getMetadataMockConfigMap(schema MetadataSchema) (*corev1.ConfigMap, error) {
+	schemaData, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling metadata schema: %w", err)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metadataServiceMockConfigMapName,
+			Namespace: framework.MachineAPINamespace,
+			Labels:    MetadataMockLabels(),
+		},
+		BinaryData: map[string][]byte{
+			metadataMockSourceKey: metadataMockSource,
+			metadataMockSchemaKey: schemaData,
+		},
+	}, nil
+}
+
+const (
+	terminationSimulatorName               = "termination-simulator"
+	terminationSimulatorServiceAccountName = terminationSimulatorName + "-service-account"
+	terminationSimulatorRoleName           = terminationSimulatorName + "-role"
+	terminationSimulatorRoleBindingName    = terminationSimulatorName + "-rolebinding"
+)
+
+// simulatorScripts are the scripts of the termination simulator, by IP family. The IPv6 script reroutes the traffic
+// with ip6tables and resolves the AAAA records of the mock service, as the iptables rules only match IPv4 traffic.
+var simulatorScripts = map[corev1.IPFamily]string{
+	corev1.IPv4Protocol: `apk update && apk add iptables bind-tools;
+export SERVICE_IP=$(dig +short ${MOCK_SERVICE_NAME}.${NAMESPACE}.svc.cluster.local);
+if [ -z ${SERVICE_IP} ]; then echo "No service IP"; exit 1; fi;
+iptables-nft -t nat -A OUTPUT -p tcp -d ${METADATA_ADDRESS} -j DNAT --to-destination ${SERVICE_IP}:${MOCK_SERVICE_PORT};
+iptables-nft -t nat -A POSTROUTING -j MASQUERADE;
+ifconfig lo:0 ${METADATA_ADDRESS} up;
+echo "Redirected metadata service to ${SERVICE_IP}:${MOCK_SERVICE_PORT}";`,
+	corev1.IPv6Protocol: `apk update && apk add iptables ip6tables bind-tools iproute2;
+export SERVICE_IP=$(dig +short AAAA ${MOCK_SERVICE_NAME}.${NAMESPACE}.svc.cluster.local | head -n 1);
+if [ -z ${SERVICE_IP} ]; then echo "No service IPv6"; exit 1; fi;
+ip6tables-nft -t nat -A OUTPUT -p tcp -d ${METADATA_ADDRESS} -j DNAT --to-destination [${SERVICE_IP}]:${MOCK_SERVICE_PORT};
+ip6tables-nft -t nat -A POSTROUTING -j MASQUERADE;
+ip -6 addr add ${METADATA_ADDRESS}/128 dev lo;
+echo "Redirected metadata service to [${SERVICE_IP}]:${MOCK_SERVICE_PORT}";`,
+}
+
+func getTerminationSimulatorJob(nodeName string, family corev1.IPFamily, metadataAddress string) *batchv1.Job {
+	script := simulatorScripts[family]
+
+	fileOrCreate := corev1.HostPathFileOrCreate
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      terminationSimulatorName,
+			Namespace: framework.MachineAPINamespace,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    "iptables",
+							Image:   "alpine:3.12",
+							Command: []string{"/bin/sh", "-c"},
+							Args:    []string{script},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "NAMESPACE",
+									Value: framework.MachineAPINamespace,
+								},
+								{
+									Name:  "MOCK_SERVICE_NAME",
+									Value: metadataServiceMockServiceName,
+								},
+								{
+									Name:  "MOCK_SERVICE_PORT",
+									Value: fmt.Sprintf("%d", metadataServiceMockPort),
+								},
+								{
+									Name:  "METADATA_ADDRESS",
+									Value: metadataAddress,
+								},
+							},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: ptr.To[bool](true),
+								Capabilities: &corev1.Capabilities{
+									Add: []corev1.Capability{"NET_ADMIN", "NET_RAW"},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "xtables-lock",
+									MountPath: "/run/xtables.lock",
+									ReadOnly:  false,
+								},
+								{
+									Name:      "lib-modules",
+									MountPath: "/lib/modules",
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					HostNetwork:        true,
+					DNSPolicy:          corev1.DNSClusterFirstWithHostNet,
+					NodeName:           nodeName,
+					ServiceAccountName: terminationSimulatorServiceAccountName,
+					Volumes: []corev1.Volume{
+						{
+							Name: "xtables-lock",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{
+									Path: "/run/xtables.lock",
+									Type: &fileOrCreate,
+								},
+							},
+						},
+						{
+							Name: "lib-modules",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{
+									Path: "/lib/modules",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTerminationSimulatorServiceAccount() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      terminationSimulatorServiceAccountName,
+			Namespace: framework.MachineAPINamespace,
+		},
+	}
+}
+
+func getTerminationSimulatorRole() *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      terminationSimulatorRoleName,
+			Namespace: framework.MachineAPINamespace,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{"security.openshift.io"},
+				ResourceNames: []string{"privileged"},
+				Resources:     []string{"securitycontextconstraints"},
+				Verbs:         []string{"use"},
+			},
+		},
+	}
+}
+
+func getTerminationSimulatorRoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      terminationSimulatorRoleBindingName,
+			Namespace: framework.MachineAPINamespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     terminationSimulatorRoleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      terminationSimulatorServiceAccountName,
+				Namespace: framework.MachineAPINamespace,
+			},
+		},
+	}
+}
+
+// GetMetadataMockLogs returns the logs of the metadata mock pods.
+// Every request served by the mock is logged, which allows checking
+// that the termination handler polled the expected endpoint.
+func GetMetadataMockLogs(ctx context.Context, cl runtimeclient.Client, clientset kubernetes.Interface) (string, error) {
+	pods := &corev1.PodList{}
+	if err := cl.List(ctx, pods, runtimeclient.InNamespace(framework.MachineAPINamespace), runtimeclient.MatchingLabels(MetadataMockLabels())); err != nil {
+		return "", fmt.Errorf("error listing metadata mock pods: %w", err)
+	}
+
+	logs := &strings.Builder{}
+
+	for _, pod := range pods.Items {
+		data, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+		if err != nil {
+			return "", fmt.Errorf("error getting logs of pod %s: %w", pod.Name, err)
+		}
+
+		logs.Write(data)
+	}
+
+	return logs.String(), nil
+}
+
+// ExpectedMockRequestLog returns the log line fragment written by the metadata mock
+// when it answered a termination handler request on the given schema path.
+func ExpectedMockRequestLog(schema MetadataSchema) string {
+	return fmt.Sprintf("status=200 path=%q", schema.Path)
+}
@@ -0,0 +1,188 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+// TimelineEvent is a single Machine phase or Node condition transition observed by a Timeline.
+type TimelineEvent struct {
+	Time    time.Time
+	Kind    string
+	Name    string
+	Message string
+}
+
+// String returns a human readable representation of the event.
+func (e TimelineEvent) String() string {
+	return fmt.Sprintf("%s %s/%s: %s", e.Time.UTC().Format(time.RFC3339), e.Kind, e.Name, e.Message)
+}
+
+// Timeline records Machine phase transitions and Node condition changes
+// observed in the cluster, so they can be attached to the report of a failed spec.
+type Timeline struct {
+	lock   sync.Mutex
+	events []TimelineEvent
+}
+
+// NewTimeline returns a new, empty Timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Start feeds the timeline from the Machine and Node informers of the suite cache, which must be running.
+// The timeline is fed until the suite cache is stopped.
+func (t *Timeline) Start(ctx context.Context) error {
+	return addSuiteCacheHandlers(ctx,
+		toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { t.onMachineAdd(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { t.onMachineUpdate(oldObj, newObj) },
+			DeleteFunc: func(obj interface{}) { t.onDelete("Machine", obj) },
+		},
+		toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { t.onNodeAdd(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { t.onNodeUpdate(oldObj, newObj) },
+			DeleteFunc: func(obj interface{}) { t.onDelete("Node", obj) },
+		},
+	)
+}
+
+// EventsSince returns the events recorded at, or after, the given time.
+func (t *Timeline) EventsSince(since time.Time) []TimelineEvent {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var events []TimelineEvent
+
+	for _, e := range t.events {
+		if !e.Time.Before(since) {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// Format returns the events recorded since the given time, one per line.
+func (t *Timeline) Format(since time.Time) string {
+	events := t.EventsSince(since)
+	if len(events) == 0 {
+		return "no Machine or Node transitions observed"
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, e := range events {
+		lines = append(lines, e.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *Timeline) record(kind, name, format string, args ...interface{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.events = append(t.events, TimelineEvent{
+		Time:    time.Now(),
+		Kind:    kind,
+		Name:    name,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (t *Timeline) onMachineAdd(obj interface{}) {
+	machine, ok := obj.(*machinev1.Machine)
+	if !ok {
+		return
+	}
+
+	t.record("Machine", machine.Name, "observed in phase %q", ptr.Deref(machine.Status.Phase, ""))
+}
+
+func (t *Timeline) onMachineUpdate(oldObj, newObj interface{}) {
+	oldMachine, ok := oldObj.(*machinev1.Machine)
+	if !ok {
+		return
+	}
+
+	newMachine, ok := newObj.(*machinev1.Machine)
+	if !ok {
+		return
+	}
+
+	oldPhase := ptr.Deref(oldMachine.Status.Phase, "")
+	newPhase := ptr.Deref(newMachine.Status.Phase, "")
+
+	if oldPhase != newPhase {
+		t.record("Machine", newMachine.Name, "phase %q -> %q", oldPhase, newPhase)
+	}
+
+	if oldMachine.Status.NodeRef == nil && newMachine.Status.NodeRef != nil {
+		t.record("Machine", newMachine.Name, "linked to node %q", newMachine.Status.NodeRef.Name)
+	}
+
+	if newMachine.Status.ErrorMessage != nil && ptr.Deref(oldMachine.Status.ErrorMessage, "") != *newMachine.Status.ErrorMessage {
+		t.record("Machine", newMachine.Name, "error: %s", *newMachine.Status.ErrorMessage)
+	}
+
+	if oldMachine.DeletionTimestamp.IsZero() && !newMachine.DeletionTimestamp.IsZero() {
+		t.record("Machine", newMachine.Name, "deletion requested")
+	}
+}
+
+func (t *Timeline) onNodeAdd(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	t.record("Node", node.Name, "observed, ready: %t", IsNodeReady(node))
+}
+
+func (t *Timeline) onNodeUpdate(oldObj, newObj interface{}) {
+	oldNode, ok := oldObj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	newNode, ok := newObj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	oldConditions := map[corev1.NodeConditionType]corev1.ConditionStatus{}
+	for _, c := range oldNode.Status.Conditions {
+		oldConditions[c.Type] = c.Status
+	}
+
+	for _, c := range newNode.Status.Conditions {
+		if oldStatus, found := oldConditions[c.Type]; !found || oldStatus != c.Status {
+			t.record("Node", newNode.Name, "condition %s %q -> %q (%s)", c.Type, oldStatus, c.Status, c.Reason)
+		}
+	}
+
+	if oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable {
+		t.record("Node", newNode.Name, "unschedulable: %t", newNode.Spec.Unschedulable)
+	}
+}
+
+func (t *Timeline) onDelete(kind string, obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	accessor, ok := obj.(interface{ GetName() string })
+	if !ok {
+		return
+	}
+
+	t.record(kind, accessor.GetName(), "deleted")
+}
@@ -0,0 +1,146 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WorkerUserDataSecretName is the name of the user data secret used by the worker MachineSets.
+	WorkerUserDataSecretName = "worker-user-data"
+
+	// userDataSecretKey is the key holding the ignition config in user data secrets.
+	userDataSecretKey = "userData"
+
+	// nodelessIgnition is a valid ignition config which does not configure anything:
+	// the instance boots but never joins the cluster.
+	nodelessIgnition = `{"ignition":{"version":"3.2.0"}}`
+)
+
+var errUserDataSecretNotSet = errors.New("providerSpec does not reference a user data secret")
+
+// UserDataPatchFunc modifies the ignition config of a user data secret.
+type UserDataPatchFunc func(userData []byte) ([]byte, error)
+
+// CloneUserDataSecret creates a copy of the given user data secret in the Machine API namespace,
+// with the ignition config modified by the given patch functions.
+// The source secret is never modified. The clone is labeled as created by the e2e framework,
+// so that it can be cleaned up should the test be interrupted.
+func CloneUserDataSecret(ctx context.Context, cl runtimeclient.Client, sourceName, targetName string, patches ...UserDataPatchFunc) (*corev1.Secret, error) {
+	source := &corev1.Secret{}
+	if err := cl.Get(ctx, runtimeclient.ObjectKey{Namespace: MachineAPINamespace, Name: sourceName}, source); err != nil {
+		return nil, fmt.Errorf("error getting user data secret %q: %w", sourceName, err)
+	}
+
+	data := make(map[string][]byte, len(source.Data))
+	for k, v := range source.Data {
+		data[k] = append([]byte(nil), v...)
+	}
+
+	for _, patch := range patches {
+		patched, err := patch(data[userDataSecretKey])
+		if err != nil {
+			return nil, fmt.Errorf("error patching user data of secret %q: %w", sourceName, err)
+		}
+
+		data[userDataSecretKey] = patched
+	}
+
+	target := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      targetName,
+			Namespace: MachineAPINamespace,
+			Labels: map[string]string{
+				ReasonKey: ReasonE2E,
+			},
+		},
+		Type: source.Type,
+		Data: data,
+	}
+
+	if err := cl.Create(ctx, target); err != nil {
+		return nil, fmt.Errorf("error creating user data secret %q: %w", targetName, err)
+	}
+
+	return target, nil
+}
+
+// CreateNodelessUserDataSecret creates a user data secret with the given generated name prefix in the Machine API
+// namespace, whose machines boot but never join the cluster, so that they never get a node.
+func CreateNodelessUserDataSecret(ctx context.Context, cl runtimeclient.Client, generateName string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+			Namespace:    MachineAPINamespace,
+			Labels: map[string]string{
+				ReasonKey: ReasonE2E,
+			},
+		},
+		Data: map[string][]byte{userDataSecretKey: []byte(nodelessIgnition)},
+	}
+
+	if err := cl.Create(ctx, secret); err != nil {
+		return nil, fmt.Errorf("error creating nodeless user data secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// WithIgnitionFile returns a UserDataPatchFunc adding a file with the given contents
+// to the storage section of the ignition config.
+func WithIgnitionFile(path, contents string) UserDataPatchFunc {
+	return func(userData []byte) ([]byte, error) {
+		ignition := map[string]interface{}{}
+		if err := json.Unmarshal(userData, &ignition); err != nil {
+			return nil, fmt.Errorf("error unmarshalling ignition config: %w", err)
+		}
+
+		storage, _ := ignition["storage"].(map[string]interface{})
+		if storage == nil {
+			storage = map[string]interface{}{}
+		}
+
+		files, _ := storage["files"].([]interface{})
+		files = append(files, map[string]interface{}{
+			"path": path,
+			"mode": 420,
+			"contents": map[string]interface{}{
+				"source": "data:," + url.PathEscape(contents),
+			},
+		})
+
+		storage["files"] = files
+		ignition["storage"] = storage
+
+		return json.Marshal(ignition)
+	}
+}
+
+// GetProviderSpecUserDataSecretName returns the name of the user data secret referenced by the given ProviderSpec.
+func GetProviderSpecUserDataSecretName(providerSpec machinev1.ProviderSpec) (string, error) {
+	if providerSpec.Value == nil {
+		return "", errUserDataSecretNotSet
+	}
+
+	var providerConfig struct {
+		UserDataSecret *corev1.SecretReference `json:"userDataSecret"`
+	}
+
+	if err := json.Unmarshal(providerSpec.Value.Raw, &providerConfig); err != nil {
+		return "", fmt.Errorf("error unmarshalling providerSpec: %w", err)
+	}
+
+	if providerConfig.UserDataSecret == nil || providerConfig.UserDataSecret.Name == "" {
+		return "", errUserDataSecretNotSet
+	}
+
+	return providerConfig.UserDataSecret.Name, nil
+}
@@ -0,0 +1,209 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ControlPlaneNodeRoleLabel is the label applied to control plane nodes.
+	ControlPlaneNodeRoleLabel = "node-role.kubernetes.io/master"
+
+	apiServerHealthKey = "apiserver"
+)
+
+// ClusterHealthWatchdog continuously monitors the API server availability,
+// the ClusterOperators and the control plane nodes while the suite runs.
+// Any problem which persists for longer than WaitLong marks the cluster as unhealthy,
+// so that the suite can be aborted early instead of disrupting an already broken cluster even further.
+// Problems already present on the first check are not the suite's doing, they are ignored until they recover.
+// The watchdog then cancels the suite context, as on interrupt, which stops the running specs
+// through their API calls and polling, and AbortSuiteIfUnhealthy aborts the suite from the next spec.
+type ClusterHealthWatchdog struct {
+	client   runtimeclient.Client
+	interval time.Duration
+
+	lock           sync.Mutex
+	unhealthySince map[string]time.Time
+	preexisting    map[string]bool
+	err            error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewClusterHealthWatchdog returns a new ClusterHealthWatchdog.
+// Problems must persist for longer than WaitLong before the cluster is considered unhealthy,
+// as disruptive specs are expected to degrade the cluster temporarily. WaitLong is read on
+// each check, so that it accounts for the platform timeouts set after the watchdog is created.
+func NewClusterHealthWatchdog(client runtimeclient.Client) *ClusterHealthWatchdog {
+	return &ClusterHealthWatchdog{
+		client:         client,
+		interval:       RetryMedium * 6,
+		unhealthySince: map[string]time.Time{},
+	}
+}
+
+// Start starts monitoring the cluster in the background until Stop is called
+// or the given context is cancelled.
+func (w *ClusterHealthWatchdog) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		wait.UntilWithContext(ctx, w.check, w.interval)
+	}()
+}
+
+// Stop stops monitoring the cluster.
+func (w *ClusterHealthWatchdog) Stop() {
+	if w.cancel == nil {
+		return
+	}
+
+	w.cancel()
+	<-w.done
+}
+
+// Err returns an error describing why the cluster is unhealthy, or nil
+// if no persistent problem was observed.
+func (w *ClusterHealthWatchdog) Err() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.err
+}
+
+// AbortSuiteIfUnhealthy aborts the suite when the watchdog observed the cluster to be unhealthy.
+// It must be called from within a Ginkgo node.
+func (w *ClusterHealthWatchdog) AbortSuiteIfUnhealthy() {
+	if err := w.Err(); err != nil {
+		AbortSuite(fmt.Sprintf("Cluster health watchdog detected an unhealthy cluster, aborting the suite: %v", err))
+	}
+}
+
+// check runs a single round of health checks and updates the watchdog state.
+func (w *ClusterHealthWatchdog) check(ctx context.Context) {
+	problems := w.collectProblems(ctx)
+	now := time.Now()
+	gracePeriod := WaitLong
+
+	if w.update(problems, now, gracePeriod) {
+		// Stop the running specs, rather than waiting for the next one to abort the suite.
+		CancelContext()
+	}
+}
+
+// update records the problems observed at the given time, and returns whether the cluster just became unhealthy.
+func (w *ClusterHealthWatchdog) update(problems map[string]string, now time.Time, gracePeriod time.Duration) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.preexisting == nil {
+		w.preexisting = map[string]bool{}
+
+		for key, message := range problems {
+			klog.Warningf("[watchdog] ignoring until it recovers, present before the suite started: %s", message)
+			w.preexisting[key] = true
+		}
+	}
+
+	for key := range w.preexisting {
+		if _, found := problems[key]; !found {
+			klog.Infof("[watchdog] %s recovered", key)
+			delete(w.preexisting, key)
+		}
+	}
+
+	for key := range w.unhealthySince {
+		if _, found := problems[key]; !found {
+			klog.Infof("[watchdog] %s recovered", key)
+			delete(w.unhealthySince, key)
+		}
+	}
+
+	var persistent []string
+
+	for key, message := range problems {
+		if w.preexisting[key] {
+			continue
+		}
+
+		if _, found := w.unhealthySince[key]; !found {
+			klog.Warningf("[watchdog] %s", message)
+			w.unhealthySince[key] = now
+		}
+
+		if now.Sub(w.unhealthySince[key]) > gracePeriod {
+			persistent = append(persistent, fmt.Sprintf("%s (since %s)", message, w.unhealthySince[key].UTC().Format(time.RFC3339)))
+		}
+	}
+
+	if len(persistent) == 0 || w.err != nil {
+		return false
+	}
+
+	sort.Strings(persistent)
+	w.err = fmt.Errorf("persistent problems for more than %s: %s", gracePeriod, strings.Join(persistent, "; "))
+	klog.Errorf("[watchdog] %v, cancelling the suite context", w.err)
+
+	return true
+}
+
+// collectProblems returns the problems currently observed in the cluster, keyed by their source.
+func (w *ClusterHealthWatchdog) collectProblems(ctx context.Context) map[string]string {
+	problems := map[string]string{}
+
+	// Use the infrastructure object as a cheap API server availability probe.
+	if _, err := GetInfrastructure(ctx, w.client); err != nil {
+		problems[apiServerHealthKey] = fmt.Sprintf("API server is not available: %v", err)
+
+		// Nothing else can be checked without the API server.
+		return problems
+	}
+
+	clusterOperators := &configv1.ClusterOperatorList{}
+	if err := w.client.List(ctx, clusterOperators); err != nil {
+		problems[apiServerHealthKey] = fmt.Sprintf("unable to list ClusterOperators: %v", err)
+	}
+
+	for _, co := range clusterOperators.Items {
+		key := "clusteroperator/" + co.Name
+
+		switch {
+		case cov1helpers.IsStatusConditionFalse(co.Status.Conditions, configv1.OperatorAvailable):
+			problems[key] = fmt.Sprintf("ClusterOperator %q is not available", co.Name)
+		case cov1helpers.IsStatusConditionTrue(co.Status.Conditions, configv1.OperatorDegraded):
+			problems[key] = fmt.Sprintf("ClusterOperator %q is degraded", co.Name)
+		}
+	}
+
+	controlPlaneNodes := &corev1.NodeList{}
+	if err := w.client.List(ctx, controlPlaneNodes, runtimeclient.HasLabels{ControlPlaneNodeRoleLabel}); err != nil {
+		problems[apiServerHealthKey] = fmt.Sprintf("unable to list control plane nodes: %v", err)
+	}
+
+	for i := range controlPlaneNodes.Items {
+		node := &controlPlaneNodes.Items[i]
+		if !IsNodeReady(node) {
+			problems["node/"+node.Name] = fmt.Sprintf("control plane node %q is not ready", node.Name)
+		}
+	}
+
+	return problems
+}
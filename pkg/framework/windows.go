@@ -0,0 +1,161 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WindowsUserDataSecretName is the name of the user data secret of the Windows machines,
+	// created by the Windows Machine Config Operator.
+	WindowsUserDataSecretName = "windows-user-data"
+
+	// WindowsOSIDLabel is the machine label the Windows Machine Config Operator selects the machines it configures by.
+	WindowsOSIDLabel = "machine.openshift.io/os-id"
+
+	// WindowsOSID is the value of WindowsOSIDLabel on Windows machines.
+	WindowsOSID = "Windows"
+
+	// WindowsMachineConfigOperatorNamespace is the namespace the Windows Machine Config Operator is installed in.
+	WindowsMachineConfigOperatorNamespace = "openshift-windows-machine-config-operator"
+
+	// WindowsMachineConfigOperatorDeployment is the name of the Windows Machine Config Operator Deployment.
+	WindowsMachineConfigOperatorDeployment = "windows-machine-config-operator"
+
+	// defaultAzureWindowsSKU and defaultGCPWindowsImage are the Windows images used when WindowsImage is not set.
+	defaultAzureWindowsSKU = "2022-datacenter-smalldisk"
+	defaultGCPWindowsImage = "projects/windows-cloud/global/images/family/windows-2022-core"
+)
+
+// WindowsImage is the Windows image of the Windows machines, set with the --windows-image flag:
+// an AMI ID on AWS, a SKU of the MicrosoftWindowsServer WindowsServer offer on Azure, or an image path on GCP.
+// It must be set on AWS, where the Windows AMIs differ per region.
+var WindowsImage string
+
+// ErrWindowsImageNotSet is returned when a Windows MachineSet is built on a platform requiring WindowsImage without it.
+var ErrWindowsImageNotSet = errors.New("the Windows image must be set with --windows-image")
+
+// IsWindowsMachineConfigOperatorInstalled returns true when the Windows Machine Config Operator, which turns
+// Windows machines into nodes, is installed on the cluster.
+func IsWindowsMachineConfigOperatorInstalled(ctx context.Context, c runtimeclient.Client) (bool, error) {
+	_, err := GetDeployment(ctx, c, WindowsMachineConfigOperatorDeployment, WindowsMachineConfigOperatorNamespace)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// BuildWindowsMachineSetParams creates MachineSetParams based on the given machineSetParams with a Windows image,
+// the Windows user data secret and the Windows os-id label, so that the Windows Machine Config Operator
+// configures its machines. The image is WindowsImage, or a default Windows Server image where the platform has one.
+func BuildWindowsMachineSetParams(machineSetParams MachineSetParams, platform configv1.PlatformType) (MachineSetParams, error) {
+	windowsMachineSetParams := machineSetParams
+	baseProviderSpec := machineSetParams.ProviderSpec.DeepCopy()
+
+	var (
+		updatedProviderSpec machinev1.ProviderSpec
+		err                 error
+	)
+
+	switch platform {
+	case configv1.AWSPlatformType:
+		updatedProviderSpec, err = updateProviderSpecAWSWindowsImage(baseProviderSpec, WindowsImage)
+	case configv1.AzurePlatformType:
+		updatedProviderSpec, err = updateProviderSpecAzureWindowsImage(baseProviderSpec, WindowsImage)
+	case configv1.GCPPlatformType:
+		updatedProviderSpec, err = updateProviderSpecGCPWindowsImage(baseProviderSpec, WindowsImage)
+	default:
+		return MachineSetParams{}, fmt.Errorf("windows images for platform %s not set", platform)
+	}
+
+	if err != nil {
+		return MachineSetParams{}, fmt.Errorf("failed to update provider spec with a Windows image: %w", err)
+	}
+
+	windowsProviderSpec, err := UpdateProviderSpecUserDataSecret(&updatedProviderSpec, WindowsUserDataSecretName)
+	if err != nil {
+		return MachineSetParams{}, fmt.Errorf("failed to update provider spec with the Windows user data secret: %w", err)
+	}
+
+	windowsMachineSetParams.ProviderSpec = windowsProviderSpec
+	windowsMachineSetParams.Labels = maps.Clone(machineSetParams.Labels)
+	windowsMachineSetParams.Labels[WindowsOSIDLabel] = WindowsOSID
+
+	return windowsMachineSetParams, nil
+}
+
+// updateProviderSpecAWSWindowsImage creates a new ProviderSpec with the given AMI.
+func updateProviderSpecAWSWindowsImage(providerSpec *machinev1.ProviderSpec, ami string) (machinev1.ProviderSpec, error) {
+	if ami == "" {
+		return machinev1.ProviderSpec{}, ErrWindowsImageNotSet
+	}
+
+	var awsProviderConfig machinev1.AWSMachineProviderConfig
+	if err := json.Unmarshal(providerSpec.Value.Raw, &awsProviderConfig); err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	awsProviderConfig.AMI = machinev1.AWSResourceReference{ID: &ami}
+
+	updatedProviderSpec, err := json.Marshal(awsProviderConfig)
+	if err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	return machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}, nil
+}
+
+// updateProviderSpecAzureWindowsImage creates a new ProviderSpec with the given SKU of the Windows Server marketplace image.
+func updateProviderSpecAzureWindowsImage(providerSpec *machinev1.ProviderSpec, sku string) (machinev1.ProviderSpec, error) {
+	if sku == "" {
+		sku = defaultAzureWindowsSKU
+	}
+
+	var azureProviderConfig machinev1.AzureMachineProviderSpec
+	if err := json.Unmarshal(providerSpec.Value.Raw, &azureProviderConfig); err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	azureProviderConfig.Image = machinev1.Image{
+		Publisher: "MicrosoftWindowsServer",
+		Offer:     "WindowsServer",
+		SKU:       sku,
+		Version:   "latest",
+	}
+	azureProviderConfig.OSDisk.OSType = "Windows"
+
+	updatedProviderSpec, err := json.Marshal(azureProviderConfig)
+	if err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	return machinev1.ProviderSpec{
+		Value: &runtime.RawExtension{Raw: updatedProviderSpec},
+	}, nil
+}
+
+// updateProviderSpecGCPWindowsImage creates a new ProviderSpec booting from the given image.
+func updateProviderSpecGCPWindowsImage(providerSpec *machinev1.ProviderSpec, image string) (machinev1.ProviderSpec, error) {
+	if image == "" {
+		image = defaultGCPWindowsImage
+	}
+
+	updatedProviderSpec, err := UpdateProviderSpecGCPBootImage(providerSpec, image)
+	if err != nil {
+		return machinev1.ProviderSpec{}, err
+	}
+
+	return *updatedProviderSpec, nil
+}
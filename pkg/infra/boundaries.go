@@ -0,0 +1,174 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// boundaryLabelPrefix is the prefix of the labels and taints of the boundary MachineSet.
+	boundaryLabelPrefix = "boundary.e2e.openshift.io/"
+
+	// boundaryLabelCount is the number of labels of the boundary MachineSet. They are copied to the selector,
+	// the machine and the node, so the count is well above what a MachineSet usually has without bloating the node.
+	boundaryLabelCount = 100
+
+	// boundaryTaintCount is the number of taints of the boundary MachineSet.
+	boundaryTaintCount = 50
+
+	// boundaryPaddingAnnotation is the provider spec metadata annotation padding the provider spec.
+	boundaryPaddingAnnotation = "e2e.openshift.io/padding"
+
+	// boundaryPaddingSize is the size of the provider spec padding. The provider spec is stored in both the
+	// MachineSet and its machines, so it stays well under the 1.5MiB etcd request limit.
+	boundaryPaddingSize = 512 * 1024
+
+	// maxLabelNameLength and maxLabelValueLength are the maximum lengths of the name part and of the value of a label.
+	maxLabelNameLength  = 63
+	maxLabelValueLength = 63
+)
+
+var _ = Describe("MachineSet at the API boundaries", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, framework.LabelPlatforms(configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType, configv1.VSpherePlatformType), func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+	var machineSet *machinev1.MachineSet
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		machineSet = nil
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			if machineSet != nil {
+				Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+				framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+			}
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: The labels and taints are checked on the node of the machine.
+	It("should propagate many labels and taints and a large provider spec without truncating them", framework.LabelMachines(1), func() {
+		labels := boundaryLabels()
+		taints := boundaryTaints()
+		padding := strings.Repeat("x", boundaryPaddingSize)
+
+		By("Creating a MachineSet with many labels and taints and a large provider spec", func() {
+			var err error
+
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+
+			for key, value := range labels {
+				machineSetParams.Labels[key] = value
+			}
+
+			machineSetParams.Taints = append(machineSetParams.Taints, taints...)
+
+			machineSetParams.ProviderSpec, err = framework.UpdateProviderSpecMetadataAnnotations(machineSetParams.ProviderSpec, map[string]string{
+				boundaryPaddingAnnotation: padding,
+			})
+			Expect(err).ToNot(HaveOccurred(), "Should be able to pad the provider spec")
+
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be admitted")
+
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
+		Expect(machines).To(HaveLen(1), "MachineSet should have a single Machine")
+
+		machine := machines[0]
+
+		By("Checking the provider spec of the machine is not truncated", func() {
+			annotations, err := framework.GetProviderSpecMetadataAnnotations(machine.Spec.ProviderSpec)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to read the provider spec of the Machine")
+			Expect(annotations[boundaryPaddingAnnotation]).To(HaveLen(boundaryPaddingSize), "The provider spec padding should be intact")
+		})
+
+		By("Checking the labels and taints are propagated to the machine and its node", func() {
+			Expect(machine.Spec.Taints).To(ContainElements(taints), "The Machine should have all the taints")
+
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the Node of the Machine")
+
+			for key, value := range labels {
+				Expect(node.Labels).To(HaveKeyWithValue(key, value), "The Node should have all the labels")
+			}
+
+			for _, taint := range taints {
+				Expect(node.Spec.Taints).To(ContainElement(SatisfyAll(
+					HaveField("Key", taint.Key),
+					HaveField("Value", taint.Value),
+					HaveField("Effect", taint.Effect),
+				)), "The Node should have all the taints")
+			}
+		})
+	})
+})
+
+// boundaryLabels returns boundaryLabelCount labels whose names and values have the maximum length.
+func boundaryLabels() map[string]string {
+	labels := make(map[string]string, boundaryLabelCount)
+
+	for i := range boundaryLabelCount {
+		name := fmt.Sprintf("label-%03d-", i)
+		value := fmt.Sprintf("value-%03d-", i)
+		labels[boundaryLabelPrefix+padLabel(name, maxLabelNameLength)] = padLabel(value, maxLabelValueLength)
+	}
+
+	return labels
+}
+
+// boundaryTaints returns boundaryTaintCount PreferNoSchedule taints, so that they do not prevent the
+// node from running its daemonsets.
+func boundaryTaints() []corev1.Taint {
+	taints := make([]corev1.Taint, 0, boundaryTaintCount)
+
+	for i := range boundaryTaintCount {
+		taints = append(taints, corev1.Taint{
+			Key:    fmt.Sprintf("%staint-%03d", boundaryLabelPrefix, i),
+			Value:  padLabel(fmt.Sprintf("value-%03d-", i), maxLabelValueLength),
+			Effect: corev1.TaintEffectPreferNoSchedule,
+		})
+	}
+
+	return taints
+}
+
+// padLabel pads the given prefix to the given length with characters valid at the end of a label.
+func padLabel(prefix string, length int) string {
+	return prefix + strings.Repeat("a", length-len(prefix))
+}
@@ -3,6 +3,7 @@ package infra
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -12,25 +13,32 @@ import (
 	machinev1 "github.com/openshift/api/machine/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/chaos"
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/quota"
 )
 
-var nodeDrainLabels = map[string]string{
-	framework.WorkerNodeRoleLabel: "",
-	"node-draining-test":          string(uuid.NewUUID()),
+// ccmNodeInitializationDeadline bounds the time the cloud-controller-manager takes to initialize a new node.
+const ccmNodeInitializationDeadline = 5 * time.Minute
+
+func nodeDrainLabels() map[string]string {
+	return map[string]string{
+		framework.WorkerNodeRoleLabel: "",
+		"node-draining-test":          framework.Rand.String(16),
+	}
 }
 
-func replicationControllerWorkload(namespace string) *corev1.ReplicationController {
+func replicationControllerWorkload(namespace string, nodeSelector map[string]string) *corev1.ReplicationController {
 	var replicas int32 = 20
 
 	return &corev1.ReplicationController{
@@ -64,7 +72,7 @@ func replicationControllerWorkload(namespace string) *corev1.ReplicationControll
 							},
 						},
 					},
-					NodeSelector: nodeDrainLabels,
+					NodeSelector: nodeSelector,
 					Tolerations: []corev1.Toleration{
 						{
 							Key:      "kubemark",
@@ -77,9 +85,7 @@ func replicationControllerWorkload(namespace string) *corev1.ReplicationControll
 	}
 }
 
-func podDisruptionBudget(namespace string) *policyv1.PodDisruptionBudget {
-	maxUnavailable := intstr.FromInt(1)
-
+func podDisruptionBudget(namespace string, maxUnavailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
 	return &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "nginx-pdb",
@@ -127,18 +133,18 @@ func invalidMachinesetWithEmptyProviderConfig() *machinev1.MachineSet {
 	}
 }
 
-func deleteObject(client runtimeclient.Client, obj runtimeclient.Object) error {
+func deleteObject(ctx context.Context, client runtimeclient.Client, obj runtimeclient.Object) error {
 	cascadeDelete := metav1.DeletePropagationForeground
 
-	return client.Delete(context.TODO(), obj, &runtimeclient.DeleteOptions{
+	return client.Delete(ctx, obj, &runtimeclient.DeleteOptions{
 		PropagationPolicy: &cascadeDelete,
 	})
 }
 
-func deleteObjects(client runtimeclient.Client, delObjects map[string]runtimeclient.Object) error {
+func deleteObjects(ctx context.Context, client runtimeclient.Client, delObjects map[string]runtimeclient.Object) error {
 	// Remove resources
 	for _, obj := range delObjects {
-		if err := deleteObject(client, obj); err != nil {
+		if err := deleteObject(ctx, client, obj); err != nil {
 			klog.Errorf("[cleanup] error deleting object: %v", err)
 			return err
 		}
@@ -147,6 +153,37 @@ func deleteObjects(client runtimeclient.Client, delObjects map[string]runtimecli
 	return nil
 }
 
+// removeNodeLabels removes the given labels from the machine and then from its node, as they outlive the spec
+// on the shared MachineSet. The labels are removed from the machine first, so that they are not reconciled back
+// onto the node. The machine and its node may already have been deleted by the spec.
+func removeNodeLabels(ctx context.Context, client runtimeclient.Client, machine *machinev1.Machine, nodeLabels map[string]string) error {
+	current := &machinev1.Machine{}
+	if err := client.Get(ctx, runtimeclient.ObjectKeyFromObject(machine), current); err != nil {
+		return runtimeclient.IgnoreNotFound(err)
+	}
+
+	patch := runtimeclient.MergeFrom(current.DeepCopy())
+	for key := range nodeLabels {
+		delete(current.Spec.ObjectMeta.Labels, key)
+	}
+
+	if err := client.Patch(ctx, current, patch); err != nil {
+		return runtimeclient.IgnoreNotFound(err)
+	}
+
+	node, err := framework.GetNodeForMachine(ctx, client, current)
+	if err != nil {
+		return runtimeclient.IgnoreNotFound(err)
+	}
+
+	nodePatch := runtimeclient.MergeFrom(node.DeepCopy())
+	for key := range nodeLabels {
+		delete(node.Labels, key)
+	}
+
+	return runtimeclient.IgnoreNotFound(client.Patch(ctx, node, nodePatch))
+}
+
 var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 	var client runtimeclient.Client
 	var ctx context.Context
@@ -186,22 +223,27 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 		}
 	})
 
-	When("machineset has one replica", framework.LabelDisruptive, func() {
+	When("machineset has one replica", framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+		// The specs only need a running machine, so they may run on the shared MachineSet.
+		var runningMachineSet *machinev1.MachineSet
+
 		BeforeEach(func() {
 			var err error
+			var release func()
 			machineSetParams = framework.BuildMachineSetParams(ctx, client, 1)
 
-			By("Creating a new MachineSet")
-			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
-			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+			By("Acquiring a MachineSet")
+			runningMachineSet, release, err = framework.AcquireMachineSet(ctx, client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be acquired")
+			DeferCleanup(release)
 
-			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+			framework.WaitForMachineSet(ctx, client, runningMachineSet.GetName())
 		})
 
 		// Machines required for test: 1
 		// Reason: This test works on a single machine and its node.
-		It("have ability to additively reconcile taints from machine to nodes", func() {
-			selector := machineSet.Spec.Selector
+		It("have ability to additively reconcile taints from machine to nodes", framework.LabelMachines(1), func() {
+			selector := runningMachineSet.Spec.Selector
 			machines, err := framework.GetMachines(ctx, client, &selector)
 			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
 			Expect(machines).ToNot(BeEmpty(), "The list of Machines should not be empty")
@@ -219,29 +261,54 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 				Effect: corev1.TaintEffectNoSchedule,
 			}
 			By(fmt.Sprintf("updating node %q with taint: %v", node.Name, nodeTaint))
-			for {
+			// The merge patch carries no resource version, so it cannot conflict with the kubelet updating the node.
+			if !slices.ContainsFunc(node.Spec.Taints, func(t corev1.Taint) bool { return t.MatchTaint(&nodeTaint) }) {
+				patch := runtimeclient.MergeFrom(node.DeepCopy())
 				node.Spec.Taints = append(node.Spec.Taints, nodeTaint)
-				err = client.Update(ctx, node)
-				if !apierrors.IsConflict(err) {
-					break
-				}
+				Expect(client.Patch(ctx, node, patch)).To(Succeed(), "Node update should succeed")
 			}
-			Expect(err).NotTo(HaveOccurred(), "Node update should succeed")
 
 			machineTaint := corev1.Taint{
-				Key:    fmt.Sprintf("from-machine-%v", string(uuid.NewUUID())),
+				Key:    framework.Names.GenerateName("from-machine-"),
 				Value:  "true",
 				Effect: corev1.TaintEffectNoSchedule,
 			}
-			By(fmt.Sprintf("updating machine %q with taint: %v", machine.Name, machineTaint))
-			for {
-				machine.Spec.Taints = append(machine.Spec.Taints, machineTaint)
-				err = client.Update(ctx, machine)
-				if !apierrors.IsConflict(err) {
-					break
+
+			// The node of a shared MachineSet outlives the spec, so the NoSchedule taints must not be left on it.
+			// The machine taint is removed from the machine first, so that it is not reconciled back onto the node.
+			DeferCleanup(func() {
+				untaintedMachine := &machinev1.Machine{
+					ObjectMeta: metav1.ObjectMeta{Name: machine.Name, Namespace: machine.Namespace},
+					Spec: machinev1.MachineSpec{
+						Taints: machine.Spec.Taints,
+					},
 				}
+				Expect(framework.PatchMachine(ctx, client, untaintedMachine)).To(Succeed(), "Should be able to remove the taint of the Machine")
+
+				Eventually(func() error {
+					current, err := framework.GetNodeForMachine(ctx, client, machine)
+					if err != nil {
+						return err
+					}
+
+					// The taints are a list replaced as a whole, so the patch is rejected if they changed meanwhile.
+					patch := runtimeclient.MergeFromWithOptions(current.DeepCopy(), runtimeclient.MergeFromWithOptimisticLock{})
+					current.Spec.Taints = slices.DeleteFunc(current.Spec.Taints, func(t corev1.Taint) bool {
+						return t.MatchTaint(&nodeTaint) || t.MatchTaint(&machineTaint)
+					})
+
+					return client.Patch(ctx, current, patch)
+				}, framework.WaitShort, framework.RetryShort).Should(Succeed(), "Should be able to remove the taints of the Node")
+			})
+			By(fmt.Sprintf("updating machine %q with taint: %v", machine.Name, machineTaint))
+			// The taints of a Machine are an atomic list, applied along with the taints it already has.
+			taintedMachine := &machinev1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: machine.Name, Namespace: machine.Namespace},
+				Spec: machinev1.MachineSpec{
+					Taints: append(slices.Clone(machine.Spec.Taints), machineTaint),
+				},
 			}
-			Expect(err).NotTo(HaveOccurred(), "Machine update should succeed")
+			Expect(framework.PatchMachine(ctx, client, taintedMachine)).To(Succeed(), "Machine update should succeed")
 
 			var expectedTaints = sets.NewString("not-from-machine", machineTaint.Key)
 			Eventually(func() bool {
@@ -262,14 +329,235 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 
 				return false
 			}, framework.WaitMedium, 5*time.Second).Should(BeTrue(), "Should find all the expected taints on the Node")
+
+			Expect(framework.ExpectNoFieldManagerConflicts(ctx, client, taintedMachine)).To(Succeed(),
+				"The taints of the Machine should not have been reverted by another field manager")
+		})
+
+		// Machines required for test: 1
+		// Reason: This test works on a single machine and its node.
+		It("have ability to additively reconcile labels from machine to nodes", framework.LabelMachines(1), func() {
+			selector := runningMachineSet.Spec.Selector
+			machines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
+			Expect(machines).ToNot(BeEmpty(), "The list of Machines should not be empty")
+
+			machine := machines[0]
+			labelKey := framework.Names.GenerateName("from-machine-")
+			labelValue := framework.Rand.String(16)
+			nodeLabels := map[string]string{labelKey: labelValue}
+
+			// The node of a shared MachineSet outlives the spec, so the labels must not be left on it.
+			DeferCleanup(func() {
+				Expect(removeNodeLabels(ctx, client, machine, nodeLabels)).To(Succeed(), "Should be able to remove the labels of the Machine and its Node")
+			})
+
+			By(fmt.Sprintf("updating machine %q with labels: %v", machine.Name, nodeLabels))
+			// The merge patch only adds the labels, so it cannot conflict with the controllers updating the machine.
+			patch := runtimeclient.MergeFrom(machine.DeepCopy())
+			if machine.Spec.ObjectMeta.Labels == nil {
+				machine.Spec.ObjectMeta.Labels = map[string]string{}
+			}
+
+			machine.Spec.ObjectMeta.Labels[labelKey] = labelValue
+			Expect(client.Patch(ctx, machine, patch)).To(Succeed(), "Machine update should succeed")
+
+			Eventually(func() (map[string]string, error) {
+				node, err := framework.GetNodeForMachine(ctx, client, machine)
+				if err != nil {
+					return nil, err
+				}
+
+				return node.Labels, nil
+			}, framework.WaitMedium, framework.RetryMedium).Should(SatisfyAll(
+				HaveKey(framework.WorkerNodeRoleLabel),
+				HaveKeyWithValue(labelKey, labelValue),
+			), "Node should have the labels of the Machine along with its own")
+		})
+
+		// Machines required for test: 1
+		// Reason: This test only inspects the addresses of a single machine and its node.
+		It("report the node internal IPs of every cluster IP family on the machine", framework.LabelMachines(1), func() {
+			ipFamilies, err := framework.GetClusterIPFamilies(ctx, client)
+			Expect(err).NotTo(HaveOccurred(), "Should be able to get the cluster IP families")
+
+			selector := runningMachineSet.Spec.Selector
+			machines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
+			Expect(machines).ToNot(BeEmpty(), "The list of Machines should not be empty")
+
+			By(fmt.Sprintf("checking the addresses of machine %q for the IP families %v", machines[0].Name, ipFamilies))
+			Eventually(func() error {
+				machine, err := framework.GetMachine(client, machines[0].Name)
+				if err != nil {
+					return err
+				}
+
+				node, err := framework.GetNodeForMachine(ctx, client, machine)
+				if err != nil {
+					return err
+				}
+
+				return framework.CheckMachineAddresses(machine, node, ipFamilies)
+			}, framework.WaitShort, framework.RetryShort).Should(Succeed(), "Machine should report an internal IP of each cluster IP family")
 		})
 
 	})
 
-	When("machineset has 2 replicas", framework.LabelDisruptive, func() {
+	When("a new machine joins the cluster", framework.LabelDisruptive, framework.LabelRequiresMachineManagement, framework.LabelPlatforms(configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType, configv1.VSpherePlatformType), func() {
+		// Machines required for test: 1
+		// Reason: The initialization is checked on the node of a new machine, from its creation.
+		It("have its node initialized by the cloud-controller-manager", framework.LabelMachines(1), func() {
+			var err error
+
+			machineSetParams = framework.BuildMachineSetParams(ctx, client, 1)
+
+			By("Creating a new MachineSet")
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet creation should succeed")
+
+			By("Waiting for the machine to be linked to its node")
+			var machine *machinev1.Machine
+			var node *corev1.Node
+			Eventually(func() error {
+				machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+				if err != nil {
+					return err
+				}
+
+				if len(machines) != 1 {
+					return fmt.Errorf("expected 1 machine, got %d", len(machines))
+				}
+
+				machine = machines[0]
+				node, err = framework.GetNodeForMachine(ctx, client, machine)
+
+				return err
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Machine should be linked to its node")
+
+			// The deadline starts with the node, so that a slow provisioning is not attributed to the cloud-controller-manager.
+			deadline := node.CreationTimestamp.Add(ccmNodeInitializationDeadline)
+
+			By(fmt.Sprintf("Checking the cloud-controller-manager initializes node %q within %s of its creation", node.Name, ccmNodeInitializationDeadline))
+			Eventually(func() error {
+				machine, err := framework.GetMachine(client, machine.Name)
+				if err != nil {
+					return err
+				}
+
+				node, err := framework.GetNodeForMachine(ctx, client, machine)
+				if err != nil {
+					return err
+				}
+
+				return framework.CheckNodeCloudInitialization(machine, node)
+			}, max(time.Until(deadline), framework.RetryShort), framework.RetryShort).Should(Succeed(),
+				"The cloud-controller-manager should initialize the node within %s of its creation", ccmNodeInitializationDeadline)
+
+			AddReportEntry("time to node initialization by the cloud-controller-manager", time.Since(node.CreationTimestamp.Time))
+
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+	})
+
+	When("machineset has 2 running replicas", framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+		// The spec only needs running machines, so it may run on the shared MachineSet, scaled up to 2 replicas.
+		var runningMachineSet *machinev1.MachineSet
+
 		BeforeEach(func() {
 			var err error
+			var release func()
 			machineSetParams = framework.BuildMachineSetParams(ctx, client, 2)
+			quota.SkipIfInsufficientQuota(ctx, client, machineSetParams, 2)
+
+			By("Acquiring a MachineSet")
+			runningMachineSet, release, err = framework.AcquireMachineSet(ctx, client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be acquired")
+			DeferCleanup(release)
+
+			framework.WaitForMachineSet(ctx, client, runningMachineSet.GetName())
+		})
+
+		// Machines required for test: 2 (3 but it gets deleted without waiting for it to be ready)
+		// Reason: Pods are spread across both machines. After one is deleted, the pods are rescheduled onto the other machine.
+		It("drain node before removing machine resource", framework.LabelMachines(2), func() {
+			By("Create a machine for node about to be drained")
+
+			selector := runningMachineSet.Spec.Selector
+			machines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to List Machines")
+			Expect(len(machines)).To(BeNumerically(">=", 2), "Should have found at least 2 Machines")
+
+			// Add node draining labels to the machines, propagated to their nodes.
+			// The merge patch only adds the labels, so it cannot conflict with the controllers updating the machines.
+			drainLabels := nodeDrainLabels()
+			for _, machine := range machines[:2] {
+				patch := runtimeclient.MergeFrom(machine.DeepCopy())
+				if machine.Spec.ObjectMeta.Labels == nil {
+					machine.Spec.ObjectMeta.Labels = map[string]string{}
+				}
+
+				for k, v := range drainLabels {
+					machine.Spec.ObjectMeta.Labels[k] = v
+				}
+
+				Expect(client.Patch(ctx, machine, patch)).To(Succeed(), "Should be able to update Machine")
+
+				DeferCleanup(func() {
+					Expect(removeNodeLabels(ctx, client, machine, drainLabels)).To(Succeed(), "Should be able to remove the node draining labels")
+				})
+			}
+
+			// Make sure RC and PDB get deleted anyway
+			delObjects := make(map[string]runtimeclient.Object)
+
+			defer func() {
+				Expect(deleteObjects(ctx, client, delObjects)).To(Succeed(), "Should be able to cleanup test objects")
+			}()
+
+			By("Creating RC with workload")
+
+			// Use the openshift-machine-api namespace as it is excluded from
+			// Pod security admission checks.
+			namespace := framework.MachineAPINamespace
+
+			rc := replicationControllerWorkload(namespace, drainLabels)
+			Expect(client.Create(ctx, rc)).To(Succeed(), "Should be able to create ReplicationController")
+			delObjects["rc"] = rc
+
+			By("Creating PDB for RC")
+			pdb := podDisruptionBudget(namespace, intstr.FromInt(1))
+			Expect(client.Create(ctx, pdb)).To(Succeed(), "Should be able to create PodDisruptionBudget")
+			delObjects["pdb"] = pdb
+
+			By("Wait until all replicas are ready")
+			Expect(framework.WaitUntilAllRCPodsAreReady(ctx, client, rc)).To(Succeed(), "Should wait until all Pod replicas are ready")
+
+			// TODO(jchaloup): delete machine that has at least half of the RC pods
+
+			// All pods are distributed evenly among all nodes so it's fine to drain
+			// random node and observe reconciliation of pods on the other one.
+			By("Delete machine to trigger node draining")
+			Expect(client.Delete(ctx, machines[0])).To(Succeed(), "Should be able to Delete Machine")
+
+			// We still should be able to list the machine as until rc.replicas-1 are running on the other node
+			By("Observing and verifying node draining")
+			drainedNodeName, err := framework.VerifyNodeDraining(ctx, client, machines[0], rc)
+			Expect(err).NotTo(HaveOccurred(), "Should verify Node was drained")
+
+			By("Validating the machine is deleted")
+			framework.WaitForMachinesDeleted(ctx, client, machines[0])
+
+			By("Validate underlying node corresponding to machine1 is removed as well")
+			Expect(framework.WaitUntilNodeDoesNotExists(ctx, client, drainedNodeName)).To(Succeed(), "Should wait until Node does not exit")
+		})
+	})
+
+	When("machineset has 2 replicas", framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+		BeforeEach(func() {
+			var err error
+			machineSetParams = framework.BuildMachineSetParams(ctx, client, 2)
+			quota.SkipIfInsufficientQuota(ctx, client, machineSetParams, 2)
 
 			By("Creating a new MachineSet")
 			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
@@ -280,22 +568,27 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 
 		// Machines required for test: 2
 		// Reason: We want to test that all machines get replaced when we delete them.
-		It("recover from deleted worker machines", framework.LabelLEVEL0, func() {
+		It("recover from deleted worker machines", framework.LabelMachines(2), framework.LabelLEVEL0, framework.LabelChaos, func() {
 			selector := machineSet.Spec.Selector
 			machines, err := framework.GetMachines(ctx, client, &selector)
 			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
 			Expect(machines).ToNot(BeEmpty(), "The list of Machines should not be empty")
 
+			stopChaos := chaos.During(ctx, client, chaos.MachineAPIControllers)
+
 			By("deleting all machines")
 			Expect(framework.DeleteMachines(ctx, client, machines...)).To(Succeed(), "Should be able to delete all Machines")
-			framework.WaitForMachinesDeleted(client, machines...)
+			framework.WaitForMachinesDeleted(ctx, client, machines...)
 
 			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+			stopChaos()
+			chaos.ExpectIdempotentMachineSet(ctx, client, machineSet)
 		})
 
 		// Machines required for test: 4
 		// Reason: MachineSet scales 2->0 and MachineSet2 scales 0->2. Changing to scaling 1->0 and 0->1 might not test this thoroughly.
-		It("grow and decrease when scaling different machineSets simultaneously", framework.LabelPeriodic, framework.LabelLEVEL0, func() {
+		It("grow and decrease when scaling different machineSets simultaneously", framework.LabelMachines(4), framework.LabelPeriodic, framework.LabelLEVEL0, framework.LabelChaos, func() {
 			By("Creating a second MachineSet") // Machineset 1 can start with 1 replica
 			machineSetParams := framework.BuildMachineSetParams(ctx, client, 0)
 			machineSet2, err := framework.CreateMachineSet(client, machineSetParams)
@@ -304,46 +597,51 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 			// Make sure second machineset gets deleted anyway
 			defer func() {
 				By("Deleting the second MachineSet")
-				Expect(deleteObject(client, machineSet2)).To(Succeed(), "Should be able to delete MachineSet")
+				Expect(deleteObject(ctx, client, machineSet2)).To(Succeed(), "Should be able to delete MachineSet")
 				framework.WaitForMachineSetsDeleted(ctx, client, machineSet2)
 			}()
 
 			framework.WaitForMachineSet(ctx, client, machineSet2.GetName())
 
+			stopChaos := chaos.During(ctx, client, chaos.MachineAPIControllers)
+
 			Expect(framework.ScaleMachineSet(machineSet.GetName(), 0)).To(Succeed(), "Should be able to scale down MachineSet")
 			Expect(framework.ScaleMachineSet(machineSet2.GetName(), 1)).To(Succeed(), "Should be able to scale MachineSet")
 
 			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
 			framework.WaitForMachineSet(ctx, client, machineSet2.GetName())
-		})
 
-		// Machines required for test: 2 (3 but it gets deleted without waiting for it to be ready)
-		// Reason: Pods are spread across both machines. After one is deleted, the pods are rescheduled onto the other machine.
-		It("drain node before removing machine resource", func() {
-			By("Create a machine for node about to be drained")
+			stopChaos()
+			chaos.ExpectIdempotentMachineSet(ctx, client, machineSet)
+			chaos.ExpectIdempotentMachineSet(ctx, client, machineSet2)
+		})
 
+		// Machines required for test: 2
+		// Reason: Pods are spread across both machines. Once the PDB is relaxed, the pods of the drained node are rescheduled onto the other machine.
+		It("wait for a PodDisruptionBudget blocking eviction before removing machine resource", framework.LabelMachines(2), func() {
 			selector := machineSet.Spec.Selector
 			machines, err := framework.GetMachines(ctx, client, &selector)
 			Expect(err).ToNot(HaveOccurred(), "Should be able to List Machines")
 			Expect(len(machines)).To(BeNumerically(">=", 2), "Should have found at least 2 Machines")
 
 			// Add node draining labels to params
-			for k, v := range nodeDrainLabels {
+			drainLabels := nodeDrainLabels()
+			for k, v := range drainLabels {
 				machineSetParams.Labels[k] = v
 			}
 
 			machines[0].Spec.ObjectMeta.Labels = machineSetParams.Labels
 			machines[1].Spec.ObjectMeta.Labels = machineSetParams.Labels
 
-			Expect(client.Update(context.TODO(), machines[0])).To(Succeed(), "Should be able to update Machine")
+			Expect(client.Update(ctx, machines[0])).To(Succeed(), "Should be able to update Machine")
 
-			Expect(client.Update(context.TODO(), machines[1])).To(Succeed(), "Should be able to update Machine")
+			Expect(client.Update(ctx, machines[1])).To(Succeed(), "Should be able to update Machine")
 
 			// Make sure RC and PDB get deleted anyway
 			delObjects := make(map[string]runtimeclient.Object)
 
 			defer func() {
-				Expect(deleteObjects(client, delObjects)).To(Succeed(), "Should be able to cleanup test objects")
+				Expect(deleteObjects(ctx, client, delObjects)).To(Succeed(), "Should be able to cleanup test objects")
 			}()
 
 			By("Creating RC with workload")
@@ -352,41 +650,393 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 			// Pod security admission checks.
 			namespace := framework.MachineAPINamespace
 
-			rc := replicationControllerWorkload(namespace)
-			Expect(client.Create(context.TODO(), rc)).To(Succeed(), "Should be able to create ReplicationController")
+			rc := replicationControllerWorkload(namespace, drainLabels)
+			Expect(client.Create(ctx, rc)).To(Succeed(), "Should be able to create ReplicationController")
 			delObjects["rc"] = rc
 
-			By("Creating PDB for RC")
-			pdb := podDisruptionBudget(namespace)
-			Expect(client.Create(context.TODO(), pdb)).To(Succeed(), "Should be able to create PodDisruptionBudget")
+			By("Creating PDB for RC which does not allow any disruption")
+			pdb := podDisruptionBudget(namespace, intstr.FromInt(0))
+			Expect(client.Create(ctx, pdb)).To(Succeed(), "Should be able to create PodDisruptionBudget")
 			delObjects["pdb"] = pdb
 
 			By("Wait until all replicas are ready")
 			Expect(framework.WaitUntilAllRCPodsAreReady(ctx, client, rc)).To(Succeed(), "Should wait until all Pod replicas are ready")
 
-			// TODO(jchaloup): delete machine that has at least half of the RC pods
-
-			// All pods are distributed evenly among all nodes so it's fine to drain
-			// random node and observe reconciliation of pods on the other one.
 			By("Delete machine to trigger node draining")
-			Expect(client.Delete(context.TODO(), machines[0])).To(Succeed(), "Should be able to Delete Machine")
+			Expect(client.Delete(ctx, machines[0])).To(Succeed(), "Should be able to Delete Machine")
+
+			By("Verifying the machine deletion waits for the blocked drain")
+			framework.WaitForMachineDrainBlocked(ctx, client, machines[0])
+			framework.ExpectMachineDrainBlocked(ctx, client, machines[0], framework.WaitShort)
+
+			By("Relaxing the PDB to allow the eviction of the pods")
+			patch := runtimeclient.MergeFrom(pdb.DeepCopy())
+			maxUnavailable := intstr.FromInt(1)
+			pdb.Spec.MaxUnavailable = &maxUnavailable
+			Expect(client.Patch(ctx, pdb, patch)).To(Succeed(), "Should be able to patch PodDisruptionBudget")
 
-			// We still should be able to list the machine as until rc.replicas-1 are running on the other node
 			By("Observing and verifying node draining")
 			drainedNodeName, err := framework.VerifyNodeDraining(ctx, client, machines[0], rc)
 			Expect(err).NotTo(HaveOccurred(), "Should verify Node was drained")
 
 			By("Validating the machine is deleted")
-			framework.WaitForMachinesDeleted(client, machines[0])
+			framework.WaitForMachinesDeleted(ctx, client, machines[0])
+
+			By("Validate underlying node corresponding to machine1 is removed as well")
+			Expect(framework.WaitUntilNodeDoesNotExists(ctx, client, drainedNodeName)).To(Succeed(), "Should wait until Node does not exit")
+		})
+
+		// Machines required for test: 2
+		// Reason: Pods are spread across both machines. The machine controller is restarted while the drain of one is blocked by a PDB.
+		It("resume draining the node after the machine controller restarts", framework.LabelMachines(2), Serial, func() {
+			selector := machineSet.Spec.Selector
+			machines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to List Machines")
+			Expect(len(machines)).To(BeNumerically(">=", 2), "Should have found at least 2 Machines")
+
+			// Add node draining labels to params
+			drainLabels := nodeDrainLabels()
+			for k, v := range drainLabels {
+				machineSetParams.Labels[k] = v
+			}
+
+			machines[0].Spec.ObjectMeta.Labels = machineSetParams.Labels
+			machines[1].Spec.ObjectMeta.Labels = machineSetParams.Labels
+
+			Expect(client.Update(ctx, machines[0])).To(Succeed(), "Should be able to update Machine")
+
+			Expect(client.Update(ctx, machines[1])).To(Succeed(), "Should be able to update Machine")
+
+			// Make sure RC and PDB get deleted anyway
+			delObjects := make(map[string]runtimeclient.Object)
+
+			defer func() {
+				Expect(deleteObjects(ctx, client, delObjects)).To(Succeed(), "Should be able to cleanup test objects")
+			}()
+
+			By("Creating RC with workload")
+
+			// Use the openshift-machine-api namespace as it is excluded from
+			// Pod security admission checks.
+			namespace := framework.MachineAPINamespace
+
+			rc := replicationControllerWorkload(namespace, drainLabels)
+			Expect(client.Create(ctx, rc)).To(Succeed(), "Should be able to create ReplicationController")
+			delObjects["rc"] = rc
+
+			By("Creating PDB for RC which does not allow any disruption")
+			pdb := podDisruptionBudget(namespace, intstr.FromInt(0))
+			Expect(client.Create(ctx, pdb)).To(Succeed(), "Should be able to create PodDisruptionBudget")
+			delObjects["pdb"] = pdb
+
+			By("Wait until all replicas are ready")
+			Expect(framework.WaitUntilAllRCPodsAreReady(ctx, client, rc)).To(Succeed(), "Should wait until all Pod replicas are ready")
+
+			By("Delete machine to trigger node draining")
+			Expect(client.Delete(ctx, machines[0])).To(Succeed(), "Should be able to Delete Machine")
+			framework.WaitForMachineDrainBlocked(ctx, client, machines[0])
+
+			By("Restarting the machine controller while the drain is in progress")
+			Expect(framework.RestartMachineAPIControllers(ctx, client)).To(Succeed(), "Should be able to restart the machine controller")
+
+			By("Verifying the restarted machine controller still waits for the blocked drain")
+			framework.ExpectMachineDrainBlocked(ctx, client, machines[0], framework.WaitShort)
+
+			By("Relaxing the PDB to allow the eviction of the pods")
+			patch := runtimeclient.MergeFrom(pdb.DeepCopy())
+			maxUnavailable := intstr.FromInt(1)
+			pdb.Spec.MaxUnavailable = &maxUnavailable
+			Expect(client.Patch(ctx, pdb, patch)).To(Succeed(), "Should be able to patch PodDisruptionBudget")
+
+			By("Observing and verifying the drain resumes")
+			drainedNodeName, err := framework.VerifyNodeDraining(ctx, client, machines[0], rc)
+			Expect(err).NotTo(HaveOccurred(), "Should verify Node was drained")
+
+			By("Validating the machine is deleted")
+			framework.WaitForMachinesDeleted(ctx, client, machines[0])
 
 			By("Validate underlying node corresponding to machine1 is removed as well")
 			Expect(framework.WaitUntilNodeDoesNotExists(ctx, client, drainedNodeName)).To(Succeed(), "Should wait until Node does not exit")
+
+			By("Validating the cloud instance of the machine is not orphaned")
+			framework.WaitForCloudInstancesDeleted(ctx, client, machines[0])
+		})
+
+		// Machines required for test: 2
+		// Reason: The workload runs on one machine, which is deleted without draining and replaced by the MachineSet.
+		It("remove machine resource without draining its node when excluded from draining", framework.LabelMachines(2), func() {
+			selector := machineSet.Spec.Selector
+			machines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to List Machines")
+			Expect(machines).ToNot(BeEmpty(), "Should have found at least 1 Machine")
+
+			machine := machines[0]
+
+			By(fmt.Sprintf("Annotating machine %q to exclude its node from draining", machine.Name))
+			drainLabels := nodeDrainLabels()
+			for k, v := range drainLabels {
+				machineSetParams.Labels[k] = v
+			}
+
+			machine.Spec.ObjectMeta.Labels = machineSetParams.Labels
+			if machine.Annotations == nil {
+				machine.Annotations = map[string]string{}
+			}
+			machine.Annotations[framework.ExcludeNodeDrainingAnnotation] = ""
+			Expect(client.Update(ctx, machine)).To(Succeed(), "Should be able to update Machine")
+
+			// Make sure RC and PDB get deleted anyway
+			delObjects := make(map[string]runtimeclient.Object)
+
+			defer func() {
+				Expect(deleteObjects(ctx, client, delObjects)).To(Succeed(), "Should be able to cleanup test objects")
+			}()
+
+			By("Creating RC with workload on the excluded machine")
+
+			// Use the openshift-machine-api namespace as it is excluded from
+			// Pod security admission checks.
+			namespace := framework.MachineAPINamespace
+
+			rc := replicationControllerWorkload(namespace, drainLabels)
+			Expect(client.Create(ctx, rc)).To(Succeed(), "Should be able to create ReplicationController")
+			delObjects["rc"] = rc
+
+			// The PDB prevents any eviction, so the machine can only be removed if its node is not drained.
+			By("Creating PDB for RC which does not allow any disruption")
+			pdb := podDisruptionBudget(namespace, intstr.FromInt(0))
+			Expect(client.Create(ctx, pdb)).To(Succeed(), "Should be able to create PodDisruptionBudget")
+			delObjects["pdb"] = pdb
+
+			By("Wait until all replicas are ready")
+			Expect(framework.WaitUntilAllRCPodsAreReady(ctx, client, rc)).To(Succeed(), "Should wait until all Pod replicas are ready")
+
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			Expect(err).NotTo(HaveOccurred(), "Should be able to retrieve Node from its Machine")
+
+			By("Delete machine excluded from draining")
+			Expect(client.Delete(ctx, machine)).To(Succeed(), "Should be able to Delete Machine")
+
+			By("Validating the machine is deleted despite the PDB")
+			framework.WaitForMachinesDeleted(ctx, client, machine)
+
+			By("Validate underlying node is removed with its pods")
+			Expect(framework.WaitUntilNodeDoesNotExists(ctx, client, node.Name)).To(Succeed(), "Should wait until Node does not exit")
+
+			Eventually(ctx, func() ([]corev1.Pod, error) {
+				pods := corev1.PodList{}
+				if err := client.List(ctx, &pods, runtimeclient.InNamespace(namespace), runtimeclient.MatchingFields{"spec.nodeName": node.Name}); err != nil {
+					return nil, err
+				}
+
+				return pods.Items, nil
+			}, framework.WaitMedium, framework.RetryMedium).Should(BeEmpty(), "Pods of the removed Node should be gone")
 		})
+
+		// Machines required for test: 3
+		// Reason: The MachineSet surges by one machine while its 2 machines are replaced one at a time.
+		It("keep a workload running while rolling its machines to another instance type", framework.LabelMachines(3), func() {
+			platform, err := framework.GetPlatform(ctx, client)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get Platform type")
+
+			if _, err := framework.GetProviderSpecInstanceType(machineSet.Spec.Template.Spec.ProviderSpec, platform); err != nil {
+				Skip(fmt.Sprintf("Skipping the instance type change: %v.", err))
+			}
+
+			selector := machineSet.Spec.Selector
+			oldMachines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
+
+			// Make sure RC and PDB get deleted anyway
+			delObjects := make(map[string]runtimeclient.Object)
+
+			defer func() {
+				Expect(deleteObjects(ctx, client, delObjects)).To(Succeed(), "Should be able to cleanup test objects")
+			}()
+
+			By("Creating RC with workload on the nodes of the MachineSet")
+
+			// Use the openshift-machine-api namespace as it is excluded from
+			// Pod security admission checks.
+			namespace := framework.MachineAPINamespace
+
+			rc := replicationControllerWorkload(namespace, map[string]string{framework.MachineSetKey: machineSet.GetName()})
+			Expect(client.Create(ctx, rc)).To(Succeed(), "Should be able to create ReplicationController")
+			delObjects["rc"] = rc
+
+			By("Creating PDB for RC")
+			pdb := podDisruptionBudget(namespace, intstr.FromInt(1))
+			Expect(client.Create(ctx, pdb)).To(Succeed(), "Should be able to create PodDisruptionBudget")
+			delObjects["pdb"] = pdb
+
+			By("Wait until all replicas are ready")
+			Expect(framework.WaitUntilAllRCPodsAreReady(ctx, client, rc)).To(Succeed(), "Should wait until all Pod replicas are ready")
+
+			By("Rolling the MachineSet to another instance type")
+			Expect(framework.RollMachineSet(ctx, client, machineSet, framework.WithAlternativeInstanceType(platform))).To(Succeed(),
+				"Should be able to roll the MachineSet")
+
+			By("Checking the machines were all replaced with the new instance type")
+			rolled, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be retrievable")
+
+			instanceType, err := framework.GetProviderSpecInstanceType(rolled.Spec.Template.Spec.ProviderSpec, platform)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the instance type of the MachineSet")
+
+			machines, err := framework.GetMachines(ctx, client, &selector)
+			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
+			Expect(machines).To(HaveLen(len(oldMachines)), "The MachineSet should have as many machines as before the roll")
+
+			for _, machine := range machines {
+				Expect(oldMachines).ToNot(ContainElement(HaveField("Name", machine.Name)), "Machine %s should have been replaced", machine.Name)
+
+				machineInstanceType, err := framework.GetProviderSpecInstanceType(machine.Spec.ProviderSpec, platform)
+				Expect(err).ToNot(HaveOccurred(), "Should be able to get the instance type of machine %s", machine.Name)
+				Expect(machineInstanceType).To(Equal(instanceType), "Machine %s should use the new instance type", machine.Name)
+			}
+
+			By("Checking the workload survived the roll")
+			Expect(framework.WaitUntilAllRCPodsAreReady(ctx, client, rc)).To(Succeed(), "Should wait until all Pod replicas are ready")
+		})
+	})
+
+	When("machineset has 3 replicas", framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+		BeforeEach(func() {
+			var err error
+			machineSetParams = framework.BuildMachineSetParams(ctx, client, 3)
+			quota.SkipIfInsufficientQuota(ctx, client, machineSetParams, 3)
+
+			By("Creating a new MachineSet")
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet creation should succeed")
+
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+
+		// Machines required for test: 3
+		// Reason: 1 machine whose node is made unready, 2 machines with ready nodes the scale down should keep.
+		It("delete the machine of an unready node marked for deletion when scaling down", framework.LabelMachines(3), func() {
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
+			Expect(machines).To(HaveLen(3), "The MachineSet should have 3 machines")
+
+			unreadyMachine, readyMachines := machines[0], machines[1:]
+
+			node, err := framework.GetNodeForMachine(ctx, client, unreadyMachine)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to retrieve Node from its Machine")
+
+			clientset, err := framework.LoadClientset()
+			Expect(err).ToNot(HaveOccurred(), "Should be able to create a Kubernetes clientset")
+
+			By(fmt.Sprintf("stopping the kubelet on node %q of machine %q", node.Name, unreadyMachine.Name))
+			Expect(framework.StopKubeletOnNode(ctx, clientset, node)).To(Succeed(), "Should be able to stop the kubelet on the Node")
+
+			Eventually(komega.Object(node), framework.WaitMedium, framework.RetryMedium).ShouldNot(Satisfy(framework.IsNodeReady),
+				"Node %q should become NotReady once its kubelet is stopped", node.Name)
+
+			// The MachineSet only prefers the machines marked for deletion, not those of unready nodes.
+			By(fmt.Sprintf("marking the machine %q of the unready node for deletion", unreadyMachine.Name))
+			Expect(framework.MarkMachineForDeletion(ctx, client, unreadyMachine)).To(Succeed(), "Should be able to mark the Machine for deletion")
+
+			By("scaling the MachineSet down by one")
+			Expect(framework.ScaleMachineSet(machineSet.GetName(), 2)).To(Succeed(), "Should be able to scale down MachineSet")
+
+			By(fmt.Sprintf("waiting for the machine %q of the unready node to be deleted", unreadyMachine.Name))
+			framework.WaitForMachinesDeleted(ctx, client, unreadyMachine)
+
+			remaining, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Listing Machines should succeed")
+			Expect(remaining).To(HaveLen(2), "The MachineSet should have 2 machines left")
+			Expect(framework.MachinesPresent(remaining, readyMachines...)).To(BeTrue(), "The machines of the ready nodes should be kept")
+
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+	})
+
+	// Machines required for test: 0
+	// Reason: Only the existing machines are checked.
+	It("have machine and node provider IDs in the format of the platform", framework.LabelMachines(0), framework.LabelReadOnly, func() {
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the platform")
+
+		machines, err := framework.GetMachines(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to list the machines")
+
+		checked := 0
+
+		for _, machine := range machines {
+			// The machines being provisioned or deleted are not linked to a node yet, or anymore.
+			if machine.Status.NodeRef == nil || machine.DeletionTimestamp != nil {
+				continue
+			}
+
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the node of machine %s", machine.Name)
+
+			Expect(framework.ValidateMachineProviderID(platform, machine, node)).To(Succeed(), "Provider IDs of machine %s and node %s should be valid", machine.Name, node.Name)
+
+			checked++
+		}
+
+		if checked == 0 {
+			Skip("No machine is linked to a node, skipping")
+		}
+	})
+
+	// Machines required for test: 0
+	// Reason: Only the existing machines and nodes are checked.
+	It("link each machine to its own node, annotated with the machine", framework.LabelMachines(0), framework.LabelReadOnly, func() {
+		machines, err := framework.GetMachines(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to list the machines")
+
+		machinesByNode := map[string]string{}
+
+		for _, machine := range machines {
+			// The machines being provisioned or deleted are not linked to a node yet, or anymore.
+			if machine.Status.NodeRef == nil || machine.DeletionTimestamp != nil {
+				continue
+			}
+
+			nodeName := machine.Status.NodeRef.Name
+			Expect(machinesByNode).ToNot(HaveKey(nodeName), "Machine %s should not be linked to node %s of machine %s",
+				machine.Name, nodeName, machinesByNode[nodeName])
+			machinesByNode[nodeName] = machine.Name
+
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the node of machine %s", machine.Name)
+			Expect(node.Annotations).To(HaveKeyWithValue(framework.MachineAnnotationKey, fmt.Sprintf("%s/%s", machine.Namespace, machine.Name)),
+				"Node %s should be annotated with its machine %s", node.Name, machine.Name)
+		}
+
+		if len(machinesByNode) == 0 {
+			Skip("No machine is linked to a node, skipping")
+		}
+	})
+
+	// Machines required for test: 0
+	// Reason: Only the existing MachineSets and their machines are checked.
+	It("have the machines of each MachineSet match its selector and be owned by it", framework.LabelMachines(0), framework.LabelReadOnly, func() {
+		machineSets, err := framework.GetMachineSets(client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to list the MachineSets")
+
+		for _, ms := range machineSets {
+			selector, err := metav1.LabelSelectorAsSelector(&ms.Spec.Selector)
+			Expect(err).ToNot(HaveOccurred(), "Selector of MachineSet %s should be valid", ms.Name)
+
+			// GetMachinesFromMachineSet returns the machines controlled by the MachineSet.
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, ms)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the machines of MachineSet %s", ms.Name)
+
+			for _, machine := range machines {
+				Expect(selector.Matches(labels.Set(machine.Labels))).To(BeTrue(),
+					"Machine %s should match the selector of its MachineSet %s", machine.Name, ms.Name)
+			}
+		}
 	})
 
 	// Machines required for test: 0
 	// Reason: The machineSet creation is rejected by the webhook.
-	It("reject invalid machinesets", func() {
+	It("reject invalid machinesets", framework.LabelMachines(0), func() {
 		client, err := framework.LoadClient()
 		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
 		// Only run on platforms that have webhooks
@@ -404,6 +1054,6 @@ var _ = Describe("Managed cluster should", framework.LabelMAPI, func() {
 		invalidMachineSet := invalidMachinesetWithEmptyProviderConfig()
 		expectedAdmissionWebhookErr := "admission webhook \"default.machineset.machine.openshift.io\" denied the request: providerSpec.value: Required value: a value must be provided"
 
-		Expect(client.Create(context.TODO(), invalidMachineSet)).To(MatchError(expectedAdmissionWebhookErr), "Should fail to create invalid MachineSet")
+		Expect(client.Create(ctx, invalidMachineSet)).To(MatchError(expectedAdmissionWebhookErr), "Should fail to create invalid MachineSet")
 	})
 })
@@ -0,0 +1,72 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/quota"
+)
+
+var _ = Describe("Managed cluster at large scale should", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelPeriodic, framework.LabelLargeScale, framework.LabelRequiresMachineManagement, Serial, func() {
+	var client runtimeclient.Client
+	var ctx context.Context
+	var machineSet *machinev1.MachineSet
+
+	BeforeEach(func() {
+		var err error
+
+		ctx = framework.GetContext()
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 0)
+		quota.SkipIfInsufficientQuota(ctx, client, machineSetParams, framework.LargeScaleReplicas)
+
+		By("Creating a new MachineSet with no replicas")
+		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "MachineSet creation should succeed")
+
+		DeferCleanup(func() {
+			By("Deleting the new MachineSet")
+			Expect(client.Delete(ctx, machineSet)).To(Succeed(), "MachineSet should be able to be deleted")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+	})
+
+	// Machines required for test: framework.LargeScaleReplicas (50 by default)
+	// Reason: Tests that scaling to many machines stays within the regression budget.
+	It("scale a MachineSet up and down within the regression budget", func() {
+		replicas := framework.LargeScaleReplicas
+
+		By(fmt.Sprintf("Scaling the MachineSet to %d replicas", replicas))
+		start := time.Now()
+		Expect(framework.ScaleMachineSet(machineSet.GetName(), replicas)).To(Succeed(), "Should be able to scale up MachineSet")
+
+		timings, err := framework.WaitForMachineSetScaled(ctx, client, machineSet.GetName(), start, framework.LargeScaleNodesReadyBudget)
+		Expect(err).ToNot(HaveOccurred(), "All the machines should be running and their nodes ready within %s", framework.LargeScaleNodesReadyBudget)
+
+		AddReportEntry(fmt.Sprintf("time to %d machines running", replicas), timings.AllRunning)
+		AddReportEntry(fmt.Sprintf("time to %d nodes ready", replicas), timings.AllNodesReady)
+
+		Expect(timings.AllRunning).To(BeNumerically("<=", framework.LargeScaleRunningBudget),
+			"All the machines should be running within the regression budget")
+		Expect(timings.AllNodesReady).To(BeNumerically("<=", framework.LargeScaleNodesReadyBudget),
+			"All the nodes should be ready within the regression budget")
+
+		By("Scaling the MachineSet back to 0 replicas")
+		start = time.Now()
+		Expect(framework.ScaleMachineSet(machineSet.GetName(), 0)).To(Succeed(), "Should be able to scale down MachineSet")
+
+		_, err = framework.WaitForMachineSetScaled(ctx, client, machineSet.GetName(), start, framework.WaitOverLong)
+		Expect(err).ToNot(HaveOccurred(), "All the machines should be deleted")
+	})
+})
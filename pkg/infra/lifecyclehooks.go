@@ -29,7 +29,7 @@ const (
 	pollingInterval                   = 3 * time.Second
 )
 
-var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelDisruptive, func() {
+var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
 	var client runtimeclient.Client
 	var machineSet *machinev1.MachineSet
 	var workload *batchv1.Job
@@ -82,7 +82,7 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 				Key:      lifecyclehooksWorkerNodeRoleLabel,
 				Operator: corev1.NodeSelectorOpExists,
 			})
-		Expect(client.Create(context.Background(), workload)).To(Succeed(), "Could not create workload job")
+		Expect(client.Create(ctx, workload)).To(Succeed(), "Could not create workload job")
 
 		// Make sure to clean up the workload job, if we create one.
 		DeferCleanup(func() {
@@ -95,7 +95,7 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 
 		By("Waiting for job pod to start running on machine.")
 		Eventually(func() (bool, error) {
-			jobPodList, err := framework.GetPods(client, map[string]string{lifecycleHooksPodLabel: ""})
+			jobPodList, err := framework.GetPods(ctx, client, map[string]string{lifecycleHooksPodLabel: ""})
 			if err != nil {
 				return false, err
 			}
@@ -117,7 +117,7 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 
 	// Machines required for test: 1
 	// Reason: Tracks the lifecycle of a single machine as we update its lifecycle hooks
-	It("pause lifecycle actions when present", func() {
+	It("pause lifecycle actions when present", framework.LabelMachines(1), func() {
 		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
 		Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
 		Expect(machines).To(HaveLen(1), "There should be only one Machine")
@@ -135,12 +135,12 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 			Owner: "cluster-api-actuator-pkg",
 		}
 		Eventually(func() (bool, error) {
-			if err = client.Get(context.Background(), machineKey, machine); err != nil {
+			if err = client.Get(ctx, machineKey, machine); err != nil {
 				return false, err
 			}
 			machine.Spec.LifecycleHooks.PreDrain = []machinev1.LifecycleHook{predrainHook}
 			machine.Spec.LifecycleHooks.PreTerminate = []machinev1.LifecycleHook{preterminateHook}
-			if err := client.Update(context.Background(), machine); err != nil {
+			if err := client.Update(ctx, machine); err != nil {
 				return false, err
 			}
 
@@ -155,10 +155,10 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 		By("Checking that workload pod is running on machine")
 		// pre-drain hook should prevent pod from being evicted
 		Eventually(func() (bool, error) {
-			if err := client.Get(context.Background(), podKey, &pod); err != nil {
+			if err := client.Get(ctx, podKey, &pod); err != nil {
 				return false, err
 			}
-			if err := client.Get(context.Background(), machineKey, machine); err != nil {
+			if err := client.Get(ctx, machineKey, machine); err != nil {
 				return false, err
 			}
 			// Check that machine drainable false condition is set
@@ -174,11 +174,11 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 
 		By("Removing pre-drain hook")
 		Eventually(func() (bool, error) {
-			if err := client.Get(context.Background(), machineKey, machine); err != nil {
+			if err := client.Get(ctx, machineKey, machine); err != nil {
 				return false, err
 			}
 			machine.Spec.LifecycleHooks.PreDrain = []machinev1.LifecycleHook{}
-			if err := client.Update(context.Background(), machine); err != nil {
+			if err := client.Update(ctx, machine); err != nil {
 				return false, err
 			}
 
@@ -188,10 +188,10 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 		By("Checking that workload pod is evicted from the machine")
 		// Check that pod is evicted, but machine is still present
 		Eventually(func() bool {
-			return apierrors.IsNotFound(client.Get(context.Background(), podKey, &pod))
+			return apierrors.IsNotFound(client.Get(ctx, podKey, &pod))
 		}, framework.WaitMedium, pollingInterval).Should(BeTrue(), "Pod was not evicted from machine")
 		Eventually(func() (bool, error) {
-			if err := client.Get(context.Background(), machineKey, machine); err != nil {
+			if err := client.Get(ctx, machineKey, machine); err != nil {
 				return false, err
 			}
 			// Machine phase should be "Deleting"
@@ -208,11 +208,11 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 
 		By("Removing pre-terminate hook")
 		Eventually(func() (bool, error) {
-			if err := client.Get(context.Background(), machineKey, machine); err != nil {
+			if err := client.Get(ctx, machineKey, machine); err != nil {
 				return false, err
 			}
 			machine.Spec.LifecycleHooks.PreTerminate = []machinev1.LifecycleHook{}
-			if err = client.Update(context.Background(), machine); err != nil {
+			if err = client.Update(ctx, machine); err != nil {
 				return false, err
 			}
 
@@ -222,7 +222,7 @@ var _ = Describe("Lifecycle Hooks should", framework.LabelMAPI, framework.LabelD
 
 		By("Checking that machine is deleted")
 		Eventually(func() bool {
-			return apierrors.IsNotFound(client.Get(context.Background(), machineKey, machine))
+			return apierrors.IsNotFound(client.Get(ctx, machineKey, machine))
 		}, framework.WaitLong, pollingInterval).Should(BeTrue(), "Machine was not deleted")
 	})
 })
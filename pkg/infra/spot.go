@@ -3,23 +3,13 @@ package infra
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"math/rand"
-	"os"
-	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	appsv1 "k8s.io/api/apps/v1"
-	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/utils/ptr"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -28,6 +18,7 @@ import (
 
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/termination"
 )
 
 const (
@@ -38,18 +29,26 @@ const (
 	spotMachineSetMaxProvisioningRetryCount = 3
 )
 
-var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisruptive, func() {
-	var ctx = context.Background()
+// spotLabels keep the Spot specs off the platforms without Spot support, where they would skip,
+// and off single node clusters, which have no worker MachineSet to build the Spot MachineSet from.
+var spotLabels = Label(append(framework.LabelPlatforms(configv1.AWSPlatformType, configv1.AzurePlatformType),
+	framework.LabelTopologies(configv1.HighlyAvailableTopologyMode)...)...)
+
+var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, spotLabels, func() {
+	var ctx context.Context
 
 	var client runtimeclient.Client
 	var machineSet *machinev1.MachineSet
 	var platform configv1.PlatformType
+	var ipFamilies []corev1.IPFamily
 
 	var delObjects map[string]runtimeclient.Object
 
 	var gatherer *gatherer.StateGatherer
 
 	BeforeEach(func() {
+		ctx = framework.GetContext()
+
 		delObjects = make(map[string]runtimeclient.Object)
 
 		// Make sure to clean up the resources we created
@@ -64,7 +63,7 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 					machineSets = append(machineSets, machineSet)
 				}
 
-				Expect(deleteObject(client, obj)).To(Succeed(), "Should be able to cleanup test objects")
+				Expect(deleteObject(ctx, client, obj)).To(Succeed(), "Should be able to cleanup test objects")
 			}
 
 			if len(machineSets) > 0 {
@@ -88,7 +87,8 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 		case configv1.AWSPlatformType, configv1.AzurePlatformType:
 			// Supported platforms, ok to continue.
 		case configv1.GCPPlatformType:
-			// TODO: GCP relies on the metadata IP for DNS.
+			// TODO: The termination package provides a GCP metadata schema,
+			// but GCP relies on the metadata IP for DNS.
 			// This test prevents it from accessing the DNS, therefore
 			// the termination handler cannot contact the API server
 			// to mark the node as terminating.
@@ -98,39 +98,30 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 			Skip(fmt.Sprintf("Platform %s does not support Spot, skipping.", platform))
 		}
 
+		ipFamilies, err = framework.GetClusterIPFamilies(ctx, client)
+		Expect(err).NotTo(HaveOccurred(), "Should be able to get the cluster IP families")
+	})
+
+	// The MachineSet is created once all the BeforeEach have run, so that the specs skipped
+	// by a nested BeforeEach do not create it.
+	JustBeforeEach(func() {
 		By("Creating a Spot backed MachineSet", func() {
-			machineSetReady := false
 			machineSetParams := framework.BuildMachineSetParams(ctx, client, machinesCount)
 			machineSetParamsList, err := framework.BuildAlternativeMachineSetParams(machineSetParams, platform)
 			Expect(err).ToNot(HaveOccurred(), "Should be able to build list of MachineSet parameters")
-			for i, machineSetParams := range machineSetParamsList {
-				if i >= spotMachineSetMaxProvisioningRetryCount {
-					// If there are many alternatives, only try the specified number of times
-					break
-				}
+
+			// If there are many alternatives, only try the specified number of times
+			machineSetParamsList = machineSetParamsList[:min(len(machineSetParamsList), spotMachineSetMaxProvisioningRetryCount)]
+			for _, machineSetParams := range machineSetParamsList {
 				Expect(setSpotOnProviderSpec(platform, machineSetParams, "")).To(Succeed(), "Should be able to set spot options on ProviderSpec")
+			}
 
-				machineSet, err = framework.CreateMachineSet(client, machineSetParams)
-				Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+			// The alternatives are tried in turn when the current one could not provision due to insufficient spot capacity.
+			machineSet, err = framework.CreateMachineSetWithCapacityRetry(ctx, client, machineSetParamsList, framework.InsufficientCapacityErrorKeys(platform))
+			if machineSet != nil {
 				delObjects[machineSet.Name] = machineSet
-
-				err = framework.WaitForSpotMachineSet(ctx, client, machineSet.GetName())
-				if errors.Is(err, framework.ErrMachineNotProvisionedInsufficientCloudCapacity) {
-					By("Trying alternative machineSet because current one could not provision due to insufficient spot capacity")
-					// If machineSet cannot scale up due to insufficient capacity, try again with different machineSetParams
-					err = framework.DeleteMachineSets(client, machineSet)
-					Expect(err).ToNot(HaveOccurred(), "MachineSet should be be able to be deleted")
-					delete(delObjects, machineSet.Name)
-					framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
-
-					continue
-				}
-				Expect(err).ToNot(HaveOccurred(), "Error while waiting for all spot MachineSet Machines to be ready")
-				machineSetReady = true
-
-				break // MachineSet created successfully
 			}
-			Expect(machineSetReady).To(BeTrue(), "Failed to create a spot backed MachineSet")
+			Expect(err).ToNot(HaveOccurred(), "Failed to create a spot backed MachineSet")
 		})
 	})
 
@@ -141,9 +132,66 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 		}
 	})
 
+	// simulateTermination reroutes the metadata service traffic of the given IP family on the node of a Spot machine
+	// to a mock announcing the termination of the instance, and waits for the machine to be deleted.
+	simulateTermination := func(family corev1.IPFamily) {
+		By("Deploying a mock metadata application", func() {
+			objs, err := termination.MetadataMockObjects(platform)
+			Expect(err).ToNot(HaveOccurred(), "Should build the metadata mock objects")
+
+			for _, obj := range objs {
+				Expect(client.Create(ctx, obj)).To(Succeed(), "Should be able to create metadata mock %T", obj)
+				delObjects[obj.GetName()] = obj
+			}
+
+			Expect(framework.IsDeploymentAvailable(ctx, client, termination.MetadataMockName, framework.MachineAPINamespace)).To(BeTrue(), "Should find an available the metadata Deployment")
+		})
+
+		var machine *machinev1.Machine
+		By("Choosing a Machine to terminate", func() {
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
+			Expect(len(machines)).To(BeNumerically(">", 0), "There should be at least one Machine")
+
+			machine = machines[framework.Rand.Intn(len(machines))]
+			Expect(machine.Status.NodeRef).ToNot(BeNil(), "Machine should have a linked Node")
+		})
+
+		By(fmt.Sprintf("Deploying a job to reroute %s metadata traffic to the mock", family), func() {
+			objs, err := termination.SimulatorObjects(platform, machine.Status.NodeRef.Name, family)
+			if err != nil {
+				Skip(fmt.Sprintf("Skipping the termination simulation: %v.", err))
+			}
+
+			for _, obj := range objs {
+				Expect(client.Create(ctx, obj)).To(Succeed(), "Should be able to create termination simulator %T", obj)
+				delObjects[obj.GetName()] = obj
+			}
+		})
+
+		By("Ensuring the termination handler polled the metadata mock", func() {
+			schema, err := termination.SchemaForPlatform(platform)
+			Expect(err).ToNot(HaveOccurred(), "Should get the metadata schema for the platform")
+
+			clientset, err := framework.LoadClientset()
+			Expect(err).ToNot(HaveOccurred(), "Should be able to create a clientset")
+
+			Eventually(func() (string, error) {
+				return termination.GetMetadataMockLogs(ctx, client, clientset)
+			}, framework.WaitMedium, framework.RetryMedium).Should(ContainSubstring(termination.ExpectedMockRequestLog(schema)),
+				"The metadata mock should have answered a termination handler request")
+		})
+
+		// If the job deploys correctly, the Machine will go away
+		By(fmt.Sprintf("Waiting for machine %q to be deleted", machine.Name), func() {
+			framework.WaitForMachinesDeleted(ctx, client, machine)
+		})
+	}
+
 	// Machines required for test: 1
 	// Reason: We only deploy the termination simulator pod on one node. Machine draining is tested in other tests.
-	It("should handle the spot instances", func() {
+	// Retried as the spot capacity of the cloud provider may run out while the spec runs.
+	It("should handle the spot instances", framework.LabelMachines(1), framework.LabelFlakeRetry, func() {
 		By("should label the Machine specs as interruptible", func() {
 			selector := machineSet.Spec.Selector
 			machines, err := framework.GetMachines(ctx, client, &selector)
@@ -171,7 +219,7 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 				Eventually(func() ([]corev1.Pod, error) {
 					podList := &corev1.PodList{}
 
-					if err := client.List(context.Background(), podList, runtimeclient.MatchingLabels(terminationLabels)); err != nil {
+					if err := client.List(ctx, podList, runtimeclient.MatchingLabels(terminationLabels)); err != nil {
 						return podList.Items, err
 					}
 
@@ -191,7 +239,7 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 				Eventually(func() (bool, error) {
 					pod := &corev1.Pod{}
 
-					if err := client.Get(context.Background(), podKey, pod); err != nil {
+					if err := client.Get(ctx, podKey, pod); err != nil {
 						return false, err
 					}
 
@@ -212,56 +260,26 @@ var _ = Describe("Running on Spot", framework.LabelMAPI, framework.LabelDisrupti
 		})
 
 		By("should terminate a Machine if a termination event is observed", func() {
-			By("Deploying a mock metadata application", func() {
-				configMap, err := getMetadataMockConfigMap()
-				Expect(err).ToNot(HaveOccurred(), "Should load the desired metadata ConfigMap")
-				Expect(client.Create(ctx, configMap)).To(Succeed(), "Should be able to create metadata ConfigMap")
-				delObjects[configMap.Name] = configMap
-
-				service := getMetadataMockService()
-				Expect(client.Create(ctx, service)).To(Succeed(), "Should be able to create metadata Service")
-				delObjects[service.Name] = service
-
-				deployment := getMetadataMockDeployment(platform)
-				Expect(client.Create(ctx, deployment)).To(Succeed(), "Should be able to create metadata Deployment")
-				delObjects[deployment.Name] = deployment
-
-				Expect(framework.IsDeploymentAvailable(ctx, client, deployment.Name, deployment.Namespace)).To(BeTrue(), "Should find an available the metadata Deployment")
-			})
-
-			var machine *machinev1.Machine
-			By("Choosing a Machine to terminate", func() {
-				machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
-				Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
-				Expect(len(machines)).To(BeNumerically(">", 0), "There should be at least one Machine")
-
-				customRand := rand.New(rand.NewSource(time.Now().Unix()))
-				machine = machines[customRand.Intn(len(machines))]
-				Expect(machine.Status.NodeRef).ToNot(BeNil(), "Machine should have a linked Node")
-			})
-
-			By("Deploying a job to reroute metadata traffic to the mock", func() {
-				serviceAccount := getTerminationSimulatorServiceAccount()
-				Expect(client.Create(ctx, serviceAccount)).To(Succeed(), "Should be able to create termination simulator ServiceAccount")
-				delObjects[serviceAccount.Name] = serviceAccount
-
-				role := getTerminationSimulatorRole()
-				Expect(client.Create(ctx, role)).To(Succeed(), "Should be able to create termination simulator Role")
-				delObjects[role.Name] = role
-
-				roleBinding := getTerminationSimulatorRoleBinding()
-				Expect(client.Create(ctx, roleBinding)).To(Succeed(), "Should be able to create termination simulator RoleBinding")
-				delObjects[roleBinding.Name] = roleBinding
-
-				job := getTerminationSimulatorJob(machine.Status.NodeRef.Name)
-				Expect(client.Create(ctx, job)).To(Succeed(), "Should be able to create termination simulator Job")
-				delObjects[job.Name] = job
-			})
-
-			// If the job deploys correctly, the Machine will go away
-			By(fmt.Sprintf("Waiting for machine %q to be deleted", machine.Name), func() {
-				framework.WaitForMachinesDeleted(client, machine)
-			})
+			// The simulator reroutes the metadata service traffic of the primary IP family, the one used by default.
+			simulateTermination(ipFamilies[0])
+		})
+	})
+
+	Context("on a dual-stack cluster", func() {
+		BeforeEach(func() {
+			if len(ipFamilies) < 2 {
+				Skip("Skipping the termination simulation of the secondary IP family, the cluster is not dual-stack.")
+			}
+
+			if _, err := termination.MetadataAddress(platform, ipFamilies[1]); err != nil {
+				Skip(fmt.Sprintf("Skipping the termination simulation of the secondary IP family: %v.", err))
+			}
+		})
+
+		// Machines required for test: 1
+		// Reason: We only deploy the termination simulator pod on one node.
+		It("should terminate a Machine if a termination event is observed over the secondary IP family", framework.LabelMachines(1), func() {
+			simulateTermination(ipFamilies[1])
 		})
 	})
 })
@@ -343,263 +361,3 @@ func setSpotOnGCPProviderSpec(params framework.MachineSetParams) error {
 
 	return nil
 }
-
-const (
-	metadataServiceMockName          = "metadata-service-mock"
-	metadataServiceMockServiceName   = metadataServiceMockName + "-service"
-	metadataServiceMockConfigMapName = metadataServiceMockName + "-configmap"
-	metadataServiceMockPort          = 8082
-)
-
-func getMetadataMockLabels() map[string]string {
-	return map[string]string{
-		"app": "metadata-mock",
-	}
-}
-
-func getMetadataMockDeployment(platform configv1.PlatformType) *appsv1.Deployment {
-	return &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      metadataServiceMockName,
-			Namespace: framework.MachineAPINamespace,
-			Labels:    getMetadataMockLabels(),
-		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: ptr.To[int32](1),
-			Selector: &metav1.LabelSelector{
-				MatchLabels: getMetadataMockLabels(),
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: getMetadataMockLabels(),
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:    "metadata-mock",
-							Image:   "golang:1.14",
-							Command: []string{"/usr/local/go/bin/go"},
-							Args: []string{
-								"run",
-								"/mock/metadata_mock.go",
-								fmt.Sprintf("--provider=%s", platform),
-								fmt.Sprintf("--listen-addr=0.0.0.0:%d", metadataServiceMockPort),
-							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "GOCACHE",
-									Value: "/go/.cache",
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "mock-server",
-									MountPath: "/mock",
-								},
-							},
-						},
-					},
-					DNSPolicy: corev1.DNSClusterFirst,
-					Volumes: []corev1.Volume{
-						{
-							Name: "mock-server",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: metadataServiceMockConfigMapName,
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-}
-
-func getMetadataMockService() *corev1.Service {
-	return &corev1.Service{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      metadataServiceMockServiceName,
-			Namespace: framework.MachineAPINamespace,
-			Labels:    getMetadataMockLabels(),
-		},
-		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "http",
-					Port:       metadataServiceMockPort,
-					Protocol:   "TCP",
-					TargetPort: intstr.FromInt(metadataServiceMockPort),
-				},
-			},
-			Selector:        getMetadataMockLabels(),
-			SessionAffinity: corev1.ServiceAffinityNone,
-			ClusterIP:       "None",
-			Type:            corev1.ServiceTypeClusterIP,
-		},
-	}
-}
-
-func getMetadataMockConfigMap() (*corev1.ConfigMap, error) {
-	// Load relative to the test execution directory
-	data, err := os.ReadFile("./infra/mock/metadata_mock.go")
-	if err != nil {
-		return nil, err
-	}
-
-	return &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      metadataServiceMockConfigMapName,
-			Namespace: framework.MachineAPINamespace,
-			Labels:    getMetadataMockLabels(),
-		},
-		BinaryData: map[string][]byte{
-			"metadata_mock.go": data,
-		},
-	}, nil
-}
-
-const (
-	terminationSimulatorName               = "termination-simulator"
-	terminationSimulatorServiceAccountName = terminationSimulatorName + "-service-account"
-	terminationSimulatorRoleName           = terminationSimulatorName + "-role"
-	terminationSimulatorRoleBindingName    = terminationSimulatorName + "-rolebinding"
-)
-
-func getTerminationSimulatorJob(nodeName string) *batchv1.Job {
-	script := `apk update && apk add iptables bind-tools;
-export SERVICE_IP=$(dig +short ${MOCK_SERVICE_NAME}.${NAMESPACE}.svc.cluster.local);
-if [ -z ${SERVICE_IP} ]; then echo "No service IP"; exit 1; fi;
-iptables-nft -t nat -A OUTPUT -p tcp -d 169.254.169.254 -j DNAT --to-destination ${SERVICE_IP}:${MOCK_SERVICE_PORT};
-iptables-nft -t nat -A POSTROUTING -j MASQUERADE;
-ifconfig lo:0 169.254.169.254 up;
-echo "Redirected metadata service to ${SERVICE_IP}:${MOCK_SERVICE_PORT}";`
-
-	fileOrCreate := corev1.HostPathFileOrCreate
-
-	return &batchv1.Job{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      terminationSimulatorName,
-			Namespace: framework.MachineAPINamespace,
-		},
-		Spec: batchv1.JobSpec{
-			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:    "iptables",
-							Image:   "alpine:3.12",
-							Command: []string{"/bin/sh", "-c"},
-							Args:    []string{script},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "NAMESPACE",
-									Value: framework.MachineAPINamespace,
-								},
-								{
-									Name:  "MOCK_SERVICE_NAME",
-									Value: metadataServiceMockServiceName,
-								},
-								{
-									Name:  "MOCK_SERVICE_PORT",
-									Value: fmt.Sprintf("%d", metadataServiceMockPort),
-								},
-							},
-							SecurityContext: &corev1.SecurityContext{
-								Privileged: ptr.To[bool](true),
-								Capabilities: &corev1.Capabilities{
-									Add: []corev1.Capability{"NET_ADMIN", "NET_RAW"},
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "xtables-lock",
-									MountPath: "/run/xtables.lock",
-									ReadOnly:  false,
-								},
-								{
-									Name:      "lib-modules",
-									MountPath: "/lib/modules",
-									ReadOnly:  true,
-								},
-							},
-						},
-					},
-					RestartPolicy:      corev1.RestartPolicyOnFailure,
-					HostNetwork:        true,
-					DNSPolicy:          corev1.DNSClusterFirstWithHostNet,
-					NodeName:           nodeName,
-					ServiceAccountName: terminationSimulatorServiceAccountName,
-					Volumes: []corev1.Volume{
-						{
-							Name: "xtables-lock",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/run/xtables.lock",
-									Type: &fileOrCreate,
-								},
-							},
-						},
-						{
-							Name: "lib-modules",
-							VolumeSource: corev1.VolumeSource{
-								HostPath: &corev1.HostPathVolumeSource{
-									Path: "/lib/modules",
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-}
-
-func getTerminationSimulatorServiceAccount() *corev1.ServiceAccount {
-	return &corev1.ServiceAccount{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      terminationSimulatorServiceAccountName,
-			Namespace: framework.MachineAPINamespace,
-		},
-	}
-}
-
-func getTerminationSimulatorRole() *rbacv1.Role {
-	return &rbacv1.Role{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      terminationSimulatorRoleName,
-			Namespace: framework.MachineAPINamespace,
-		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				APIGroups:     []string{"security.openshift.io"},
-				ResourceNames: []string{"privileged"},
-				Resources:     []string{"securitycontextconstraints"},
-				Verbs:         []string{"use"},
-			},
-		},
-	}
-}
-
-func getTerminationSimulatorRoleBinding() *rbacv1.RoleBinding {
-	return &rbacv1.RoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      terminationSimulatorRoleBindingName,
-			Namespace: framework.MachineAPINamespace,
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: "rbac.authorization.k8s.io",
-			Kind:     "Role",
-			Name:     terminationSimulatorRoleName,
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      "ServiceAccount",
-				Name:      terminationSimulatorServiceAccountName,
-				Namespace: framework.MachineAPINamespace,
-			},
-		},
-	}
-}
@@ -0,0 +1,182 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// rotatedUserDataMarkerPath is the file written by the rotated ignition config on the nodes.
+	rotatedUserDataMarkerPath = "/etc/machine-api-e2e-user-data-rotated"
+)
+
+var _ = Describe("User data secret", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+	var clientset *kubernetes.Clientset
+	var machineSet *machinev1.MachineSet
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		clientset, err = framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "Clientset should be able to be created")
+
+		machineSet = nil
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			if machineSet != nil {
+				Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+				framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+			}
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 2
+	// Reason: One machine is created before the rotation and one after, to compare the user data they consume.
+	It("should only be consumed by new machines after being rotated", framework.LabelMachines(2), func() {
+		By("Creating a MachineSet using the worker user data secret", func() {
+			var err error
+
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+
+		existingMachines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
+		Expect(existingMachines).To(HaveLen(1), "MachineSet should have a single Machine")
+
+		existingSecretName, err := framework.GetProviderSpecUserDataSecretName(existingMachines[0].Spec.ProviderSpec)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the user data secret of the existing Machine")
+
+		rotatedSecretName := fmt.Sprintf("%s-user-data-rotated", machineSet.GetName())
+
+		By("Rotating the user data secret of the MachineSet", func() {
+			secret, err := framework.CloneUserDataSecret(ctx, client, existingSecretName, rotatedSecretName,
+				framework.WithIgnitionFile(rotatedUserDataMarkerPath, machineSet.GetName()))
+			Expect(err).ToNot(HaveOccurred(), "Should be able to clone the user data secret")
+
+			DeferCleanup(func() {
+				Expect(runtimeclient.IgnoreNotFound(client.Delete(context.Background(), secret))).To(Succeed(), "Should be able to delete the rotated user data secret")
+			})
+
+			Eventually(func() error {
+				ms, err := framework.GetMachineSet(ctx, client, machineSet.GetName())
+				if err != nil {
+					return err
+				}
+
+				providerSpec, err := framework.UpdateProviderSpecUserDataSecret(&ms.Spec.Template.Spec.ProviderSpec, rotatedSecretName)
+				if err != nil {
+					return err
+				}
+
+				ms.Spec.Template.Spec.ProviderSpec = *providerSpec
+
+				return client.Update(ctx, ms)
+			}, framework.WaitShort, framework.RetryShort).Should(Succeed(), "Should be able to point the MachineSet to the rotated user data secret")
+		})
+
+		By("Scaling up the MachineSet", func() {
+			Expect(framework.ScaleMachineSet(machineSet.GetName(), 2)).To(Succeed(), "Should be able to scale up the MachineSet")
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
+		Expect(machines).To(HaveLen(2), "MachineSet should have two Machines")
+
+		for _, machine := range machines {
+			secretName, err := framework.GetProviderSpecUserDataSecretName(machine.Spec.ProviderSpec)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the user data secret of Machine %s", machine.Name)
+
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the Node of Machine %s", machine.Name)
+
+			markerCheck := fmt.Sprintf("cat %s 2>/dev/null || true", rotatedUserDataMarkerPath)
+			marker, err := framework.RunHostCommandOnNode(ctx, clientset, node, "sh", "-c", markerCheck)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to read the rotation marker on Node %s", node.Name)
+
+			if machine.Name == existingMachines[0].Name {
+				Expect(secretName).To(Equal(existingSecretName), "Existing Machine should still reference the original user data secret")
+				Expect(strings.TrimSpace(marker)).To(BeEmpty(), "Existing Node should not have consumed the rotated user data")
+			} else {
+				Expect(secretName).To(Equal(rotatedSecretName), "New Machine should reference the rotated user data secret")
+				Expect(strings.TrimSpace(marker)).To(Equal(machineSet.GetName()), "New Node should have consumed the rotated user data")
+			}
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: The machine is never provisioned, it only needs to report the missing secret.
+	It("should surface a missing user data secret on the Machine", framework.LabelMachines(1), func() {
+		missingSecretName := "machine-api-e2e-missing-user-data"
+
+		By("Ensuring the user data secret does not exist", func() {
+			err := client.Get(ctx, runtimeclient.ObjectKey{Namespace: framework.MachineAPINamespace, Name: missingSecretName}, &corev1.Secret{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "User data secret %q should not exist", missingSecretName)
+		})
+
+		By("Creating a MachineSet referencing the missing user data secret", func() {
+			machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+
+			var err error
+			machineSetParams.ProviderSpec, err = framework.UpdateProviderSpecUserDataSecret(machineSetParams.ProviderSpec, missingSecretName)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to update the user data secret of the ProviderSpec")
+
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+		})
+
+		By("Waiting for the Machine to report the missing secret", func() {
+			Eventually(func() (string, error) {
+				machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+				if err != nil {
+					return "", err
+				}
+
+				if len(machines) == 0 {
+					return "", nil
+				}
+
+				return ptr.Deref(machines[0].Status.ErrorMessage, ""), nil
+			}, framework.WaitMedium, framework.RetryMedium).Should(ContainSubstring(missingSecretName), "Machine should report the missing user data secret")
+		})
+	})
+})
@@ -12,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -30,9 +31,11 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 
 	var gatherer *gatherer.StateGatherer
 
-	var ctx = context.Background()
+	var ctx context.Context
 
 	BeforeEach(func() {
+		ctx = framework.GetContext()
+
 		var err error
 		gatherer, err = framework.NewGatherer()
 		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
@@ -51,7 +54,17 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 			Skip(fmt.Sprintf("Platform %s does not have webhooks, skipping.", platform))
 		}
 
-		machineSetParams = framework.BuildMachineSetParams(ctx, client, 1)
+		// Restore the MachineSets of the cluster if a spec fails midway, after its own resources are cleaned up,
+		// so that it cannot leave the worker MachineSets broken.
+		snapshot, err := framework.SnapshotMachineSets(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to snapshot the MachineSets")
+
+		DeferCleanup(func() {
+			Expect(snapshot.Restore(ctx, client)).To(Succeed(), "Should be able to restore the MachineSets")
+		})
+
+		// The validation specs also run on single node clusters, which have no worker MachineSet.
+		machineSetParams = framework.BuildValidationMachineSetParams(ctx, client, 1)
 		ps, err := createMinimalProviderSpec(platform, machineSetParams.ProviderSpec)
 		Expect(err).ToNot(HaveOccurred(), "Should be able to generate MachineSet ProviderSpec")
 		machineSetParams.ProviderSpec = ps
@@ -75,13 +88,13 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 		DeferCleanup(func() {
 			machineSets, err := framework.GetMachineSets(client, testSelector)
 			Expect(err).ToNot(HaveOccurred(), "Should be able to list test MachineSets")
-			Expect(framework.DeleteMachineSets(client, machineSets...)).To(Succeed(), "Should be able to delete test MachineSets")
+			Expect(framework.DeleteMachineSets(ctx, client, machineSets...)).To(Succeed(), "Should be able to delete test MachineSets")
 			framework.WaitForMachineSetsDeleted(ctx, client, machineSets...)
 
 			machines, err := framework.GetMachines(ctx, client, testSelector)
 			Expect(err).ToNot(HaveOccurred(), "Should be able to get test Machines")
 			Expect(framework.DeleteMachines(ctx, client, machines...)).To(Succeed(), "Should be able to delete test Machines")
-			framework.WaitForMachinesDeleted(client, machines...)
+			framework.WaitForMachinesDeleted(ctx, client, machines...)
 		})
 	})
 
@@ -94,7 +107,7 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 
 	// Machines required for test: 1
 	// Reason: It needs to verify that machine with minimal provider spec is able to go into running phase.
-	It("should be able to create a machine from a minimal providerSpec", func() {
+	It("should be able to create a machine from a minimal providerSpec", framework.LabelMachines(1), framework.LabelRequiresMachineManagement, func() {
 		machine := &machinev1beta1.Machine{
 			ObjectMeta: metav1.ObjectMeta{
 				GenerateName: fmt.Sprintf("%s-webhook-", machineSetParams.Name),
@@ -123,7 +136,7 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 
 	// Machines required for test: 1
 	// Reason: It needs to verify that machine created from the machineSet with minimal provider spec is able to go into running phase.
-	It("should be able to create machines from a machineset with a minimal providerSpec", func() {
+	It("should be able to create machines from a machineset with a minimal providerSpec", framework.LabelMachines(1), framework.LabelRequiresMachineManagement, func() {
 		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
 		Expect(err).ToNot(HaveOccurred(), "Should be able to create MachineSet")
 
@@ -132,7 +145,7 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 
 	// Machines required for test: 1
 	// Reason: We need a machine to test updating its providerSpec. We don't wait for this machine to be running.
-	It("should return an error when removing required fields from the Machine providerSpec", func() {
+	It("should return an error when removing required fields from the Machine providerSpec", framework.LabelMachines(1), framework.LabelRequiresMachineManagement, func() {
 		machine := &machinev1beta1.Machine{
 			ObjectMeta: metav1.ObjectMeta{
 				GenerateName: fmt.Sprintf("%s-webhook-", machineSetParams.Name),
@@ -145,58 +158,129 @@ var _ = Describe("Webhooks", framework.LabelMAPI, framework.LabelDisruptive, fun
 		}
 		Expect(client.Create(ctx, machine)).To(Succeed(), "Should be able to create Machine")
 
-		updated := false
-		for !updated {
-			machine, err := framework.GetMachine(client, machine.Name)
-			Expect(err).ToNot(HaveOccurred(), "Should be able to get Machine")
+		minimalSpec, err := createMinimalProviderSpec(platform, &machine.Spec.ProviderSpec)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to generate Machine's ProviderSpec")
 
-			minimalSpec, err := createMinimalProviderSpec(platform, &machine.Spec.ProviderSpec)
-			Expect(err).ToNot(HaveOccurred(), "Should be able to generate Machine's ProviderSpec")
+		err = framework.PatchMachine(ctx, client, &machinev1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: machine.Name, Namespace: machine.Namespace},
+			Spec:       machinev1beta1.MachineSpec{ProviderSpec: *minimalSpec},
+		})
+		Expect(err).To(HaveOccurred(), "Should not be able to update Machine")
+		Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machine.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+	})
 
-			machine.Spec.ProviderSpec = *minimalSpec
-			err = client.Update(ctx, machine)
-			if apierrors.IsConflict(err) {
-				// Try again if there was a conflict
-				continue
-			}
+	// Machines required for test: 0
+	// Reason: We don't need to start creating the machine, because we are only testing the machineSet webhook.
+	It("should return an error when removing required fields from the MachineSet providerSpec", framework.LabelMachines(0), func() {
+		machineSetParams.Replicas = 0
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to create MachineSet")
+
+		minimalSpec, err := createMinimalProviderSpec(platform, &machineSet.Spec.Template.Spec.ProviderSpec)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to generate Machine's ProviderSpec")
+
+		err = framework.PatchMachineSet(ctx, client, &machinev1beta1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: machineSet.Name, Namespace: machineSet.Namespace},
+			Spec: machinev1beta1.MachineSetSpec{
+				Template: machinev1beta1.MachineTemplateSpec{
+					Spec: machinev1beta1.MachineSpec{ProviderSpec: *minimalSpec},
+				},
+			},
+		})
+		Expect(err).To(HaveOccurred(), "Should not be able to update MachineSet")
+		Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machineset.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+	})
 
-			// No conflict, so the update "worked"
-			updated = true
-			Expect(err).To(HaveOccurred(), "Should be able to update Machine")
-			Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machine.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+	// Machines required for test: 0
+	// Reason: The MachineSet is rejected at admission, so no machines are created.
+	It("should return an error when creating a MachineSet whose selector does not match the template labels", framework.LabelMachines(0), func() {
+		machineSet := &machinev1beta1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      machineSetParams.Name,
+				Namespace: framework.MachineAPINamespace,
+				Labels:    machineSetParams.Labels,
+			},
+			Spec: machinev1beta1.MachineSetSpec{
+				Replicas: ptr.To[int32](0),
+				Selector: metav1.LabelSelector{
+					MatchLabels: withLabel(machineSetParams.Labels, "webhook-test", "selector"),
+				},
+				Template: machinev1beta1.MachineTemplateSpec{
+					ObjectMeta: machinev1beta1.ObjectMeta{
+						Labels: withLabel(machineSetParams.Labels, "webhook-test", "template"),
+					},
+					Spec: machinev1beta1.MachineSpec{
+						ProviderSpec: *machineSetParams.ProviderSpec,
+					},
+				},
+			},
 		}
+
+		err := client.Create(ctx, machineSet)
+		Expect(err).To(HaveOccurred(), "Should not be able to create a MachineSet whose selector does not match its template labels")
+		Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machineset.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+		Expect(err).To(MatchError(ContainSubstring("`selector` does not match template `labels`")), "Should explain that the selector does not match the template labels")
 	})
 
 	// Machines required for test: 0
 	// Reason: We don't need to start creating the machine, because we are only testing the machineSet webhook.
-	It("should return an error when removing required fields from the MachineSet providerSpec", func() {
+	It("should return an error when updating the MachineSet selector", framework.LabelMachines(0), func() {
 		machineSetParams.Replicas = 0
 		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
 		Expect(err).ToNot(HaveOccurred(), "Should be able to create MachineSet")
 
-		updated := false
-		for !updated {
-			machineSet, err = framework.GetMachineSet(ctx, client, machineSet.Name)
-			Expect(err).ToNot(HaveOccurred(), "Should be able to get MachineSet")
+		// Update the template labels too, so that only the immutability of the selector is violated.
+		err = updateMachineSet(ctx, client, machineSet.Name, func(ms *machinev1beta1.MachineSet) {
+			ms.Spec.Selector.MatchLabels = withLabel(ms.Spec.Selector.MatchLabels, "webhook-test", "updated")
+			ms.Spec.Template.Labels = withLabel(ms.Spec.Template.Labels, "webhook-test", "updated")
+		})
+		Expect(err).To(HaveOccurred(), "Should not be able to update the MachineSet selector")
+		Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machineset.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+		Expect(err).To(MatchError(ContainSubstring("selector is immutable")), "Should explain that the selector is immutable")
+	})
 
-			minimalSpec, err := createMinimalProviderSpec(platform, &machineSet.Spec.Template.Spec.ProviderSpec)
-			Expect(err).ToNot(HaveOccurred(), "Should be able to generate Machine's ProviderSpec")
+	// Machines required for test: 0
+	// Reason: We don't need to start creating the machine, because we are only testing the machineSet webhook.
+	It("should return an error when updating the MachineSet template labels so they no longer match the selector", framework.LabelMachines(0), func() {
+		machineSetParams.Replicas = 0
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to create MachineSet")
 
-			machineSet.Spec.Template.Spec.ProviderSpec = *minimalSpec
-			err = client.Update(ctx, machineSet)
-			if apierrors.IsConflict(err) {
-				// Try again if there was a conflict
-				continue
-			}
+		err = updateMachineSet(ctx, client, machineSet.Name, func(ms *machinev1beta1.MachineSet) {
+			ms.Spec.Template.Labels = map[string]string{"webhook-test": "mismatch"}
+		})
+		Expect(err).To(HaveOccurred(), "Should not be able to update the MachineSet template labels")
+		Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machineset.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+		Expect(err).To(MatchError(ContainSubstring("`selector` does not match template `labels`")), "Should explain that the selector does not match the template labels")
+	})
+})
+
+// updateMachineSet applies the mutation to the latest version of the named MachineSet and updates it,
+// retrying on conflicts. It returns the error of the first non conflicting update.
+func updateMachineSet(ctx context.Context, client runtimeclient.Client, name string, mutate func(*machinev1beta1.MachineSet)) error {
+	for {
+		machineSet, err := framework.GetMachineSet(ctx, client, name)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get MachineSet")
+
+		mutate(machineSet)
 
-			// No conflict, so the update "worked"
-			updated = true
-			Expect(err).To(HaveOccurred(), "Should be able to update MachineSet")
-			Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machineset.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+		if err := client.Update(ctx, machineSet); !apierrors.IsConflict(err) {
+			return err
 		}
+	}
+}
 
-	})
-})
+// withLabel returns a copy of the labels with the given label added.
+func withLabel(labels map[string]string, key, value string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	result[key] = value
+
+	return result
+}
 
 func createMinimalProviderSpec(platform configv1.PlatformType, ps *machinev1beta1.ProviderSpec) (*machinev1beta1.ProviderSpec, error) {
 	switch platform {
@@ -0,0 +1,87 @@
+package infra
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+// windowsLabels keep the Windows specs off the platforms without Windows images support in the framework.
+var windowsLabels = Label(framework.LabelPlatforms(configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType)...)
+
+var _ = Describe("Windows MachineSet", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, windowsLabels, func() {
+	var ctx context.Context
+	var client runtimeclient.Client
+	var machineSet *machinev1.MachineSet
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		var err error
+
+		ctx = framework.GetContext()
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		installed, err := framework.IsWindowsMachineConfigOperatorInstalled(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to check whether the Windows Machine Config Operator is installed")
+
+		if !installed {
+			Skip("The Windows Machine Config Operator is not installed, Windows machines would never become nodes.")
+		}
+
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get Platform type")
+
+		machineSetParams, err := framework.BuildWindowsMachineSetParams(framework.BuildMachineSetParams(ctx, client, 1), platform)
+		if errors.Is(err, framework.ErrWindowsImageNotSet) {
+			Skip(err.Error())
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "Should be able to build the Windows MachineSet parameters")
+
+		By("Creating a Windows MachineSet")
+		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "MachineSet creation should succeed")
+
+		DeferCleanup(func() {
+			By("Deleting the Windows MachineSet")
+			Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "MachineSet should be able to be deleted")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: A single machine is enough to verify that Windows machines become nodes.
+	It("should provision a Windows machine that becomes a Windows node", framework.LabelMachines(1), func() {
+		By("Waiting for the Windows machine to be running and its node ready")
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the nodes of the Windows MachineSet")
+		Expect(nodes).To(HaveLen(1), "The Windows MachineSet should have a single node")
+
+		Expect(nodes[0].Labels).To(HaveKeyWithValue(corev1.LabelOSStable, "windows"), "The node should run Windows")
+	})
+})
@@ -14,9 +14,10 @@ import (
 
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
 	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/quota"
 )
 
-var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framework.LabelDisruptive, func() {
+var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
 	var client client.Client
 	var ctx context.Context
 
@@ -27,16 +28,7 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 	var maxUnhealthy = 1
 	const expectedReplicas = 2
 
-	const E2EConditionType = "MachineHealthCheckE2E"
-
-	nodeCondition := corev1.NodeCondition{
-		Type:               E2EConditionType,
-		Status:             corev1.ConditionTrue,
-		LastHeartbeatTime:  metav1.Now(),
-		LastTransitionTime: metav1.Now(),
-		Reason:             "E2E",
-		Message:            "MachineHealthCheck E2E tests",
-	}
+	nodeCondition := framework.NewE2EUnhealthyNodeCondition()
 
 	BeforeEach(func() {
 		var err error
@@ -50,6 +42,7 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 		Expect(err).ToNot(HaveOccurred(), "failed to create a new controller-runtime client")
 
 		machineSetParams := framework.BuildMachineSetParams(ctx, client, expectedReplicas)
+		quota.SkipIfInsufficientQuota(ctx, client, machineSetParams, expectedReplicas)
 
 		By("Creating a new MachineSet")
 		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
@@ -78,7 +71,7 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 
 	// Machines required for test: 3
 	// Reason: 1 unhealthy, 1 healthy, 1 replacement for the unhealthy
-	It("should remediate unhealthy nodes", func() {
+	It("should remediate unhealthy nodes", framework.LabelMachines(3), func() {
 		selector := machineSet.Spec.Selector
 		machines, err := framework.GetMachines(ctx, client, &selector)
 		Expect(err).ToNot(HaveOccurred(), "failed to get machines using a selector")
@@ -94,15 +87,9 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 
 		By("Creating a MachineHealthCheck resource")
 		mhcParams := framework.MachineHealthCheckParams{
-			Name:   machineSet.Name,
-			Labels: machineSet.Labels,
-			Conditions: []machinev1.UnhealthyCondition{
-				{
-					Type:    E2EConditionType,
-					Status:  corev1.ConditionTrue,
-					Timeout: metav1.Duration{Duration: time.Second},
-				},
-			},
+			Name:         machineSet.Name,
+			Labels:       machineSet.Labels,
+			Conditions:   framework.E2EUnhealthyConditions(time.Second),
 			MaxUnhealthy: &maxUnhealthy,
 		}
 
@@ -111,7 +98,7 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 		Expect(machinehealthcheck).ToNot(BeNil(), "expected the new MHC resource to not be nil")
 
 		By("Waiting for each unhealthy machine to be deleted")
-		framework.WaitForMachinesDeleted(client, unhealthyMachines...)
+		framework.WaitForMachinesDeleted(ctx, client, unhealthyMachines...)
 
 		By("Waiting for MachineDeleted event from MachineHealthCheck for each unhealthy machine")
 		for _, machine := range unhealthyMachines {
@@ -128,9 +115,72 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
 	})
 
+	// Machines required for test: 3
+	// Reason: 1 unreachable, 1 healthy, 1 replacement for the unreachable
+	It("should remediate unreachable nodes", framework.LabelMachines(3), func() {
+		selector := machineSet.Spec.Selector
+		machines, err := framework.GetMachines(ctx, client, &selector)
+		Expect(err).ToNot(HaveOccurred(), "failed to get machines using a selector")
+		Expect(machines).ToNot(BeEmpty(), "expected to get a non empty list of machines, got an empty list of machines")
+
+		unreachableMachines, healthyMachines := machines[:maxUnhealthy], machines[maxUnhealthy:]
+
+		clientset, err := framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "failed to create a new Kubernetes clientset")
+
+		By("Stopping the kubelet on machine nodes, but not exceding maxUnhealthy threshold")
+		for _, machine := range unreachableMachines {
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			Expect(err).ToNot(HaveOccurred(), "failed to get a node for a machine")
+			Expect(framework.StopKubeletOnNode(ctx, clientset, node)).To(Succeed(), "failed to stop the kubelet on a node")
+		}
+
+		By("Waiting for the unreachable taint to be applied to machine nodes")
+		for _, machine := range unreachableMachines {
+			Eventually(func() ([]corev1.Taint, error) {
+				node, err := framework.GetNodeForMachine(ctx, client, machine)
+				if err != nil {
+					return nil, err
+				}
+
+				return node.Spec.Taints, nil
+			}, framework.WaitMedium, framework.RetryMedium).Should(ContainElement(HaveField("Key", corev1.TaintNodeUnreachable)),
+				"expected the node of machine %s to be tainted as unreachable", machine.GetName())
+		}
+
+		By("Creating a MachineHealthCheck resource")
+		mhcParams := framework.MachineHealthCheckParams{
+			Name:   machineSet.Name,
+			Labels: machineSet.Labels,
+			Conditions: []machinev1.UnhealthyCondition{
+				{
+					Type:    corev1.NodeReady,
+					Status:  corev1.ConditionUnknown,
+					Timeout: metav1.Duration{Duration: time.Minute},
+				},
+			},
+			MaxUnhealthy: &maxUnhealthy,
+		}
+
+		machinehealthcheck, err = framework.CreateMHC(client, mhcParams)
+		Expect(err).ToNot(HaveOccurred(), "failed to create a new MHC resource")
+		Expect(machinehealthcheck).ToNot(BeNil(), "expected the new MHC resource to not be nil")
+
+		By("Waiting for each unreachable machine to be deleted")
+		framework.WaitForMachinesDeleted(ctx, client, unreachableMachines...)
+
+		By("Ensure none of the healthy machines were deleted")
+		allMachines, err := framework.GetMachines(ctx, client, &selector)
+		Expect(err).ToNot(HaveOccurred(), "failed to get machines using a selector")
+		Expect(framework.MachinesPresent(allMachines, healthyMachines...)).To(BeTrue(), "expected all healthy machines to be present, but atleast one is not present")
+
+		By("Verifying the MachineSet recovers")
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+	})
+
 	// Machines required for test: 2
 	// Reason: We have two unhealthy machines, but the maxUnhealthy threshold is 1, so the MHC should not remediate.
-	It("should not remediate larger number of unhealthy machines then maxUnhealthy", func() {
+	It("should not remediate larger number of unhealthy machines then maxUnhealthy", framework.LabelMachines(2), func() {
 		selector := machineSet.Spec.Selector
 		machines, err := framework.GetMachines(ctx, client, &selector)
 		Expect(err).ToNot(HaveOccurred(), "failed to get machines using a selector")
@@ -146,15 +196,9 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 
 		By("Creating a MachineHealthCheck resource")
 		mhcParams := framework.MachineHealthCheckParams{
-			Name:   machineSet.Name,
-			Labels: machineSet.Labels,
-			Conditions: []machinev1.UnhealthyCondition{
-				{
-					Type:    E2EConditionType,
-					Status:  corev1.ConditionTrue,
-					Timeout: metav1.Duration{Duration: time.Second},
-				},
-			},
+			Name:         machineSet.Name,
+			Labels:       machineSet.Labels,
+			Conditions:   framework.E2EUnhealthyConditions(time.Second),
 			MaxUnhealthy: &maxUnhealthy,
 		}
 
@@ -172,3 +216,102 @@ var _ = Describe("MachineHealthCheck", framework.LabelMachineHealthCheck, framew
 		Expect(framework.MachinesPresent(allMachines, machines...)).To(BeTrue(), "expected all machines to be present, but atleast one is not present")
 	})
 })
+
+var _ = Describe("MachineHealthCheck with a node startup timeout", framework.LabelMachineHealthCheck, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+	var client client.Client
+	var ctx context.Context
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		var err error
+
+		ctx = framework.GetContext()
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "failed to create a new StateGatherer")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "failed to create a new controller-runtime client")
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "failed to gather spec report")
+		}
+	})
+
+	// Machines required for test: 2
+	// Reason: 1 machine which never gets a node, 1 replacement which never gets a node either.
+	It("should remediate machines whose node never becomes ready", framework.LabelMachines(2), func() {
+		By("Creating a user data secret which does not let nodes join the cluster")
+		userDataSecret, err := framework.CreateNodelessUserDataSecret(ctx, client, "mhc-e2e-user-data-")
+		Expect(err).ToNot(HaveOccurred(), "failed to create the user data secret")
+
+		DeferCleanup(func() {
+			By("Deleting the user data secret")
+			Expect(client.Delete(context.Background(), userDataSecret)).To(Succeed(), "failed to delete the user data secret")
+		})
+
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+
+		machineSetParams.ProviderSpec, err = framework.UpdateProviderSpecUserDataSecret(machineSetParams.ProviderSpec, userDataSecret.GetName())
+		Expect(err).ToNot(HaveOccurred(), "failed to update the providerSpec user data secret")
+
+		By("Creating a new MachineSet")
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "failed to create a new machineSet resource")
+
+		DeferCleanup(func() {
+			By("Deleting the new MachineSet")
+			Expect(client.Delete(context.Background(), machineSet)).To(Succeed(), "failed to delete machineSet")
+
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+
+		By("Waiting for the machine to be provisioned")
+		var machine *machinev1.Machine
+		Eventually(func() ([]*machinev1.Machine, error) {
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			if err != nil {
+				return nil, err
+			}
+
+			provisioned := framework.FilterMachines(machines, framework.MachinePhaseProvisioned)
+			if len(provisioned) > 0 {
+				machine = provisioned[0]
+			}
+
+			return provisioned, nil
+		}, framework.WaitLong, framework.RetryMedium).ShouldNot(BeEmpty(), "expected the machine to be provisioned")
+
+		By("Creating a MachineHealthCheck resource with a node startup timeout")
+		mhcParams := framework.MachineHealthCheckParams{
+			Name:   machineSet.Name,
+			Labels: machineSet.Labels,
+			Conditions: []machinev1.UnhealthyCondition{
+				{
+					Type:    corev1.NodeReady,
+					Status:  corev1.ConditionUnknown,
+					Timeout: metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+			NodeStartupTimeout: &metav1.Duration{Duration: 5 * time.Minute},
+		}
+
+		machinehealthcheck, err := framework.CreateMHC(client, mhcParams)
+		Expect(err).ToNot(HaveOccurred(), "failed to create a new MHC resource")
+
+		DeferCleanup(func() {
+			By("Deleting the MachineHealthCheck resource")
+			Expect(client.Delete(context.Background(), machinehealthcheck)).To(Succeed(), "failed to delete MHC")
+		})
+
+		By("Waiting for the machine without a node to be deleted")
+		framework.WaitForMachinesDeleted(ctx, client, machine)
+
+		By("Waiting for MachineDeleted event from MachineHealthCheck")
+		Expect(framework.WaitForEvent(ctx, client, "Machine", machine.Name, "MachineDeleted")).To(Succeed(), "failed to find event MachineDeleted for machine named %s", machine.GetName())
+	})
+})
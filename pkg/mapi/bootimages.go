@@ -0,0 +1,168 @@
+package mapi
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// managedBootImagesLabel opts the MachineSets of the specs in the boot image management.
+	managedBootImagesLabel = "e2e.openshift.io/managed-boot-images"
+
+	// outdatedAWSBootImage and outdatedGCPBootImage are the outdated boot images the MachineSets are created with.
+	// The MachineSets have no replicas, so the images are never booted. The GCP image is in the RHCOS project,
+	// as the Machine Config Operator does not update the custom images.
+	outdatedAWSBootImage = "ami-0123456789abcdef0"
+	outdatedGCPBootImage = "projects/rhcos-cloud/global/images/rhcos-410-84-202210040010-0-gcp-x86-64"
+)
+
+// The boot image management is configured cluster-wide, so the specs run on their own, and configure it once.
+var _ = Describe("Managed boot images", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelPlatforms(configv1.AWSPlatformType, configv1.GCPPlatformType), framework.LabelRequiresMachineManagement, Serial, Ordered, func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+	var platform configv1.PlatformType
+	var outdatedBootImage, streamBootImage string
+	var optedIn, optedOut *machinev1.MachineSet
+
+	var gatherer *gatherer.StateGatherer
+
+	// bootImageOf returns the boot image of the latest version of the MachineSet.
+	bootImageOf := func(machineSet *machinev1.MachineSet) func(Gomega) string {
+		return func(g Gomega) string {
+			current, err := framework.GetMachineSet(ctx, client, machineSet.Name)
+			g.Expect(err).ToNot(HaveOccurred(), "Should be able to get the MachineSet")
+
+			image, err := framework.GetBootImage(&current.Spec.Template.Spec.ProviderSpec, platform)
+			g.Expect(err).ToNot(HaveOccurred(), "Should be able to get the boot image of the MachineSet")
+
+			return image
+		}
+	}
+
+	BeforeAll(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		infra, err := framework.GetInfrastructure(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the Infrastructure")
+
+		platform = infra.Status.PlatformStatus.Type
+
+		featureGate, err := framework.BootImagesFeatureGate(platform)
+		if err != nil {
+			Skip(err.Error())
+		}
+
+		framework.SkipUnlessFeatureGateEnabled(ctx, client, featureGate)
+
+		outdatedBootImage = outdatedGCPBootImage
+
+		var region string
+		if platform == configv1.AWSPlatformType {
+			outdatedBootImage = outdatedAWSBootImage
+			region = infra.Status.PlatformStatus.AWS.Region
+		}
+
+		streamBootImage, err = framework.GetStreamBootImage(ctx, client, platform, region)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the boot image of the release")
+
+		By("Opting the MachineSets with the opt in label in the boot image management", func() {
+			restore, err := framework.SetManagedBootImagesSelector(ctx, client, &metav1.LabelSelector{
+				MatchLabels: map[string]string{managedBootImagesLabel: "true"},
+			})
+			Expect(err).ToNot(HaveOccurred(), "Should be able to configure the boot image management")
+
+			// Registered in BeforeAll, the restore runs once the last spec is done.
+			DeferCleanup(func() {
+				Expect(restore()).To(Succeed(), "Should be able to restore the boot image management configuration")
+			})
+		})
+	})
+
+	BeforeEach(func() {
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		optedIn, optedOut = nil, nil
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			var machineSets []*machinev1.MachineSet
+
+			for _, machineSet := range []*machinev1.MachineSet{optedIn, optedOut} {
+				if machineSet != nil {
+					machineSets = append(machineSets, machineSet)
+				}
+			}
+
+			Expect(framework.DeleteMachineSets(ctx, client, machineSets...)).To(Succeed(), "Should be able to delete test MachineSets")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSets...)
+		})
+
+		By("Creating an opted in and an opted out MachineSet with an outdated boot image", func() {
+			optedIn = createOutdatedBootImageMachineSet(ctx, client, platform, outdatedBootImage, "true")
+			optedOut = createOutdatedBootImageMachineSet(ctx, client, platform, outdatedBootImage, "false")
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 0
+	// Reason: Only the provider spec of the MachineSet is updated, it has no replicas.
+	It("should update the boot image of the opted in MachineSets to the boot image of the release", framework.LabelMachines(0), func() {
+		Eventually(bootImageOf(optedIn), framework.WaitMedium, framework.RetryMedium).Should(Equal(streamBootImage),
+			"The Machine Config Operator should update the boot image of the opted in MachineSet")
+	})
+
+	// Machines required for test: 0
+	// Reason: Only the provider spec of the MachineSet is checked, it has no replicas.
+	It("should not update the boot image of the opted out e2e MachineSets", framework.LabelMachines(0), func() {
+		initialBootImage := bootImageOf(optedOut)(Default)
+
+		// Wait for the opted in MachineSet first, so that the Machine Config Operator has reconciled both.
+		Eventually(bootImageOf(optedIn), framework.WaitMedium, framework.RetryMedium).Should(Equal(streamBootImage),
+			"The Machine Config Operator should update the boot image of the opted in MachineSet")
+
+		Consistently(bootImageOf(optedOut), framework.WaitShort, framework.RetryMedium).Should(Equal(initialBootImage),
+			"The Machine Config Operator should not update the boot image of the opted out MachineSet")
+	})
+})
+
+// createOutdatedBootImageMachineSet creates a MachineSet without replicas booting the given outdated image,
+// with the given value of the opt in label of the boot image management.
+func createOutdatedBootImageMachineSet(ctx context.Context, client runtimeclient.Client, platform configv1.PlatformType, image, optIn string) *machinev1.MachineSet {
+	machineSetParams := framework.BuildMachineSetParams(ctx, client, 0)
+	machineSetParams.Labels[managedBootImagesLabel] = optIn
+
+	providerSpec, err := framework.UpdateProviderSpecBootImage(machineSetParams.ProviderSpec, platform, image)
+	Expect(err).ToNot(HaveOccurred(), "Should be able to set the boot image of the MachineSet")
+
+	machineSetParams.ProviderSpec = providerSpec
+
+	machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+	Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+
+	return machineSet
+}
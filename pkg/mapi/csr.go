@@ -0,0 +1,102 @@
+package mapi
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+// csrApprovalDeadline is the longest the machine approver may take to approve a certificate signing request of a kubelet.
+const csrApprovalDeadline = 5 * time.Minute
+
+var _ = Describe("Machine approver", framework.LabelMAPI, framework.LabelMachineApprover, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// approvedWithinDeadline matches the certificate signing requests of the signer approved within csrApprovalDeadline.
+	approvedWithinDeadline := func(signerName string) OmegaMatcher {
+		return Satisfy(func(csr certificatesv1.CertificateSigningRequest) bool {
+			delay, approved := framework.CSRApprovalDelay(csr)
+			return csr.Spec.SignerName == signerName && approved && delay <= csrApprovalDeadline
+		})
+	}
+
+	// Machines required for test: 1
+	// Reason: The certificate signing requests of the kubelet of a new machine are checked.
+	It("should approve the client and serving certificate signing requests of the kubelets of new machines", framework.LabelMachines(1), func() {
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+
+		DeferCleanup(func() {
+			Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the nodes of the MachineSet")
+		Expect(nodes).To(HaveLen(1), "MachineSet should have a node")
+
+		// The kubelet requests its serving certificate once its node is registered, so it may still be pending.
+		Eventually(func() ([]certificatesv1.CertificateSigningRequest, error) {
+			return framework.GetNodeCSRs(ctx, client, nodes[0].Name)
+		}, csrApprovalDeadline, framework.RetryMedium).Should(SatisfyAll(
+			ContainElement(approvedWithinDeadline(certificatesv1.KubeAPIServerClientKubeletSignerName)),
+			ContainElement(approvedWithinDeadline(certificatesv1.KubeletServingSignerName)),
+		), "Machine approver should approve the client and serving certificate signing requests of node %s within %s", nodes[0].Name, csrApprovalDeadline)
+	})
+
+	// Machines required for test: 0
+	// Reason: The certificate signing request is for a node without a machine.
+	It("should not approve the certificate signing requests of nodes without a machine", framework.LabelMachines(0), func() {
+		// The machine approver only considers the client certificate signing requests of the node bootstrapper.
+		bootstrapperClient, err := framework.LoadImpersonatingClient(framework.NodeBootstrapperUser,
+			"system:serviceaccounts", "system:serviceaccounts:openshift-machine-config-operator")
+		Expect(err).ToNot(HaveOccurred(), "Should be able to create a client impersonating the node bootstrapper")
+
+		csr, err := framework.CreateNodeClientCSR(ctx, bootstrapperClient, "e2e-no-machine-"+framework.Rand.String(8))
+		Expect(err).ToNot(HaveOccurred(), "Should be able to create the certificate signing request")
+
+		DeferCleanup(framework.DeleteObjects, ctx, client, csr)
+
+		Expect(csr.Spec.Username).To(Equal(framework.NodeBootstrapperUser), "Certificate signing request should be requested by the node bootstrapper")
+
+		Consistently(komega.Object(csr), csrApprovalDeadline, framework.RetryMedium).Should(
+			HaveField("Status.Conditions", Not(ContainElement(HaveField("Type", certificatesv1.CertificateApproved)))),
+			"Machine approver should not approve the certificate signing request of a node without a machine")
+	})
+})
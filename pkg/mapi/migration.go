@@ -0,0 +1,176 @@
+package mapi
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/api/features"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// migrationLabel is the label the specs add to the machine template to bump the generation of the MachineSets.
+	migrationLabel = "e2e.openshift.io/migration"
+)
+
+var _ = Describe("Machine API migration", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelTechPreview, framework.LabelRequiresMachineManagement, func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+	var machineSet *machinev1.MachineSet
+
+	var gatherer *gatherer.StateGatherer
+
+	// beRejected matches the errors of the updates denied by the admission of the non-authoritative resources.
+	beRejected := SatisfyAny(
+		WithTransform(apierrors.IsForbidden, BeTrue()),
+		WithTransform(apierrors.IsInvalid, BeTrue()),
+	)
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		framework.SkipUnlessFeatureGateEnabled(ctx, client, features.FeatureGateMachineAPIMigration)
+
+		machineSet, err = framework.CreateMachineSet(client, framework.BuildMachineSetParams(ctx, client, 1))
+		Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: The status of a single machine is enough to verify it is synchronized.
+	It("should synchronize the Machine API authoritative MachineSet and its machines", framework.LabelMachines(1), func() {
+		By("Waiting for the MachineSet to report the Machine API as authoritative and not paused")
+		framework.WaitForMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityMachineAPI)
+		expectSynchronizedGeneration(ctx, client, machineSet)
+
+		By("Waiting for the machines to report the Machine API as authoritative and not paused")
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the machines of the MachineSet")
+		Expect(machines).ToNot(BeEmpty(), "MachineSet should have machines")
+
+		for _, machine := range machines {
+			framework.WaitForMachineAuthoritativeAPI(ctx, client, machine, machinev1.MachineAuthorityMachineAPI)
+			expectSynchronizedGeneration(ctx, client, machine)
+		}
+
+		By("Updating the machine template of the MachineSet")
+		Expect(client.Get(ctx, runtimeclient.ObjectKeyFromObject(machineSet), machineSet)).To(Succeed(), "Should be able to get the MachineSet")
+		generation := machineSet.GetGeneration()
+		Expect(addMachineTemplateLabel(ctx, client, machineSet)).To(Succeed(), "Should be able to update the MachineSet")
+		Expect(machineSet.GetGeneration()).To(BeNumerically(">", generation), "The update should bump the generation of the MachineSet")
+
+		By("Waiting for the synchronized generation of the MachineSet to advance")
+		expectSynchronizedGeneration(ctx, client, machineSet)
+	})
+
+	// Machines required for test: 1
+	// Reason: The Cluster API mirror of a MachineSet with 1 replica.
+	It("should reject updates to the Cluster API mirror of a Machine API authoritative MachineSet", framework.LabelMachines(1), func() {
+		framework.WaitForMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityMachineAPI)
+
+		By("Waiting for the Cluster API mirror of the MachineSet")
+		Eventually(func() error {
+			_, err := framework.GetCAPIMachineSet(ctx, client, machineSet.GetName())
+
+			return err
+		}, framework.WaitShort, framework.RetryMedium).Should(Succeed(), "The Cluster API mirror of MachineSet %s should be created", machineSet.GetName())
+
+		By("Updating the machine template of the Cluster API mirror")
+		mirror, err := framework.GetCAPIMachineSet(ctx, client, machineSet.GetName())
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get the Cluster API mirror")
+
+		patch := runtimeclient.MergeFrom(mirror.DeepCopy())
+		if mirror.Spec.Template.ObjectMeta.Labels == nil {
+			mirror.Spec.Template.ObjectMeta.Labels = map[string]string{}
+		}
+
+		mirror.Spec.Template.ObjectMeta.Labels[migrationLabel] = "updated"
+		Expect(client.Patch(ctx, mirror, patch)).To(beRejected, "Updates to the non-authoritative Cluster API mirror should be rejected")
+	})
+
+	// Machines required for test: 1
+	// Reason: The MachineSet with 1 replica handed over to the Cluster API.
+	It("should pause the MachineSet and reject its updates once the Cluster API is authoritative", framework.LabelMachines(1), func() {
+		framework.WaitForMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityMachineAPI)
+
+		By("Handing the MachineSet over to the Cluster API")
+		Expect(framework.SetMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityClusterAPI)).To(Succeed(),
+			"Should be able to set the authoritative API of the MachineSet")
+
+		// Hand the MachineSet back to the Machine API, which then deletes it with its machines.
+		DeferCleanup(func() {
+			Expect(client.Get(ctx, runtimeclient.ObjectKeyFromObject(machineSet), machineSet)).To(Succeed(), "Should be able to get the MachineSet")
+			Expect(framework.SetMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityMachineAPI)).To(Succeed(),
+				"Should be able to set the authoritative API of the MachineSet")
+			framework.WaitForMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityMachineAPI)
+		})
+
+		By("Waiting for the MachineSet to report the Cluster API as authoritative and be paused")
+		framework.WaitForMachineSetAuthoritativeAPI(ctx, client, machineSet, machinev1.MachineAuthorityClusterAPI)
+
+		By("Updating the machine template of the non-authoritative MachineSet")
+		Expect(client.Get(ctx, runtimeclient.ObjectKeyFromObject(machineSet), machineSet)).To(Succeed(), "Should be able to get the MachineSet")
+		Expect(addMachineTemplateLabel(ctx, client, machineSet)).To(beRejected, "Updates to the non-authoritative MachineSet should be rejected")
+	})
+})
+
+// addMachineTemplateLabel adds the migration label to the machine template of the MachineSet, bumping its generation.
+func addMachineTemplateLabel(ctx context.Context, client runtimeclient.Client, ms *machinev1.MachineSet) error {
+	patch := runtimeclient.MergeFrom(ms.DeepCopy())
+	if ms.Spec.Template.Spec.ObjectMeta.Labels == nil {
+		ms.Spec.Template.Spec.ObjectMeta.Labels = map[string]string{}
+	}
+
+	ms.Spec.Template.Spec.ObjectMeta.Labels[migrationLabel] = "updated"
+
+	return client.Patch(ctx, ms, patch)
+}
+
+// expectSynchronizedGeneration waits for the non-authoritative copy of the resource to be synchronized
+// with the current generation of the Machine API resource.
+func expectSynchronizedGeneration(ctx context.Context, client runtimeclient.Client, obj runtimeclient.Object) {
+	Eventually(func() (int64, error) {
+		if err := client.Get(ctx, runtimeclient.ObjectKeyFromObject(obj), obj); err != nil {
+			return 0, err
+		}
+
+		switch o := obj.(type) {
+		case *machinev1.MachineSet:
+			return o.Status.SynchronizedGeneration - o.GetGeneration(), nil
+		case *machinev1.Machine:
+			return o.Status.SynchronizedGeneration - o.GetGeneration(), nil
+		default:
+			return 0, StopTrying("unexpected object type")
+		}
+	}, framework.WaitMedium, framework.RetryMedium).Should(BeZero(), "%s should be synchronized with its current generation", obj.GetName())
+}
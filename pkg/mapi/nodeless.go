@@ -0,0 +1,126 @@
+package mapi
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+// nodelessMachineRole is the role of the machines which never get a node, neither worker nor master.
+const nodelessMachineRole = "e2e-nodeless"
+
+var _ = Describe("Machines without a node", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+	var machineSet *machinev1.MachineSet
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		userDataSecret, err := framework.CreateNodelessUserDataSecret(ctx, client, "nodeless-e2e-user-data-")
+		Expect(err).ToNot(HaveOccurred(), "Should be able to create the user data secret")
+
+		DeferCleanup(func() {
+			Expect(client.Delete(ctx, userDataSecret)).To(Succeed(), "Should be able to delete the user data secret")
+		})
+
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		machineSetParams.Labels[framework.MachineRoleLabel] = nodelessMachineRole
+
+		machineSetParams.ProviderSpec, err = framework.UpdateProviderSpecUserDataSecret(machineSetParams.ProviderSpec, userDataSecret.GetName())
+		Expect(err).ToNot(HaveOccurred(), "Should be able to set the user data secret of the providerSpec")
+
+		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+
+		// Deleting the MachineSet also exercises the deletion of machines without a node to drain.
+		DeferCleanup(func() {
+			Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 2
+	// Reason: 1 machine which never gets a node, 1 replacement created by the MachineSet once it is deleted.
+	It("should stay Provisioned without being failed nor remediated, and be deleted without a node", framework.LabelMachines(2), func() {
+		var machine *machinev1.Machine
+
+		By("Waiting for the machine to be provisioned", func() {
+			Eventually(func() ([]*machinev1.Machine, error) {
+				return framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			}, framework.WaitShort, framework.RetryMedium).ShouldNot(BeEmpty(), "MachineSet should create a Machine")
+
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the Machines of the MachineSet")
+
+			machine = framework.WaitForMachinePhase(ctx, client, machines[0], framework.WaitLong, framework.MachinePhaseProvisioned)
+		})
+
+		By("Creating a MachineHealthCheck with the node startup timeout disabled", func() {
+			mhc, err := framework.CreateMHC(client, framework.MachineHealthCheckParams{
+				Name:   machineSet.Name,
+				Labels: machineSet.Spec.Selector.MatchLabels,
+				Conditions: []machinev1.UnhealthyCondition{
+					{
+						Type:    corev1.NodeReady,
+						Status:  corev1.ConditionUnknown,
+						Timeout: metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+				NodeStartupTimeout: &metav1.Duration{Duration: 0},
+			})
+			Expect(err).ToNot(HaveOccurred(), "MachineHealthCheck should be able to be created")
+
+			DeferCleanup(func() {
+				Expect(client.Delete(ctx, mhc)).To(Succeed(), "Should be able to delete the MachineHealthCheck")
+			})
+
+			Eventually(komega.Object(mhc), framework.WaitShort, framework.RetryMedium).Should(
+				HaveField("Status.ExpectedMachines", HaveValue(Equal(1))), "MachineHealthCheck should select the Machine")
+		})
+
+		By("Checking the machine is neither failed nor remediated", func() {
+			Consistently(komega.Object(machine), framework.WaitMedium, framework.RetryMedium).Should(SatisfyAll(
+				HaveField("DeletionTimestamp", BeNil()),
+				HaveField("Status.Phase", HaveValue(Equal(framework.MachinePhaseProvisioned))),
+				HaveField("Status.ErrorReason", BeNil()),
+				HaveField("Status.ErrorMessage", BeNil()),
+				HaveField("Status.NodeRef", BeNil()),
+			), "Machine without a node should stay Provisioned, and not be deleted by the MachineHealthCheck")
+		})
+
+		By("Deleting the machine without a node", func() {
+			Expect(framework.DeleteMachines(ctx, client, machine)).To(Succeed(), "Should be able to delete the Machine")
+			framework.WaitForMachinesDeleted(ctx, client, machine)
+		})
+	})
+})
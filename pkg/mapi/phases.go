@@ -0,0 +1,182 @@
+package mapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// invalidAWSInstanceType is rejected by EC2 with a 4xx error, which the AWS actuator
+	// reports as an invalid configuration.
+	invalidAWSInstanceType = "e2e.invalid"
+)
+
+var _ = Describe("Machine lifecycle contract", framework.LabelMAPI, framework.LabelDisruptive, framework.LabelRequiresMachineManagement, func() {
+	var ctx context.Context
+
+	var client runtimeclient.Client
+	var platform configv1.PlatformType
+	var machineSetParams framework.MachineSetParams
+	var machineSet *machinev1.MachineSet
+	var phaseWatcher *framework.MachinePhaseWatcher
+
+	var gatherer *gatherer.StateGatherer
+
+	BeforeEach(func() {
+		ctx = framework.GetContext()
+
+		var err error
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "StateGatherer should be able to be created")
+
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Controller-runtime client should be able to be created")
+
+		platform, err = framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get Platform type")
+
+		machineSetParams = framework.BuildMachineSetParams(ctx, client, 1)
+		machineSet = nil
+
+		phaseWatcher, err = framework.WatchMachinePhases(ctx, &metav1.LabelSelector{MatchLabels: machineSetParams.Labels})
+		Expect(err).ToNot(HaveOccurred(), "Should be able to watch Machine phases")
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			phaseWatcher.Stop()
+
+			if machineSet != nil {
+				Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed(), "Should be able to delete test MachineSet")
+				framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+			}
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed(), "StateGatherer should be able to gather resources")
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: The phases and conditions of a single machine are enough to verify the contract.
+	It("should go through Provisioning, Provisioned and Running with the expected conditions", framework.LabelMachines(1), func() {
+		By("Creating a MachineSet", func() {
+			var err error
+
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+
+			framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+		})
+
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Should be able to get Machines from MachineSet")
+		Expect(machines).To(HaveLen(1), "MachineSet should have a single Machine")
+		machine := machines[0]
+
+		By("Checking the Machine phase transitions", func() {
+			Expect(phaseWatcher.Phases(machine.Name)).To(Equal([]string{
+				framework.MachinePhaseProvisioning,
+				framework.MachinePhaseProvisioned,
+				framework.MachinePhaseRunning,
+			}), "Machine should go through each phase in order, exactly once")
+
+			transitions := phaseWatcher.Transitions(machine.Name)
+			for i, transition := range transitions {
+				Expect(transition.LastUpdated).ToNot(BeNil(), "Machine status lastUpdated should be set in phase %s", transition.Phase)
+
+				if i > 0 {
+					Expect(transition.LastUpdated.Time).ToNot(BeTemporally("<", transitions[i-1].LastUpdated.Time),
+						"Machine status lastUpdated should not go backwards from phase %s to %s", transitions[i-1].Phase, transition.Phase)
+				}
+			}
+		})
+
+		By("Checking the Machine conditions", func() {
+			for _, conditionType := range []machinev1.ConditionType{machinev1.InstanceExistsCondition, machinev1.MachineDrainable} {
+				condition := getMachineCondition(machine, conditionType)
+				Expect(condition).ToNot(BeNil(), "Machine should have the %s condition", conditionType)
+				Expect(condition.Status).To(Equal(corev1.ConditionTrue), "Machine %s condition should be True", conditionType)
+				Expect(condition.LastTransitionTime.IsZero()).To(BeFalse(), "Machine %s condition should have a lastTransitionTime", conditionType)
+			}
+		})
+
+		By("Checking the Machine status", func() {
+			Expect(machine.Status.ErrorReason).To(BeNil(), "Running Machine should not have an errorReason")
+			Expect(machine.Status.ErrorMessage).To(BeNil(), "Running Machine should not have an errorMessage")
+			Expect(machine.Status.NodeRef).ToNot(BeNil(), "Running Machine should reference its Node")
+			Expect(machine.Status.ProviderStatus).ToNot(BeNil(), "Running Machine should have a providerStatus")
+			Expect(machine.Spec.ProviderID).ToNot(BeNil(), "Running Machine should have a providerID")
+		})
+	})
+
+	// Machines required for test: 1
+	// Reason: The machine is never provisioned, it only needs to report the invalid configuration.
+	It("should go into the Failed phase with an errorReason on an invalid providerSpec", framework.LabelMachines(1), func() {
+		if platform != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("Invalid configuration detection is not verified on platform %s, skipping.", platform))
+		}
+
+		By("Creating a MachineSet with an invalid instance type", func() {
+			providerSpec := machinev1.AWSMachineProviderConfig{}
+			Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &providerSpec)).To(Succeed(), "Should be able to unmarshal the providerSpec")
+
+			providerSpec.InstanceType = invalidAWSInstanceType
+
+			var err error
+			machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(providerSpec)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to marshal the providerSpec")
+
+			machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+			Expect(err).ToNot(HaveOccurred(), "MachineSet should be able to be created")
+		})
+
+		var machine *machinev1.Machine
+
+		By("Waiting for the Machine to fail", func() {
+			Eventually(func() ([]*machinev1.Machine, error) {
+				return framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			}, framework.WaitShort, framework.RetryMedium).ShouldNot(BeEmpty(), "MachineSet should create a Machine")
+
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Should be able to get the Machines of the MachineSet")
+
+			machine = framework.WaitForMachinePhase(ctx, client, machines[0], framework.WaitLong, framework.MachinePhaseFailed)
+		})
+
+		By("Checking the Machine status", func() {
+			Expect(machine.Status.ErrorReason).To(HaveValue(Equal(machinev1.InvalidConfigurationMachineError)), "Failed Machine should report an invalid configuration")
+			Expect(ptr.Deref(machine.Status.ErrorMessage, "")).ToNot(BeEmpty(), "Failed Machine should have an errorMessage")
+			Expect(machine.Status.NodeRef).To(BeNil(), "Failed Machine should not reference a Node")
+			Expect(phaseWatcher.Phases(machine.Name)).ToNot(ContainElement(framework.MachinePhaseRunning), "Failed Machine should never have been Running")
+		})
+	})
+})
+
+// getMachineCondition returns the condition of the given type, or nil if the Machine does not have it.
+func getMachineCondition(machine *machinev1.Machine, conditionType machinev1.ConditionType) *machinev1.Condition {
+	for i := range machine.Status.Conditions {
+		if machine.Status.Conditions[i].Type == conditionType {
+			return &machine.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
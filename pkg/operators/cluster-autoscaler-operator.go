@@ -57,7 +57,7 @@ var _ = Describe("Cluster autoscaler operator should", framework.LabelAutoscaler
 			},
 		}
 
-		Expect(client.Create(context.TODO(), invalidCA)).ToNot(Succeed(), "Failed to create invalid ClusterAutoscaler")
+		Expect(client.Create(ctx, invalidCA)).ToNot(Succeed(), "Failed to create invalid ClusterAutoscaler")
 	})
 
 	It("reject invalid MachineAutoscaler resources early via webhook", func() {
@@ -82,11 +82,11 @@ var _ = Describe("Cluster autoscaler operator should", framework.LabelAutoscaler
 			},
 		}
 
-		Expect(client.Create(context.TODO(), invalidMA)).ToNot(Succeed(), "Failed to create invalid MachineAutoscaler")
+		Expect(client.Create(ctx, invalidMA)).ToNot(Succeed(), "Failed to create invalid MachineAutoscaler")
 	})
 })
 
-var _ = Describe("Cluster autoscaler operator deployment should", framework.LabelAutoscaler, framework.LabelLEVEL0, func() {
+var _ = Describe("Cluster autoscaler operator deployment should", framework.LabelAutoscaler, framework.LabelLEVEL0, framework.LabelReadOnly, func() {
 	It("be available", func() {
 		client, err := framework.LoadClient()
 		Expect(err).NotTo(HaveOccurred(), "Failed to load client")
@@ -97,7 +97,7 @@ var _ = Describe("Cluster autoscaler operator deployment should", framework.Labe
 	})
 })
 
-var _ = Describe("Cluster autoscaler cluster operator status should", framework.LabelAutoscaler, framework.LabelLEVEL0, func() {
+var _ = Describe("Cluster autoscaler cluster operator status should", framework.LabelAutoscaler, framework.LabelLEVEL0, framework.LabelReadOnly, func() {
 	It("be available", func() {
 		client, err := framework.LoadClient()
 		Expect(err).NotTo(HaveOccurred(), "Failed to load client")
@@ -3,12 +3,19 @@ package operators
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	configv1 "github.com/openshift/api/config/v1"
+	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +27,10 @@ import (
 var (
 	maoDeployment        = "machine-api-operator"
 	maoManagedDeployment = "machine-api-controllers"
+
+	// maoTerminationHandlerDaemonSet runs the spot instance termination handler on the interruptible nodes
+	// of the platforms supporting spot instances.
+	maoTerminationHandlerDaemonSet = "machine-api-termination-handler"
 )
 
 var _ = Describe(
@@ -42,7 +53,7 @@ var _ = Describe(
 			}
 		})
 
-		It("be available", framework.LabelLEVEL0, func() {
+		It("be available", framework.LabelLEVEL0, framework.LabelReadOnly, func() {
 			ctx := framework.GetContext()
 			client, err := framework.LoadClient()
 			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
@@ -104,7 +115,80 @@ var _ = Describe(
 
 		})
 
-		It("reconcile mutating webhook configuration", func() {
+		It("reconcile termination handler daemonset", framework.LabelDisruptive, func() {
+			ctx := framework.GetContext()
+			client, err := framework.LoadClient()
+			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
+
+			skipUnlessTerminationHandlerPlatform(ctx, client)
+
+			initialDaemonSet, err := framework.GetDaemonset(ctx, client, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)
+			Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("Failed to get %s DaemonSet", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("checking daemonset %q is rolled out", maoTerminationHandlerDaemonSet))
+			Expect(framework.IsDaemonsetRolledOut(ctx, client, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)).To(BeTrue(),
+				fmt.Sprintf("Failed to wait for %s DaemonSet to be rolled out", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("deleting daemonset %q", maoTerminationHandlerDaemonSet))
+			Expect(framework.DeleteDaemonset(ctx, client, initialDaemonSet)).NotTo(HaveOccurred(),
+				fmt.Sprintf("Failed to delete %s DaemonSet", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("checking daemonset %q is recreated", maoTerminationHandlerDaemonSet))
+			key := runtimeclient.ObjectKeyFromObject(initialDaemonSet)
+			Eventually(func() (apitypes.UID, error) {
+				current := &appsv1.DaemonSet{}
+				if err := client.Get(ctx, key, current); err != nil && !apierrors.IsNotFound(err) {
+					return "", err
+				}
+
+				return current.GetUID(), nil
+			}, framework.WaitMedium, framework.RetryShort).Should(And(Not(BeEmpty()), Not(Equal(initialDaemonSet.GetUID()))),
+				fmt.Sprintf("%s DaemonSet should have been recreated", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("checking daemonset %q spec matches", maoTerminationHandlerDaemonSet))
+			Expect(framework.IsDaemonsetSynced(ctx, client, initialDaemonSet, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)).To(BeTrue(),
+				fmt.Sprintf("Failed verifying %s DaemonSet spec has been reconciled", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("checking daemonset %q is rolled out again", maoTerminationHandlerDaemonSet))
+			Expect(framework.IsDaemonsetRolledOut(ctx, client, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)).To(BeTrue(),
+				fmt.Sprintf("Failed to wait for %s DaemonSet to be rolled out", maoTerminationHandlerDaemonSet))
+		})
+
+		It("maintains termination handler daemonset tolerations", framework.LabelDisruptive, func() {
+			ctx := framework.GetContext()
+			client, err := framework.LoadClient()
+			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
+
+			skipUnlessTerminationHandlerPlatform(ctx, client)
+
+			initialDaemonSet, err := framework.GetDaemonset(ctx, client, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)
+			Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("Failed to get %s DaemonSet", maoTerminationHandlerDaemonSet))
+
+			changedDaemonSet := initialDaemonSet.DeepCopy()
+			changedDaemonSet.Spec.Template.Spec.Tolerations = []corev1.Toleration{
+				{
+					Key:      "e2e.openshift.io/termination-handler",
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				},
+			}
+
+			By(fmt.Sprintf("updating daemonset %q tolerations", maoTerminationHandlerDaemonSet))
+			Expect(framework.UpdateDaemonset(ctx, client, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace, changedDaemonSet)).NotTo(HaveOccurred(),
+				fmt.Sprintf("Failed to update %s DaemonSet", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("checking daemonset %q spec matches", maoTerminationHandlerDaemonSet))
+			Eventually(func() bool {
+				return framework.IsDaemonsetSynced(ctx, client, initialDaemonSet, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)
+			}, framework.WaitMedium, framework.RetryShort).Should(BeTrue(),
+				fmt.Sprintf("Failed verifying %s DaemonSet spec has been reconciled", maoTerminationHandlerDaemonSet))
+
+			By(fmt.Sprintf("checking daemonset %q is rolled out again", maoTerminationHandlerDaemonSet))
+			Expect(framework.IsDaemonsetRolledOut(ctx, client, maoTerminationHandlerDaemonSet, framework.MachineAPINamespace)).To(BeTrue(),
+				fmt.Sprintf("Failed to wait for %s DaemonSet to be rolled out", maoTerminationHandlerDaemonSet))
+		})
+
+		It("reconcile mutating webhook configuration", framework.LabelReadOnly, func() {
 			client, err := framework.LoadClient()
 			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
 
@@ -114,7 +198,7 @@ var _ = Describe(
 				"Failed to wait for MutatingWebhookConfiguration to be in sync")
 		})
 
-		It("reconcile validating webhook configuration", func() {
+		It("reconcile validating webhook configuration", framework.LabelReadOnly, func() {
 			client, err := framework.LoadClient()
 			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
 
@@ -143,7 +227,7 @@ var _ = Describe(
 			key := runtimeclient.ObjectKey{Name: initial.Name}
 			Eventually(func() (apitypes.UID, error) {
 				current := &admissionregistrationv1.ValidatingWebhookConfiguration{}
-				if err := client.Get(context.Background(), key, current); err != nil && !apierrors.IsNotFound(err) {
+				if err := client.Get(ctx, key, current); err != nil && !apierrors.IsNotFound(err) {
 					return "", err
 				}
 
@@ -173,7 +257,7 @@ var _ = Describe(
 			key := runtimeclient.ObjectKey{Name: initial.Name}
 			Eventually(func() (apitypes.UID, error) {
 				current := &admissionregistrationv1.MutatingWebhookConfiguration{}
-				if err := client.Get(context.Background(), key, current); err != nil && !apierrors.IsNotFound(err) {
+				if err := client.Get(ctx, key, current); err != nil && !apierrors.IsNotFound(err) {
 					return "", err
 				}
 
@@ -248,11 +332,126 @@ var _ = Describe(
 					"Validating Webhooks CABundles should all be equal")
 			}
 		})
+
+		It("require authentication and authorization to scrape its metrics endpoints", func() {
+			ctx := framework.GetContext()
+			client, err := framework.LoadClient()
+			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
+
+			clientset, err := framework.LoadClientset()
+			Expect(err).NotTo(HaveOccurred(), "Failed to load clientset")
+
+			nodes, err := framework.GetReadyAndSchedulableNodes(ctx, client)
+			Expect(err).NotTo(HaveOccurred(), "Failed to get ready and schedulable nodes")
+			Expect(nodes).NotTo(BeEmpty(), "Expected a ready and schedulable node to scrape the metrics from")
+			node := &nodes[0]
+
+			endpoints, err := framework.GetMetricsEndpoints(ctx, clientset, framework.MachineAPINamespace, framework.MachineAPIMetricsServices...)
+			Expect(err).NotTo(HaveOccurred(), "Failed to get the metrics endpoints of the Machine API")
+			Expect(endpoints).NotTo(BeEmpty(), "Expected the Machine API to expose metrics endpoints")
+
+			token, err := framework.CreateServiceAccountToken(ctx, clientset, framework.MonitoringNamespace, framework.PrometheusServiceAccount)
+			Expect(err).NotTo(HaveOccurred(), "Failed to create a token for the Prometheus service account")
+
+			for _, endpoint := range endpoints {
+				By(fmt.Sprintf("checking anonymous scrapes of %s port %s are rejected", endpoint.Service, endpoint.Port.Name))
+				Expect(framework.ScrapeMetricsEndpoint(ctx, clientset, node, endpoint.URL, "")).To(Equal(http.StatusUnauthorized),
+					fmt.Sprintf("Expected anonymous scrapes of %s to be unauthorized", endpoint.URL))
+
+				By(fmt.Sprintf("checking the Prometheus service account can scrape %s port %s", endpoint.Service, endpoint.Port.Name))
+				Expect(framework.ScrapeMetricsEndpoint(ctx, clientset, node, endpoint.URL, token)).To(Equal(http.StatusOK),
+					fmt.Sprintf("Expected the Prometheus service account to be allowed to scrape %s", endpoint.URL))
+			}
+		})
+
+		It("tolerate or report a restrictive ResourceQuota and LimitRange in its namespace", framework.LabelDisruptive, func() {
+			ctx := framework.GetContext()
+			client, err := framework.LoadClient()
+			Expect(err).NotTo(HaveOccurred(), "Failed to load client")
+
+			resourceQuota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "e2e-restrictive-quota",
+					Namespace: framework.MachineAPINamespace,
+					Labels:    map[string]string{framework.ReasonKey: framework.ReasonE2E},
+				},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{
+						corev1.ResourcePods: resource.MustParse("0"),
+					},
+				},
+			}
+			limitRange := &corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "e2e-restrictive-limits",
+					Namespace: framework.MachineAPINamespace,
+					Labels:    map[string]string{framework.ReasonKey: framework.ReasonE2E},
+				},
+				Spec: corev1.LimitRangeSpec{
+					Limits: []corev1.LimitRangeItem{
+						{
+							Type: corev1.LimitTypeContainer,
+							Max: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1m"),
+								corev1.ResourceMemory: resource.MustParse("1Mi"),
+							},
+						},
+					},
+				},
+			}
+
+			By("creating a restrictive ResourceQuota and LimitRange in the machine API namespace")
+			Expect(client.Create(ctx, resourceQuota)).To(Succeed(), "Failed to create ResourceQuota")
+			Expect(client.Create(ctx, limitRange)).To(Succeed(), "Failed to create LimitRange")
+
+			// Registered first, so it runs last: the operator must recover once the restrictions are lifted.
+			DeferCleanup(func() {
+				By(fmt.Sprintf("checking deployment %q recovers once the restrictions are removed", maoManagedDeployment))
+				Expect(framework.IsDeploymentAvailable(ctx, client, maoManagedDeployment, framework.MachineAPINamespace)).To(BeTrue(),
+					fmt.Sprintf("Failed to wait for %s Deployment to become available", maoManagedDeployment))
+				Expect(framework.WaitForStatusAvailableMedium(ctx, client, "machine-api")).To(BeTrue(),
+					"Failed to wait for machine-api ClusterOperator to become available")
+			})
+			DeferCleanup(func() {
+				By("removing the restrictive ResourceQuota and LimitRange")
+				framework.DeleteObjects(ctx, client, resourceQuota, limitRange)
+			})
+
+			initialDeployment, err := framework.GetDeployment(ctx, client, maoManagedDeployment, framework.MachineAPINamespace)
+			Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("Failed to get %s Deployment", maoManagedDeployment))
+
+			By(fmt.Sprintf("deleting deployment %q so its pods are recreated under the restrictions", maoManagedDeployment))
+			Expect(framework.DeleteDeployment(ctx, client, initialDeployment)).NotTo(HaveOccurred(),
+				fmt.Sprintf("Failed to delete %s Deployment", maoManagedDeployment))
+
+			By("checking the operator either tolerates the restrictions or reports itself as degraded")
+			Eventually(func() (string, error) {
+				deployment, err := framework.GetDeployment(ctx, client, maoManagedDeployment, framework.MachineAPINamespace)
+				// Ignore the deleted deployment until it is replaced by the operator.
+				if err == nil && deployment.UID != initialDeployment.UID && deployment.Status.AvailableReplicas > 0 {
+					return "tolerated", nil
+				}
+
+				clusterOperator := &configv1.ClusterOperator{}
+				if err := client.Get(ctx, runtimeclient.ObjectKey{Name: "machine-api"}, clusterOperator); err != nil {
+					return "", err
+				}
+
+				degraded := cov1helpers.FindStatusCondition(clusterOperator.Status.Conditions, configv1.OperatorDegraded)
+				if degraded != nil && degraded.Status == configv1.ConditionTrue && degraded.Message != "" {
+					GinkgoWriter.Printf("machine-api ClusterOperator is degraded: %s\n", degraded.Message)
+					return "degraded", nil
+				}
+
+				return "", nil
+			}, framework.WaitLong, framework.RetryMedium).Should(BeElementOf("tolerated", "degraded"),
+				"Expected the machine API controllers to either tolerate the restrictions or the machine-api ClusterOperator to report Degraded with a message")
+		})
 	})
 
 var _ = Describe(
 	"Machine API cluster operator status should", framework.LabelMAPI, func() {
-		It("be available", framework.LabelLEVEL0, func() {
+		It("be available", framework.LabelLEVEL0, framework.LabelReadOnly, func() {
 			ctx := framework.GetContext()
 
 			client, err := framework.LoadClient()
@@ -265,10 +464,11 @@ var _ = Describe(
 
 var _ = Describe(
 	"When cluster-wide proxy is configured, Machine API cluster operator should ",
-	framework.LabelDisruptive, framework.LabelPeriodic, framework.LabelMAPI,
+	framework.LabelDisruptive, framework.LabelPeriodic, framework.LabelMAPI, framework.LabelRequiresMachineManagement,
 	Serial,
 	func() {
 		var gatherer *gatherer.StateGatherer
+		var proxyOptions framework.ProxyOptions
 		client, err := framework.LoadClient()
 		ctx := framework.GetContext()
 		Expect(err).NotTo(HaveOccurred(), "Failed to load client")
@@ -278,16 +478,20 @@ var _ = Describe(
 			gatherer, err = framework.NewGatherer()
 			Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
 
+			proxyOptions = framework.DefaultProxyOptions
+		})
+
+		JustBeforeEach(func() {
 			By("deploying an HTTP proxy")
-			framework.DeployProxy(client)
+			framework.DeployProxyWithOptions(client, proxyOptions)
 
 			By("configuring cluster-wide proxy")
-			framework.ConfigureClusterWideProxy(client)
+			framework.ConfigureClusterWideProxyWithOptions(client, proxyOptions)
 		})
 
 		// Machines required for test: 1
 		// Reason: Tests that machine creation is possible behind a proxy.
-		It("create machines when configured behind a proxy", func() {
+		It("create machines when configured behind a proxy", framework.LabelMachines(1), func() {
 			By("creating a machineset")
 			machineSet, err := framework.CreateMachineSet(client, framework.BuildMachineSetParams(ctx, client, 1))
 			Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet")
@@ -300,6 +504,33 @@ var _ = Describe(
 			framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
 		})
 
+		Context("with the TLS intercepting proxy CA added to the user-ca-bundle", func() {
+			BeforeEach(func() {
+				proxyOptions = framework.ProxyOptions{TrustedCAConfigMapName: framework.UserCABundleConfigMapName}
+			})
+
+			// Machines required for test: 1
+			// Reason: Tests that the machine API trusts the CA of a TLS intercepting proxy through the trusted CA bundle.
+			It("create machines and stay available while trusting the proxy CA", framework.LabelMachines(1), func() {
+				framework.WaitForProxyCATrustedByMachineAPI(client)
+
+				By("creating a machineset")
+				machineSet, err := framework.CreateMachineSet(client, framework.BuildMachineSetParams(ctx, client, 1))
+				Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet")
+
+				By("waiting for the all MachineSet's Machines (and Nodes) to become Running (and Ready)")
+				framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+				By("checking the machine-api cluster operator is available behind the proxy")
+				Expect(framework.WaitForStatusAvailableMedium(ctx, client, "machine-api")).To(BeTrue(),
+					"Failed to wait for machine-api Cluster Operator to be available")
+
+				By("destroying a machineset")
+				Expect(client.Delete(context.Background(), machineSet)).To(Succeed(), "Failed to delete MachineSet")
+				framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+			})
+		})
+
 		AfterEach(func() {
 			By("unconfiguring cluster-wide proxy")
 			framework.UnconfigureClusterWideProxy(client)
@@ -326,6 +557,18 @@ var _ = Describe(
 				"Failed to wait for machine-api Cluster Operator to become available")
 
 			By("Removing the mitm-proxy")
-			framework.DeleteProxy(client)
+			framework.DeleteProxyWithOptions(client, proxyOptions)
 		})
 	})
+
+// skipUnlessTerminationHandlerPlatform skips the spec on the platforms the termination handler is not deployed on.
+func skipUnlessTerminationHandlerPlatform(ctx context.Context, client runtimeclient.Client) {
+	platform, err := framework.GetPlatform(ctx, client)
+	Expect(err).NotTo(HaveOccurred(), "Failed to get platform")
+
+	switch platform {
+	case configv1.AWSPlatformType, configv1.AzurePlatformType, configv1.GCPPlatformType:
+	default:
+		Skip(fmt.Sprintf("Platform %s does not run the termination handler, skipping.", platform))
+	}
+}
@@ -3,9 +3,13 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -23,7 +27,7 @@ const (
 	amiIDMetadataEndpoint = "http://169.254.169.254/latest/meta-data/ami-id"
 )
 
-var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.LabelMAPI, func() {
+var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.AWSPlatformType), framework.LabelRequiresMachineManagement, func() {
 	var client runtimeclient.Client
 	var clientset *kubernetes.Clientset
 
@@ -53,7 +57,7 @@ var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.
 
 		// Make sure to clean up the resources we created
 		DeferCleanup(func() {
-			Expect(framework.DeleteMachineSets(client, toDelete...)).To(Succeed())
+			Expect(framework.DeleteMachineSets(ctx, client, toDelete...)).To(Succeed())
 			toDelete = make([]*machinev1.MachineSet, 0, 3)
 
 			framework.WaitForMachineSetsDeleted(ctx, client, toDelete...)
@@ -105,7 +109,7 @@ var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.
 					},
 				},
 			}
-			pod, lastLog, cleanupPod, err := framework.RunPodOnNode(clientset, nodes[0], framework.MachineAPINamespace, podSpec)
+			pod, lastLog, cleanupPod, err := framework.RunPodOnNode(ctx, clientset, nodes[0], framework.MachineAPINamespace, podSpec)
 			Expect(err).ToNot(HaveOccurred(), "Failed to run pod on node")
 			defer func() {
 				Expect(cleanupPod()).To(Succeed())
@@ -113,7 +117,7 @@ var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.
 
 			By("Ensure curl pod is ready")
 			Eventually(func() (bool, error) {
-				if err := client.Get(context.Background(), runtimeclient.ObjectKeyFromObject(pod), pod); err != nil {
+				if err := client.Get(ctx, runtimeclient.ObjectKeyFromObject(pod), pod); err != nil {
 					return false, err
 				}
 
@@ -131,9 +135,29 @@ var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.
 		})
 	}
 
+	assertInstanceHTTPTokens := func(machineSet *machinev1.MachineSet, httpTokens string) {
+		By(fmt.Sprintf("Ensure the instance metadata options of the EC2 instances have httpTokens set to %s", httpTokens), func() {
+			oc, err := framework.NewCLI()
+			Expect(err).ToNot(HaveOccurred(), "Failed to create CLI")
+			awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			Expect(err).ToNot(HaveOccurred(), "Failed to get machines from MachineSet")
+			Expect(machines).ToNot(BeEmpty(), "Expected the MachineSet to have machines")
+
+			for _, machine := range machines {
+				Expect(machine.Spec.ProviderID).ToNot(BeNil(), "Expected machine %s to have a providerID", machine.Name)
+				instance, err := awsClient.DescribeInstanceByProviderID(*machine.Spec.ProviderID)
+				Expect(err).ToNot(HaveOccurred(), "Failed to describe the instance of machine %s", machine.Name)
+				Expect(instance.MetadataOptions).ToNot(BeNil(), "Expected the instance of machine %s to have metadata options", machine.Name)
+				Expect(ptr.Deref(instance.MetadataOptions.HttpTokens, "")).To(Equal(httpTokens), "Unexpected httpTokens on the instance of machine %s", machine.Name)
+			}
+		})
+	}
+
 	// Machines required for test: 0
 	// No machines are created, because the machineSet is rejected.
-	It("should not allow to create machineset with incorrect metadataServiceOptions.authentication", func() {
+	It("should not allow to create machineset with incorrect metadataServiceOptions.authentication", framework.LabelMachines(0), func() {
 		_, err := createMachineSet("fooobaar")
 		Expect(err).To(HaveOccurred(), "Expected error, shouldn't be able to create machineSet with incorrect metadataServiceOptions.authentication")
 		Expect(err.Error()).Should(ContainSubstring("Invalid value: \"fooobaar\": Allowed values are either 'Optional' or 'Required'"))
@@ -141,22 +165,27 @@ var _ = Describe("MetadataServiceOptions", framework.LabelDisruptive, framework.
 
 	// Machines required for test: 1
 	// Reason: Deploys a pod on the node, so it requires a machine to be running.
-	It("should enforce auth on metadata service if metadataServiceOptions.authentication set to Required", func() {
+	It("should enforce auth on metadata service if metadataServiceOptions.authentication set to Required", framework.LabelMachines(1), func() {
 		machineSet, err := createMachineSet(machinev1.MetadataServiceAuthenticationRequired)
 		Expect(err).ToNot(HaveOccurred(), "metadataServiceOptions.authentication set to Required, authentication needed")
 		assertIMDSavailability(machineSet, "HTTP_CODE:401")
+		assertInstanceHTTPTokens(machineSet, ec2.HttpTokensStateRequired)
 	})
 
 	// Machines required for test: 1
 	// Reason: Deploys a pod on the node, so it requires a machine to be running.
-	It("should allow unauthorized requests to metadata service if metadataServiceOptions.authentication is Optional", func() {
+	It("should allow unauthorized requests to metadata service if metadataServiceOptions.authentication is Optional", framework.LabelMachines(1), func() {
 		machineSet, err := createMachineSet(machinev1.MetadataServiceAuthenticationOptional)
 		Expect(err).ToNot(HaveOccurred(), "Failed to create unauthorized request to metadata service")
 		assertIMDSavailability(machineSet, "HTTP_CODE:200")
+		assertInstanceHTTPTokens(machineSet, ec2.HttpTokensStateOptional)
 	})
 })
 
-var _ = Describe("CapacityReservationID", framework.LabelDisruptive, framework.LabelMAPI, func() {
+// Capacity Blocks for ML are not covered: launching into one requires the capacity-block market type, which
+// neither the Machine API nor the Cluster API AWS provider spec exposes yet, and a capacity block cannot be
+// cancelled once purchased.
+var _ = Describe("CapacityReservationID", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.AWSPlatformType), framework.LabelRequiresMachineManagement, func() {
 	var client runtimeclient.Client
 	var gatherer *gatherer.StateGatherer
 	var ctx context.Context
@@ -180,7 +209,7 @@ var _ = Describe("CapacityReservationID", framework.LabelDisruptive, framework.L
 		}
 		// Make sure to clean up the resources we created
 		DeferCleanup(func() {
-			Expect(framework.DeleteMachineSets(client, toDelete...)).To(Succeed())
+			Expect(framework.DeleteMachineSets(ctx, client, toDelete...)).To(Succeed())
 			toDelete = make([]*machinev1.MachineSet, 0, 3)
 
 			framework.WaitForMachineSetsDeleted(ctx, client, toDelete...)
@@ -219,14 +248,14 @@ var _ = Describe("CapacityReservationID", framework.LabelDisruptive, framework.L
 
 	// Machines required for test: 0
 	// No machines are created, because the machineSet is rejected.
-	It("should not allow to create machineset with incorrect capacityReservationId", func() {
+	It("should not allow to create machineset with incorrect capacityReservationId", framework.LabelMachines(0), func() {
 		_, err := createMachineSetWithCapacityReservationID("fooobaar")
 		Expect(err).To(HaveOccurred(), "Expected error, shouldn't be able to create machineSet with incorrect capacityReservationId")
 		Expect(err.Error()).Should(ContainSubstring("invalid value for capacityReservationId: \"fooobaar\", it must start with 'cr-' and be exactly 20 characters long with 17 hexadecimal characters"))
 	})
 
 	// Machines required for test: 1
-	It("machine should get Running with active capacityReservationId", framework.LabelQEOnly, func() {
+	It("machine should get Running with active capacityReservationId", framework.LabelMachines(1), framework.LabelQEOnly, func() {
 		By("Get instanceType and availabilityZone from the first worker MachineSet")
 		workers, err := framework.GetWorkerMachineSets(ctx, client)
 		Expect(err).ToNot(HaveOccurred())
@@ -237,7 +266,7 @@ var _ = Describe("CapacityReservationID", framework.LabelDisruptive, framework.L
 
 		By("Access AWS to create CapacityReservation")
 		oc, _ := framework.NewCLI()
-		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc))
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
 		capacityReservationID, err := awsClient.CreateCapacityReservation(awsProviderConfig.InstanceType, "Linux/UNIX", awsProviderConfig.Placement.AvailabilityZone, 1)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(capacityReservationID).ToNot(Equal(""))
@@ -260,3 +289,288 @@ var _ = Describe("CapacityReservationID", framework.LabelDisruptive, framework.L
 		Expect(awsProviderConfig.CapacityReservationID).Should(Equal(capacityReservationID))
 	})
 })
+
+var _ = Describe("Dedicated hosts", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelQEOnly, framework.LabelPlatforms(configv1.AWSPlatformType), framework.LabelRequiresMachineManagement, func() {
+	var client runtimeclient.Client
+	var gatherer *gatherer.StateGatherer
+	var ctx context.Context
+
+	BeforeEach(func() {
+		var err error
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load client")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
+
+		ctx = framework.GetContext()
+
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+		if platform != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("skipping AWS specific tests on %s", platform))
+		}
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed())
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: Allocating dedicated hosts is expensive, a single host and machine is enough to verify the placement.
+	It("machine should get Running on an allocated dedicated host with host tenancy", framework.LabelMachines(1), func() {
+		By("Get instanceType and availabilityZone from the first worker MachineSet")
+		workers, err := framework.GetWorkerMachineSets(ctx, client)
+		Expect(err).ToNot(HaveOccurred())
+		var awsProviderConfig machinev1.AWSMachineProviderConfig
+		Expect(json.Unmarshal(workers[0].Spec.Template.Spec.ProviderSpec.Value.Raw, &awsProviderConfig)).To(Succeed())
+
+		By("Access AWS to allocate a dedicated host")
+		oc, err := framework.NewCLI()
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CLI")
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		hostIDs, err := awsClient.AllocateHosts(awsProviderConfig.InstanceType, awsProviderConfig.Placement.AvailabilityZone, 1)
+		if err != nil {
+			Skip(fmt.Sprintf("Unable to allocate a dedicated host, skipping: %v", err))
+		}
+		Expect(hostIDs).To(HaveLen(1), "Expected a single dedicated host to be allocated")
+
+		var machineSet *machinev1.MachineSet
+
+		// The host can only be released once the instances running on it are gone,
+		// so the MachineSet must be deleted first.
+		DeferCleanup(func() {
+			if machineSet != nil {
+				Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed())
+				framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+			}
+
+			Eventually(func() error {
+				return awsClient.ReleaseHosts(hostIDs...)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to release the dedicated host")
+		})
+
+		By("Create machineset with host tenancy")
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.AWSMachineProviderConfig{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		spec.Placement.Tenancy = machinev1.HostTenancy
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with host tenancy")
+
+		// The instance type can only run on the allocated host, there is no alternative to fall back to.
+		err = framework.WaitForMachineSetWithCapacityRetry(ctx, client, machineSet.GetName(), framework.InsufficientCapacityErrorKeys(configv1.AWSPlatformType))
+		if errors.Is(err, framework.ErrMachineNotProvisionedInsufficientCloudCapacity) {
+			Skip(fmt.Sprintf("The dedicated host lacks the capacity for the instance, skipping: %v", err))
+		}
+		Expect(err).ToNot(HaveOccurred(), "Machine with host tenancy should be running")
+
+		By("Check the instance is placed on the allocated dedicated host")
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(machines).To(HaveLen(1))
+		Expect(machines[0].Spec.ProviderID).ToNot(BeNil(), "Expected the machine to have a providerID")
+
+		instance, err := awsClient.DescribeInstanceByProviderID(*machines[0].Spec.ProviderID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to describe the instance of the machine")
+		Expect(instance.Placement).ToNot(BeNil(), "Expected the instance to have a placement")
+		Expect(ptr.Deref(instance.Placement.Tenancy, "")).To(Equal(ec2.TenancyHost))
+		Expect(ptr.Deref(instance.Placement.HostId, "")).To(Equal(hostIDs[0]), "Expected the instance to run on the allocated dedicated host")
+	})
+})
+
+var _ = Describe("Security groups", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.AWSPlatformType), framework.LabelRequiresMachineManagement, func() {
+	var client runtimeclient.Client
+	var gatherer *gatherer.StateGatherer
+	var ctx context.Context
+
+	BeforeEach(func() {
+		var err error
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load client")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
+
+		ctx = framework.GetContext()
+
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+		if platform != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("skipping AWS specific tests on %s", platform))
+		}
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed())
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: A single machine is enough to verify the security groups of its network interface.
+	It("machine should get Running with a custom security group referenced by ID attached to its network interface", framework.LabelMachines(1), func() {
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.AWSMachineProviderConfig{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		By("Discover the subnet and security groups of the zone of the machine")
+		network, err := framework.DiscoverNetwork(ctx, client, configv1.AWSPlatformType)
+		Expect(err).ToNot(HaveOccurred(), "Failed to discover the cluster network")
+		zoneNetwork, err := network.ForZone(spec.Placement.AvailabilityZone)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the network of the zone")
+
+		By("Create a custom security group in the VPC of the machine")
+		oc, err := framework.NewCLI()
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CLI")
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		vpcID, err := awsClient.GetSubnetVPCID(zoneNetwork.SubnetID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the VPC of the subnet")
+		groupID, err := awsClient.CreateSecurityGroup("machine-api-e2e-"+framework.Rand.String(8), vpcID, "machine-api e2e custom security group")
+		Expect(err).ToNot(HaveOccurred(), "Failed to create the custom security group")
+
+		var machineSet *machinev1.MachineSet
+
+		// The security group can only be deleted once the network interfaces attached to it are gone,
+		// so the MachineSet and its instances must be deleted first.
+		DeferCleanup(func() {
+			if machineSet != nil {
+				framework.DeleteMachineSetsAndWaitForInstances(ctx, client, machineSet)
+			}
+
+			Eventually(func() error {
+				return awsClient.DeleteSecurityGroup(groupID)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to delete the custom security group")
+		})
+
+		By("Create machineset referencing the security groups by ID")
+		securityGroupIDs := append(slices.Clone(zoneNetwork.SecurityGroupIDs), groupID)
+		spec.SecurityGroups = nil
+		for _, id := range securityGroupIDs {
+			spec.SecurityGroups = append(spec.SecurityGroups, machinev1.AWSResourceReference{ID: ptr.To(id)})
+		}
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		machineSet, err = framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with the custom security group")
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the network interface of the instance carries the custom security group")
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(machines).To(HaveLen(1))
+		Expect(machines[0].Spec.ProviderID).ToNot(BeNil(), "Expected the machine to have a providerID")
+
+		instance, err := awsClient.DescribeInstanceByProviderID(*machines[0].Spec.ProviderID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to describe the instance of the machine")
+		Expect(framework.AWSInstanceENISecurityGroupIDs(instance)).To(ContainElements(securityGroupIDs),
+			"Expected the network interface of the instance to carry the security groups of the machine")
+	})
+})
+
+// Source/destination check is not covered: neither the Machine API nor Cluster API AWS provider spec can disable it.
+var _ = Describe("Public IP", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.AWSPlatformType), framework.LabelRequiresMachineManagement, func() {
+	var client runtimeclient.Client
+	var gatherer *gatherer.StateGatherer
+	var ctx context.Context
+
+	BeforeEach(func() {
+		var err error
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load client")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
+
+		ctx = framework.GetContext()
+
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+		if platform != configv1.AWSPlatformType {
+			Skip(fmt.Sprintf("skipping AWS specific tests on %s", platform))
+		}
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed())
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: A single machine is enough to verify its public IP is reported.
+	It("machine should get Running in a public subnet with a public IP reported as the ExternalIP of its node", framework.LabelMachines(1), func() {
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.AWSMachineProviderConfig{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		By("Find a public subnet in the zone of the machine")
+		network, err := framework.DiscoverNetwork(ctx, client, configv1.AWSPlatformType)
+		Expect(err).ToNot(HaveOccurred(), "Failed to discover the cluster network")
+		zoneNetwork, err := network.ForZone(spec.Placement.AvailabilityZone)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the network of the zone")
+
+		oc, err := framework.NewCLI()
+		Expect(err).ToNot(HaveOccurred(), "Failed to create CLI")
+		awsClient := framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		vpcID, err := awsClient.GetSubnetVPCID(zoneNetwork.SubnetID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the VPC of the subnet")
+
+		publicSubnetID, err := awsClient.GetPublicSubnetID(vpcID, spec.Placement.AvailabilityZone)
+		if err != nil {
+			Skip(fmt.Sprintf("No public subnet to run the machine in, skipping: %v", err))
+		}
+
+		By("Create machineset with a public IP in the public subnet")
+		spec.PublicIP = ptr.To(true)
+		spec.Subnet = machinev1.AWSResourceReference{ID: ptr.To(publicSubnetID)}
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with a public IP")
+
+		DeferCleanup(func() {
+			framework.DeleteMachineSetsAndWaitForInstances(ctx, client, machineSet)
+		})
+
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the instance has a public IPv4 address")
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(machines).To(HaveLen(1))
+		Expect(machines[0].Spec.ProviderID).ToNot(BeNil(), "Expected the machine to have a providerID")
+
+		instance, err := awsClient.DescribeInstanceByProviderID(*machines[0].Spec.ProviderID)
+		Expect(err).ToNot(HaveOccurred(), "Failed to describe the instance of the machine")
+		publicIP := ptr.Deref(instance.PublicIpAddress, "")
+		Expect(publicIP).ToNot(BeEmpty(), "Expected the instance to have a public IPv4 address")
+
+		By("Check the public IP is the ExternalIP of the machine and its node")
+		externalIP := corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: publicIP}
+		Expect(machines[0].Status.Addresses).To(ContainElement(externalIP), "Expected the machine to report the public IP as its ExternalIP")
+
+		Eventually(func() ([]corev1.NodeAddress, error) {
+			node, err := framework.GetNodeForMachine(ctx, client, machines[0])
+			if err != nil {
+				return nil, err
+			}
+
+			return node.Status.Addresses, nil
+		}, framework.WaitMedium, framework.RetryMedium).Should(ContainElement(externalIP), "Expected the node to report the public IP as its ExternalIP")
+	})
+})
@@ -0,0 +1,363 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	// azureEphemeralOSDiskVMSize has a cache large enough to hold the default OS disk.
+	azureEphemeralOSDiskVMSize = "Standard_D8s_v3"
+)
+
+var _ = Describe("Azure MachineSet provider spec", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.AzurePlatformType), framework.LabelRequiresMachineManagement, func() {
+	var client runtimeclient.Client
+	var clientset *kubernetes.Clientset
+
+	var gatherer *gatherer.StateGatherer
+	var ctx context.Context
+
+	toDelete := make([]*machinev1.MachineSet, 0, 3)
+
+	BeforeEach(func() {
+		var err error
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load client")
+
+		clientset, err = framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load clientset")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
+
+		ctx = framework.GetContext()
+
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+		if platform != configv1.AzurePlatformType {
+			Skip(fmt.Sprintf("skipping Azure specific tests on %s", platform))
+		}
+
+		// Make sure to clean up the resources we created, checking the virtual machines are not left behind
+		DeferCleanup(func() {
+			framework.DeleteMachineSetsAndWaitForInstances(ctx, client, toDelete...)
+
+			toDelete = make([]*machinev1.MachineSet, 0, 3)
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed())
+		}
+	})
+
+	// createMachineSetWithoutWaiting creates a MachineSet of a single machine, its provider spec mutated,
+	// without waiting for the machine to be running.
+	createMachineSetWithoutWaiting := func(mutate func(*machinev1.AzureMachineProviderSpec)) *machinev1.MachineSet {
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.AzureMachineProviderSpec{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		mutate(&spec)
+
+		var err error
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred(), "Failed to marshal providerSpec")
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet")
+		toDelete = append(toDelete, machineSet)
+
+		return machineSet
+	}
+
+	createMachineSet := func(mutate func(*machinev1.AzureMachineProviderSpec)) *machinev1.MachineSet {
+		machineSet := createMachineSetWithoutWaiting(mutate)
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		return machineSet
+	}
+
+	getDefaultProviderSpec := func() machinev1.AzureMachineProviderSpec {
+		spec := machinev1.AzureMachineProviderSpec{}
+		Expect(json.Unmarshal(framework.BuildMachineSetParams(ctx, client, 1).ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		return spec
+	}
+
+	getInstanceMetadata := func(machineSet *machinev1.MachineSet) *framework.AzureInstanceMetadata {
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get nodes from MachineSet")
+		Expect(nodes).To(HaveLen(1))
+
+		metadata, err := framework.GetAzureInstanceMetadata(ctx, clientset, nodes[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the instance metadata of the node")
+
+		return metadata
+	}
+
+	// Machines required for test: 1
+	// Reason: Queries the instance metadata from the node, so it requires a machine to be running.
+	It("should run a machine with an ephemeral OS disk", framework.LabelMachines(1), func() {
+		By("Create machineset with an ephemeral OS disk")
+		machineSet := createMachineSet(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.VMSize = azureEphemeralOSDiskVMSize
+			spec.OSDisk.CachingType = string(machinev1.CachingTypeReadOnly)
+			spec.OSDisk.DiskSettings.EphemeralStorageLocation = "Local"
+		})
+
+		By("Check the storage profile of the virtual machine")
+		metadata := getInstanceMetadata(machineSet)
+		Expect(metadata.Compute.StorageProfile.OSDisk.DiffDiskSettings.Option).To(Equal("Local"), "Expected the OS disk to be ephemeral")
+	})
+
+	// Machines required for test: 1
+	// Reason: Queries the instance metadata from the node, so it requires a machine to be running.
+	It("should run a machine with an UltraSSD data disk", framework.LabelMachines(1), framework.LabelQEOnly, func() {
+		By("Create machineset with an UltraSSD data disk")
+		machineSet := createMachineSet(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.UltraSSDCapability = machinev1.AzureUltraSSDCapabilityEnabled
+			spec.DataDisks = []machinev1.DataDisk{
+				{
+					NameSuffix: "ultrassd",
+					DiskSizeGB: 4,
+					Lun:        0,
+					ManagedDisk: machinev1.DataDiskManagedDiskParameters{
+						StorageAccountType: machinev1.StorageAccountUltraSSDLRS,
+					},
+					CachingType:    machinev1.CachingTypeNone,
+					DeletionPolicy: machinev1.DiskDeletionPolicyTypeDelete,
+				},
+			}
+		})
+
+		By("Check the storage profile of the virtual machine")
+		metadata := getInstanceMetadata(machineSet)
+		Expect(metadata.Compute.StorageProfile.DataDisks).To(ConsistOf(
+			HaveField("ManagedDisk.StorageAccountType", Equal(string(machinev1.StorageAccountUltraSSDLRS))),
+		), "Expected a single UltraSSD data disk to be attached")
+	})
+
+	// Machines required for test: 1
+	// Reason: Inspects the network interfaces of the node, so it requires a machine to be running.
+	It("should run a machine with accelerated networking", framework.LabelMachines(1), func() {
+		By("Create machineset with accelerated networking enabled")
+		machineSet := createMachineSet(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.AcceleratedNetworking = true
+		})
+
+		By("Check accelerated networking is active on the node")
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get nodes from MachineSet")
+		Expect(nodes).To(HaveLen(1))
+
+		active, err := framework.IsAzureAcceleratedNetworkingActive(ctx, clientset, nodes[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to inspect the network interfaces of the node")
+		Expect(active).To(BeTrue(), "Expected accelerated networking to be active on the node")
+	})
+
+	// Machines required for test: 1
+	// Reason: Queries the instance metadata from the node, so it requires a machine to be running.
+	It("should run a machine from a compute gallery image", framework.LabelMachines(1), func() {
+		image := getDefaultProviderSpec().Image
+
+		galleryImage, err := framework.ParseAzureGalleryImageID(image.ResourceID)
+		if err != nil {
+			Skip(fmt.Sprintf("skipping compute gallery image test, the default image is not a gallery image: %v", err))
+		}
+
+		By(fmt.Sprintf("Create machineset with image %s of gallery %s", galleryImage.Name, galleryImage.Gallery))
+		machineSet := createMachineSet(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.Image = machinev1.Image{
+				ResourceID: image.ResourceID,
+				Type:       machinev1.AzureImageTypeID,
+			}
+		})
+
+		By("Check the virtual machine was created from the gallery image")
+		metadata := getInstanceMetadata(machineSet)
+		Expect(strings.ToLower(metadata.Compute.StorageProfile.ImageReference.ID)).To(HaveSuffix(strings.ToLower(image.ResourceID)),
+			"Expected the virtual machine to be created from the gallery image")
+	})
+
+	// Machines required for test: 1
+	// Reason: Queries the instance metadata from the node, so it requires a machine to be running.
+	It("should run a machine from a marketplace image with a purchase plan", framework.LabelMachines(1), func() {
+		By("Create machineset with the OpenShift marketplace image")
+		machineSet := createMachineSetWithoutWaiting(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.Image = machinev1.Image{
+				Publisher: framework.AzureMarketplaceImagePublisher,
+				Offer:     framework.AzureMarketplaceImageOffer,
+				SKU:       framework.AzureMarketplaceImageSKU,
+				Version:   "latest",
+				Type:      machinev1.AzureImageTypeMarketplaceWithPlan,
+			}
+		})
+
+		By("Wait for the machine to be running or to fail")
+		var machine *machinev1.Machine
+
+		Eventually(func() (string, error) {
+			machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+			if err != nil || len(machines) == 0 {
+				return "", err
+			}
+
+			machine = machines[0]
+
+			return ptr.Deref(machine.Status.Phase, ""), nil
+		}, framework.WaitLong, framework.RetryMedium).Should(BeElementOf(framework.MachinePhaseRunning, framework.MachinePhaseFailed),
+			"Expected the machine to be either running or failed")
+
+		// The subscription must have accepted the terms of the image: when it has not, the machine is expected to
+		// fail with an error naming the purchase plan rather than an opaque provisioning error.
+		if ptr.Deref(machine.Status.Phase, "") == framework.MachinePhaseFailed {
+			Expect(ptr.Deref(machine.Status.ErrorMessage, "")).To(MatchRegexp(`(?i)(marketplace|purchase|terms|plan)`),
+				"Expected the failure to be attributed to the purchase plan of the image")
+			Skip("skipping the purchase plan checks, the terms of the marketplace image are not accepted in this subscription")
+		}
+
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the virtual machine was created with the purchase plan of the image")
+		metadata := getInstanceMetadata(machineSet)
+		Expect(metadata.Compute.StorageProfile.ImageReference).To(SatisfyAll(
+			HaveField("Publisher", Equal(framework.AzureMarketplaceImagePublisher)),
+			HaveField("Offer", Equal(framework.AzureMarketplaceImageOffer)),
+			HaveField("SKU", Equal(framework.AzureMarketplaceImageSKU)),
+		), "Expected the virtual machine to be created from the marketplace image")
+		Expect(metadata.Compute.Plan).To(Equal(framework.AzurePlanMetadata{
+			Name:      framework.AzureMarketplaceImageSKU,
+			Product:   framework.AzureMarketplaceImageOffer,
+			Publisher: framework.AzureMarketplaceImagePublisher,
+		}), "Expected the virtual machine to have the purchase plan of the image")
+	})
+
+	// getAzureClient returns an Azure client for the checks the instance metadata does not cover.
+	getAzureClient := func() *framework.AzureClient {
+		azureClient, err := framework.NewAzureClient(ctx, client)
+		if errors.Is(err, framework.ErrAzureClientSecretMissing) {
+			Skip(fmt.Sprintf("skipping Azure API checks: %v", err))
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "Failed to create Azure client")
+
+		return azureClient
+	}
+
+	// Machines required for test: 1
+	// Reason: Queries the instance metadata from the node, so it requires a machine to be running.
+	It("should run a machine in the requested availability zone", framework.LabelMachines(1), func() {
+		if getDefaultProviderSpec().Zone == "" {
+			Skip("skipping availability zone test, the region of the cluster has no availability zones")
+		}
+
+		// Pin the machine to the zone of the last worker MachineSet, which differs from the zone of
+		// the first one copied by the MachineSet params on clusters spread across zones.
+		workers, err := framework.GetWorkerMachineSets(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get worker MachineSets")
+
+		workerSpec := machinev1.AzureMachineProviderSpec{}
+		Expect(json.Unmarshal(workers[len(workers)-1].Spec.Template.Spec.ProviderSpec.Value.Raw, &workerSpec)).To(Succeed())
+
+		zone := workerSpec.Zone
+
+		By(fmt.Sprintf("Create machineset in availability zone %s", zone))
+		machineSet := createMachineSet(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.Zone = zone
+			spec.AvailabilitySet = ""
+		})
+
+		By("Check the zone of the virtual machine")
+		metadata := getInstanceMetadata(machineSet)
+		Expect(metadata.Compute.Zone).To(Equal(zone), "Expected the virtual machine to be placed in the requested zone")
+	})
+
+	// Machines required for test: 1
+	// Reason: The availability set of the virtual machine is checked, so it requires a machine to be running.
+	It("should run a machine in the requested availability set", framework.LabelMachines(1), func() {
+		defaultSpec := getDefaultProviderSpec()
+		if defaultSpec.Zone != "" {
+			Skip("skipping availability set test, the region of the cluster has availability zones")
+		}
+
+		azureClient := getAzureClient()
+		availabilitySetName := "e2e-" + framework.Rand.String(8)
+
+		By(fmt.Sprintf("Create availability set %s", availabilitySetName))
+		_, err := azureClient.CreateAvailabilitySet(defaultSpec.ResourceGroup, defaultSpec.Location, availabilitySetName)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create availability set")
+
+		// The availability set cannot be deleted while it has virtual machines.
+		DeferCleanup(func() {
+			framework.DeleteMachineSetsAndWaitForInstances(ctx, client, toDelete...)
+
+			toDelete = make([]*machinev1.MachineSet, 0, 3)
+
+			Eventually(func() error {
+				return azureClient.DeleteAvailabilitySet(defaultSpec.ResourceGroup, availabilitySetName)
+			}, framework.WaitLong, framework.RetryMedium).Should(Succeed(), "Failed to delete availability set")
+		})
+
+		By("Create machineset in the availability set")
+		machineSet := createMachineSet(func(spec *machinev1.AzureMachineProviderSpec) {
+			spec.AvailabilitySet = availabilitySetName
+		})
+
+		By("Check the availability set of the virtual machine")
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get machines from MachineSet")
+		Expect(machines).To(HaveLen(1))
+
+		// The virtual machines are named after their machines.
+		vm, err := azureClient.GetVirtualMachine(defaultSpec.ResourceGroup, machines[0].Name)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the virtual machine")
+		Expect(vm.Properties).ToNot(BeNil())
+		Expect(vm.Properties.AvailabilitySet).ToNot(BeNil(), "Expected the virtual machine to be in an availability set")
+		Expect(strings.ToLower(ptr.Deref(vm.Properties.AvailabilitySet.ID, ""))).To(HaveSuffix("/availabilitysets/"+strings.ToLower(availabilitySetName)),
+			"Expected the virtual machine to be in the requested availability set")
+		Expect(vm.Zones).To(BeEmpty(), "Expected the virtual machine not to be zonal")
+	})
+
+	// Machines required for test: 0
+	// Reason: The MachineSet is rejected at admission, so no machines are created.
+	It("should reject a MachineSet with both an availability zone and an availability set", framework.LabelMachines(0), func() {
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 0)
+		spec := machinev1.AzureMachineProviderSpec{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		spec.Zone = "1"
+		spec.AvailabilitySet = "e2e-" + framework.Rand.String(8)
+
+		var err error
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred(), "Failed to marshal providerSpec")
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		if err == nil {
+			toDelete = append(toDelete, machineSet)
+		}
+
+		Expect(err).To(MatchError(ContainSubstring("admission webhook \"validation.machineset.machine.openshift.io\" denied the request")), "Should get an admission webhook denied error back")
+		Expect(err).To(MatchError(MatchRegexp(`(?i)availability ?set`)), "Should explain that the availability set cannot be used with a zone")
+	})
+})
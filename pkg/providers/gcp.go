@@ -0,0 +1,274 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+const (
+	gcpLocalSSDDiskType = "local-ssd"
+	gcpPdSSDDiskType    = "pd-ssd"
+
+	// GCP local SSDs always have a fixed size of 375GiB.
+	gcpLocalSSDSizeGB = 375
+
+	// gcpCustomMachineType is an N1 custom machine type with 4 vCPUs and 16GiB of memory.
+	gcpCustomMachineType = "custom-4-16384"
+)
+
+// Reservation affinity is not covered: neither the Machine API nor the Cluster API GCP provider spec
+// exposes it yet, and the framework has no GCP compute client to create the reservations.
+var _ = Describe("GCP MachineSet provider spec", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.GCPPlatformType), framework.LabelRequiresMachineManagement, func() {
+	var client runtimeclient.Client
+	var clientset *kubernetes.Clientset
+
+	var gatherer *gatherer.StateGatherer
+	var ctx context.Context
+
+	toDelete := make([]*machinev1.MachineSet, 0, 3)
+
+	BeforeEach(func() {
+		var err error
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load client")
+
+		clientset, err = framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load clientset")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
+
+		ctx = framework.GetContext()
+
+		platform, err := framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+		if platform != configv1.GCPPlatformType {
+			Skip(fmt.Sprintf("skipping GCP specific tests on %s", platform))
+		}
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			Expect(framework.DeleteMachineSets(ctx, client, toDelete...)).To(Succeed())
+			framework.WaitForMachineSetsDeleted(ctx, client, toDelete...)
+
+			toDelete = make([]*machinev1.MachineSet, 0, 3)
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed())
+		}
+	})
+
+	// Machines required for test: 1
+	// Reason: Lists the disks attached to the node, so it requires a machine to be running.
+	It("should attach local SSD and additional persistent disks to the machine", framework.LabelMachines(1), func() {
+		By("Create machineset with a local SSD and an additional persistent disk")
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.GCPMachineProviderSpec{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		spec.Disks = append(spec.Disks,
+			&machinev1.GCPDisk{
+				AutoDelete: true,
+				Type:       gcpLocalSSDDiskType,
+				SizeGB:     gcpLocalSSDSizeGB,
+			},
+			&machinev1.GCPDisk{
+				AutoDelete: true,
+				Type:       gcpPdSSDDiskType,
+				SizeGB:     64,
+			},
+		)
+
+		var err error
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred(), "Failed to marshal providerSpec")
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with additional disks")
+		toDelete = append(toDelete, machineSet)
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the disks attached to the node")
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get nodes from MachineSet")
+		Expect(nodes).To(HaveLen(1))
+
+		disks, err := framework.GetNodeDisks(ctx, clientset, nodes[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to list the disks attached to the node")
+		Expect(disks).To(HaveLen(len(spec.Disks)), "Expected the boot disk and the additional disks to be attached to the node")
+		Expect(disks).To(ContainElement(HaveField("SizeBytes", BeEquivalentTo(int64(gcpLocalSSDSizeGB)<<30))), "Expected the local SSD to be attached to the node")
+		Expect(disks).To(ContainElement(HaveField("SizeBytes", BeEquivalentTo(int64(64)<<30))), "Expected the additional persistent disk to be attached to the node")
+	})
+
+	// Machines required for test: 1
+	// Reason: Reads the network tags from the metadata server of the instance, so it requires a machine to be running.
+	// The labels are not verified: the metadata server does not expose them and the framework has no GCP client.
+	It("should apply additional network tags to the instance", framework.LabelMachines(1), func() {
+		networkTag := "machine-api-e2e-" + framework.Rand.String(8)
+
+		By(fmt.Sprintf("Create machineset with the additional network tag %s", networkTag))
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.GCPMachineProviderSpec{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		spec.Tags = append(spec.Tags, networkTag)
+
+		var err error
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred(), "Failed to marshal providerSpec")
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with an additional network tag")
+		toDelete = append(toDelete, machineSet)
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the network tags of the instance")
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get nodes from MachineSet")
+		Expect(nodes).To(HaveLen(1))
+
+		tags, err := framework.GetGCPInstanceNetworkTags(ctx, clientset, nodes[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the network tags of the instance")
+		Expect(tags).To(ContainElements(spec.Tags), "Expected the instance to have the network tags of the machine")
+	})
+
+	// Machines required for test: 1
+	// Reason: Verifies the capacity reported by the node, so it requires a machine to be running.
+	It("should run a machine with a custom machine type", framework.LabelMachines(1), func() {
+		By(fmt.Sprintf("Create machineset with machine type %s", gcpCustomMachineType))
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+		spec := machinev1.GCPMachineProviderSpec{}
+		Expect(json.Unmarshal(machineSetParams.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+
+		spec.MachineType = gcpCustomMachineType
+
+		var err error
+
+		machineSetParams.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+		Expect(err).ToNot(HaveOccurred(), "Failed to marshal providerSpec")
+
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with a custom machine type")
+		toDelete = append(toDelete, machineSet)
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the node matches the custom machine type")
+		nodes, err := framework.GetNodesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get nodes from MachineSet")
+		Expect(nodes).To(HaveLen(1))
+		Expect(nodes[0].Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, gcpCustomMachineType))
+		Expect(nodes[0].Status.Capacity.Cpu().Value()).To(BeEquivalentTo(4), "Expected the node to have 4 vCPUs")
+	})
+
+	// Machines required for test: 1
+	// Reason: Verifies the firmware of the host of the node, so it requires a machine to be running.
+	DescribeTable("should boot a machine with the Shielded VM options", func(uefi bool, config machinev1.GCPShieldedInstanceConfig) {
+		if config.IntegrityMonitoring == machinev1.IntegrityMonitoringPolicyEnabled && config.VirtualizedTrustedPlatformModule != machinev1.VirtualizedTrustedPlatformModulePolicyEnabled {
+			Skip("Integrity monitoring requires the virtual TPM")
+		}
+
+		if !uefi && config != gcpShieldedVMDisabled {
+			Skip("Shielded VM options require an image supporting UEFI")
+		}
+
+		machineSetParams := framework.BuildMachineSetParams(ctx, client, 1)
+
+		image, err := framework.ResolveGCPBootImage(machineSetParams.ProviderSpec, uefi)
+		if errors.Is(err, framework.ErrGCPNonUEFIImageNotSet) {
+			Skip(fmt.Sprintf("Skipping the non UEFI image: %v", err))
+		}
+
+		Expect(err).ToNot(HaveOccurred(), "Failed to resolve the boot image")
+
+		machineSetParams.ProviderSpec, err = framework.UpdateProviderSpecGCPBootImage(machineSetParams.ProviderSpec, image)
+		Expect(err).ToNot(HaveOccurred(), "Failed to override the boot image")
+
+		machineSetParams.ProviderSpec, err = framework.UpdateProviderSpecGCPShieldedInstanceConfig(machineSetParams.ProviderSpec, config)
+		Expect(err).ToNot(HaveOccurred(), "Failed to set the Shielded VM options")
+
+		By(fmt.Sprintf("Create machineset booting %s with the Shielded VM options %+v", image, config))
+		machineSet, err := framework.CreateMachineSet(client, machineSetParams)
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet with the Shielded VM options")
+		toDelete = append(toDelete, machineSet)
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		By("Check the machine keeps the Shielded VM options")
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get machines from MachineSet")
+		Expect(machines).To(HaveLen(1))
+
+		spec := machinev1.GCPMachineProviderSpec{}
+		Expect(json.Unmarshal(machines[0].Spec.ProviderSpec.Value.Raw, &spec)).To(Succeed())
+		Expect(spec.ShieldedInstanceConfig).To(Equal(config), "Expected the machine to have the Shielded VM options of the MachineSet")
+
+		By("Check the firmware of the node matches the image and the Shielded VM options")
+		node, err := framework.GetNodeForMachine(ctx, client, machines[0])
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the node of the machine")
+
+		firmware, err := framework.GetNodeFirmware(ctx, clientset, node)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get the firmware of the node")
+		Expect(firmware).To(Equal(framework.NodeFirmware{
+			UEFI:       uefi,
+			SecureBoot: config.SecureBoot == machinev1.SecureBootPolicyEnabled,
+			TPM:        config.VirtualizedTrustedPlatformModule == machinev1.VirtualizedTrustedPlatformModulePolicyEnabled,
+		}), "Expected the firmware of the node to match the image and the Shielded VM options")
+	}, gcpShieldedVMEntries())
+})
+
+// gcpShieldedVMDisabled is the Shielded VM configuration with all the options disabled.
+var gcpShieldedVMDisabled = machinev1.GCPShieldedInstanceConfig{
+	SecureBoot:                       machinev1.SecureBootPolicyDisabled,
+	VirtualizedTrustedPlatformModule: machinev1.VirtualizedTrustedPlatformModulePolicyDisabled,
+	IntegrityMonitoring:              machinev1.IntegrityMonitoringPolicyDisabled,
+}
+
+// gcpShieldedVMEntries returns the matrix of the UEFI and non UEFI images and of the Shielded VM options.
+func gcpShieldedVMEntries() []TableEntry {
+	var entries []TableEntry
+
+	for _, uefi := range []bool{true, false} {
+		imageKind := "UEFI"
+		if !uefi {
+			imageKind = "non UEFI"
+		}
+
+		for _, secureBoot := range []machinev1.SecureBootPolicy{machinev1.SecureBootPolicyEnabled, machinev1.SecureBootPolicyDisabled} {
+			for _, vtpm := range []machinev1.VirtualizedTrustedPlatformModulePolicy{machinev1.VirtualizedTrustedPlatformModulePolicyEnabled, machinev1.VirtualizedTrustedPlatformModulePolicyDisabled} {
+				for _, integrityMonitoring := range []machinev1.IntegrityMonitoringPolicy{machinev1.IntegrityMonitoringPolicyEnabled, machinev1.IntegrityMonitoringPolicyDisabled} {
+					entries = append(entries, Entry(
+						fmt.Sprintf("%s image, secure boot %s, vTPM %s, integrity monitoring %s", imageKind, secureBoot, vtpm, integrityMonitoring),
+						framework.LabelMachines(1),
+						uefi,
+						machinev1.GCPShieldedInstanceConfig{
+							SecureBoot:                       secureBoot,
+							VirtualizedTrustedPlatformModule: vtpm,
+							IntegrityMonitoring:              integrityMonitoring,
+						},
+					))
+				}
+			}
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1beta1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework"
+	"github.com/openshift/cluster-api-actuator-pkg/pkg/framework/gatherer"
+)
+
+// The tags of the Infrastructure resource are not covered: this version of the API only has them in
+// its status, which cannot be updated on day 2. GCP is not covered either, the framework has no GCP
+// client to read the labels of an instance, which its metadata service does not expose.
+var _ = Describe("Day-2 user tags", framework.LabelDisruptive, framework.LabelMAPI, framework.LabelPlatforms(configv1.AWSPlatformType, configv1.AzurePlatformType), framework.LabelRequiresMachineManagement, func() {
+	var client runtimeclient.Client
+	var clientset *kubernetes.Clientset
+
+	var gatherer *gatherer.StateGatherer
+	var ctx context.Context
+
+	var platform configv1.PlatformType
+	var awsClient *framework.AwsClient
+	var machineSet *machinev1.MachineSet
+
+	BeforeEach(func() {
+		var err error
+		client, err = framework.LoadClient()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load client")
+
+		clientset, err = framework.LoadClientset()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load clientset")
+
+		gatherer, err = framework.NewGatherer()
+		Expect(err).ToNot(HaveOccurred(), "Failed to load gatherer")
+
+		ctx = framework.GetContext()
+
+		platform, err = framework.GetPlatform(ctx, client)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get platform")
+
+		switch platform {
+		case configv1.AWSPlatformType:
+			oc, err := framework.NewCLI()
+			Expect(err).ToNot(HaveOccurred(), "Failed to create CLI")
+			awsClient = framework.NewAwsClient(framework.GetCredentialsFromCluster(oc)).WithContext(ctx)
+		case configv1.AzurePlatformType:
+			// The tags are read from the instance metadata service, no cloud client is needed.
+		default:
+			Skip(fmt.Sprintf("skipping day-2 user tags tests on %s, the instance tags cannot be verified", platform))
+		}
+
+		machineSet = nil
+
+		// Make sure to clean up the resources we created
+		DeferCleanup(func() {
+			if machineSet != nil {
+				Expect(framework.DeleteMachineSets(ctx, client, machineSet)).To(Succeed())
+				framework.WaitForMachineSetsDeleted(ctx, client, machineSet)
+			}
+		})
+	})
+
+	AfterEach(func() {
+		specReport := CurrentSpecReport()
+		if specReport.Failed() {
+			Expect(gatherer.WithSpecReport(specReport).GatherAll()).To(Succeed())
+		}
+	})
+
+	// getInstanceTags returns the tags of the cloud instance backing the machine, read from the cloud API on AWS
+	// and from the instance metadata service of the node on Azure.
+	getInstanceTags := func(machine *machinev1.Machine) (map[string]string, error) {
+		switch platform {
+		case configv1.AWSPlatformType:
+			instance, err := awsClient.DescribeInstanceByProviderID(ptr.Deref(machine.Spec.ProviderID, ""))
+			if err != nil {
+				return nil, err
+			}
+
+			return framework.AWSInstanceTags(instance), nil
+		default:
+			node, err := framework.GetNodeForMachine(ctx, client, machine)
+			if err != nil {
+				return nil, err
+			}
+
+			metadata, err := framework.GetAzureInstanceMetadata(ctx, clientset, node)
+			if err != nil {
+				return nil, err
+			}
+
+			return metadata.Compute.Tags(), nil
+		}
+	}
+
+	// Machines required for test: 1
+	// Reason: The tags are updated in place on a single running machine.
+	It("should propagate updated tags to the running instance without replacing the machine", framework.LabelMachines(1), func() {
+		By("Create machineset with a single machine")
+		var err error
+
+		machineSet, err = framework.CreateMachineSet(client, framework.BuildMachineSetParams(ctx, client, 1))
+		Expect(err).ToNot(HaveOccurred(), "Failed to create MachineSet")
+		framework.WaitForMachineSet(ctx, client, machineSet.GetName())
+
+		machines, err := framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get machines from MachineSet")
+		Expect(machines).To(HaveLen(1))
+
+		machine := machines[0]
+		Expect(machine.Spec.ProviderID).ToNot(BeNil(), "Expected the machine to have a providerID")
+		providerID := *machine.Spec.ProviderID
+
+		tagName := "e2e-day2-tag"
+		tagValue := framework.Rand.String(8)
+
+		By(fmt.Sprintf("Update the provider spec of machine %s with the tag %s=%s", machine.Name, tagName, tagValue))
+		patch := runtimeclient.MergeFrom(machine.DeepCopy())
+		providerSpec, err := framework.UpdateProviderSpecTags(&machine.Spec.ProviderSpec, platform, map[string]string{tagName: tagValue})
+		Expect(err).ToNot(HaveOccurred(), "Failed to update the tags of the provider spec")
+
+		machine.Spec.ProviderSpec = *providerSpec
+		Expect(client.Patch(ctx, machine, patch)).To(Succeed(), "Failed to update the tags of the machine")
+
+		By("Check the tag propagates to the cloud instance")
+		Eventually(func() (map[string]string, error) {
+			return getInstanceTags(machine)
+		}, framework.WaitLong, framework.RetryMedium).Should(HaveKeyWithValue(tagName, tagValue), "Expected the instance to be tagged in place")
+
+		By("Check the machine was not replaced")
+		machines, err = framework.GetMachinesFromMachineSet(ctx, client, machineSet)
+		Expect(err).ToNot(HaveOccurred(), "Failed to get machines from MachineSet")
+		Expect(machines).To(ConsistOf(HaveField("Name", machine.Name)), "Expected the MachineSet to keep its machine")
+		Expect(ptr.Deref(machines[0].Spec.ProviderID, "")).To(Equal(providerID), "Expected the machine to keep its instance")
+		Expect(ptr.Deref(machines[0].Status.Phase, "")).To(Equal(framework.MachinePhaseRunning), "Expected the machine to stay Running")
+	})
+})
@@ -10,12 +10,14 @@ require (
 	github.com/onsi/ginkgo/v2 v2.22.0
 	github.com/onsi/gomega v1.36.0
 	github.com/openshift/api v0.0.0-20250106182855-361e35fd82e5
+	github.com/openshift/cluster-autoscaler-operator v0.0.1-0.20240509123215-40cadf8a4729
 	k8s.io/api v0.31.1
 	k8s.io/apimachinery v0.31.1
 	k8s.io/client-go v0.31.0
 	k8s.io/utils v0.0.0-20240711033017-18e509b52bc8
 	sigs.k8s.io/cluster-api v1.8.5
 	sigs.k8s.io/cluster-api-provider-aws/v2 v2.6.1
+	sigs.k8s.io/cluster-api-provider-gcp v1.8.0
 	sigs.k8s.io/cluster-api-provider-ibmcloud v0.9.0
 	sigs.k8s.io/cluster-api-provider-openstack v0.11.3
 	sigs.k8s.io/controller-runtime v0.19.0
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	caov1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterAutoscaler creates a new ClusterAutoscaler builder.
+func ClusterAutoscaler() ClusterAutoscalerBuilder {
+	return ClusterAutoscalerBuilder{}
+}
+
+// ClusterAutoscalerBuilder is used to build out a ClusterAutoscaler object.
+type ClusterAutoscalerBuilder struct {
+	// Object meta fields.
+	annotations map[string]string
+	labels      map[string]string
+	name        string
+	namespace   string
+
+	// Spec fields.
+	balanceSimilarNodeGroups    *bool
+	balancingIgnoredLabels      []string
+	expanders                   []caov1.ExpanderString
+	ignoreDaemonsetsUtilization *bool
+	logVerbosity                *int32
+	maxNodeProvisionTime        string
+	maxNodesTotal               *int32
+	maxPodGracePeriod           *int32
+	podPriorityThreshold        *int32
+	resourceLimits              *caov1.ResourceLimits
+	scaleDown                   *caov1.ScaleDownConfig
+	skipNodesWithLocalStorage   *bool
+}
+
+// Build builds a new ClusterAutoscaler based on the configuration provided.
+func (c ClusterAutoscalerBuilder) Build() *caov1.ClusterAutoscaler {
+	clusterAutoscaler := &caov1.ClusterAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterAutoscaler",
+			APIVersion: caov1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: c.annotations,
+			Labels:      c.labels,
+			Name:        c.name,
+			Namespace:   c.namespace,
+		},
+		Spec: caov1.ClusterAutoscalerSpec{
+			BalanceSimilarNodeGroups:    c.balanceSimilarNodeGroups,
+			BalancingIgnoredLabels:      c.balancingIgnoredLabels,
+			Expanders:                   c.expanders,
+			IgnoreDaemonsetsUtilization: c.ignoreDaemonsetsUtilization,
+			LogVerbosity:                c.logVerbosity,
+			MaxNodeProvisionTime:        c.maxNodeProvisionTime,
+			MaxPodGracePeriod:           c.maxPodGracePeriod,
+			PodPriorityThreshold:        c.podPriorityThreshold,
+			ResourceLimits:              c.buildResourceLimits(),
+			ScaleDown:                   c.scaleDown.DeepCopy(),
+			SkipNodesWithLocalStorage:   c.skipNodesWithLocalStorage,
+		},
+	}
+
+	return clusterAutoscaler
+}
+
+// buildResourceLimits returns the resource limits, with the max nodes total when specified.
+func (c ClusterAutoscalerBuilder) buildResourceLimits() *caov1.ResourceLimits {
+	if c.resourceLimits == nil && c.maxNodesTotal == nil {
+		return nil
+	}
+
+	resourceLimits := &caov1.ResourceLimits{}
+	if c.resourceLimits != nil {
+		resourceLimits = c.resourceLimits.DeepCopy()
+	}
+
+	if c.maxNodesTotal != nil {
+		maxNodesTotal := *c.maxNodesTotal
+		resourceLimits.MaxNodesTotal = &maxNodesTotal
+	}
+
+	return resourceLimits
+}
+
+// Object meta fields.
+
+// WithAnnotations sets the annotations for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithAnnotations(annotations map[string]string) ClusterAutoscalerBuilder {
+	c.annotations = annotations
+	return c
+}
+
+// WithLabel sets a label for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithLabel(key, value string) ClusterAutoscalerBuilder {
+	labels := make(map[string]string, len(c.labels)+1)
+	for k, v := range c.labels {
+		labels[k] = v
+	}
+
+	labels[key] = value
+	c.labels = labels
+
+	return c
+}
+
+// WithLabels sets the labels for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithLabels(labels map[string]string) ClusterAutoscalerBuilder {
+	c.labels = labels
+	return c
+}
+
+// WithName sets the name for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithName(name string) ClusterAutoscalerBuilder {
+	c.name = name
+	return c
+}
+
+// WithNamespace sets the namespace for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithNamespace(namespace string) ClusterAutoscalerBuilder {
+	c.namespace = namespace
+	return c
+}
+
+// Spec fields.
+
+// WithBalanceSimilarNodeGroups sets the balanceSimilarNodeGroups for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithBalanceSimilarNodeGroups(balance bool) ClusterAutoscalerBuilder {
+	c.balanceSimilarNodeGroups = &balance
+	return c
+}
+
+// WithBalancingIgnoredLabels sets the balancingIgnoredLabels for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithBalancingIgnoredLabels(labels ...string) ClusterAutoscalerBuilder {
+	c.balancingIgnoredLabels = labels
+	return c
+}
+
+// WithExpanders sets the expanders for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithExpanders(expanders ...caov1.ExpanderString) ClusterAutoscalerBuilder {
+	c.expanders = expanders
+	return c
+}
+
+// WithIgnoreDaemonsetsUtilization sets the ignoreDaemonsetsUtilization for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithIgnoreDaemonsetsUtilization(ignore bool) ClusterAutoscalerBuilder {
+	c.ignoreDaemonsetsUtilization = &ignore
+	return c
+}
+
+// WithLogVerbosity sets the logVerbosity for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithLogVerbosity(verbosity int32) ClusterAutoscalerBuilder {
+	c.logVerbosity = &verbosity
+	return c
+}
+
+// WithMaxNodeProvisionTime sets the maxNodeProvisionTime for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithMaxNodeProvisionTime(maxNodeProvisionTime string) ClusterAutoscalerBuilder {
+	c.maxNodeProvisionTime = maxNodeProvisionTime
+	return c
+}
+
+// WithMaxNodesTotal sets the resource limits maxNodesTotal for the ClusterAutoscaler builder.
+// It takes precedence over the maxNodesTotal of WithResourceLimits.
+func (c ClusterAutoscalerBuilder) WithMaxNodesTotal(maxNodesTotal int32) ClusterAutoscalerBuilder {
+	c.maxNodesTotal = &maxNodesTotal
+	return c
+}
+
+// WithMaxPodGracePeriod sets the maxPodGracePeriod for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithMaxPodGracePeriod(maxPodGracePeriod int32) ClusterAutoscalerBuilder {
+	c.maxPodGracePeriod = &maxPodGracePeriod
+	return c
+}
+
+// WithPodPriorityThreshold sets the podPriorityThreshold for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithPodPriorityThreshold(threshold int32) ClusterAutoscalerBuilder {
+	c.podPriorityThreshold = &threshold
+	return c
+}
+
+// WithResourceLimits sets the resourceLimits for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithResourceLimits(resourceLimits *caov1.ResourceLimits) ClusterAutoscalerBuilder {
+	c.resourceLimits = resourceLimits
+	return c
+}
+
+// WithScaleDown sets the scaleDown configuration for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithScaleDown(scaleDown *caov1.ScaleDownConfig) ClusterAutoscalerBuilder {
+	c.scaleDown = scaleDown
+	return c
+}
+
+// WithSkipNodesWithLocalStorage sets the skipNodesWithLocalStorage for the ClusterAutoscaler builder.
+func (c ClusterAutoscalerBuilder) WithSkipNodesWithLocalStorage(skip bool) ClusterAutoscalerBuilder {
+	c.skipNodesWithLocalStorage = &skip
+	return c
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	caov1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("ClusterAutoscaler", func() {
+	Describe("Build", func() {
+		It("should return a default clusterAutoscaler when no options are specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler).ToNot(BeNil())
+		})
+
+		It("should set the type meta", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Kind).To(Equal("ClusterAutoscaler"))
+			Expect(clusterAutoscaler.APIVersion).To(Equal("autoscaling.openshift.io/v1"))
+		})
+	})
+
+	Describe("WithAnnotations", func() {
+		It("should return the custom value when specified", func() {
+			annotations := map[string]string{"key": "value"}
+			clusterAutoscaler := ClusterAutoscaler().WithAnnotations(annotations).Build()
+			Expect(clusterAutoscaler.Annotations).To(Equal(annotations))
+		})
+
+		It("should return nil when not specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Annotations).To(BeNil())
+		})
+	})
+
+	Describe("WithLabel", func() {
+		It("should add the label to the existing labels", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithLabels(map[string]string{"a": "1"}).WithLabel("b", "2").Build()
+			Expect(clusterAutoscaler.Labels).To(Equal(map[string]string{"a": "1", "b": "2"}))
+		})
+
+		It("should not modify the labels of the original builder", func() {
+			builder := ClusterAutoscaler().WithLabel("a", "1")
+			_ = builder.WithLabel("b", "2")
+			Expect(builder.Build().Labels).To(Equal(map[string]string{"a": "1"}))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := map[string]string{"key": "value"}
+			clusterAutoscaler := ClusterAutoscaler().WithLabels(labels).Build()
+			Expect(clusterAutoscaler.Labels).To(Equal(labels))
+		})
+
+		It("should return nil when not specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Labels).To(BeNil())
+		})
+	})
+
+	Describe("WithName", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithName("default").Build()
+			Expect(clusterAutoscaler.Name).To(Equal("default"))
+		})
+	})
+
+	Describe("WithNamespace", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithNamespace("ns").Build()
+			Expect(clusterAutoscaler.Namespace).To(Equal("ns"))
+		})
+	})
+
+	Describe("WithBalanceSimilarNodeGroups", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithBalanceSimilarNodeGroups(true).Build()
+			Expect(clusterAutoscaler.Spec.BalanceSimilarNodeGroups).To(HaveValue(BeTrue()))
+		})
+
+		It("should return nil when not specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Spec.BalanceSimilarNodeGroups).To(BeNil())
+		})
+	})
+
+	Describe("WithBalancingIgnoredLabels", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithBalancingIgnoredLabels("a", "b").Build()
+			Expect(clusterAutoscaler.Spec.BalancingIgnoredLabels).To(Equal([]string{"a", "b"}))
+		})
+	})
+
+	Describe("WithExpanders", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithExpanders(caov1.PriorityExpander, caov1.LeastWasteExpander).Build()
+			Expect(clusterAutoscaler.Spec.Expanders).To(Equal([]caov1.ExpanderString{caov1.PriorityExpander, caov1.LeastWasteExpander}))
+		})
+
+		It("should return nil when not specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Spec.Expanders).To(BeNil())
+		})
+	})
+
+	Describe("WithIgnoreDaemonsetsUtilization", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithIgnoreDaemonsetsUtilization(true).Build()
+			Expect(clusterAutoscaler.Spec.IgnoreDaemonsetsUtilization).To(HaveValue(BeTrue()))
+		})
+	})
+
+	Describe("WithLogVerbosity", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithLogVerbosity(4).Build()
+			Expect(clusterAutoscaler.Spec.LogVerbosity).To(HaveValue(BeEquivalentTo(4)))
+		})
+	})
+
+	Describe("WithMaxNodeProvisionTime", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithMaxNodeProvisionTime("30m").Build()
+			Expect(clusterAutoscaler.Spec.MaxNodeProvisionTime).To(Equal("30m"))
+		})
+	})
+
+	Describe("WithMaxNodesTotal", func() {
+		It("should set the resource limits when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithMaxNodesTotal(100).Build()
+			Expect(clusterAutoscaler.Spec.ResourceLimits).To(HaveField("MaxNodesTotal", HaveValue(BeEquivalentTo(100))))
+		})
+
+		It("should take precedence over the resource limits", func() {
+			resourceLimits := &caov1.ResourceLimits{
+				MaxNodesTotal: ptr.To[int32](10),
+				Cores:         &caov1.ResourceRange{Min: 1, Max: 2},
+			}
+			clusterAutoscaler := ClusterAutoscaler().WithResourceLimits(resourceLimits).WithMaxNodesTotal(100).Build()
+			Expect(clusterAutoscaler.Spec.ResourceLimits).To(HaveField("MaxNodesTotal", HaveValue(BeEquivalentTo(100))))
+			Expect(clusterAutoscaler.Spec.ResourceLimits.Cores).To(Equal(resourceLimits.Cores))
+			Expect(resourceLimits.MaxNodesTotal).To(HaveValue(BeEquivalentTo(10)), "the given resource limits should not be modified")
+		})
+
+		It("should leave the resource limits nil when not specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Spec.ResourceLimits).To(BeNil())
+		})
+	})
+
+	Describe("WithMaxPodGracePeriod", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithMaxPodGracePeriod(60).Build()
+			Expect(clusterAutoscaler.Spec.MaxPodGracePeriod).To(HaveValue(BeEquivalentTo(60)))
+		})
+	})
+
+	Describe("WithPodPriorityThreshold", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithPodPriorityThreshold(-10).Build()
+			Expect(clusterAutoscaler.Spec.PodPriorityThreshold).To(HaveValue(BeEquivalentTo(-10)))
+		})
+	})
+
+	Describe("WithResourceLimits", func() {
+		It("should return the custom value when specified", func() {
+			resourceLimits := &caov1.ResourceLimits{
+				Memory: &caov1.ResourceRange{Min: 1, Max: 2},
+			}
+			clusterAutoscaler := ClusterAutoscaler().WithResourceLimits(resourceLimits).Build()
+			Expect(clusterAutoscaler.Spec.ResourceLimits).To(Equal(resourceLimits))
+		})
+	})
+
+	Describe("WithScaleDown", func() {
+		It("should return the custom value when specified", func() {
+			scaleDown := &caov1.ScaleDownConfig{
+				Enabled:      true,
+				UnneededTime: ptr.To("60s"),
+			}
+			clusterAutoscaler := ClusterAutoscaler().WithScaleDown(scaleDown).Build()
+			Expect(clusterAutoscaler.Spec.ScaleDown).To(Equal(scaleDown))
+		})
+
+		It("should return nil when not specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().Build()
+			Expect(clusterAutoscaler.Spec.ScaleDown).To(BeNil())
+		})
+	})
+
+	Describe("WithSkipNodesWithLocalStorage", func() {
+		It("should return the custom value when specified", func() {
+			clusterAutoscaler := ClusterAutoscaler().WithSkipNodesWithLocalStorage(false).Build()
+			Expect(clusterAutoscaler.Spec.SkipNodesWithLocalStorage).To(HaveValue(BeFalse()))
+		})
+	})
+})
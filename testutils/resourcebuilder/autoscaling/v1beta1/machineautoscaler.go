@@ -0,0 +1,145 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	caov1beta1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MachineAutoscaler creates a new MachineAutoscaler builder.
+func MachineAutoscaler() MachineAutoscalerBuilder {
+	return MachineAutoscalerBuilder{}
+}
+
+// MachineAutoscalerBuilder is used to build out a MachineAutoscaler object.
+type MachineAutoscalerBuilder struct {
+	// Object meta fields.
+	annotations  map[string]string
+	generateName string
+	labels       map[string]string
+	name         string
+	namespace    string
+
+	// Spec fields.
+	maxReplicas    int32
+	minReplicas    int32
+	scaleTargetRef caov1beta1.CrossVersionObjectReference
+
+	// Status fields.
+	lastTargetRef *caov1beta1.CrossVersionObjectReference
+}
+
+// Build builds a new MachineAutoscaler based on the configuration provided.
+func (m MachineAutoscalerBuilder) Build() *caov1beta1.MachineAutoscaler {
+	machineAutoscaler := &caov1beta1.MachineAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MachineAutoscaler",
+			APIVersion: caov1beta1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations:  m.annotations,
+			GenerateName: m.generateName,
+			Labels:       m.labels,
+			Name:         m.name,
+			Namespace:    m.namespace,
+		},
+		Spec: caov1beta1.MachineAutoscalerSpec{
+			MaxReplicas:    m.maxReplicas,
+			MinReplicas:    m.minReplicas,
+			ScaleTargetRef: m.scaleTargetRef,
+		},
+		Status: caov1beta1.MachineAutoscalerStatus{
+			LastTargetRef: m.lastTargetRef,
+		},
+	}
+
+	return machineAutoscaler
+}
+
+// Object meta fields.
+
+// WithAnnotations sets the annotations for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithAnnotations(annotations map[string]string) MachineAutoscalerBuilder {
+	m.annotations = annotations
+	return m
+}
+
+// WithGenerateName sets the generateName for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithGenerateName(generateName string) MachineAutoscalerBuilder {
+	m.generateName = generateName
+	return m
+}
+
+// WithLabel sets a label for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithLabel(key, value string) MachineAutoscalerBuilder {
+	labels := make(map[string]string, len(m.labels)+1)
+	for k, v := range m.labels {
+		labels[k] = v
+	}
+
+	labels[key] = value
+	m.labels = labels
+
+	return m
+}
+
+// WithLabels sets the labels for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithLabels(labels map[string]string) MachineAutoscalerBuilder {
+	m.labels = labels
+	return m
+}
+
+// WithName sets the name for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithName(name string) MachineAutoscalerBuilder {
+	m.name = name
+	return m
+}
+
+// WithNamespace sets the namespace for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithNamespace(namespace string) MachineAutoscalerBuilder {
+	m.namespace = namespace
+	return m
+}
+
+// Spec fields.
+
+// WithMaxReplicas sets the maxReplicas for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithMaxReplicas(maxReplicas int32) MachineAutoscalerBuilder {
+	m.maxReplicas = maxReplicas
+	return m
+}
+
+// WithMinReplicas sets the minReplicas for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithMinReplicas(minReplicas int32) MachineAutoscalerBuilder {
+	m.minReplicas = minReplicas
+	return m
+}
+
+// WithScaleTargetRef sets the scaleTargetRef for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithScaleTargetRef(scaleTargetRef caov1beta1.CrossVersionObjectReference) MachineAutoscalerBuilder {
+	m.scaleTargetRef = scaleTargetRef
+	return m
+}
+
+// Status fields.
+
+// WithStatusLastTargetRef sets the status lastTargetRef for the MachineAutoscaler builder.
+func (m MachineAutoscalerBuilder) WithStatusLastTargetRef(lastTargetRef *caov1beta1.CrossVersionObjectReference) MachineAutoscalerBuilder {
+	m.lastTargetRef = lastTargetRef
+	return m
+}
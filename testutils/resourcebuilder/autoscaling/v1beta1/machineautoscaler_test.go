@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	caov1beta1 "github.com/openshift/cluster-autoscaler-operator/pkg/apis/autoscaling/v1beta1"
+)
+
+var _ = Describe("MachineAutoscaler", func() {
+	Describe("Build", func() {
+		It("should return a default machineAutoscaler when no options are specified", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler).ToNot(BeNil())
+		})
+
+		It("should set the type meta", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler.Kind).To(Equal("MachineAutoscaler"))
+			Expect(machineAutoscaler.APIVersion).To(Equal("autoscaling.openshift.io/v1beta1"))
+		})
+	})
+
+	Describe("WithAnnotations", func() {
+		It("should return the custom value when specified", func() {
+			annotations := map[string]string{"key": "value"}
+			machineAutoscaler := MachineAutoscaler().WithAnnotations(annotations).Build()
+			Expect(machineAutoscaler.Annotations).To(Equal(annotations))
+		})
+
+		It("should return nil when not specified", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler.Annotations).To(BeNil())
+		})
+	})
+
+	Describe("WithGenerateName", func() {
+		It("should return the custom value when specified", func() {
+			machineAutoscaler := MachineAutoscaler().WithGenerateName("autoscale-").Build()
+			Expect(machineAutoscaler.GenerateName).To(Equal("autoscale-"))
+		})
+	})
+
+	Describe("WithLabel", func() {
+		It("should add the label to the existing labels", func() {
+			machineAutoscaler := MachineAutoscaler().WithLabels(map[string]string{"a": "1"}).WithLabel("b", "2").Build()
+			Expect(machineAutoscaler.Labels).To(Equal(map[string]string{"a": "1", "b": "2"}))
+		})
+
+		It("should not modify the labels of the original builder", func() {
+			builder := MachineAutoscaler().WithLabel("a", "1")
+			_ = builder.WithLabel("b", "2")
+			Expect(builder.Build().Labels).To(Equal(map[string]string{"a": "1"}))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := map[string]string{"key": "value"}
+			machineAutoscaler := MachineAutoscaler().WithLabels(labels).Build()
+			Expect(machineAutoscaler.Labels).To(Equal(labels))
+		})
+
+		It("should return nil when not specified", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler.Labels).To(BeNil())
+		})
+	})
+
+	Describe("WithName", func() {
+		It("should return the custom value when specified", func() {
+			machineAutoscaler := MachineAutoscaler().WithName("autoscaler").Build()
+			Expect(machineAutoscaler.Name).To(Equal("autoscaler"))
+		})
+	})
+
+	Describe("WithNamespace", func() {
+		It("should return the custom value when specified", func() {
+			machineAutoscaler := MachineAutoscaler().WithNamespace("ns").Build()
+			Expect(machineAutoscaler.Namespace).To(Equal("ns"))
+		})
+	})
+
+	Describe("WithMaxReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineAutoscaler := MachineAutoscaler().WithMaxReplicas(3).Build()
+			Expect(machineAutoscaler.Spec.MaxReplicas).To(BeEquivalentTo(3))
+		})
+
+		It("should return the default value when not specified", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler.Spec.MaxReplicas).To(BeZero())
+		})
+	})
+
+	Describe("WithMinReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineAutoscaler := MachineAutoscaler().WithMinReplicas(1).Build()
+			Expect(machineAutoscaler.Spec.MinReplicas).To(BeEquivalentTo(1))
+		})
+
+		It("should return the default value when not specified", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler.Spec.MinReplicas).To(BeZero())
+		})
+	})
+
+	Describe("WithScaleTargetRef", func() {
+		It("should return the custom value when specified", func() {
+			scaleTargetRef := caov1beta1.CrossVersionObjectReference{
+				APIVersion: "machine.openshift.io/v1beta1",
+				Kind:       "MachineSet",
+				Name:       "machineset",
+			}
+			machineAutoscaler := MachineAutoscaler().WithScaleTargetRef(scaleTargetRef).Build()
+			Expect(machineAutoscaler.Spec.ScaleTargetRef).To(Equal(scaleTargetRef))
+		})
+	})
+
+	Describe("WithStatusLastTargetRef", func() {
+		It("should return the custom value when specified", func() {
+			lastTargetRef := &caov1beta1.CrossVersionObjectReference{
+				Kind: "MachineSet",
+				Name: "machineset",
+			}
+			machineAutoscaler := MachineAutoscaler().WithStatusLastTargetRef(lastTargetRef).Build()
+			Expect(machineAutoscaler.Status.LastTargetRef).To(Equal(lastTargetRef))
+		})
+
+		It("should return nil when not specified", func() {
+			machineAutoscaler := MachineAutoscaler().Build()
+			Expect(machineAutoscaler.Status.LastTargetRef).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MachineDeployment creates a new MachineDeployment builder.
+func MachineDeployment() MachineDeploymentBuilder {
+	return MachineDeploymentBuilder{}
+}
+
+// MachineDeploymentBuilder is used to build out a MachineDeployment object.
+type MachineDeploymentBuilder struct {
+	// Object meta fields.
+	annotations       map[string]string
+	creationTimestamp metav1.Time
+	deletionTimestamp *metav1.Time
+	generateName      string
+	labels            map[string]string
+	name              string
+	namespace         string
+	ownerReferences   []metav1.OwnerReference
+
+	// Spec fields.
+	clusterName             string
+	minReadySeconds         *int32
+	paused                  bool
+	progressDeadlineSeconds *int32
+	replicas                *int32
+	revisionHistoryLimit    *int32
+	rolloutAfter            *metav1.Time
+	selector                metav1.LabelSelector
+	strategy                *capiv1.MachineDeploymentStrategy
+	template                capiv1.MachineTemplateSpec
+
+	// Status fields.
+	availableReplicas   int32
+	conditions          capiv1.Conditions
+	observedGeneration  int64
+	phase               string
+	readyReplicas       int32
+	statusReplicas      int32
+	statusSelector      string
+	unavailableReplicas int32
+	updatedReplicas     int32
+}
+
+// Build builds a new MachineDeployment based on the configuration provided.
+func (m MachineDeploymentBuilder) Build() *capiv1.MachineDeployment {
+	machineDeployment := &capiv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations:       m.annotations,
+			CreationTimestamp: m.creationTimestamp,
+			DeletionTimestamp: m.deletionTimestamp,
+			GenerateName:      m.generateName,
+			Labels:            m.labels,
+			Name:              m.name,
+			Namespace:         m.namespace,
+			OwnerReferences:   m.ownerReferences,
+		},
+		Spec: capiv1.MachineDeploymentSpec{
+			ClusterName:             m.clusterName,
+			MinReadySeconds:         m.minReadySeconds,
+			Paused:                  m.paused,
+			ProgressDeadlineSeconds: m.progressDeadlineSeconds,
+			Replicas:                m.replicas,
+			RevisionHistoryLimit:    m.revisionHistoryLimit,
+			RolloutAfter:            m.rolloutAfter,
+			Selector:                m.selector,
+			Strategy:                m.strategy,
+			Template:                m.template,
+		},
+		Status: capiv1.MachineDeploymentStatus{
+			AvailableReplicas:   m.availableReplicas,
+			Conditions:          m.conditions,
+			ObservedGeneration:  m.observedGeneration,
+			Phase:               m.phase,
+			ReadyReplicas:       m.readyReplicas,
+			Replicas:            m.statusReplicas,
+			Selector:            m.statusSelector,
+			UnavailableReplicas: m.unavailableReplicas,
+			UpdatedReplicas:     m.updatedReplicas,
+		},
+	}
+
+	return machineDeployment
+}
+
+// Object meta fields.
+
+// WithAnnotations sets the annotations for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithAnnotations(annotations map[string]string) MachineDeploymentBuilder {
+	m.annotations = annotations
+	return m
+}
+
+// WithCreationTimestamp sets the creationTimestamp for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithCreationTimestamp(timestamp metav1.Time) MachineDeploymentBuilder {
+	m.creationTimestamp = timestamp
+	return m
+}
+
+// WithDeletionTimestamp sets the deletionTimestamp for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithDeletionTimestamp(timestamp *metav1.Time) MachineDeploymentBuilder {
+	m.deletionTimestamp = timestamp
+	return m
+}
+
+// WithGenerateName sets the generateName for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithGenerateName(generateName string) MachineDeploymentBuilder {
+	m.generateName = generateName
+	return m
+}
+
+// WithLabels sets the labels for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithLabels(labels map[string]string) MachineDeploymentBuilder {
+	m.labels = labels
+	return m
+}
+
+// WithName sets the name for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithName(name string) MachineDeploymentBuilder {
+	m.name = name
+	return m
+}
+
+// WithNamespace sets the namespace for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithNamespace(namespace string) MachineDeploymentBuilder {
+	m.namespace = namespace
+	return m
+}
+
+// WithOwnerReferences sets the OwnerReferences for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithOwnerReferences(ownerRefs []metav1.OwnerReference) MachineDeploymentBuilder {
+	m.ownerReferences = ownerRefs
+	return m
+}
+
+// Spec fields.
+
+// WithClusterName sets the clusterName for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithClusterName(clusterName string) MachineDeploymentBuilder {
+	m.clusterName = clusterName
+	return m
+}
+
+// WithMinReadySeconds sets the minReadySeconds for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithMinReadySeconds(minReadySeconds int32) MachineDeploymentBuilder {
+	m.minReadySeconds = &minReadySeconds
+	return m
+}
+
+// WithPaused sets the paused flag for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithPaused(paused bool) MachineDeploymentBuilder {
+	m.paused = paused
+	return m
+}
+
+// WithProgressDeadlineSeconds sets the progressDeadlineSeconds for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithProgressDeadlineSeconds(progressDeadlineSeconds int32) MachineDeploymentBuilder {
+	m.progressDeadlineSeconds = &progressDeadlineSeconds
+	return m
+}
+
+// WithReplicas sets the replicas for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithReplicas(replicas int32) MachineDeploymentBuilder {
+	m.replicas = &replicas
+	return m
+}
+
+// WithRevisionHistoryLimit sets the revisionHistoryLimit for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithRevisionHistoryLimit(revisionHistoryLimit int32) MachineDeploymentBuilder {
+	m.revisionHistoryLimit = &revisionHistoryLimit
+	return m
+}
+
+// WithRolloutAfter sets the rolloutAfter for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithRolloutAfter(rolloutAfter *metav1.Time) MachineDeploymentBuilder {
+	m.rolloutAfter = rolloutAfter
+	return m
+}
+
+// WithSelector sets the selector for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithSelector(selector metav1.LabelSelector) MachineDeploymentBuilder {
+	m.selector = selector
+	return m
+}
+
+// WithStrategy sets the strategy for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStrategy(strategy *capiv1.MachineDeploymentStrategy) MachineDeploymentBuilder {
+	m.strategy = strategy
+	return m
+}
+
+// WithTemplate sets the template for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithTemplate(template capiv1.MachineTemplateSpec) MachineDeploymentBuilder {
+	m.template = template
+	return m
+}
+
+// Status fields.
+
+// WithStatusAvailableReplicas sets the status availableReplicas for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusAvailableReplicas(availableReplicas int32) MachineDeploymentBuilder {
+	m.availableReplicas = availableReplicas
+	return m
+}
+
+// WithStatusConditions sets the status conditions for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusConditions(conditions capiv1.Conditions) MachineDeploymentBuilder {
+	m.conditions = conditions
+	return m
+}
+
+// WithStatusObservedGeneration sets the status observedGeneration for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusObservedGeneration(observedGeneration int64) MachineDeploymentBuilder {
+	m.observedGeneration = observedGeneration
+	return m
+}
+
+// WithStatusPhase sets the status phase for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusPhase(phase capiv1.MachineDeploymentPhase) MachineDeploymentBuilder {
+	m.phase = string(phase)
+	return m
+}
+
+// WithStatusReadyReplicas sets the status readyReplicas for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusReadyReplicas(readyReplicas int32) MachineDeploymentBuilder {
+	m.readyReplicas = readyReplicas
+	return m
+}
+
+// WithStatusReplicas sets the status replicas for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusReplicas(replicas int32) MachineDeploymentBuilder {
+	m.statusReplicas = replicas
+	return m
+}
+
+// WithStatusSelector sets the status selector for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusSelector(selector string) MachineDeploymentBuilder {
+	m.statusSelector = selector
+	return m
+}
+
+// WithStatusUnavailableReplicas sets the status unavailableReplicas for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusUnavailableReplicas(unavailableReplicas int32) MachineDeploymentBuilder {
+	m.unavailableReplicas = unavailableReplicas
+	return m
+}
+
+// WithStatusUpdatedReplicas sets the status updatedReplicas for the MachineDeployment builder.
+func (m MachineDeploymentBuilder) WithStatusUpdatedReplicas(updatedReplicas int32) MachineDeploymentBuilder {
+	m.updatedReplicas = updatedReplicas
+	return m
+}
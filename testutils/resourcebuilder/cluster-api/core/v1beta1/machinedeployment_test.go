@@ -0,0 +1,289 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("MachineDeployment", func() {
+	Describe("Build", func() {
+		It("should return a default machine deployment when no options are specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment).ToNot(BeNil())
+		})
+	})
+
+	// Object meta fields.
+
+	Describe("WithAnnotations", func() {
+		It("should return the custom value when specified", func() {
+			annotations := map[string]string{"key": "value"}
+			machineDeployment := MachineDeployment().WithAnnotations(annotations).Build()
+			Expect(machineDeployment.Annotations).To(Equal(annotations))
+		})
+
+		It("should return nil when not specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment.Annotations).To(BeNil())
+		})
+	})
+
+	Describe("WithCreationTimestamp", func() {
+		It("should return the custom value when specified", func() {
+			timestamp := metav1.Now()
+			machineDeployment := MachineDeployment().WithCreationTimestamp(timestamp).Build()
+			Expect(machineDeployment.CreationTimestamp).To(Equal(timestamp))
+		})
+	})
+
+	Describe("WithDeletionTimestamp", func() {
+		It("should return the custom value when specified", func() {
+			timestamp := metav1.Now()
+			machineDeployment := MachineDeployment().WithDeletionTimestamp(&timestamp).Build()
+			Expect(machineDeployment.DeletionTimestamp).To(Equal(&timestamp))
+		})
+	})
+
+	Describe("WithGenerateName", func() {
+		It("should return the custom value when specified", func() {
+			generateName := "test-machine-deployment-"
+			machineDeployment := MachineDeployment().WithGenerateName(generateName).Build()
+			Expect(machineDeployment.GenerateName).To(Equal(generateName))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := map[string]string{"key1": "value1", "key2": "value2"}
+			machineDeployment := MachineDeployment().WithLabels(labels).Build()
+			Expect(machineDeployment.Labels).To(Equal(labels))
+		})
+
+		It("should return nil when not specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment.Labels).To(BeNil())
+		})
+	})
+
+	Describe("WithName", func() {
+		It("should return the custom value when specified", func() {
+			name := "test-machine-deployment"
+			machineDeployment := MachineDeployment().WithName(name).Build()
+			Expect(machineDeployment.Name).To(Equal(name))
+		})
+	})
+
+	Describe("WithNamespace", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithNamespace("ns-test").Build()
+			Expect(machineDeployment.Namespace).To(Equal("ns-test"))
+		})
+	})
+
+	Describe("WithOwnerReferences", func() {
+		It("should return the custom value when specified", func() {
+			ownerReferences := []metav1.OwnerReference{
+				{APIVersion: "cluster.x-k8s.io/v1beta1", Kind: "Cluster", Name: "test-cluster"},
+			}
+			machineDeployment := MachineDeployment().WithOwnerReferences(ownerReferences).Build()
+			Expect(machineDeployment.OwnerReferences).To(Equal(ownerReferences))
+		})
+	})
+
+	// Spec fields.
+
+	Describe("WithClusterName", func() {
+		It("should return the custom value when specified", func() {
+			clusterName := "test-cluster"
+			machineDeployment := MachineDeployment().WithClusterName(clusterName).Build()
+			Expect(machineDeployment.Spec.ClusterName).To(Equal(clusterName))
+		})
+	})
+
+	Describe("WithMinReadySeconds", func() {
+		It("should return the custom value when specified", func() {
+			minReadySeconds := int32(10)
+			machineDeployment := MachineDeployment().WithMinReadySeconds(minReadySeconds).Build()
+			Expect(machineDeployment.Spec.MinReadySeconds).To(Equal(&minReadySeconds))
+		})
+
+		It("should return nil when not specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment.Spec.MinReadySeconds).To(BeNil())
+		})
+	})
+
+	Describe("WithPaused", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithPaused(true).Build()
+			Expect(machineDeployment.Spec.Paused).To(BeTrue())
+		})
+
+		It("should return false when not specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment.Spec.Paused).To(BeFalse())
+		})
+	})
+
+	Describe("WithProgressDeadlineSeconds", func() {
+		It("should return the custom value when specified", func() {
+			progressDeadlineSeconds := int32(600)
+			machineDeployment := MachineDeployment().WithProgressDeadlineSeconds(progressDeadlineSeconds).Build()
+			Expect(machineDeployment.Spec.ProgressDeadlineSeconds).To(Equal(&progressDeadlineSeconds))
+		})
+	})
+
+	Describe("WithReplicas", func() {
+		It("should return the custom value when specified", func() {
+			replicas := int32(5)
+			machineDeployment := MachineDeployment().WithReplicas(replicas).Build()
+			Expect(machineDeployment.Spec.Replicas).To(Equal(&replicas))
+		})
+
+		It("should return nil when not specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment.Spec.Replicas).To(BeNil())
+		})
+	})
+
+	Describe("WithRevisionHistoryLimit", func() {
+		It("should return the custom value when specified", func() {
+			revisionHistoryLimit := int32(2)
+			machineDeployment := MachineDeployment().WithRevisionHistoryLimit(revisionHistoryLimit).Build()
+			Expect(machineDeployment.Spec.RevisionHistoryLimit).To(Equal(&revisionHistoryLimit))
+		})
+	})
+
+	Describe("WithRolloutAfter", func() {
+		It("should return the custom value when specified", func() {
+			rolloutAfter := metav1.Now()
+			machineDeployment := MachineDeployment().WithRolloutAfter(&rolloutAfter).Build()
+			Expect(machineDeployment.Spec.RolloutAfter).To(Equal(&rolloutAfter))
+		})
+	})
+
+	Describe("WithSelector", func() {
+		It("should return the custom value when specified", func() {
+			selector := metav1.LabelSelector{
+				MatchLabels: map[string]string{"key": "value"},
+			}
+			machineDeployment := MachineDeployment().WithSelector(selector).Build()
+			Expect(machineDeployment.Spec.Selector).To(Equal(selector))
+		})
+	})
+
+	Describe("WithStrategy", func() {
+		It("should return the custom value when specified", func() {
+			strategy := &capiv1.MachineDeploymentStrategy{
+				Type: capiv1.OnDeleteMachineDeploymentStrategyType,
+			}
+			machineDeployment := MachineDeployment().WithStrategy(strategy).Build()
+			Expect(machineDeployment.Spec.Strategy).To(Equal(strategy))
+		})
+
+		It("should return nil when not specified", func() {
+			machineDeployment := MachineDeployment().Build()
+			Expect(machineDeployment.Spec.Strategy).To(BeNil())
+		})
+	})
+
+	Describe("WithTemplate", func() {
+		It("should return the custom value when specified", func() {
+			template := capiv1.MachineTemplateSpec{
+				ObjectMeta: capiv1.ObjectMeta{
+					Labels: map[string]string{"key": "value"},
+				},
+			}
+			machineDeployment := MachineDeployment().WithTemplate(template).Build()
+			Expect(machineDeployment.Spec.Template).To(Equal(template))
+		})
+	})
+
+	// Status fields.
+
+	Describe("WithStatusAvailableReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusAvailableReplicas(5).Build()
+			Expect(machineDeployment.Status.AvailableReplicas).To(BeEquivalentTo(5))
+		})
+	})
+
+	Describe("WithStatusConditions", func() {
+		It("should return the custom value when specified", func() {
+			conditions := capiv1.Conditions{
+				{Type: capiv1.ReadyCondition, Status: corev1.ConditionTrue},
+			}
+			machineDeployment := MachineDeployment().WithStatusConditions(conditions).Build()
+			Expect(machineDeployment.Status.Conditions).To(Equal(conditions))
+		})
+	})
+
+	Describe("WithStatusObservedGeneration", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusObservedGeneration(1).Build()
+			Expect(machineDeployment.Status.ObservedGeneration).To(BeEquivalentTo(1))
+		})
+	})
+
+	Describe("WithStatusPhase", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusPhase(capiv1.MachineDeploymentPhaseRunning).Build()
+			Expect(machineDeployment.Status.GetTypedPhase()).To(Equal(capiv1.MachineDeploymentPhaseRunning))
+		})
+	})
+
+	Describe("WithStatusReadyReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusReadyReplicas(5).Build()
+			Expect(machineDeployment.Status.ReadyReplicas).To(BeEquivalentTo(5))
+		})
+	})
+
+	Describe("WithStatusReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusReplicas(5).Build()
+			Expect(machineDeployment.Status.Replicas).To(BeEquivalentTo(5))
+		})
+	})
+
+	Describe("WithStatusSelector", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusSelector("test-selector").Build()
+			Expect(machineDeployment.Status.Selector).To(Equal("test-selector"))
+		})
+	})
+
+	Describe("WithStatusUnavailableReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusUnavailableReplicas(2).Build()
+			Expect(machineDeployment.Status.UnavailableReplicas).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("WithStatusUpdatedReplicas", func() {
+		It("should return the custom value when specified", func() {
+			machineDeployment := MachineDeployment().WithStatusUpdatedReplicas(3).Build()
+			Expect(machineDeployment.Status.UpdatedReplicas).To(BeEquivalentTo(3))
+		})
+	})
+})
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MachineHealthCheck creates a new MachineHealthCheck builder.
+func MachineHealthCheck() MachineHealthCheckBuilder {
+	return MachineHealthCheckBuilder{}
+}
+
+// MachineHealthCheckBuilder is used to build out a MachineHealthCheck object.
+type MachineHealthCheckBuilder struct {
+	// Object meta fields.
+	annotations       map[string]string
+	creationTimestamp metav1.Time
+	deletionTimestamp *metav1.Time
+	generateName      string
+	labels            map[string]string
+	name              string
+	namespace         string
+	ownerReferences   []metav1.OwnerReference
+
+	// Spec fields.
+	clusterName         string
+	maxUnhealthy        *intstr.IntOrString
+	nodeStartupTimeout  *metav1.Duration
+	remediationTemplate *corev1.ObjectReference
+	selector            metav1.LabelSelector
+	unhealthyConditions []capiv1.UnhealthyCondition
+	unhealthyRange      *string
+
+	// Status fields.
+	conditions          capiv1.Conditions
+	currentHealthy      int32
+	expectedMachines    int32
+	observedGeneration  int64
+	remediationsAllowed int32
+	targets             []string
+}
+
+// Build builds a new MachineHealthCheck based on the configuration provided.
+func (m MachineHealthCheckBuilder) Build() *capiv1.MachineHealthCheck {
+	machineHealthCheck := &capiv1.MachineHealthCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations:       m.annotations,
+			CreationTimestamp: m.creationTimestamp,
+			DeletionTimestamp: m.deletionTimestamp,
+			GenerateName:      m.generateName,
+			Labels:            m.labels,
+			Name:              m.name,
+			Namespace:         m.namespace,
+			OwnerReferences:   m.ownerReferences,
+		},
+		Spec: capiv1.MachineHealthCheckSpec{
+			ClusterName:         m.clusterName,
+			MaxUnhealthy:        m.maxUnhealthy,
+			NodeStartupTimeout:  m.nodeStartupTimeout,
+			RemediationTemplate: m.remediationTemplate,
+			Selector:            m.selector,
+			UnhealthyConditions: m.unhealthyConditions,
+			UnhealthyRange:      m.unhealthyRange,
+		},
+		Status: capiv1.MachineHealthCheckStatus{
+			Conditions:          m.conditions,
+			CurrentHealthy:      m.currentHealthy,
+			ExpectedMachines:    m.expectedMachines,
+			ObservedGeneration:  m.observedGeneration,
+			RemediationsAllowed: m.remediationsAllowed,
+			Targets:             m.targets,
+		},
+	}
+
+	return machineHealthCheck
+}
+
+// Object meta fields.
+
+// WithAnnotations sets the annotations for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithAnnotations(annotations map[string]string) MachineHealthCheckBuilder {
+	m.annotations = annotations
+	return m
+}
+
+// WithCreationTimestamp sets the creationTimestamp for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithCreationTimestamp(timestamp metav1.Time) MachineHealthCheckBuilder {
+	m.creationTimestamp = timestamp
+	return m
+}
+
+// WithDeletionTimestamp sets the deletionTimestamp for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithDeletionTimestamp(timestamp *metav1.Time) MachineHealthCheckBuilder {
+	m.deletionTimestamp = timestamp
+	return m
+}
+
+// WithGenerateName sets the generateName for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithGenerateName(generateName string) MachineHealthCheckBuilder {
+	m.generateName = generateName
+	return m
+}
+
+// WithLabels sets the labels for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithLabels(labels map[string]string) MachineHealthCheckBuilder {
+	m.labels = labels
+	return m
+}
+
+// WithName sets the name for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithName(name string) MachineHealthCheckBuilder {
+	m.name = name
+	return m
+}
+
+// WithNamespace sets the namespace for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithNamespace(namespace string) MachineHealthCheckBuilder {
+	m.namespace = namespace
+	return m
+}
+
+// WithOwnerReferences sets the OwnerReferences for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithOwnerReferences(ownerRefs []metav1.OwnerReference) MachineHealthCheckBuilder {
+	m.ownerReferences = ownerRefs
+	return m
+}
+
+// Spec fields.
+
+// WithClusterName sets the clusterName for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithClusterName(clusterName string) MachineHealthCheckBuilder {
+	m.clusterName = clusterName
+	return m
+}
+
+// WithMaxUnhealthy sets the maxUnhealthy for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithMaxUnhealthy(maxUnhealthy intstr.IntOrString) MachineHealthCheckBuilder {
+	m.maxUnhealthy = &maxUnhealthy
+	return m
+}
+
+// WithNodeStartupTimeout sets the nodeStartupTimeout for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithNodeStartupTimeout(timeout *metav1.Duration) MachineHealthCheckBuilder {
+	m.nodeStartupTimeout = timeout
+	return m
+}
+
+// WithRemediationTemplate sets the remediationTemplate for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithRemediationTemplate(ref *corev1.ObjectReference) MachineHealthCheckBuilder {
+	m.remediationTemplate = ref
+	return m
+}
+
+// WithSelector sets the selector for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithSelector(selector metav1.LabelSelector) MachineHealthCheckBuilder {
+	m.selector = selector
+	return m
+}
+
+// WithUnhealthyConditions sets the unhealthyConditions for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithUnhealthyConditions(conditions []capiv1.UnhealthyCondition) MachineHealthCheckBuilder {
+	m.unhealthyConditions = conditions
+	return m
+}
+
+// WithUnhealthyRange sets the unhealthyRange for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithUnhealthyRange(unhealthyRange string) MachineHealthCheckBuilder {
+	m.unhealthyRange = &unhealthyRange
+	return m
+}
+
+// Status fields.
+
+// WithStatusConditions sets the status conditions for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithStatusConditions(conditions capiv1.Conditions) MachineHealthCheckBuilder {
+	m.conditions = conditions
+	return m
+}
+
+// WithStatusCurrentHealthy sets the status currentHealthy for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithStatusCurrentHealthy(currentHealthy int32) MachineHealthCheckBuilder {
+	m.currentHealthy = currentHealthy
+	return m
+}
+
+// WithStatusExpectedMachines sets the status expectedMachines for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithStatusExpectedMachines(expectedMachines int32) MachineHealthCheckBuilder {
+	m.expectedMachines = expectedMachines
+	return m
+}
+
+// WithStatusObservedGeneration sets the status observedGeneration for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithStatusObservedGeneration(observedGeneration int64) MachineHealthCheckBuilder {
+	m.observedGeneration = observedGeneration
+	return m
+}
+
+// WithStatusRemediationsAllowed sets the status remediationsAllowed for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithStatusRemediationsAllowed(remediationsAllowed int32) MachineHealthCheckBuilder {
+	m.remediationsAllowed = remediationsAllowed
+	return m
+}
+
+// WithStatusTargets sets the status targets for the MachineHealthCheck builder.
+func (m MachineHealthCheckBuilder) WithStatusTargets(targets []string) MachineHealthCheckBuilder {
+	m.targets = targets
+	return m
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("MachineHealthCheck", func() {
+	Describe("Build", func() {
+		It("should return a default machine health check when no options are specified", func() {
+			machineHealthCheck := MachineHealthCheck().Build()
+			Expect(machineHealthCheck).ToNot(BeNil())
+		})
+	})
+
+	// Object meta fields.
+
+	Describe("WithAnnotations", func() {
+		It("should return the custom value when specified", func() {
+			annotations := map[string]string{"key": "value"}
+			machineHealthCheck := MachineHealthCheck().WithAnnotations(annotations).Build()
+			Expect(machineHealthCheck.Annotations).To(Equal(annotations))
+		})
+
+		It("should return nil when not specified", func() {
+			machineHealthCheck := MachineHealthCheck().Build()
+			Expect(machineHealthCheck.Annotations).To(BeNil())
+		})
+	})
+
+	Describe("WithCreationTimestamp", func() {
+		It("should return the custom value when specified", func() {
+			timestamp := metav1.Now()
+			machineHealthCheck := MachineHealthCheck().WithCreationTimestamp(timestamp).Build()
+			Expect(machineHealthCheck.CreationTimestamp).To(Equal(timestamp))
+		})
+	})
+
+	Describe("WithDeletionTimestamp", func() {
+		It("should return the custom value when specified", func() {
+			timestamp := metav1.Now()
+			machineHealthCheck := MachineHealthCheck().WithDeletionTimestamp(&timestamp).Build()
+			Expect(machineHealthCheck.DeletionTimestamp).To(Equal(&timestamp))
+		})
+	})
+
+	Describe("WithGenerateName", func() {
+		It("should return the custom value when specified", func() {
+			generateName := "test-machine-health-check-"
+			machineHealthCheck := MachineHealthCheck().WithGenerateName(generateName).Build()
+			Expect(machineHealthCheck.GenerateName).To(Equal(generateName))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := map[string]string{"key1": "value1", "key2": "value2"}
+			machineHealthCheck := MachineHealthCheck().WithLabels(labels).Build()
+			Expect(machineHealthCheck.Labels).To(Equal(labels))
+		})
+
+		It("should return nil when not specified", func() {
+			machineHealthCheck := MachineHealthCheck().Build()
+			Expect(machineHealthCheck.Labels).To(BeNil())
+		})
+	})
+
+	Describe("WithName", func() {
+		It("should return the custom value when specified", func() {
+			name := "test-machine-health-check"
+			machineHealthCheck := MachineHealthCheck().WithName(name).Build()
+			Expect(machineHealthCheck.Name).To(Equal(name))
+		})
+	})
+
+	Describe("WithNamespace", func() {
+		It("should return the custom value when specified", func() {
+			machineHealthCheck := MachineHealthCheck().WithNamespace("ns-test").Build()
+			Expect(machineHealthCheck.Namespace).To(Equal("ns-test"))
+		})
+	})
+
+	Describe("WithOwnerReferences", func() {
+		It("should return the custom value when specified", func() {
+			ownerReferences := []metav1.OwnerReference{
+				{APIVersion: "cluster.x-k8s.io/v1beta1", Kind: "Cluster", Name: "test-cluster"},
+			}
+			machineHealthCheck := MachineHealthCheck().WithOwnerReferences(ownerReferences).Build()
+			Expect(machineHealthCheck.OwnerReferences).To(Equal(ownerReferences))
+		})
+	})
+
+	// Spec fields.
+
+	Describe("WithClusterName", func() {
+		It("should return the custom value when specified", func() {
+			clusterName := "test-cluster"
+			machineHealthCheck := MachineHealthCheck().WithClusterName(clusterName).Build()
+			Expect(machineHealthCheck.Spec.ClusterName).To(Equal(clusterName))
+		})
+	})
+
+	Describe("WithMaxUnhealthy", func() {
+		It("should return the custom value when specified", func() {
+			maxUnhealthy := intstr.FromString("40%")
+			machineHealthCheck := MachineHealthCheck().WithMaxUnhealthy(maxUnhealthy).Build()
+			Expect(machineHealthCheck.Spec.MaxUnhealthy).To(Equal(&maxUnhealthy))
+		})
+
+		It("should return nil when not specified", func() {
+			machineHealthCheck := MachineHealthCheck().Build()
+			Expect(machineHealthCheck.Spec.MaxUnhealthy).To(BeNil())
+		})
+	})
+
+	Describe("WithNodeStartupTimeout", func() {
+		It("should return the custom value when specified", func() {
+			timeout := &metav1.Duration{Duration: 10 * time.Minute}
+			machineHealthCheck := MachineHealthCheck().WithNodeStartupTimeout(timeout).Build()
+			Expect(machineHealthCheck.Spec.NodeStartupTimeout).To(Equal(timeout))
+		})
+	})
+
+	Describe("WithRemediationTemplate", func() {
+		It("should return the custom value when specified", func() {
+			ref := &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "Metal3RemediationTemplate",
+				Name:       "test-template",
+			}
+			machineHealthCheck := MachineHealthCheck().WithRemediationTemplate(ref).Build()
+			Expect(machineHealthCheck.Spec.RemediationTemplate).To(Equal(ref))
+		})
+	})
+
+	Describe("WithSelector", func() {
+		It("should return the custom value when specified", func() {
+			selector := metav1.LabelSelector{
+				MatchLabels: map[string]string{"key": "value"},
+			}
+			machineHealthCheck := MachineHealthCheck().WithSelector(selector).Build()
+			Expect(machineHealthCheck.Spec.Selector).To(Equal(selector))
+		})
+	})
+
+	Describe("WithUnhealthyConditions", func() {
+		It("should return the custom value when specified", func() {
+			conditions := []capiv1.UnhealthyCondition{
+				{
+					Type:    corev1.NodeReady,
+					Status:  corev1.ConditionFalse,
+					Timeout: metav1.Duration{Duration: 5 * time.Minute},
+				},
+			}
+			machineHealthCheck := MachineHealthCheck().WithUnhealthyConditions(conditions).Build()
+			Expect(machineHealthCheck.Spec.UnhealthyConditions).To(Equal(conditions))
+		})
+
+		It("should return nil when not specified", func() {
+			machineHealthCheck := MachineHealthCheck().Build()
+			Expect(machineHealthCheck.Spec.UnhealthyConditions).To(BeNil())
+		})
+	})
+
+	Describe("WithUnhealthyRange", func() {
+		It("should return the custom value when specified", func() {
+			machineHealthCheck := MachineHealthCheck().WithUnhealthyRange("[1-3]").Build()
+			Expect(machineHealthCheck.Spec.UnhealthyRange).To(HaveValue(Equal("[1-3]")))
+		})
+	})
+
+	// Status fields.
+
+	Describe("WithStatusConditions", func() {
+		It("should return the custom value when specified", func() {
+			conditions := capiv1.Conditions{
+				{Type: capiv1.RemediationAllowedCondition, Status: corev1.ConditionTrue},
+			}
+			machineHealthCheck := MachineHealthCheck().WithStatusConditions(conditions).Build()
+			Expect(machineHealthCheck.Status.Conditions).To(Equal(conditions))
+		})
+	})
+
+	Describe("WithStatusCurrentHealthy", func() {
+		It("should return the custom value when specified", func() {
+			machineHealthCheck := MachineHealthCheck().WithStatusCurrentHealthy(2).Build()
+			Expect(machineHealthCheck.Status.CurrentHealthy).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("WithStatusExpectedMachines", func() {
+		It("should return the custom value when specified", func() {
+			machineHealthCheck := MachineHealthCheck().WithStatusExpectedMachines(3).Build()
+			Expect(machineHealthCheck.Status.ExpectedMachines).To(BeEquivalentTo(3))
+		})
+	})
+
+	Describe("WithStatusObservedGeneration", func() {
+		It("should return the custom value when specified", func() {
+			machineHealthCheck := MachineHealthCheck().WithStatusObservedGeneration(1).Build()
+			Expect(machineHealthCheck.Status.ObservedGeneration).To(BeEquivalentTo(1))
+		})
+	})
+
+	Describe("WithStatusRemediationsAllowed", func() {
+		It("should return the custom value when specified", func() {
+			machineHealthCheck := MachineHealthCheck().WithStatusRemediationsAllowed(1).Build()
+			Expect(machineHealthCheck.Status.RemediationsAllowed).To(BeEquivalentTo(1))
+		})
+	})
+
+	Describe("WithStatusTargets", func() {
+		It("should return the custom value when specified", func() {
+			targets := []string{"machine-1", "machine-2"}
+			machineHealthCheck := MachineHealthCheck().WithStatusTargets(targets).Build()
+			Expect(machineHealthCheck.Status.Targets).To(Equal(targets))
+		})
+	})
+})
@@ -0,0 +1,282 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capgv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+)
+
+// GCPMachineTemplate creates a new GCPMachineTemplate builder.
+func GCPMachineTemplate() GCPMachineTemplateBuilder {
+	return GCPMachineTemplateBuilder{}
+}
+
+// GCPMachineTemplateBuilder is used to build out a GCPMachineTemplate object.
+type GCPMachineTemplateBuilder struct {
+	// Object meta fields.
+	annotations       map[string]string
+	creationTimestamp metav1.Time
+	deletionTimestamp *metav1.Time
+	generateName      string
+	labels            map[string]string
+	name              string
+	namespace         string
+
+	// Spec fields.
+	additionalDisks        []capgv1.AttachedDiskSpec
+	additionalLabels       capgv1.Labels
+	additionalMetadata     []capgv1.MetadataItem
+	additionalNetworkTags  []string
+	confidentialCompute    *capgv1.ConfidentialComputePolicy
+	image                  *string
+	imageFamily            *string
+	instanceType           string
+	ipForwarding           *capgv1.IPForwarding
+	onHostMaintenance      *capgv1.HostMaintenancePolicy
+	preemptible            bool
+	providerID             *string
+	provisioningModel      *capgv1.ProvisioningModel
+	publicIP               *bool
+	resourceManagerTags    capgv1.ResourceManagerTags
+	rootDeviceSize         int64
+	rootDeviceType         *capgv1.DiskType
+	rootDiskEncryptionKey  *capgv1.CustomerEncryptionKey
+	serviceAccount         *capgv1.ServiceAccount
+	shieldedInstanceConfig *capgv1.GCPShieldedInstanceConfig
+	subnet                 *string
+}
+
+// Build builds a new GCPMachineTemplate based on the configuration provided.
+func (g GCPMachineTemplateBuilder) Build() *capgv1.GCPMachineTemplate {
+	gcpMachineTemplate := &capgv1.GCPMachineTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: capgv1.GroupVersion.String(),
+			Kind:       "GCPMachineTemplate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations:       g.annotations,
+			CreationTimestamp: g.creationTimestamp,
+			DeletionTimestamp: g.deletionTimestamp,
+			GenerateName:      g.generateName,
+			Labels:            g.labels,
+			Name:              g.name,
+			Namespace:         g.namespace,
+		},
+		Spec: capgv1.GCPMachineTemplateSpec{
+			Template: capgv1.GCPMachineTemplateResource{
+				Spec: capgv1.GCPMachineSpec{
+					AdditionalDisks:        g.additionalDisks,
+					AdditionalLabels:       g.additionalLabels,
+					AdditionalMetadata:     g.additionalMetadata,
+					AdditionalNetworkTags:  g.additionalNetworkTags,
+					ConfidentialCompute:    g.confidentialCompute,
+					Image:                  g.image,
+					ImageFamily:            g.imageFamily,
+					InstanceType:           g.instanceType,
+					IPForwarding:           g.ipForwarding,
+					OnHostMaintenance:      g.onHostMaintenance,
+					Preemptible:            g.preemptible,
+					ProviderID:             g.providerID,
+					ProvisioningModel:      g.provisioningModel,
+					PublicIP:               g.publicIP,
+					ResourceManagerTags:    g.resourceManagerTags,
+					RootDeviceSize:         g.rootDeviceSize,
+					RootDeviceType:         g.rootDeviceType,
+					RootDiskEncryptionKey:  g.rootDiskEncryptionKey,
+					ServiceAccount:         g.serviceAccount,
+					ShieldedInstanceConfig: g.shieldedInstanceConfig,
+					Subnet:                 g.subnet,
+				},
+			},
+		},
+	}
+
+	return gcpMachineTemplate
+}
+
+// Object meta fields.
+
+// WithAnnotations sets the annotations for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithAnnotations(annotations map[string]string) GCPMachineTemplateBuilder {
+	g.annotations = annotations
+	return g
+}
+
+// WithCreationTimestamp sets the creationTimestamp for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithCreationTimestamp(timestamp metav1.Time) GCPMachineTemplateBuilder {
+	g.creationTimestamp = timestamp
+	return g
+}
+
+// WithDeletionTimestamp sets the deletionTimestamp for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithDeletionTimestamp(timestamp *metav1.Time) GCPMachineTemplateBuilder {
+	g.deletionTimestamp = timestamp
+	return g
+}
+
+// WithGenerateName sets the generateName for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithGenerateName(generateName string) GCPMachineTemplateBuilder {
+	g.generateName = generateName
+	return g
+}
+
+// WithLabels sets the labels for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithLabels(labels map[string]string) GCPMachineTemplateBuilder {
+	g.labels = labels
+	return g
+}
+
+// WithName sets the name for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithName(name string) GCPMachineTemplateBuilder {
+	g.name = name
+	return g
+}
+
+// WithNamespace sets the namespace for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithNamespace(namespace string) GCPMachineTemplateBuilder {
+	g.namespace = namespace
+	return g
+}
+
+// Spec fields.
+
+// WithAdditionalDisks sets the additionalDisks for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithAdditionalDisks(disks []capgv1.AttachedDiskSpec) GCPMachineTemplateBuilder {
+	g.additionalDisks = disks
+	return g
+}
+
+// WithAdditionalLabels sets the additionalLabels for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithAdditionalLabels(labels capgv1.Labels) GCPMachineTemplateBuilder {
+	g.additionalLabels = labels
+	return g
+}
+
+// WithAdditionalMetadata sets the additionalMetadata for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithAdditionalMetadata(metadata []capgv1.MetadataItem) GCPMachineTemplateBuilder {
+	g.additionalMetadata = metadata
+	return g
+}
+
+// WithAdditionalNetworkTags sets the additionalNetworkTags for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithAdditionalNetworkTags(tags []string) GCPMachineTemplateBuilder {
+	g.additionalNetworkTags = tags
+	return g
+}
+
+// WithConfidentialCompute sets the confidentialCompute policy for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithConfidentialCompute(policy capgv1.ConfidentialComputePolicy) GCPMachineTemplateBuilder {
+	g.confidentialCompute = &policy
+	return g
+}
+
+// WithImage sets the image for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithImage(image string) GCPMachineTemplateBuilder {
+	g.image = &image
+	return g
+}
+
+// WithImageFamily sets the imageFamily for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithImageFamily(imageFamily string) GCPMachineTemplateBuilder {
+	g.imageFamily = &imageFamily
+	return g
+}
+
+// WithInstanceType sets the instanceType for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithInstanceType(instanceType string) GCPMachineTemplateBuilder {
+	g.instanceType = instanceType
+	return g
+}
+
+// WithIPForwarding sets the ipForwarding for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithIPForwarding(ipForwarding capgv1.IPForwarding) GCPMachineTemplateBuilder {
+	g.ipForwarding = &ipForwarding
+	return g
+}
+
+// WithOnHostMaintenance sets the onHostMaintenance policy for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithOnHostMaintenance(policy capgv1.HostMaintenancePolicy) GCPMachineTemplateBuilder {
+	g.onHostMaintenance = &policy
+	return g
+}
+
+// WithPreemptible sets the preemptible flag for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithPreemptible(preemptible bool) GCPMachineTemplateBuilder {
+	g.preemptible = preemptible
+	return g
+}
+
+// WithProviderID sets the providerID for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithProviderID(providerID string) GCPMachineTemplateBuilder {
+	g.providerID = &providerID
+	return g
+}
+
+// WithProvisioningModel sets the provisioningModel for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithProvisioningModel(model capgv1.ProvisioningModel) GCPMachineTemplateBuilder {
+	g.provisioningModel = &model
+	return g
+}
+
+// WithPublicIP sets the publicIP for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithPublicIP(publicIP bool) GCPMachineTemplateBuilder {
+	g.publicIP = &publicIP
+	return g
+}
+
+// WithResourceManagerTags sets the resourceManagerTags for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithResourceManagerTags(tags capgv1.ResourceManagerTags) GCPMachineTemplateBuilder {
+	g.resourceManagerTags = tags
+	return g
+}
+
+// WithRootDeviceSize sets the rootDeviceSize for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithRootDeviceSize(size int64) GCPMachineTemplateBuilder {
+	g.rootDeviceSize = size
+	return g
+}
+
+// WithRootDeviceType sets the rootDeviceType for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithRootDeviceType(diskType capgv1.DiskType) GCPMachineTemplateBuilder {
+	g.rootDeviceType = &diskType
+	return g
+}
+
+// WithRootDiskEncryptionKey sets the rootDiskEncryptionKey for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithRootDiskEncryptionKey(key *capgv1.CustomerEncryptionKey) GCPMachineTemplateBuilder {
+	g.rootDiskEncryptionKey = key
+	return g
+}
+
+// WithServiceAccount sets the serviceAccount for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithServiceAccount(serviceAccount *capgv1.ServiceAccount) GCPMachineTemplateBuilder {
+	g.serviceAccount = serviceAccount
+	return g
+}
+
+// WithShieldedInstanceConfig sets the shieldedInstanceConfig for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithShieldedInstanceConfig(config *capgv1.GCPShieldedInstanceConfig) GCPMachineTemplateBuilder {
+	g.shieldedInstanceConfig = config
+	return g
+}
+
+// WithSubnet sets the subnet for the GCPMachineTemplate builder.
+func (g GCPMachineTemplateBuilder) WithSubnet(subnet string) GCPMachineTemplateBuilder {
+	g.subnet = &subnet
+	return g
+}
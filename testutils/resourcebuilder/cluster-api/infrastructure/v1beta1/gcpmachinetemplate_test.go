@@ -0,0 +1,255 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capgv1 "sigs.k8s.io/cluster-api-provider-gcp/api/v1beta1"
+)
+
+var _ = Describe("GCPMachineTemplateBuilder", func() {
+	Describe("Build", func() {
+		It("should return a default GCPMachineTemplate when no options are specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().Build()
+			Expect(gcpMachineTemplate).ToNot(BeNil())
+			Expect(gcpMachineTemplate.TypeMeta.APIVersion).To(Equal("infrastructure.cluster.x-k8s.io/v1beta1"))
+			Expect(gcpMachineTemplate.TypeMeta.Kind).To(Equal("GCPMachineTemplate"))
+		})
+	})
+
+	// Object meta fields.
+
+	Describe("WithAnnotations", func() {
+		It("should return the custom value when specified", func() {
+			annotations := map[string]string{"key": "value"}
+			gcpMachineTemplate := GCPMachineTemplate().WithAnnotations(annotations).Build()
+			Expect(gcpMachineTemplate.Annotations).To(Equal(annotations))
+		})
+	})
+
+	Describe("WithCreationTimestamp", func() {
+		It("should return the custom value when specified", func() {
+			timestamp := metav1.Now()
+			gcpMachineTemplate := GCPMachineTemplate().WithCreationTimestamp(timestamp).Build()
+			Expect(gcpMachineTemplate.CreationTimestamp).To(Equal(timestamp))
+		})
+	})
+
+	Describe("WithDeletionTimestamp", func() {
+		It("should return the custom value when specified", func() {
+			timestamp := metav1.Now()
+			gcpMachineTemplate := GCPMachineTemplate().WithDeletionTimestamp(&timestamp).Build()
+			Expect(gcpMachineTemplate.DeletionTimestamp).To(Equal(&timestamp))
+		})
+	})
+
+	Describe("WithGenerateName", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithGenerateName("gcpmachinetemplate-").Build()
+			Expect(gcpMachineTemplate.GenerateName).To(Equal("gcpmachinetemplate-"))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := map[string]string{"key": "value"}
+			gcpMachineTemplate := GCPMachineTemplate().WithLabels(labels).Build()
+			Expect(gcpMachineTemplate.Labels).To(Equal(labels))
+		})
+	})
+
+	Describe("WithName", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithName("test").Build()
+			Expect(gcpMachineTemplate.Name).To(Equal("test"))
+		})
+	})
+
+	Describe("WithNamespace", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithNamespace("ns-test").Build()
+			Expect(gcpMachineTemplate.Namespace).To(Equal("ns-test"))
+		})
+	})
+
+	// Spec fields.
+
+	Describe("WithAdditionalDisks", func() {
+		It("should return the custom value when specified", func() {
+			diskType := capgv1.PdSsdDiskType
+			disks := []capgv1.AttachedDiskSpec{{DeviceType: &diskType}}
+			gcpMachineTemplate := GCPMachineTemplate().WithAdditionalDisks(disks).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.AdditionalDisks).To(Equal(disks))
+		})
+	})
+
+	Describe("WithAdditionalLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := capgv1.Labels{"key": "value"}
+			gcpMachineTemplate := GCPMachineTemplate().WithAdditionalLabels(labels).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.AdditionalLabels).To(Equal(labels))
+		})
+	})
+
+	Describe("WithAdditionalMetadata", func() {
+		It("should return the custom value when specified", func() {
+			value := "value"
+			metadata := []capgv1.MetadataItem{{Key: "key", Value: &value}}
+			gcpMachineTemplate := GCPMachineTemplate().WithAdditionalMetadata(metadata).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.AdditionalMetadata).To(Equal(metadata))
+		})
+	})
+
+	Describe("WithAdditionalNetworkTags", func() {
+		It("should return the custom value when specified", func() {
+			tags := []string{"tag-1", "tag-2"}
+			gcpMachineTemplate := GCPMachineTemplate().WithAdditionalNetworkTags(tags).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.AdditionalNetworkTags).To(Equal(tags))
+		})
+	})
+
+	Describe("WithConfidentialCompute", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithConfidentialCompute(capgv1.ConfidentialComputePolicyEnabled).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ConfidentialCompute).To(HaveValue(Equal(capgv1.ConfidentialComputePolicyEnabled)))
+		})
+
+		It("should return nil when not specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ConfidentialCompute).To(BeNil())
+		})
+	})
+
+	Describe("WithImage", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithImage("projects/rhcos-cloud/global/images/rhcos").Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.Image).To(HaveValue(Equal("projects/rhcos-cloud/global/images/rhcos")))
+		})
+	})
+
+	Describe("WithImageFamily", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithImageFamily("rhcos").Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ImageFamily).To(HaveValue(Equal("rhcos")))
+		})
+	})
+
+	Describe("WithInstanceType", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithInstanceType("n2-standard-4").Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.InstanceType).To(Equal("n2-standard-4"))
+		})
+	})
+
+	Describe("WithIPForwarding", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithIPForwarding(capgv1.IPForwardingDisabled).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.IPForwarding).To(HaveValue(Equal(capgv1.IPForwardingDisabled)))
+		})
+	})
+
+	Describe("WithOnHostMaintenance", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithOnHostMaintenance(capgv1.HostMaintenancePolicyTerminate).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.OnHostMaintenance).To(HaveValue(Equal(capgv1.HostMaintenancePolicyTerminate)))
+		})
+	})
+
+	Describe("WithPreemptible", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithPreemptible(true).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.Preemptible).To(BeTrue())
+		})
+	})
+
+	Describe("WithProviderID", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithProviderID("gce://project/zone/instance").Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ProviderID).To(HaveValue(Equal("gce://project/zone/instance")))
+		})
+	})
+
+	Describe("WithProvisioningModel", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithProvisioningModel(capgv1.ProvisioningModelSpot).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ProvisioningModel).To(HaveValue(Equal(capgv1.ProvisioningModelSpot)))
+		})
+	})
+
+	Describe("WithPublicIP", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithPublicIP(true).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.PublicIP).To(HaveValue(BeTrue()))
+		})
+	})
+
+	Describe("WithResourceManagerTags", func() {
+		It("should return the custom value when specified", func() {
+			tags := capgv1.ResourceManagerTags{{ParentID: "parent", Key: "key", Value: "value"}}
+			gcpMachineTemplate := GCPMachineTemplate().WithResourceManagerTags(tags).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ResourceManagerTags).To(Equal(tags))
+		})
+	})
+
+	Describe("WithRootDeviceSize", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithRootDeviceSize(128).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.RootDeviceSize).To(BeEquivalentTo(128))
+		})
+	})
+
+	Describe("WithRootDeviceType", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithRootDeviceType(capgv1.PdStandardDiskType).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.RootDeviceType).To(HaveValue(Equal(capgv1.PdStandardDiskType)))
+		})
+	})
+
+	Describe("WithRootDiskEncryptionKey", func() {
+		It("should return the custom value when specified", func() {
+			key := &capgv1.CustomerEncryptionKey{KeyType: capgv1.CustomerManagedKey}
+			gcpMachineTemplate := GCPMachineTemplate().WithRootDiskEncryptionKey(key).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.RootDiskEncryptionKey).To(Equal(key))
+		})
+	})
+
+	Describe("WithServiceAccount", func() {
+		It("should return the custom value when specified", func() {
+			serviceAccount := &capgv1.ServiceAccount{Email: "test@example.com", Scopes: []string{"scope"}}
+			gcpMachineTemplate := GCPMachineTemplate().WithServiceAccount(serviceAccount).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ServiceAccount).To(Equal(serviceAccount))
+		})
+	})
+
+	Describe("WithShieldedInstanceConfig", func() {
+		It("should return the custom value when specified", func() {
+			config := &capgv1.GCPShieldedInstanceConfig{SecureBoot: capgv1.SecureBootPolicyEnabled}
+			gcpMachineTemplate := GCPMachineTemplate().WithShieldedInstanceConfig(config).Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.ShieldedInstanceConfig).To(Equal(config))
+		})
+	})
+
+	Describe("WithSubnet", func() {
+		It("should return the custom value when specified", func() {
+			gcpMachineTemplate := GCPMachineTemplate().WithSubnet("test-subnet").Build()
+			Expect(gcpMachineTemplate.Spec.Template.Spec.Subnet).To(HaveValue(Equal("test-subnet")))
+		})
+	})
+})
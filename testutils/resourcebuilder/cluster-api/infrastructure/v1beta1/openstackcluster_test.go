@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/utils/ptr"
 	capov1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/optional"
 )
 
 var _ = Describe("OpenStackCluster", func() {
@@ -29,7 +30,7 @@ var _ = Describe("OpenStackCluster", func() {
 		It("should return a default OpenStackCluster when no options are specified", func() {
 			openstackCluster := OpenStackCluster().Build()
 			Expect(openstackCluster).ToNot(BeNil())
-			Expect(openstackCluster.TypeMeta.APIVersion).To(Equal("infrastructure.cluster.x-k8s.io/v1beta2"))
+			Expect(openstackCluster.TypeMeta.APIVersion).To(Equal("infrastructure.cluster.x-k8s.io/v1beta1"))
 			Expect(openstackCluster.TypeMeta.Kind).To(Equal("OpenStackCluster"))
 		})
 	})
@@ -50,7 +51,7 @@ var _ = Describe("OpenStackCluster", func() {
 		It("should return the custom value when specified", func() {
 			fixedIP := ptr.To("192.168.25.10")
 			openstackCluster := OpenStackCluster().WithAPIServerFixedIP(fixedIP).Build()
-			Expect(openstackCluster.Spec.APIServerFixedIP).To(Equal(fixedIP))
+			Expect(openstackCluster.Spec.APIServerFixedIP).To(Equal(optional.String(fixedIP)))
 		})
 	})
 
@@ -66,7 +66,9 @@ func ControlPlaneMachineSet() ControlPlaneMachineSetBuilder {
 
 // ControlPlaneMachineSetBuilder is used to build out a controlplanemachineset object.
 type ControlPlaneMachineSetBuilder struct {
+	annotations            map[string]string
 	generation             int64
+	labels                 map[string]string
 	machineTemplateBuilder resourcebuilder.ControlPlaneMachineSetTemplateBuilder
 	name                   string
 	namespace              string
@@ -74,16 +76,25 @@ type ControlPlaneMachineSetBuilder struct {
 	selector               metav1.LabelSelector
 	state                  machinev1.ControlPlaneMachineSetState
 	strategyType           machinev1.ControlPlaneMachineSetStrategyType
-	conditions             []metav1.Condition
+
+	// Status fields.
+	conditions          []metav1.Condition
+	observedGeneration  int64
+	readyReplicas       int32
+	statusReplicas      int32
+	unavailableReplicas int32
+	updatedReplicas     int32
 }
 
 // Build builds a new controlplanemachineset based on the configuration provided.
 func (m ControlPlaneMachineSetBuilder) Build() *machinev1.ControlPlaneMachineSet {
 	cpms := &machinev1.ControlPlaneMachineSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:       m.name,
-			Namespace:  m.namespace,
-			Generation: m.generation,
+			Annotations: m.annotations,
+			Generation:  m.generation,
+			Labels:      m.labels,
+			Name:        m.name,
+			Namespace:   m.namespace,
 		},
 		Spec: machinev1.ControlPlaneMachineSetSpec{
 			Replicas: ptr.To[int32](m.replicas),
@@ -94,7 +105,12 @@ func (m ControlPlaneMachineSetBuilder) Build() *machinev1.ControlPlaneMachineSet
 			},
 		},
 		Status: machinev1.ControlPlaneMachineSetStatus{
-			Conditions: m.conditions,
+			Conditions:          m.conditions,
+			ObservedGeneration:  m.observedGeneration,
+			ReadyReplicas:       m.readyReplicas,
+			Replicas:            m.statusReplicas,
+			UnavailableReplicas: m.unavailableReplicas,
+			UpdatedReplicas:     m.updatedReplicas,
 		},
 	}
 
@@ -105,6 +121,18 @@ func (m ControlPlaneMachineSetBuilder) Build() *machinev1.ControlPlaneMachineSet
 	return cpms
 }
 
+// WithAnnotations sets the annotations for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithAnnotations(annotations map[string]string) ControlPlaneMachineSetBuilder {
+	m.annotations = annotations
+	return m
+}
+
+// WithLabels sets the labels for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithLabels(labels map[string]string) ControlPlaneMachineSetBuilder {
+	m.labels = labels
+	return m
+}
+
 // WithMachineTemplateBuilder sets the machine template builder for the controlplanemachineset builder.
 func (m ControlPlaneMachineSetBuilder) WithMachineTemplateBuilder(builder resourcebuilder.ControlPlaneMachineSetTemplateBuilder) ControlPlaneMachineSetBuilder {
 	m.machineTemplateBuilder = builder
@@ -158,3 +186,33 @@ func (m ControlPlaneMachineSetBuilder) WithConditions(conditions []metav1.Condit
 	m.conditions = conditions
 	return m
 }
+
+// WithStatusObservedGeneration sets the status observedGeneration for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithStatusObservedGeneration(observedGeneration int64) ControlPlaneMachineSetBuilder {
+	m.observedGeneration = observedGeneration
+	return m
+}
+
+// WithStatusReadyReplicas sets the status readyReplicas for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithStatusReadyReplicas(readyReplicas int32) ControlPlaneMachineSetBuilder {
+	m.readyReplicas = readyReplicas
+	return m
+}
+
+// WithStatusReplicas sets the status replicas for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithStatusReplicas(replicas int32) ControlPlaneMachineSetBuilder {
+	m.statusReplicas = replicas
+	return m
+}
+
+// WithStatusUnavailableReplicas sets the status unavailableReplicas for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithStatusUnavailableReplicas(unavailableReplicas int32) ControlPlaneMachineSetBuilder {
+	m.unavailableReplicas = unavailableReplicas
+	return m
+}
+
+// WithStatusUpdatedReplicas sets the status updatedReplicas for the controlplanemachineset builder.
+func (m ControlPlaneMachineSetBuilder) WithStatusUpdatedReplicas(updatedReplicas int32) ControlPlaneMachineSetBuilder {
+	m.updatedReplicas = updatedReplicas
+	return m
+}
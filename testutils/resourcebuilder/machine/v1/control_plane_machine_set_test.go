@@ -0,0 +1,179 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder"
+)
+
+var _ = Describe("ControlPlaneMachineSet", func() {
+	Describe("Build", func() {
+		It("should return a default active controlplanemachineset when no options are specified", func() {
+			cpms := ControlPlaneMachineSet().Build()
+			Expect(cpms.Name).To(Equal(ControlPlaneMachineSetName))
+			Expect(cpms.Namespace).To(Equal(resourcebuilder.OpenshiftMachineAPINamespaceName))
+			Expect(cpms.Spec.Replicas).To(HaveValue(BeEquivalentTo(3)))
+			Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateActive))
+			Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.RollingUpdate))
+			Expect(cpms.Spec.Selector.MatchLabels).To(HaveKeyWithValue(resourcebuilder.MachineRoleLabelName, "master"))
+		})
+
+		It("should use an OpenShift machine v1beta1 template by default", func() {
+			cpms := ControlPlaneMachineSet().Build()
+			Expect(cpms.Spec.Template.MachineType).To(Equal(machinev1.OpenShiftMachineV1Beta1MachineType))
+			Expect(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine).ToNot(BeNil())
+			Expect(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value).ToNot(BeNil())
+		})
+
+		It("should leave the template empty when no template builder is set", func() {
+			cpms := ControlPlaneMachineSet().WithMachineTemplateBuilder(nil).Build()
+			Expect(cpms.Spec.Template).To(Equal(machinev1.ControlPlaneMachineSetTemplate{}))
+		})
+	})
+
+	// Object meta fields.
+
+	Describe("WithAnnotations", func() {
+		It("should return the custom value when specified", func() {
+			annotations := map[string]string{"key": "value"}
+			cpms := ControlPlaneMachineSet().WithAnnotations(annotations).Build()
+			Expect(cpms.Annotations).To(Equal(annotations))
+		})
+	})
+
+	Describe("WithGeneration", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithGeneration(2).Build()
+			Expect(cpms.Generation).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should return the custom value when specified", func() {
+			labels := map[string]string{"key": "value"}
+			cpms := ControlPlaneMachineSet().WithLabels(labels).Build()
+			Expect(cpms.Labels).To(Equal(labels))
+		})
+	})
+
+	Describe("WithName", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithName("invalid").Build()
+			Expect(cpms.Name).To(Equal("invalid"))
+		})
+	})
+
+	Describe("WithNamespace", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithNamespace("ns-test").Build()
+			Expect(cpms.Namespace).To(Equal("ns-test"))
+		})
+	})
+
+	// Spec fields.
+
+	Describe("WithMachineTemplateBuilder", func() {
+		It("should build the template from the failure domains builder", func() {
+			cpms := ControlPlaneMachineSet().
+				WithMachineTemplateBuilder(OpenShiftMachineV1Beta1Template().WithFailureDomainsBuilder(AWSFailureDomains())).
+				Build()
+			Expect(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains).ToNot(BeNil())
+			Expect(cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.FailureDomains.Platform).To(Equal(configv1.AWSPlatformType))
+		})
+	})
+
+	Describe("WithReplicas", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithReplicas(5).Build()
+			Expect(cpms.Spec.Replicas).To(HaveValue(BeEquivalentTo(5)))
+		})
+	})
+
+	Describe("WithSelector", func() {
+		It("should return the custom value when specified", func() {
+			selector := metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}}
+			cpms := ControlPlaneMachineSet().WithSelector(selector).Build()
+			Expect(cpms.Spec.Selector).To(Equal(selector))
+		})
+	})
+
+	Describe("WithState", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithState(machinev1.ControlPlaneMachineSetStateInactive).Build()
+			Expect(cpms.Spec.State).To(Equal(machinev1.ControlPlaneMachineSetStateInactive))
+		})
+	})
+
+	Describe("WithStrategyType", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithStrategyType(machinev1.OnDelete).Build()
+			Expect(cpms.Spec.Strategy.Type).To(Equal(machinev1.OnDelete))
+		})
+	})
+
+	// Status fields.
+
+	Describe("WithConditions", func() {
+		It("should return the custom value when specified", func() {
+			conditions := []metav1.Condition{{Type: "Available", Status: metav1.ConditionTrue}}
+			cpms := ControlPlaneMachineSet().WithConditions(conditions).Build()
+			Expect(cpms.Status.Conditions).To(Equal(conditions))
+		})
+	})
+
+	Describe("WithStatusObservedGeneration", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithStatusObservedGeneration(2).Build()
+			Expect(cpms.Status.ObservedGeneration).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("WithStatusReadyReplicas", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithStatusReadyReplicas(3).Build()
+			Expect(cpms.Status.ReadyReplicas).To(BeEquivalentTo(3))
+		})
+	})
+
+	Describe("WithStatusReplicas", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithStatusReplicas(4).Build()
+			Expect(cpms.Status.Replicas).To(BeEquivalentTo(4))
+		})
+	})
+
+	Describe("WithStatusUnavailableReplicas", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithStatusUnavailableReplicas(1).Build()
+			Expect(cpms.Status.UnavailableReplicas).To(BeEquivalentTo(1))
+		})
+	})
+
+	Describe("WithStatusUpdatedReplicas", func() {
+		It("should return the custom value when specified", func() {
+			cpms := ControlPlaneMachineSet().WithStatusUpdatedReplicas(2).Build()
+			Expect(cpms.Status.UpdatedReplicas).To(BeEquivalentTo(2))
+		})
+	})
+})
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("FailureDomains", func() {
+	Describe("AWSFailureDomains", func() {
+		It("should build three failure domains by default", func() {
+			fds := AWSFailureDomains().BuildFailureDomains()
+			Expect(fds.Platform).To(Equal(configv1.AWSPlatformType))
+			Expect(fds.AWS).To(HaveValue(HaveLen(3)))
+			Expect((*fds.AWS)[0].Placement.AvailabilityZone).To(Equal("us-east-1a"))
+		})
+
+		It("should replace the failure domains when builders are specified", func() {
+			subnet := machinev1.AWSResourceReference{Type: machinev1.AWSIDReferenceType, ID: ptr.To("subnet-1")}
+			fds := AWSFailureDomains().
+				WithFailureDomainBuilders(AWSFailureDomain().WithAvailabilityZone("eu-west-1a").WithSubnet(subnet)).
+				BuildFailureDomains()
+			Expect(*fds.AWS).To(Equal([]machinev1.AWSFailureDomain{{
+				Placement: machinev1.AWSFailureDomainPlacement{AvailabilityZone: "eu-west-1a"},
+				Subnet:    &subnet,
+			}}))
+		})
+
+		It("should append a failure domain when a builder is added", func() {
+			fds := AWSFailureDomains().WithFailureDomainBuilder(AWSFailureDomain().WithAvailabilityZone("us-east-1d")).BuildFailureDomains()
+			Expect(*fds.AWS).To(HaveLen(4))
+		})
+	})
+
+	Describe("AzureFailureDomains", func() {
+		It("should build three failure domains by default", func() {
+			fds := AzureFailureDomains().BuildFailureDomains()
+			Expect(fds.Platform).To(Equal(configv1.AzurePlatformType))
+			Expect(fds.Azure).To(HaveValue(HaveLen(3)))
+		})
+
+		It("should replace the failure domains when builders are specified", func() {
+			fds := AzureFailureDomains().
+				WithFailureDomainBuilders(AzureFailureDomain().WithZone("1").WithSubnet("subnet-1")).
+				BuildFailureDomains()
+			Expect(*fds.Azure).To(Equal([]machinev1.AzureFailureDomain{{Zone: "1", Subnet: "subnet-1"}}))
+		})
+	})
+
+	Describe("GCPFailureDomains", func() {
+		It("should build three failure domains by default", func() {
+			fds := GCPFailureDomains().BuildFailureDomains()
+			Expect(fds.Platform).To(Equal(configv1.GCPPlatformType))
+			Expect(fds.GCP).To(HaveValue(HaveLen(3)))
+		})
+
+		It("should replace the failure domains when builders are specified", func() {
+			fds := GCPFailureDomains().WithFailureDomainBuilders(GCPFailureDomain().WithZone("europe-west1-b")).BuildFailureDomains()
+			Expect(*fds.GCP).To(Equal([]machinev1.GCPFailureDomain{{Zone: "europe-west1-b"}}))
+		})
+	})
+
+	Describe("NutanixFailureDomains", func() {
+		It("should build three failure domains by default", func() {
+			fds := NutanixFailureDomains().BuildFailureDomains()
+			Expect(fds.Platform).To(Equal(configv1.NutanixPlatformType))
+			Expect(fds.Nutanix).To(HaveLen(3))
+		})
+
+		It("should replace the failure domains when builders are specified", func() {
+			fds := NutanixFailureDomains().WithFailureDomainBuilders(NewNutanixFailureDomainBuilder().WithName("fd-custom")).BuildFailureDomains()
+			Expect(fds.Nutanix).To(Equal([]machinev1.NutanixFailureDomainReference{{Name: "fd-custom"}}))
+		})
+	})
+
+	Describe("OpenStackFailureDomains", func() {
+		It("should build failure domains by default", func() {
+			fds := OpenStackFailureDomains().BuildFailureDomains()
+			Expect(fds.Platform).To(Equal(configv1.OpenStackPlatformType))
+			Expect(fds.OpenStack).ToNot(BeEmpty())
+		})
+
+		It("should replace the failure domains when builders are specified", func() {
+			rootVolume := &machinev1.RootVolume{AvailabilityZone: "cinder-az1"}
+			fds := OpenStackFailureDomains().
+				WithFailureDomainBuilders(OpenStackFailureDomain().WithComputeAvailabilityZone("nova-az1").WithRootVolume(rootVolume)).
+				BuildFailureDomains()
+			Expect(fds.OpenStack).To(Equal([]machinev1.OpenStackFailureDomain{{AvailabilityZone: "nova-az1", RootVolume: rootVolume}}))
+		})
+	})
+
+	Describe("VSphereFailureDomains", func() {
+		It("should build three failure domains by default", func() {
+			fds := VSphereFailureDomains().BuildFailureDomains()
+			Expect(fds.Platform).To(Equal(configv1.VSpherePlatformType))
+			Expect(fds.VSphere).To(HaveLen(3))
+		})
+
+		It("should replace the failure domains when builders are specified", func() {
+			fds := VSphereFailureDomains().WithFailureDomainBuilders(VSphereFailureDomain().WithZone("zone-1")).BuildFailureDomains()
+			Expect(fds.VSphere).To(Equal([]machinev1.VSphereFailureDomain{{Name: "zone-1"}}))
+		})
+	})
+})
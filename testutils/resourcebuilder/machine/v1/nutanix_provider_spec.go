@@ -27,6 +27,31 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
+
+	"github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder"
+)
+
+var (
+	defaultNutanixCluster = machinev1.NutanixResourceIdentifier{
+		Type: machinev1.NutanixIdentifierUUID,
+		UUID: ptr.To[string]("pe-uuid"),
+	}
+	defaultNutanixCredentialsSecretName = "nutanix-credentials"
+	defaultNutanixImage                 = machinev1.NutanixResourceIdentifier{
+		Type: machinev1.NutanixIdentifierName,
+		Name: ptr.To[string]("rhcos"),
+	}
+	defaultNutanixMemorySize = resource.MustParse(fmt.Sprintf("%dMi", 8096))
+	defaultNutanixSubnets    = []machinev1.NutanixResourceIdentifier{
+		{
+			Type: machinev1.NutanixIdentifierName,
+			Name: ptr.To[string]("default-net"),
+		},
+	}
+	defaultNutanixSystemDiskSize           = resource.MustParse(fmt.Sprintf("%dGi", 120))
+	defaultNutanixUserDataSecretName       = "nutanix-user-data"
+	defaultNutanixVCPUSockets        int32 = 4
+	defaultNutanixVCPUsPerSocket     int32 = 1
 )
 
 // NewNutanixMachineProviderConfigBuilder returns a NutanixMachineProviderConfigBuilder.
@@ -40,6 +65,21 @@ type NutanixMachineProviderConfigBuilder struct {
 	failureDomains []configv1.NutanixFailureDomain
 	// failureDomainName configures the failure domain name the build will use
 	failureDomainName string
+
+	bootType          *machinev1.NutanixBootType
+	categories        []machinev1.NutanixCategory
+	cluster           *machinev1.NutanixResourceIdentifier
+	credentialsSecret **corev1.LocalObjectReference
+	dataDisks         []machinev1.NutanixVMDisk
+	gpus              []machinev1.NutanixGPU
+	image             *machinev1.NutanixResourceIdentifier
+	memorySize        *resource.Quantity
+	project           *machinev1.NutanixResourceIdentifier
+	subnets           *[]machinev1.NutanixResourceIdentifier
+	systemDiskSize    *resource.Quantity
+	userDataSecret    **corev1.LocalObjectReference
+	vcpuSockets       *int32
+	vcpusPerSocket    *int32
 }
 
 // Build returns the generated NutanixMachineProviderConfig.
@@ -49,21 +89,20 @@ func (n *NutanixMachineProviderConfigBuilder) Build() *machinev1.NutanixMachineP
 			APIVersion: machinev1.GroupVersion.String(),
 			Kind:       "NutanixMachineProviderConfig",
 		},
-		UserDataSecret:    &corev1.LocalObjectReference{Name: "nutanix-user-data"},
-		CredentialsSecret: &corev1.LocalObjectReference{Name: "nutanix-credentials"},
-		Image: machinev1.NutanixResourceIdentifier{
-			Type: machinev1.NutanixIdentifierName,
-			Name: ptr.To[string]("rhcos"),
-		},
-		Subnets:        []machinev1.NutanixResourceIdentifier{{Type: machinev1.NutanixIdentifierName, Name: ptr.To[string]("default-net")}},
-		VCPUsPerSocket: int32(1),
-		VCPUSockets:    int32(4),
-		MemorySize:     resource.MustParse(fmt.Sprintf("%dMi", 8096)),
-		Cluster: machinev1.NutanixResourceIdentifier{
-			Type: machinev1.NutanixIdentifierUUID,
-			UUID: ptr.To[string]("pe-uuid"),
-		},
-		SystemDiskSize: resource.MustParse(fmt.Sprintf("%dGi", 120)),
+		BootType:          resourcebuilder.Coalesce(n.bootType, ""),
+		Categories:        n.categories,
+		Cluster:           resourcebuilder.Coalesce(n.cluster, defaultNutanixCluster),
+		CredentialsSecret: resourcebuilder.Coalesce(n.credentialsSecret, &corev1.LocalObjectReference{Name: defaultNutanixCredentialsSecretName}),
+		DataDisks:         n.dataDisks,
+		GPUs:              n.gpus,
+		Image:             resourcebuilder.Coalesce(n.image, defaultNutanixImage),
+		MemorySize:        resourcebuilder.Coalesce(n.memorySize, defaultNutanixMemorySize),
+		Project:           resourcebuilder.Coalesce(n.project, machinev1.NutanixResourceIdentifier{}),
+		Subnets:           coalesceNutanixResourceIdentifiers(n.subnets, defaultNutanixSubnets),
+		SystemDiskSize:    resourcebuilder.Coalesce(n.systemDiskSize, defaultNutanixSystemDiskSize),
+		UserDataSecret:    resourcebuilder.Coalesce(n.userDataSecret, &corev1.LocalObjectReference{Name: defaultNutanixUserDataSecretName}),
+		VCPUSockets:       resourcebuilder.Coalesce(n.vcpuSockets, defaultNutanixVCPUSockets),
+		VCPUsPerSocket:    resourcebuilder.Coalesce(n.vcpusPerSocket, defaultNutanixVCPUsPerSocket),
 	}
 
 	if len(n.failureDomainName) > 0 {
@@ -149,3 +188,97 @@ func (n *NutanixMachineProviderConfigBuilder) WithFailureDomainName(failureDomai
 	n.failureDomainName = failureDomainName
 	return n
 }
+
+// WithBootType sets the bootType field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithBootType(bootType machinev1.NutanixBootType) *NutanixMachineProviderConfigBuilder {
+	n.bootType = &bootType
+	return n
+}
+
+// WithCategories sets the categories field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithCategories(categories []machinev1.NutanixCategory) *NutanixMachineProviderConfigBuilder {
+	n.categories = categories
+	return n
+}
+
+// WithCluster sets the cluster field with the input value.
+// The cluster of the failure domain takes precedence when a failure domain name is set.
+func (n *NutanixMachineProviderConfigBuilder) WithCluster(cluster machinev1.NutanixResourceIdentifier) *NutanixMachineProviderConfigBuilder {
+	n.cluster = &cluster
+	return n
+}
+
+// WithCredentialsSecret sets the credentialsSecret field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithCredentialsSecret(credentialsSecret *corev1.LocalObjectReference) *NutanixMachineProviderConfigBuilder {
+	n.credentialsSecret = &credentialsSecret
+	return n
+}
+
+// WithDataDisks sets the dataDisks field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithDataDisks(dataDisks []machinev1.NutanixVMDisk) *NutanixMachineProviderConfigBuilder {
+	n.dataDisks = dataDisks
+	return n
+}
+
+// WithGPUs sets the gpus field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithGPUs(gpus []machinev1.NutanixGPU) *NutanixMachineProviderConfigBuilder {
+	n.gpus = gpus
+	return n
+}
+
+// WithImage sets the image field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithImage(image machinev1.NutanixResourceIdentifier) *NutanixMachineProviderConfigBuilder {
+	n.image = &image
+	return n
+}
+
+// WithMemorySize sets the memorySize field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithMemorySize(memorySize resource.Quantity) *NutanixMachineProviderConfigBuilder {
+	n.memorySize = &memorySize
+	return n
+}
+
+// WithProject sets the project field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithProject(project machinev1.NutanixResourceIdentifier) *NutanixMachineProviderConfigBuilder {
+	n.project = &project
+	return n
+}
+
+// WithSubnets sets the subnets field with the input value.
+// The subnets of the failure domain take precedence when a failure domain name is set.
+func (n *NutanixMachineProviderConfigBuilder) WithSubnets(subnets []machinev1.NutanixResourceIdentifier) *NutanixMachineProviderConfigBuilder {
+	n.subnets = &subnets
+	return n
+}
+
+// WithSystemDiskSize sets the systemDiskSize field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithSystemDiskSize(systemDiskSize resource.Quantity) *NutanixMachineProviderConfigBuilder {
+	n.systemDiskSize = &systemDiskSize
+	return n
+}
+
+// WithUserDataSecret sets the userDataSecret field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithUserDataSecret(userDataSecret *corev1.LocalObjectReference) *NutanixMachineProviderConfigBuilder {
+	n.userDataSecret = &userDataSecret
+	return n
+}
+
+// WithVCPUSockets sets the vcpuSockets field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithVCPUSockets(vcpuSockets int32) *NutanixMachineProviderConfigBuilder {
+	n.vcpuSockets = &vcpuSockets
+	return n
+}
+
+// WithVCPUsPerSocket sets the vcpusPerSocket field with the input value.
+func (n *NutanixMachineProviderConfigBuilder) WithVCPUsPerSocket(vcpusPerSocket int32) *NutanixMachineProviderConfigBuilder {
+	n.vcpusPerSocket = &vcpusPerSocket
+	return n
+}
+
+func coalesceNutanixResourceIdentifiers(v1 *[]machinev1.NutanixResourceIdentifier, v2 []machinev1.NutanixResourceIdentifier) []machinev1.NutanixResourceIdentifier {
+	if v1 == nil {
+		return v2
+	}
+
+	return *v1
+}
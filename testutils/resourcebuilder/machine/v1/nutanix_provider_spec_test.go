@@ -0,0 +1,243 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("NutanixMachineProviderConfig", func() {
+	Describe("Build", func() {
+		It("should return the type meta", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.Kind).To(Equal("NutanixMachineProviderConfig"))
+			Expect(nutanixPs.APIVersion).To(Equal("machine.openshift.io/v1"))
+		})
+	})
+
+	Describe("BuildRawExtension", func() {
+		It("should marshal the built provider config", func() {
+			raw := NewNutanixMachineProviderConfigBuilder().WithVCPUSockets(2).BuildRawExtension()
+
+			nutanixPs := &machinev1.NutanixMachineProviderConfig{}
+			Expect(json.Unmarshal(raw.Raw, nutanixPs)).To(Succeed())
+			Expect(nutanixPs.VCPUSockets).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("BootType", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.BootType).To(BeEmpty())
+		})
+
+		It("should return the custom value when specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithBootType(machinev1.NutanixUEFIBoot).Build()
+			Expect(nutanixPs.BootType).To(Equal(machinev1.NutanixUEFIBoot))
+		})
+	})
+
+	Describe("Categories", func() {
+		It("should return nil when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.Categories).To(BeNil())
+		})
+
+		It("should return the custom value when specified", func() {
+			categories := []machinev1.NutanixCategory{{Key: "key", Value: "value"}}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithCategories(categories).Build()
+			Expect(nutanixPs.Categories).To(Equal(categories))
+		})
+	})
+
+	Describe("Cluster", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.Cluster).To(Equal(defaultNutanixCluster))
+		})
+
+		It("should return the custom value when specified", func() {
+			cluster := machinev1.NutanixResourceIdentifier{Type: machinev1.NutanixIdentifierName, Name: ptr.To("pe-cluster")}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithCluster(cluster).Build()
+			Expect(nutanixPs.Cluster).To(Equal(cluster))
+		})
+	})
+
+	Describe("CredentialsSecret", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.CredentialsSecret).To(Equal(&corev1.LocalObjectReference{Name: defaultNutanixCredentialsSecretName}))
+		})
+
+		It("should return nil when specified as such", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithCredentialsSecret(nil).Build()
+			Expect(nutanixPs.CredentialsSecret).To(BeNil())
+		})
+
+		It("should return the custom value when specified", func() {
+			secret := &corev1.LocalObjectReference{Name: "custom-credentials"}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithCredentialsSecret(secret).Build()
+			Expect(nutanixPs.CredentialsSecret).To(Equal(secret))
+		})
+	})
+
+	Describe("DataDisks", func() {
+		It("should return the custom value when specified", func() {
+			disks := []machinev1.NutanixVMDisk{{DiskSize: resource.MustParse("50Gi")}}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithDataDisks(disks).Build()
+			Expect(nutanixPs.DataDisks).To(Equal(disks))
+		})
+	})
+
+	Describe("FailureDomainName", func() {
+		It("should use the cluster and subnets of the failure domain when specified", func() {
+			failureDomains := []configv1.NutanixFailureDomain{
+				{
+					Name: "fd-1",
+					Cluster: configv1.NutanixResourceIdentifier{
+						Type: configv1.NutanixIdentifierUUID,
+						UUID: ptr.To("fd-cluster-uuid"),
+					},
+					Subnets: []configv1.NutanixResourceIdentifier{
+						{Type: configv1.NutanixIdentifierUUID, UUID: ptr.To("fd-subnet-uuid")},
+					},
+				},
+			}
+
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().
+				WithCluster(machinev1.NutanixResourceIdentifier{Type: machinev1.NutanixIdentifierName, Name: ptr.To("ignored")}).
+				WithFailureDomains(failureDomains).
+				WithFailureDomainName("fd-1").
+				Build()
+			Expect(nutanixPs.Cluster.UUID).To(HaveValue(Equal("fd-cluster-uuid")))
+			Expect(nutanixPs.Subnets).To(HaveLen(1))
+			Expect(nutanixPs.Subnets[0].UUID).To(HaveValue(Equal("fd-subnet-uuid")))
+		})
+	})
+
+	Describe("GPUs", func() {
+		It("should return the custom value when specified", func() {
+			gpus := []machinev1.NutanixGPU{{Type: machinev1.NutanixGPUIdentifierName, Name: ptr.To("gpu")}}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithGPUs(gpus).Build()
+			Expect(nutanixPs.GPUs).To(Equal(gpus))
+		})
+	})
+
+	Describe("Image", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.Image).To(Equal(defaultNutanixImage))
+		})
+
+		It("should return the custom value when specified", func() {
+			image := machinev1.NutanixResourceIdentifier{Type: machinev1.NutanixIdentifierUUID, UUID: ptr.To("image-uuid")}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithImage(image).Build()
+			Expect(nutanixPs.Image).To(Equal(image))
+		})
+	})
+
+	Describe("MemorySize", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.MemorySize).To(Equal(defaultNutanixMemorySize))
+		})
+
+		It("should return the custom value when specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithMemorySize(resource.MustParse("16Gi")).Build()
+			Expect(nutanixPs.MemorySize).To(Equal(resource.MustParse("16Gi")))
+		})
+	})
+
+	Describe("Project", func() {
+		It("should return the custom value when specified", func() {
+			project := machinev1.NutanixResourceIdentifier{Type: machinev1.NutanixIdentifierName, Name: ptr.To("project")}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithProject(project).Build()
+			Expect(nutanixPs.Project).To(Equal(project))
+		})
+	})
+
+	Describe("Subnets", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.Subnets).To(Equal(defaultNutanixSubnets))
+		})
+
+		It("should return the custom value when specified", func() {
+			subnets := []machinev1.NutanixResourceIdentifier{{Type: machinev1.NutanixIdentifierUUID, UUID: ptr.To("subnet-uuid")}}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithSubnets(subnets).Build()
+			Expect(nutanixPs.Subnets).To(Equal(subnets))
+		})
+	})
+
+	Describe("SystemDiskSize", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.SystemDiskSize).To(Equal(defaultNutanixSystemDiskSize))
+		})
+
+		It("should return the custom value when specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithSystemDiskSize(resource.MustParse("200Gi")).Build()
+			Expect(nutanixPs.SystemDiskSize).To(Equal(resource.MustParse("200Gi")))
+		})
+	})
+
+	Describe("UserDataSecret", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.UserDataSecret).To(Equal(&corev1.LocalObjectReference{Name: defaultNutanixUserDataSecretName}))
+		})
+
+		It("should return the custom value when specified", func() {
+			secret := &corev1.LocalObjectReference{Name: "worker-user-data"}
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithUserDataSecret(secret).Build()
+			Expect(nutanixPs.UserDataSecret).To(Equal(secret))
+		})
+	})
+
+	Describe("VCPUSockets", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.VCPUSockets).To(Equal(defaultNutanixVCPUSockets))
+		})
+
+		It("should return the custom value when specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithVCPUSockets(8).Build()
+			Expect(nutanixPs.VCPUSockets).To(BeEquivalentTo(8))
+		})
+	})
+
+	Describe("VCPUsPerSocket", func() {
+		It("should return the default value when not specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().Build()
+			Expect(nutanixPs.VCPUsPerSocket).To(Equal(defaultNutanixVCPUsPerSocket))
+		})
+
+		It("should return the custom value when specified", func() {
+			nutanixPs := NewNutanixMachineProviderConfigBuilder().WithVCPUsPerSocket(2).Build()
+			Expect(nutanixPs.VCPUsPerSocket).To(BeEquivalentTo(2))
+		})
+	})
+})
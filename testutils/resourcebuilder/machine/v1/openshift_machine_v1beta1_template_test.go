@@ -0,0 +1,82 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	"github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder"
+	machinev1beta1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/machine/v1beta1"
+)
+
+var _ = Describe("OpenShiftMachineV1Beta1Template", func() {
+	Describe("BuildTemplate", func() {
+		It("should return the default master labels and an AWS provider spec", func() {
+			template := OpenShiftMachineV1Beta1Template().BuildTemplate()
+			Expect(template.MachineType).To(Equal(machinev1.OpenShiftMachineV1Beta1MachineType))
+			Expect(template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels).To(HaveKeyWithValue(resourcebuilder.MachineTypeLabelName, "master"))
+			Expect(template.OpenShiftMachineV1Beta1Machine.FailureDomains).To(BeNil())
+
+			awsPs := &machinev1beta1.AWSMachineProviderConfig{}
+			Expect(json.Unmarshal(template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw, awsPs)).To(Succeed())
+			Expect(awsPs.Kind).To(Equal("AWSMachineProviderConfig"))
+		})
+	})
+
+	Describe("WithFailureDomainsBuilder", func() {
+		It("should return the built failure domains when specified", func() {
+			template := OpenShiftMachineV1Beta1Template().WithFailureDomainsBuilder(GCPFailureDomains()).BuildTemplate()
+			Expect(template.OpenShiftMachineV1Beta1Machine.FailureDomains).ToNot(BeNil())
+			Expect(template.OpenShiftMachineV1Beta1Machine.FailureDomains.Platform).To(Equal(configv1.GCPPlatformType))
+		})
+	})
+
+	Describe("WithLabel", func() {
+		It("should add the label to the default labels", func() {
+			template := OpenShiftMachineV1Beta1Template().WithLabel("key", "value").BuildTemplate()
+			Expect(template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels).To(HaveKeyWithValue("key", "value"))
+			Expect(template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels).To(HaveKeyWithValue(resourcebuilder.MachineRoleLabelName, "master"))
+		})
+	})
+
+	Describe("WithLabels", func() {
+		It("should replace the default labels", func() {
+			labels := map[string]string{"key": "value"}
+			template := OpenShiftMachineV1Beta1Template().WithLabels(labels).BuildTemplate()
+			Expect(template.OpenShiftMachineV1Beta1Machine.ObjectMeta.Labels).To(Equal(labels))
+		})
+	})
+
+	Describe("WithProviderSpecBuilder", func() {
+		It("should return the custom provider spec when specified", func() {
+			template := OpenShiftMachineV1Beta1Template().
+				WithProviderSpecBuilder(machinev1beta1resourcebuilder.GCPProviderSpec()).
+				BuildTemplate()
+
+			gcpPs := &machinev1beta1.GCPMachineProviderSpec{}
+			Expect(json.Unmarshal(template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value.Raw, gcpPs)).To(Succeed())
+			Expect(gcpPs.Kind).To(Equal("GCPMachineProviderSpec"))
+		})
+	})
+})
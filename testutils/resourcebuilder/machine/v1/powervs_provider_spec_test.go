@@ -0,0 +1,190 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("PowerVSMachineProviderConfig", func() {
+	Describe("Build", func() {
+		It("should return the type meta", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.Kind).To(Equal("PowerVSMachineProviderConfig"))
+			Expect(powerVSPs.APIVersion).To(Equal("machine.openshift.io/v1"))
+		})
+	})
+
+	Describe("BuildRawExtension", func() {
+		It("should marshal the built provider config", func() {
+			raw := PowerVSProviderSpec().WithSystemType("e980").BuildRawExtension()
+
+			powerVSPs := &machinev1.PowerVSMachineProviderConfig{}
+			Expect(json.Unmarshal(raw.Raw, powerVSPs)).To(Succeed())
+			Expect(powerVSPs.SystemType).To(Equal("e980"))
+		})
+	})
+
+	Describe("CredentialsSecret", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.CredentialsSecret).To(Equal(&machinev1.PowerVSSecretReference{Name: defaultCredentialsSecretName}))
+		})
+
+		It("should return nil when specified as such", func() {
+			powerVSPs := PowerVSProviderSpec().WithCredentialSecret(nil).Build()
+			Expect(powerVSPs.CredentialsSecret).To(BeNil())
+		})
+
+		It("should return the custom value when specified", func() {
+			secret := &machinev1.PowerVSSecretReference{Name: "custom-credentials"}
+			powerVSPs := PowerVSProviderSpec().WithCredentialSecret(secret).Build()
+			Expect(powerVSPs.CredentialsSecret).To(Equal(secret))
+		})
+	})
+
+	Describe("Image", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.Image).To(Equal(defaultImage))
+		})
+
+		It("should return the custom value when specified", func() {
+			image := machinev1.PowerVSResource{Type: machinev1.PowerVSResourceTypeName, Name: ptr.To("rhcos")}
+			powerVSPs := PowerVSProviderSpec().WithImage(image).Build()
+			Expect(powerVSPs.Image).To(Equal(image))
+		})
+	})
+
+	Describe("KeyPairName", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.KeyPairName).To(Equal(defaultKeyPairName))
+		})
+
+		It("should return the custom value when specified", func() {
+			powerVSPs := PowerVSProviderSpec().WithKeyPairName("custom-key").Build()
+			Expect(powerVSPs.KeyPairName).To(Equal("custom-key"))
+		})
+	})
+
+	Describe("LoadBalancers", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.LoadBalancers).To(Equal(defaultLoadBalancer))
+		})
+
+		It("should return an empty list when specified as such", func() {
+			powerVSPs := PowerVSProviderSpec().WithLoadBalancers([]machinev1.LoadBalancerReference{}).Build()
+			Expect(powerVSPs.LoadBalancers).To(BeEmpty())
+		})
+	})
+
+	Describe("MemoryGiB", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.MemoryGiB).To(Equal(defaultMemory))
+		})
+
+		It("should return the custom value when specified", func() {
+			powerVSPs := PowerVSProviderSpec().WithMemoryGIB(64).Build()
+			Expect(powerVSPs.MemoryGiB).To(BeEquivalentTo(64))
+		})
+	})
+
+	Describe("Network", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.Network).To(Equal(defaultNetwork))
+		})
+
+		It("should return the custom value when specified", func() {
+			network := machinev1.PowerVSResource{Type: machinev1.PowerVSResourceTypeRegEx, RegEx: ptr.To("^DHCPSERVER.*")}
+			powerVSPs := PowerVSProviderSpec().WithNetwork(network).Build()
+			Expect(powerVSPs.Network).To(Equal(network))
+		})
+	})
+
+	Describe("Processors", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.Processors).To(Equal(defaultProcessors))
+		})
+
+		It("should return the custom value when specified", func() {
+			powerVSPs := PowerVSProviderSpec().WithProcessors(intstr.FromString("0.5")).Build()
+			Expect(powerVSPs.Processors).To(Equal(intstr.FromString("0.5")))
+		})
+	})
+
+	Describe("ProcessorType", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.ProcessorType).To(Equal(defaultProcessorType))
+		})
+
+		It("should return the custom value when specified", func() {
+			powerVSPs := PowerVSProviderSpec().WithProcessorType(machinev1.PowerVSProcessorTypeDedicated).Build()
+			Expect(powerVSPs.ProcessorType).To(Equal(machinev1.PowerVSProcessorTypeDedicated))
+		})
+	})
+
+	Describe("ServiceInstance", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.ServiceInstance).To(Equal(defaultServiceInstance))
+		})
+
+		It("should return the custom value when specified", func() {
+			serviceInstance := machinev1.PowerVSResource{Type: machinev1.PowerVSResourceTypeName, Name: ptr.To("instance")}
+			powerVSPs := PowerVSProviderSpec().WithServiceInstance(serviceInstance).Build()
+			Expect(powerVSPs.ServiceInstance).To(Equal(serviceInstance))
+		})
+	})
+
+	Describe("SystemType", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.SystemType).To(Equal(defaultSystemType))
+		})
+
+		It("should return the custom value when specified", func() {
+			powerVSPs := PowerVSProviderSpec().WithSystemType("e980").Build()
+			Expect(powerVSPs.SystemType).To(Equal("e980"))
+		})
+	})
+
+	Describe("UserDataSecret", func() {
+		It("should return the default value when not specified", func() {
+			powerVSPs := PowerVSProviderSpec().Build()
+			Expect(powerVSPs.UserDataSecret).To(Equal(&machinev1.PowerVSSecretReference{Name: defaultUserDataSecretName}))
+		})
+
+		It("should return the custom value when specified", func() {
+			secret := &machinev1.PowerVSSecretReference{Name: "worker-user-data"}
+			powerVSPs := PowerVSProviderSpec().WithUserDataSecret(secret).Build()
+			Expect(powerVSPs.UserDataSecret).To(Equal(secret))
+		})
+	})
+})
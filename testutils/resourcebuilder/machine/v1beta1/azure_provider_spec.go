@@ -32,15 +32,22 @@ func AzureProviderSpec() AzureProviderSpecBuilder {
 		vmSize:               "Standard_D4s_v3",
 		zone:                 "1",
 		subnet:               "cluster-subnet-12345678",
+		vnet:                 "vnet-12345678",
+
+		acceleratedNetworking: true,
 	}
 }
 
 // AzureProviderSpecBuilder is used to build a Azure machine config object.
 type AzureProviderSpecBuilder struct {
+	availabilitySet      string
 	internalLoadBalancer string
 	vmSize               string
 	zone                 string
 	subnet               string
+	vnet                 string
+
+	acceleratedNetworking bool
 }
 
 // Build builds a new Azure machine config based on the configuration provided.
@@ -58,7 +65,7 @@ func (m AzureProviderSpecBuilder) Build() *machinev1beta1.AzureMachineProviderSp
 			Namespace: "openshift-machine-api",
 		},
 		Location: "test-location",
-		Vnet:     "vnet-12345678",
+		Vnet:     m.vnet,
 		VMSize:   m.vmSize,
 		Image: machinev1beta1.Image{
 			ResourceID: "/resourceGroups/test-rg/providers/Microsoft.Compute/images/test-image",
@@ -77,7 +84,8 @@ func (m AzureProviderSpecBuilder) Build() *machinev1beta1.AzureMachineProviderSp
 		PublicIP:              false,
 		ResourceGroup:         "resource-group-12345678",
 		Zone:                  m.zone,
-		AcceleratedNetworking: true,
+		AcceleratedNetworking: m.acceleratedNetworking,
+		AvailabilitySet:       m.availabilitySet,
 		Subnet:                m.subnet,
 	}
 }
@@ -97,6 +105,19 @@ func (m AzureProviderSpecBuilder) BuildRawExtension() *runtime.RawExtension {
 	}
 }
 
+// WithAcceleratedNetworking sets the acceleratedNetworking for the Azure machine config builder.
+func (m AzureProviderSpecBuilder) WithAcceleratedNetworking(acceleratedNetworking bool) AzureProviderSpecBuilder {
+	m.acceleratedNetworking = acceleratedNetworking
+	return m
+}
+
+// WithAvailabilitySet sets the availabilitySet for the Azure machine config builder.
+// An availability set cannot be used together with an availability zone: clear the zone with WithZone("").
+func (m AzureProviderSpecBuilder) WithAvailabilitySet(availabilitySet string) AzureProviderSpecBuilder {
+	m.availabilitySet = availabilitySet
+	return m
+}
+
 // WithInternalLoadBalancer sets the internalLoadBalancer for the Azure machine config builder.
 func (m AzureProviderSpecBuilder) WithInternalLoadBalancer(lb string) AzureProviderSpecBuilder {
 	m.internalLoadBalancer = lb
@@ -120,3 +141,9 @@ func (m AzureProviderSpecBuilder) WithSubnet(subnet string) AzureProviderSpecBui
 	m.subnet = subnet
 	return m
 }
+
+// WithVnet sets the vnet for the Azure machine config builder.
+func (m AzureProviderSpecBuilder) WithVnet(vnet string) AzureProviderSpecBuilder {
+	m.vnet = vnet
+	return m
+}
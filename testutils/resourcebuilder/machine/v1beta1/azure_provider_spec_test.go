@@ -0,0 +1,52 @@
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AzureProviderSpec", func() {
+	Describe("AcceleratedNetworking", func() {
+		It("should be enabled when not specified", func() {
+			azurePs := AzureProviderSpec().Build()
+			Expect(azurePs.AcceleratedNetworking).To(BeTrue())
+		})
+
+		It("should return the custom value when specified", func() {
+			azurePs := AzureProviderSpec().WithAcceleratedNetworking(false).Build()
+			Expect(azurePs.AcceleratedNetworking).To(BeFalse())
+		})
+	})
+
+	Describe("AvailabilitySet", func() {
+		It("should be empty when not specified", func() {
+			azurePs := AzureProviderSpec().Build()
+			Expect(azurePs.AvailabilitySet).To(BeEmpty())
+		})
+
+		It("should return the custom value when specified", func() {
+			azurePs := AzureProviderSpec().WithZone("").WithAvailabilitySet("custom-as").Build()
+			Expect(azurePs.AvailabilitySet).To(Equal("custom-as"))
+			Expect(azurePs.Zone).To(BeEmpty())
+		})
+	})
+
+	Describe("Subnet", func() {
+		It("should return the custom value when specified", func() {
+			azurePs := AzureProviderSpec().WithSubnet("custom-subnet").Build()
+			Expect(azurePs.Subnet).To(Equal("custom-subnet"))
+		})
+	})
+
+	Describe("Vnet", func() {
+		It("should return the default value when not specified", func() {
+			azurePs := AzureProviderSpec().Build()
+			Expect(azurePs.Vnet).To(Equal("vnet-12345678"))
+		})
+
+		It("should return the custom value when specified", func() {
+			azurePs := AzureProviderSpec().WithVnet("custom-vnet").Build()
+			Expect(azurePs.Vnet).To(Equal("custom-vnet"))
+		})
+	})
+})
@@ -26,9 +26,19 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder"
 	configv1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/config/v1"
 )
 
+var (
+	defaultVSphereCredentialsSecretName       = "vsphere-cloud-credentials"
+	defaultVSphereDiskGiB               int32 = 120
+	defaultVSphereMemoryMiB             int64 = 16384
+	defaultVSphereNumCPUs               int32 = 4
+	defaultVSphereNumCoresPerSocket     int32 = 4
+	defaultVSphereUserDataSecretName          = "master-user-data"
+)
+
 // VSphereProviderSpec creates a new VSphere machine config builder.
 func VSphereProviderSpec() VSphereProviderSpecBuilder {
 	return VSphereProviderSpecBuilder{
@@ -44,6 +54,15 @@ type VSphereProviderSpecBuilder struct {
 	infrastructure    *configv1.Infrastructure
 	ippool            bool
 	tags              []string
+
+	cloneMode         *machinev1beta1.CloneMode
+	credentialsSecret **v1.LocalObjectReference
+	diskGiB           *int32
+	memoryMiB         *int64
+	numCPUs           *int32
+	numCoresPerSocket *int32
+	snapshot          *string
+	userDataSecret    **v1.LocalObjectReference
 }
 
 // Build builds a new VSphere machine config based on the configuration provided.
@@ -112,22 +131,20 @@ func (v VSphereProviderSpecBuilder) Build() *machinev1beta1.VSphereMachineProvid
 			Kind:       "VSphereMachineProviderSpec",
 			APIVersion: "machine.openshift.io/v1beta1",
 		},
-		NumCoresPerSocket: 4,
-		DiskGiB:           120,
-		UserDataSecret: &v1.LocalObjectReference{
-			Name: "master-user-data",
-		},
-		MemoryMiB: 16384,
-		CredentialsSecret: &v1.LocalObjectReference{
-			Name: "vsphere-cloud-credentials",
-		},
+		CloneMode:         resourcebuilder.Coalesce(v.cloneMode, ""),
+		CredentialsSecret: resourcebuilder.Coalesce(v.credentialsSecret, &v1.LocalObjectReference{Name: defaultVSphereCredentialsSecretName}),
+		DiskGiB:           resourcebuilder.Coalesce(v.diskGiB, defaultVSphereDiskGiB),
+		MemoryMiB:         resourcebuilder.Coalesce(v.memoryMiB, defaultVSphereMemoryMiB),
 		Network: machinev1beta1.NetworkSpec{
 			Devices: networkDevices,
 		},
-		TagIDs:    v.tags,
-		Workspace: workspace,
-		NumCPUs:   4,
-		Template:  template,
+		NumCPUs:           resourcebuilder.Coalesce(v.numCPUs, defaultVSphereNumCPUs),
+		NumCoresPerSocket: resourcebuilder.Coalesce(v.numCoresPerSocket, defaultVSphereNumCoresPerSocket),
+		Snapshot:          resourcebuilder.Coalesce(v.snapshot, ""),
+		TagIDs:            v.tags,
+		Template:          template,
+		UserDataSecret:    resourcebuilder.Coalesce(v.userDataSecret, &v1.LocalObjectReference{Name: defaultVSphereUserDataSecretName}),
+		Workspace:         workspace,
 	}
 }
 
@@ -184,3 +201,51 @@ func (v VSphereProviderSpecBuilder) WithIPPool() VSphereProviderSpecBuilder {
 	v.ippool = true
 	return v
 }
+
+// WithCloneMode sets the cloneMode for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithCloneMode(cloneMode machinev1beta1.CloneMode) VSphereProviderSpecBuilder {
+	v.cloneMode = &cloneMode
+	return v
+}
+
+// WithCredentialsSecret sets the credentialsSecret for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithCredentialsSecret(credentialsSecret *v1.LocalObjectReference) VSphereProviderSpecBuilder {
+	v.credentialsSecret = &credentialsSecret
+	return v
+}
+
+// WithDiskGiB sets the diskGiB for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithDiskGiB(diskGiB int32) VSphereProviderSpecBuilder {
+	v.diskGiB = &diskGiB
+	return v
+}
+
+// WithMemoryMiB sets the memoryMiB for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithMemoryMiB(memoryMiB int64) VSphereProviderSpecBuilder {
+	v.memoryMiB = &memoryMiB
+	return v
+}
+
+// WithNumCPUs sets the numCPUs for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithNumCPUs(numCPUs int32) VSphereProviderSpecBuilder {
+	v.numCPUs = &numCPUs
+	return v
+}
+
+// WithNumCoresPerSocket sets the numCoresPerSocket for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithNumCoresPerSocket(numCoresPerSocket int32) VSphereProviderSpecBuilder {
+	v.numCoresPerSocket = &numCoresPerSocket
+	return v
+}
+
+// WithSnapshot sets the snapshot for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithSnapshot(snapshot string) VSphereProviderSpecBuilder {
+	v.snapshot = &snapshot
+	return v
+}
+
+// WithUserDataSecret sets the userDataSecret for the VSphere machine config builder.
+func (v VSphereProviderSpecBuilder) WithUserDataSecret(userDataSecret *v1.LocalObjectReference) VSphereProviderSpecBuilder {
+	v.userDataSecret = &userDataSecret
+	return v
+}
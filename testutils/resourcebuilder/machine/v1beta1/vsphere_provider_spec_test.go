@@ -0,0 +1,220 @@
+/*
+Copyright 2026 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("VSphereProviderSpec", func() {
+	Describe("Build", func() {
+		It("should return the type meta", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.Kind).To(Equal("VSphereMachineProviderSpec"))
+			Expect(vspherePs.APIVersion).To(Equal("machine.openshift.io/v1beta1"))
+		})
+
+		It("should return the default workspace and network when no zone is specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.Workspace).To(Equal(&machinev1beta1.Workspace{
+				Server:       "test-vcenter",
+				Datacenter:   "test-datacenter",
+				Datastore:    "test-datastore",
+				ResourcePool: "/test-datacenter/hosts/test-cluster/resources",
+			}))
+			Expect(vspherePs.Network.Devices).To(Equal([]machinev1beta1.NetworkDeviceSpec{{NetworkName: "test-network"}}))
+		})
+	})
+
+	Describe("BuildRawExtension", func() {
+		It("should marshal the built provider spec", func() {
+			raw := VSphereProviderSpec().WithNumCPUs(8).BuildRawExtension()
+
+			vspherePs := &machinev1beta1.VSphereMachineProviderSpec{}
+			Expect(json.Unmarshal(raw.Raw, vspherePs)).To(Succeed())
+			Expect(vspherePs.NumCPUs).To(BeEquivalentTo(8))
+		})
+	})
+
+	Describe("AsControlPlaneMachineSetProviderSpec", func() {
+		It("should leave the workspace, network and template empty", func() {
+			vspherePs := VSphereProviderSpec().AsControlPlaneMachineSetProviderSpec().Build()
+			Expect(vspherePs.Workspace).To(Equal(&machinev1beta1.Workspace{}))
+			Expect(vspherePs.Network.Devices).To(BeNil())
+			Expect(vspherePs.Template).To(BeEmpty())
+		})
+	})
+
+	Describe("CloneMode", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.CloneMode).To(BeEmpty())
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithCloneMode(machinev1beta1.LinkedClone).Build()
+			Expect(vspherePs.CloneMode).To(Equal(machinev1beta1.LinkedClone))
+		})
+	})
+
+	Describe("CredentialsSecret", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.CredentialsSecret).To(Equal(&corev1.LocalObjectReference{Name: defaultVSphereCredentialsSecretName}))
+		})
+
+		It("should return nil when specified as such", func() {
+			vspherePs := VSphereProviderSpec().WithCredentialsSecret(nil).Build()
+			Expect(vspherePs.CredentialsSecret).To(BeNil())
+		})
+
+		It("should return the custom value when specified", func() {
+			secret := &corev1.LocalObjectReference{Name: "custom-credentials"}
+			vspherePs := VSphereProviderSpec().WithCredentialsSecret(secret).Build()
+			Expect(vspherePs.CredentialsSecret).To(Equal(secret))
+		})
+	})
+
+	Describe("DiskGiB", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.DiskGiB).To(Equal(defaultVSphereDiskGiB))
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithDiskGiB(200).Build()
+			Expect(vspherePs.DiskGiB).To(BeEquivalentTo(200))
+		})
+	})
+
+	Describe("IPPool", func() {
+		It("should not use an IP pool when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.Network.Devices[0].AddressesFromPools).To(BeEmpty())
+		})
+
+		It("should get the addresses from an IP pool when specified", func() {
+			vspherePs := VSphereProviderSpec().WithIPPool().Build()
+			Expect(vspherePs.Network.Devices[0].AddressesFromPools).To(HaveLen(1))
+		})
+	})
+
+	Describe("MemoryMiB", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.MemoryMiB).To(Equal(defaultVSphereMemoryMiB))
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithMemoryMiB(8192).Build()
+			Expect(vspherePs.MemoryMiB).To(BeEquivalentTo(8192))
+		})
+	})
+
+	Describe("NumCPUs", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.NumCPUs).To(Equal(defaultVSphereNumCPUs))
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithNumCPUs(8).Build()
+			Expect(vspherePs.NumCPUs).To(BeEquivalentTo(8))
+		})
+	})
+
+	Describe("NumCoresPerSocket", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.NumCoresPerSocket).To(Equal(defaultVSphereNumCoresPerSocket))
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithNumCoresPerSocket(2).Build()
+			Expect(vspherePs.NumCoresPerSocket).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("Snapshot", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.Snapshot).To(BeEmpty())
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithSnapshot("snapshot-1").Build()
+			Expect(vspherePs.Snapshot).To(Equal("snapshot-1"))
+		})
+	})
+
+	Describe("Tags", func() {
+		It("should return nil when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.TagIDs).To(BeNil())
+		})
+
+		It("should return the custom value when specified", func() {
+			tags := []string{"urn:vmomi:InventoryServiceTag:1"}
+			vspherePs := VSphereProviderSpec().WithTags(tags).Build()
+			Expect(vspherePs.TagIDs).To(Equal(tags))
+		})
+	})
+
+	Describe("Template", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.Template).To(Equal("/datacenter/vm/test-ln-xw89i22-c1627-rvtrn-rhcos"))
+		})
+
+		It("should return the custom value when specified", func() {
+			vspherePs := VSphereProviderSpec().WithTemplate("custom-template").Build()
+			Expect(vspherePs.Template).To(Equal("custom-template"))
+		})
+	})
+
+	Describe("UserDataSecret", func() {
+		It("should return the default value when not specified", func() {
+			vspherePs := VSphereProviderSpec().Build()
+			Expect(vspherePs.UserDataSecret).To(Equal(&corev1.LocalObjectReference{Name: defaultVSphereUserDataSecretName}))
+		})
+
+		It("should return the custom value when specified", func() {
+			secret := &corev1.LocalObjectReference{Name: "worker-user-data"}
+			vspherePs := VSphereProviderSpec().WithUserDataSecret(secret).Build()
+			Expect(vspherePs.UserDataSecret).To(Equal(secret))
+		})
+	})
+
+	Describe("Zone", func() {
+		It("should use the workspace and network of the failure domain when specified", func() {
+			vspherePs := VSphereProviderSpec().WithZone("us-central1-b").Build()
+			Expect(vspherePs.Workspace).To(Equal(&machinev1beta1.Workspace{
+				Server:       "vcenter.test.com",
+				Datacenter:   "test-dc2",
+				Datastore:    "/test-dc2/datastore/test-datastore-2",
+				ResourcePool: "/test-dc2/host/test-cluster-2/Resources",
+			}))
+			Expect(vspherePs.Network.Devices[0].NetworkName).To(Equal("test-network-2"))
+		})
+	})
+})